@@ -14,13 +14,16 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	commonsettings "github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/DataDog/datadog-agent/pkg/logs"
 	"github.com/spf13/cobra"
 
 	"github.com/fatih/color"
 )
 
 func init() {
-	AgentCmd.AddCommand(cmdconfig.Config(getSettingsClient))
+	configCmd := cmdconfig.Config(getSettingsClient)
+	configCmd.AddCommand(configValidateCommand)
+	AgentCmd.AddCommand(configCmd)
 }
 
 func setupConfig() error {
@@ -76,3 +79,63 @@ func initRuntimeSettings() error {
 	}
 	return commonsettings.RegisterRuntimeSetting(commonsettings.ProfilingRuntimeSetting("internal_profiling"))
 }
+
+// initReloadHandlers registers the components able to apply configuration changes
+// when the configuration is reloaded, they must be started beforehand.
+func initReloadHandlers() error {
+	// Runtime settings are reloaded too, the other changed keys require a restart
+	if err := commonsettings.RegisterReloadHandler(commonsettings.NewReloadHandler(
+		"forwarder",
+		[]string{"api_key", "additional_endpoints"},
+		func([]string) error {
+			keysPerDomain, err := config.GetMultipleEndpoints()
+			if err != nil {
+				return err
+			}
+			return demux.UpdateForwarderAPIKeys(keysPerDomain)
+		},
+	)); err != nil {
+		return err
+	}
+
+	if common.MetadataScheduler != nil && common.MetadataScheduler.IsScheduled("host") {
+		if err := commonsettings.RegisterReloadHandler(commonsettings.NewReloadHandler(
+			"host-metadata",
+			[]string{"tags", "extra_tags", "env"},
+			func([]string) error {
+				// send the host metadata, and so the host tags, right away
+				common.MetadataScheduler.TriggerAndResetCollectorTimer("host", 0)
+				return nil
+			},
+		)); err != nil {
+			return err
+		}
+	}
+
+	if common.DSD != nil {
+		if err := commonsettings.RegisterReloadHandler(commonsettings.NewReloadHandler(
+			"dogstatsd",
+			[]string{"dogstatsd_tags", "tags", "extra_tags", "statsd_metric_namespace", "statsd_metric_namespace_blacklist", "statsd_metric_blocklist"},
+			func([]string) error {
+				common.DSD.ReloadSettings()
+				return nil
+			},
+		)); err != nil {
+			return err
+		}
+	}
+
+	if logs.IsAgentRunning() {
+		if err := commonsettings.RegisterReloadHandler(commonsettings.NewReloadHandler(
+			"logs-agent",
+			[]string{"logs_config.processing_rules"},
+			func([]string) error {
+				return logs.ReloadProcessingRules()
+			},
+		)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var validateStrict bool
+
+func init() {
+	configValidateCommand.Flags().BoolVarP(&validateStrict, "strict", "", false, "fail on warnings too, e.g. unknown or deprecated keys")
+}
+
+var configValidateCommand = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration files without starting the agent",
+	Long: `Check datadog.yaml for unknown or deprecated keys and for values of the wrong type,
+and the instances of the core checks configured in conf.d by configuring them without scheduling them.`,
+	RunE: validateConfig,
+}
+
+func validateConfig(*cobra.Command, []string) error {
+	if flagNoColor {
+		color.NoColor = true
+	}
+
+	err := common.SetupConfigWithoutSecrets(confFilePath, "")
+	if err != nil {
+		return fmt.Errorf("unable to set up global agent configuration: %v", err)
+	}
+
+	err = config.SetupLogger(loggerName, config.GetEnvDefault("DD_LOG_LEVEL", "off"), "", "", false, true, false)
+	if err != nil {
+		fmt.Printf("Cannot setup logger, exiting: %v\n", err)
+		return err
+	}
+
+	var errorCount, warningCount int
+
+	configFile := config.Datadog.ConfigFileUsed()
+	fmt.Printf("=== %s ===\n", color.BlueString(configFile))
+	issues, err := config.ValidateFile(configFile)
+	if err != nil {
+		return fmt.Errorf("unable to validate %s: %v", configFile, err)
+	}
+	for _, issue := range issues {
+		if issue.Severity == config.ValidationError {
+			errorCount++
+			fmt.Printf("%s: %s\n", color.RedString("Error"), issue.Message)
+		} else {
+			warningCount++
+			fmt.Printf("%s: %s\n", color.YellowString("Warning"), issue.Message)
+		}
+	}
+	if len(issues) == 0 {
+		fmt.Println(color.GreenString("OK"))
+	}
+
+	checkErrors, err := validateCheckConfigs()
+	if err != nil {
+		return err
+	}
+	errorCount += checkErrors
+
+	fmt.Printf("\n%d error(s), %d warning(s)\n", errorCount, warningCount)
+	if errorCount > 0 || (validateStrict && warningCount > 0) {
+		return fmt.Errorf("the configuration is not valid")
+	}
+	return nil
+}
+
+// validateCheckConfigs configures the instances of the core checks found in
+// the configuration files, without scheduling them, and returns the number of
+// errors found.
+func validateCheckConfigs() (int, error) {
+	// Configure needs a sender, initialize the aggregator without flushing anything
+	opts := aggregator.DefaultDemultiplexerOptions(nil)
+	opts.FlushInterval = 0
+	opts.UseNoopForwarder = true
+	opts.UseNoopEventPlatformForwarder = true
+	opts.UseOrchestratorForwarder = false
+	demux := aggregator.InitAndStartAgentDemultiplexer(opts, "")
+	defer demux.Stop(false)
+
+	providers.InitConfigFilesReader([]string{
+		config.Datadog.GetString("confd_path"),
+		filepath.Join(common.GetDistPath(), "conf.d"),
+		"",
+	})
+	configs, readErrors, err := providers.ReadConfigFiles(providers.GetAll)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the check configurations: %v", err)
+	}
+
+	fmt.Printf("\n=== %s ===\n", color.BlueString("Core checks"))
+	errorCount := 0
+
+	names := make([]string, 0, len(readErrors))
+	for name := range readErrors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errorCount++
+		fmt.Printf("%s: %s: %s\n", color.RedString("Error"), name, readErrors[name])
+	}
+
+	validated := 0
+	for _, c := range configs {
+		factory := corechecks.GetCheckFactory(c.Name)
+		// templates are only resolved by autodiscovery and python checks are
+		// configured by their own loader, skip them
+		if factory == nil || !c.IsCheckConfig() || c.IsTemplate() {
+			continue
+		}
+		for i, instance := range c.Instances {
+			validated++
+			if err := factory().Configure(instance, c.InitConfig, c.Source); err != nil {
+				errorCount++
+				fmt.Printf("%s: %s (instance #%d): %v\n", color.RedString("Error"), c.Name, i, err)
+			}
+		}
+	}
+
+	if errorCount == 0 {
+		fmt.Printf("%s: %d instance(s) validated\n", color.GreenString("OK"), validated)
+	}
+	return errorCount, nil
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/config/settings"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reloadCmd = &cobra.Command{
+		Use:   "reload",
+		Short: "Reload the configuration of a running Agent",
+		Long: `Read the configuration file again and apply the changes to the components
+of the running Agent supporting it. The changed settings requiring a restart are reported.
+Sending SIGHUP to the Agent process has the same effect.`,
+		RunE: reload,
+	}
+)
+
+func init() {
+	// attach the command to the root
+	AgentCmd.AddCommand(reloadCmd)
+}
+
+func reload(*cobra.Command, []string) error {
+	if err := setupConfig(); err != nil {
+		return err
+	}
+
+	c := util.GetClient(false) // FIX: get certificates right then make this true
+	ipcAddress, err := config.GetIPCAddress()
+	if err != nil {
+		return err
+	}
+	urlstr := fmt.Sprintf("https://%v:%v/agent/config/reload", ipcAddress, config.Datadog.GetInt("cmd_port"))
+
+	r, err := util.DoPost(c, urlstr, "application/json", bytes.NewBuffer([]byte{}))
+	if err != nil {
+		var errMap = make(map[string]string)
+		_ = json.Unmarshal(r, &errMap)
+		// If the error has been marshalled into a json object, check it and return it properly
+		if e, found := errMap["error"]; found {
+			err = fmt.Errorf(e)
+		}
+		return fmt.Errorf("Error reloading the configuration: %v", err)
+	}
+
+	var response settings.ReloadResponse
+	if err = json.Unmarshal(r, &response); err != nil {
+		return err
+	}
+
+	if len(response.Applied) == 0 && len(response.RestartRequired) == 0 && len(response.Errors) == 0 {
+		fmt.Println("No configuration change detected")
+		return nil
+	}
+	for _, key := range response.Applied {
+		fmt.Printf("%s: %s\n", color.GreenString("Applied"), key)
+	}
+	for _, key := range response.RestartRequired {
+		fmt.Printf("%s: %s\n", color.YellowString("Restart required"), key)
+	}
+
+	if len(response.Errors) > 0 {
+		keys := make([]string, 0, len(response.Errors))
+		for key := range response.Errors {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Printf("%s: %s: %s\n", color.RedString("Error"), key, response.Errors[key])
+		}
+		return fmt.Errorf("some configuration changes could not be applied")
+	}
+	return nil
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/embed/jmx"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	remoteconfig "github.com/DataDog/datadog-agent/pkg/config/remote/service"
+	commonsettings "github.com/DataDog/datadog-agent/pkg/config/settings"
 	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
 	"github.com/DataDog/datadog-agent/pkg/logs"
@@ -66,11 +67,13 @@ import (
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/embed"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/net"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/nvidia/jetson"
+	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/openmetrics"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/cpu"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/disk"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/filehandles"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/memory"
+	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/process"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/uptime"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/winkmem"
 	_ "github.com/DataDog/datadog-agent/pkg/collector/corechecks/system/winproc"
@@ -151,6 +154,21 @@ func run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Reload the configuration upon receiving SIGHUP
+	reloadCh := make(chan os.Signal, 1)
+	signal.Notify(reloadCh, syscall.SIGHUP)
+	go func() {
+		for range reloadCh {
+			log.Info("Received signal 'hangup', reloading the configuration...")
+			response, err := commonsettings.ReloadConfig()
+			if err != nil {
+				log.Errorf("Unable to reload the configuration: %v", err)
+				continue
+			}
+			log.Infof("Configuration reloaded, applied: %v, requiring a restart: %v", response.Applied, response.RestartRequired)
+		}
+	}()
+
 	select {
 	case err := <-stopCh:
 		return err
@@ -311,8 +329,9 @@ func StartAgent() error {
 		log.Errorf("Unable to initialize host metadata: %v", err)
 	}
 
-	// start remote configuration management
-	if config.Datadog.GetBool("remote_configuration.enabled") {
+	// start remote configuration management, unless the clients read their
+	// configurations from a local directory
+	if config.Datadog.GetBool("remote_configuration.enabled") && config.Datadog.GetString("remote_configuration.local_dir") == "" {
 		configService, err = remoteconfig.NewService()
 		if err != nil {
 			log.Errorf("Failed to initialize config management service: %s", err)
@@ -448,6 +467,11 @@ func StartAgent() error {
 		}
 	}
 
+	// register the components supporting configuration reloads
+	if err := initReloadHandlers(); err != nil {
+		log.Warnf("Can't initialize the configuration reload handlers: %v", err)
+	}
+
 	// start dependent services
 	go startDependentServices()
 
@@ -36,6 +36,8 @@ const jsonContentType = "application/json"
 
 type admissionFunc func([]byte, string, dynamic.Interface) ([]byte, error)
 
+type validationFunc func([]byte, string, dynamic.Interface) ([]string, error)
+
 type Server struct {
 	decoder runtime.Decoder
 	mux     *http.ServeMux
@@ -72,7 +74,20 @@ func (s *Server) initDecoder() {
 // Register must be called to register the desired webhook handlers before calling Run.
 func (s *Server) Register(uri string, f admissionFunc, dc dynamic.Interface) {
 	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
-		s.mutateHandler(w, r, f, dc)
+		s.handle(w, r, func(raw []byte, ns string) *admiv1.AdmissionResponse {
+			return mutationResponse(f(raw, ns, dc))
+		})
+	})
+}
+
+// RegisterValidation adds an admission webhook handler that doesn't mutate
+// objects but can deny them and return warnings.
+// RegisterValidation must be called to register the desired webhook handlers before calling Run.
+func (s *Server) RegisterValidation(uri string, f validationFunc, dc dynamic.Interface) {
+	s.mux.HandleFunc(uri, func(w http.ResponseWriter, r *http.Request) {
+		s.handle(w, r, func(raw []byte, ns string) *admiv1.AdmissionResponse {
+			return validationResponse(f(raw, ns, dc))
+		})
 	})
 }
 
@@ -104,9 +119,9 @@ func (s *Server) Run(mainCtx context.Context, client kubernetes.Interface) error
 	return server.Shutdown(shutdownCtx)
 }
 
-// mutateHandler contains the main logic responsible for handling mutation requests.
+// handle contains the main logic responsible for handling admission requests.
 // It supports both v1 and v1beta1 requests.
-func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, mutateFunc admissionFunc, dc dynamic.Interface) {
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, respond func([]byte, string) *admiv1.AdmissionResponse) {
 	metrics.WebhooksReceived.Inc()
 
 	start := time.Now()
@@ -150,8 +165,7 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, mutateFun
 		}
 		admissionReviewResp := &admiv1.AdmissionReview{}
 		admissionReviewResp.SetGroupVersionKind(*gvk)
-		jsonPatch, err := mutateFunc(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace, dc)
-		admissionReviewResp.Response = mutationResponse(jsonPatch, err)
+		admissionReviewResp.Response = respond(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace)
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	case admiv1beta1.SchemeGroupVersion.WithKind("AdmissionReview"):
@@ -161,8 +175,7 @@ func (s *Server) mutateHandler(w http.ResponseWriter, r *http.Request, mutateFun
 		}
 		admissionReviewResp := &admiv1beta1.AdmissionReview{}
 		admissionReviewResp.SetGroupVersionKind(*gvk)
-		jsonPatch, err := mutateFunc(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace, dc)
-		admissionReviewResp.Response = responseV1ToV1beta1(mutationResponse(jsonPatch, err))
+		admissionReviewResp.Response = responseV1ToV1beta1(respond(admissionReviewReq.Request.Object.Raw, admissionReviewReq.Request.Namespace))
 		admissionReviewResp.Response.UID = admissionReviewReq.Request.UID
 		response = admissionReviewResp
 	default:
@@ -203,6 +216,29 @@ func mutationResponse(jsonPatch []byte, err error) *admiv1.AdmissionResponse {
 	}
 }
 
+// validationResponse returns the adequate v1.AdmissionResponse based on the validation result.
+func validationResponse(warnings []string, err error) *admiv1.AdmissionResponse {
+	if err != nil {
+		log.Debugf("Denying the admission request: %v", err)
+
+		return &admiv1.AdmissionResponse{
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Reason:  metav1.StatusReasonInvalid,
+				Code:    http.StatusUnprocessableEntity,
+				Message: err.Error(),
+			},
+			Warnings: warnings,
+			Allowed:  false,
+		}
+	}
+
+	return &admiv1.AdmissionResponse{
+		Warnings: warnings,
+		Allowed:  true,
+	}
+}
+
 // responseV1ToV1beta1 converts a v1.AdmissionResponse into a v1beta1.AdmissionResponse.
 func responseV1ToV1beta1(resp *admiv1.AdmissionResponse) *admiv1beta1.AdmissionResponse {
 	var patchType *admiv1beta1.PatchType
@@ -26,6 +26,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/startstop"
+	"github.com/DataDog/datadog-agent/pkg/version"
 )
 
 const (
@@ -86,6 +87,16 @@ func startCompliance(stopper startstop.Stopper, apiCl *apiserver.APIClient, isLe
 		return err
 	}
 
+	sarifPath := coreconfig.Datadog.GetString("compliance_config.export.sarif_path")
+	junitPath := coreconfig.Datadog.GetString("compliance_config.export.junit_path")
+	if sarifPath != "" || junitPath != "" {
+		exportReporter := event.NewExportReporter(reporter, version.AgentVersion, sarifPath, junitPath)
+		exportReporter.Start(event.ExportInterval)
+		stopper.Add(exportReporter)
+
+		reporter = exportReporter
+	}
+
 	runner := runner.NewRunner()
 	stopper.Add(runner)
 
@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/process/events"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// eventsHandler returns the exec and exit events of the processes collected after the `since` sequence number.
+// Clients poll the endpoint with the sequence number of the last event they received.
+func eventsHandler(w http.ResponseWriter, r *http.Request) {
+	collector := events.Default()
+	if collector == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := io.WriteString(w, "process events collection is not running, set process_config.event_collection.enabled to true to enable it\n")
+		if err != nil {
+			_ = log.Error(err)
+		}
+		return
+	}
+
+	var since uint64
+	if s := r.URL.Query().Get("since"); s != "" {
+		var err error
+		since, err = strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			writeError(fmt.Errorf("invalid since parameter %q: %v", s, err), http.StatusBadRequest, w)
+			return
+		}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	err := e.Encode(collector.Events(since))
+	if err != nil {
+		writeError(err, http.StatusInternalServerError, w)
+		_ = log.Error(err)
+		return
+	}
+}
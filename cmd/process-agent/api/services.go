@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	ddconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/process/checks"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// servicesHandler returns the processes listening on sockets, as collected by the last run of the process check
+func servicesHandler(w http.ResponseWriter, _ *http.Request) {
+	if !ddconfig.Datadog.GetBool("process_config.listening_sockets.enabled") {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := io.WriteString(w, "listening sockets collection is disabled, set process_config.listening_sockets.enabled to true to enable it\n")
+		if err != nil {
+			_ = log.Error(err)
+		}
+		return
+	}
+
+	services, ok := checks.Process.GetServices()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, err := io.WriteString(w, fmt.Sprintf("%s check is not running or has not been scheduled yet\n", checks.Process.Name()))
+		if err != nil {
+			_ = log.Error(err)
+		}
+		return
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	err := e.Encode(services)
+	if err != nil {
+		writeError(err, http.StatusInternalServerError, w)
+		_ = log.Error(err)
+		return
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/metadata/host"
 	"github.com/DataDog/datadog-agent/pkg/pidfile"
 	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/events"
 	"github.com/DataDog/datadog-agent/pkg/process/statsd"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/tagger"
@@ -276,6 +277,16 @@ func runAgent(exit chan struct{}) {
 		}
 	}()
 
+	// Collect the exec and exit events of the processes, exposed by the API server
+	if ddconfig.Datadog.GetBool("process_config.event_collection.enabled") {
+		datadogEvents := ddconfig.Datadog.GetBool("process_config.event_collection.datadog_events.enabled")
+		if eventCollector, err := events.Start(cfg.Scrubber, datadogEvents); err != nil {
+			log.Errorf("Error starting the process events collection: %s", err)
+		} else {
+			defer eventCollector.Stop()
+		}
+	}
+
 	// Run API server
 	err = api.StartServer()
 	if err != nil {
@@ -25,6 +25,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/startstop"
+	"github.com/DataDog/datadog-agent/pkg/version"
 	"github.com/cihub/seelog"
 	"github.com/spf13/cobra"
 )
@@ -38,6 +39,8 @@ var (
 		overrideRegoInput string
 		dumpRegoInput     string
 		dumpReports       string
+		sarifOutput       string
+		junitOutput       string
 	}{}
 )
 
@@ -49,6 +52,8 @@ func setupCheckCmd(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&checkArgs.overrideRegoInput, "override-rego-input", "", "", "Rego input to use when running rego checks")
 	cmd.Flags().StringVarP(&checkArgs.dumpRegoInput, "dump-rego-input", "", "", "Path to file where to dump the Rego input JSON")
 	cmd.Flags().StringVarP(&checkArgs.dumpReports, "dump-reports", "", "", "Path to file where to dump reports")
+	cmd.Flags().StringVarP(&checkArgs.sarifOutput, "sarif-output", "", "", "Path to file where to write the results as a SARIF report")
+	cmd.Flags().StringVarP(&checkArgs.junitOutput, "junit-output", "", "", "Path to file where to write the results as a JUnit XML report")
 }
 
 // CheckCmd returns a cobra command to run security agent checks
@@ -126,7 +131,7 @@ func runCheck(cmd *cobra.Command, confPathArray []string, args []string) error {
 	stopper = startstop.NewSerialStopper()
 	defer stopper.Stop()
 
-	reporter, err := NewCheckReporter(stopper, checkArgs.report, checkArgs.dumpReports)
+	reporter, err := NewCheckReporter(stopper, checkArgs.report, checkArgs.dumpReports, checkArgs.sarifOutput, checkArgs.junitOutput)
 	if err != nil {
 		return err
 	}
@@ -193,9 +198,11 @@ type RunCheckReporter struct {
 	reporter        event.Reporter
 	events          map[string][]*event.Event
 	dumpReportsPath string
+	sarifPath       string
+	junitPath       string
 }
 
-func NewCheckReporter(stopper startstop.Stopper, report bool, dumpReportsPath string) (*RunCheckReporter, error) {
+func NewCheckReporter(stopper startstop.Stopper, report bool, dumpReportsPath, sarifPath, junitPath string) (*RunCheckReporter, error) {
 	r := &RunCheckReporter{}
 
 	if report {
@@ -215,6 +222,8 @@ func NewCheckReporter(stopper startstop.Stopper, report bool, dumpReportsPath st
 
 	r.events = make(map[string][]*event.Event)
 	r.dumpReportsPath = dumpReportsPath
+	r.sarifPath = sarifPath
+	r.junitPath = junitPath
 
 	return r, nil
 }
@@ -246,8 +255,21 @@ func (r *RunCheckReporter) dumpReports() error {
 			return err
 		}
 
-		return os.WriteFile(r.dumpReportsPath, reportsJSON, 0644)
+		if err := os.WriteFile(r.dumpReportsPath, reportsJSON, 0644); err != nil {
+			return err
+		}
 	}
+
+	if r.sarifPath != "" || r.junitPath != "" {
+		exportReporter := event.NewExportReporter(nil, version.AgentVersion, r.sarifPath, r.junitPath)
+		for _, ruleEvents := range r.events {
+			for _, e := range ruleEvents {
+				exportReporter.Report(e)
+			}
+		}
+		return exportReporter.Flush()
+	}
+
 	return nil
 }
 
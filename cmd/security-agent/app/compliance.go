@@ -24,6 +24,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/startstop"
+	"github.com/DataDog/datadog-agent/pkg/version"
 	ddgostatsd "github.com/DataDog/datadog-go/v5/statsd"
 )
 
@@ -117,6 +118,16 @@ func startCompliance(hostname string, stopper startstop.Stopper, statsdClient *d
 		return nil, err
 	}
 
+	sarifPath := coreconfig.Datadog.GetString("compliance_config.export.sarif_path")
+	junitPath := coreconfig.Datadog.GetString("compliance_config.export.junit_path")
+	if sarifPath != "" || junitPath != "" {
+		exportReporter := event.NewExportReporter(reporter, version.AgentVersion, sarifPath, junitPath)
+		exportReporter.Start(event.ExportInterval)
+		stopper.Add(exportReporter)
+
+		reporter = exportReporter
+	}
+
 	runner := runner.NewRunner()
 	stopper.Add(runner)
 
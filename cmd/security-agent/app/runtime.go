@@ -183,6 +183,17 @@ var (
 		outputPath string
 	}{}
 
+	evaluatePolicyCmd = &cobra.Command{
+		Use:   "evaluate",
+		Short: "Evaluate a policy against recorded events",
+		RunE:  evaluatePolicy,
+	}
+
+	evaluatePolicyArgs = struct {
+		policyFile string
+		eventFile  string
+	}{}
+
 	commonPolicyCmd = &cobra.Command{
 		Use:   "policy",
 		Short: "Policy related commands",
@@ -290,6 +301,13 @@ func init() {
 	commonPolicyCmd.AddCommand(commonCheckPoliciesCmd)
 
 	commonPolicyCmd.AddCommand(commonReloadPoliciesCmd)
+
+	evaluatePolicyCmd.Flags().StringVar(&evaluatePolicyArgs.policyFile, "policy-file", "", "Path to the policy file to evaluate")
+	evaluatePolicyCmd.Flags().StringVar(&evaluatePolicyArgs.eventFile, "event-file", "", "Path to the file of recorded events, as sent by the agent, one JSON event after the other")
+	_ = evaluatePolicyCmd.MarkFlagRequired("policy-file")
+	_ = evaluatePolicyCmd.MarkFlagRequired("event-file")
+	commonPolicyCmd.AddCommand(evaluatePolicyCmd)
+
 	runtimeCmd.AddCommand(commonPolicyCmd)
 
 	dumpNetworkNamespaceCmd.Flags().BoolVar(&dumpNetworkNamespaceArgs.snapshotInterfaces, "snapshot-interfaces", true, "snapshot the interfaces of each network namespace during the dump")
@@ -522,15 +540,8 @@ func generateGraphFromActivityDump(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func checkPoliciesInner(dir string) error {
-	cfg := &secconfig.Config{
-		PoliciesDir:         dir,
-		EnableKernelFilters: true,
-		EnableApprovers:     true,
-		EnableDiscarders:    true,
-		PIDCacheSize:        1,
-	}
-
+// newPolicyRuleSet returns a rule set with all the event types enabled, as loaded by the probe
+func newPolicyRuleSet() *rules.RuleSet {
 	// enabled all the rules
 	enabled := map[eval.EventType]bool{"*": true}
 
@@ -542,23 +553,45 @@ func checkPoliciesInner(dir string) error {
 		WithEventTypeEnabled(enabled).
 		WithReservedRuleIDs(sprobe.AllCustomRuleIDs()).
 		WithLegacyFields(model.SECLLegacyFields).
+		WithCorrelationScopes(map[rules.Scope]rules.CorrelationScopeFunc{
+			"process":      model.ProcessScopeKeys,
+			"process_tree": model.ProcessTreeScopeKeys,
+			"container":    model.ContainerScopeKeys,
+		}).
 		WithLogger(&seclog.PatternLogger{})
 
 	model := &model.Model{}
-	ruleSet := rules.NewRuleSet(model, model.NewEvent, &opts)
+	return rules.NewRuleSet(model, model.NewEvent, &opts)
+}
 
-	if err := rules.LoadPolicies(cfg.PoliciesDir, ruleSet); err.ErrorOrNil() != nil {
-		return err
+// applyPolicyRuleSet returns the kernel filters and the approvers that the probe would apply for a rule set
+func applyPolicyRuleSet(dir string, ruleSet *rules.RuleSet) (*sprobe.Report, error) {
+	cfg := &secconfig.Config{
+		PoliciesDir:         dir,
+		EnableKernelFilters: true,
+		EnableApprovers:     true,
+		EnableDiscarders:    true,
+		PIDCacheSize:        1,
 	}
 
 	approvers, err := ruleSet.GetApprovers(sprobe.GetCapababilities())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	rsa := sprobe.NewRuleSetApplier(cfg, nil)
 
-	report, err := rsa.Apply(ruleSet, approvers)
+	return rsa.Apply(ruleSet, approvers)
+}
+
+func checkPoliciesInner(dir string) error {
+	ruleSet := newPolicyRuleSet()
+
+	if err := rules.LoadPolicies(dir, ruleSet); err.ErrorOrNil() != nil {
+		return err
+	}
+
+	report, err := applyPolicyRuleSet(dir, ruleSet)
 	if err != nil {
 		return err
 	}
@@ -737,3 +770,72 @@ func downloadPolicy(cmd *cobra.Command, args []string) error {
 	_, err = outputWriter.Write(resBytes)
 	return err
 }
+
+// policyEvaluationReport is the output of the policy evaluate command
+type policyEvaluationReport struct {
+	Policies *sprobe.Report                  `json:"policies"`
+	Events   []*sprobe.EventEvaluationReport `json:"events"`
+}
+
+func evaluatePolicy(cmd *cobra.Command, args []string) error {
+	ruleSet := newPolicyRuleSet()
+
+	f, err := os.Open(evaluatePolicyArgs.policyFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	policy, err := rules.LoadPolicy(f, path.Base(evaluatePolicyArgs.policyFile))
+	if err != nil {
+		return err
+	}
+
+	macros, ruleDefs, mErr := policy.GetValidMacroAndRules()
+	if mErr.ErrorOrNil() != nil {
+		return mErr
+	}
+	if err := ruleSet.AddMacros(macros); err.ErrorOrNil() != nil {
+		return err
+	}
+	if err := ruleSet.AddRules(ruleDefs); err.ErrorOrNil() != nil {
+		return err
+	}
+
+	policies, err := applyPolicyRuleSet(path.Dir(evaluatePolicyArgs.policyFile), ruleSet)
+	if err != nil {
+		return err
+	}
+
+	events, err := os.Open(evaluatePolicyArgs.eventFile)
+	if err != nil {
+		return err
+	}
+	defer events.Close()
+
+	report := policyEvaluationReport{
+		Policies: policies,
+		Events:   []*sprobe.EventEvaluationReport{},
+	}
+
+	evaluator := sprobe.NewReplayEvaluator(ruleSet)
+
+	decoder := json.NewDecoder(events)
+	for i := 0; decoder.More(); i++ {
+		var event sprobe.EventSerializer
+		if err := decoder.Decode(&event); err != nil {
+			return errors.Wrapf(err, "failed to decode event %d", i)
+		}
+
+		eventReport, err := evaluator.Evaluate(&event)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate event %d", i)
+		}
+		report.Events = append(report.Events, eventReport)
+	}
+
+	content, _ := json.MarshalIndent(report, "", "\t")
+	fmt.Printf("%s\n", string(content))
+
+	return nil
+}
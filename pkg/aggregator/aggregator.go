@@ -373,7 +373,9 @@ func (agg *BufferedAggregator) handleSenderSample(ss senderMetricSample) {
 	defer agg.mu.Unlock()
 
 	if checkSampler, ok := agg.checkSamplers[ss.id]; ok {
-		if ss.commit {
+		if ss.histogramConfig != nil {
+			checkSampler.setHistogramConfig(ss.histogramConfig)
+		} else if ss.commit {
 			checkSampler.commit(timeNowNano())
 		} else {
 			ss.metricSample.Tags = util.SortUniqInPlace(ss.metricSample.Tags)
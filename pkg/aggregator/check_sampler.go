@@ -25,6 +25,7 @@ type CheckSampler struct {
 	metrics         metrics.CheckMetrics
 	sketchMap       sketchMap
 	lastBucketValue map[ckey.ContextKey]int64
+	histogramConfig *metrics.HistogramConfig
 }
 
 // newCheckSampler returns a newly initialized CheckSampler
@@ -39,9 +40,21 @@ func newCheckSampler(expirationCount int, expireMetrics bool, statefulTimeout ti
 	}
 }
 
+func (cs *CheckSampler) setHistogramConfig(config *metrics.HistogramConfig) {
+	cs.histogramConfig = config
+	cs.metrics.SetHistogramConfig(config)
+}
+
 func (cs *CheckSampler) addSample(metricSample *metrics.MetricSample) {
 	contextKey := cs.contextResolver.trackContext(metricSample)
 
+	if metricSample.Mtype == metrics.HistogramType && cs.histogramConfig.IsDistribution(metricSample.Name) {
+		if !cs.sketchMap.insert(int64(metricSample.Timestamp), contextKey, metricSample.Value, metricSample.SampleRate) {
+			log.Debugf("Ignoring sample '%s' on host '%s' and tags '%s': sample with value '%v'", metricSample.Name, metricSample.Host, metricSample.Tags, metricSample.Value)
+		}
+		return
+	}
+
 	if err := cs.metrics.AddSample(contextKey, metricSample, metricSample.Timestamp, 1); err != nil {
 		log.Debugf("Ignoring sample '%s' on host '%s' and tags '%s': %s", metricSample.Name, metricSample.Host, metricSample.Tags, err)
 	}
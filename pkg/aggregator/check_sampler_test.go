@@ -211,6 +211,56 @@ func TestHistogramCountSampling(t *testing.T) {
 	testWithTagsStore(t, testHistogramCountSampling)
 }
 
+func testCheckHistogramConfigSampling(t *testing.T, store *tags.Store) {
+	asDistribution := true
+	checkSampler := newCheckSampler(1, true, 1*time.Second, store)
+	checkSampler.setHistogramConfig(&metrics.HistogramConfig{
+		Aggregates:  []string{"max"},
+		Percentiles: []int{99},
+		Metrics: map[string]metrics.HistogramMetricConfig{
+			"my.metric.dist": {AsDistribution: &asDistribution},
+		},
+	})
+
+	for i, v := range []float64{1, 10, 5} {
+		checkSampler.addSample(&metrics.MetricSample{
+			Name:       "my.metric.name",
+			Value:      v,
+			Mtype:      metrics.HistogramType,
+			Tags:       []string{"foo", "bar"},
+			SampleRate: 1,
+			Timestamp:  12345.0 + float64(i),
+		})
+		checkSampler.addSample(&metrics.MetricSample{
+			Name:       "my.metric.dist",
+			Value:      v,
+			Mtype:      metrics.HistogramType,
+			Tags:       []string{"foo", "bar"},
+			SampleRate: 1,
+			Timestamp:  12345.0,
+		})
+	}
+
+	checkSampler.commit(12349.0)
+	series, sketches := checkSampler.flush()
+
+	require.Len(t, series, 2)
+	names := []string{series[0].Name, series[1].Name}
+	assert.ElementsMatch(t, []string{"my.metric.name.max", "my.metric.name.99percentile"}, names)
+	for _, serie := range series {
+		assert.Equal(t, 10., serie.Points[0].Value)
+	}
+
+	require.Len(t, sketches, 1)
+	assert.Equal(t, "my.metric.dist", sketches[0].Name)
+	require.Len(t, sketches[0].Points, 1)
+	assert.EqualValues(t, 3, sketches[0].Points[0].Sketch.Basic.Cnt)
+	assert.EqualValues(t, 10, sketches[0].Points[0].Sketch.Basic.Max)
+}
+func TestCheckHistogramConfigSampling(t *testing.T) {
+	testWithTagsStore(t, testCheckHistogramConfigSampling)
+}
+
 func testCheckHistogramBucketSampling(t *testing.T, store *tags.Store) {
 	checkSampler := newCheckSampler(1, true, 1*time.Second, store)
 
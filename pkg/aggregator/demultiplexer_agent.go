@@ -6,6 +6,7 @@
 package aggregator
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -216,6 +217,18 @@ func (d *AgentDemultiplexer) AddAgentStartupTelemetry(agentVersion string) {
 	}
 }
 
+// UpdateForwarderAPIKeys replaces the API keys used by the shared forwarder for each domain.
+func (d *AgentDemultiplexer) UpdateForwarderAPIKeys(keysPerDomain map[string][]string) error {
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	fwd, ok := d.dataOutputs.forwarders.shared.(*forwarder.DefaultForwarder)
+	if !ok {
+		return errors.New("the forwarder doesn't support updating its API keys")
+	}
+	return fwd.UpdateAPIKeys(keysPerDomain)
+}
+
 // Run runs all demultiplexer parts
 func (d *AgentDemultiplexer) Run() {
 	if !d.options.DontStartForwarders {
@@ -96,6 +96,11 @@ func (m *MockSender) FinalizeCheckServiceTag() {
 	m.Called()
 }
 
+//SetCheckHistogramConfig enables the setting of check histogram config mock call.
+func (m *MockSender) SetCheckHistogramConfig(config *metrics.HistogramConfig) {
+	m.Called(config)
+}
+
 //GetSenderStats enables the get metric stats mock call.
 func (m *MockSender) GetSenderStats() check.SenderStats {
 	m.Called()
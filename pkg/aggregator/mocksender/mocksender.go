@@ -81,6 +81,7 @@ func (m *MockSender) SetupAcceptAll() {
 	m.On("SetCheckCustomTags", mock.AnythingOfType("[]string")).Return()
 	m.On("SetCheckService", mock.AnythingOfType("string")).Return()
 	m.On("FinalizeCheckServiceTag").Return()
+	m.On("SetCheckHistogramConfig", mock.AnythingOfType("*metrics.HistogramConfig")).Return()
 	m.On("Commit").Return()
 }
 
@@ -37,6 +37,7 @@ type Sender interface {
 	SetCheckCustomTags(tags []string)
 	SetCheckService(service string)
 	FinalizeCheckServiceTag()
+	SetCheckHistogramConfig(config *metrics.HistogramConfig)
 	OrchestratorMetadata(msgs []serializer.ProcessMessageBody, clusterID string, nodeType int)
 	ContainerLifecycleEvent(msgs []serializer.ContainerLifecycleMessage)
 }
@@ -71,6 +72,8 @@ type senderMetricSample struct {
 	id           check.ID
 	metricSample *metrics.MetricSample
 	commit       bool
+	// histogramConfig, when set, updates the histogram configuration of the check sampler
+	histogramConfig *metrics.HistogramConfig
 }
 
 type senderHistogramBucket struct {
@@ -198,11 +201,17 @@ func (s *checkSender) FinalizeCheckServiceTag() {
 	}
 }
 
+// SetCheckHistogramConfig sets the histogram aggregation overrides configured on the check instance.
+// Histogram samples sent afterwards are aggregated according to it.
+func (s *checkSender) SetCheckHistogramConfig(config *metrics.HistogramConfig) {
+	s.smsOut <- senderMetricSample{s.id, &metrics.MetricSample{}, false, config}
+}
+
 // Commit commits the metric samples & histogram buckets that were added during a check run
 // Should be called at the end of every check run
 func (s *checkSender) Commit() {
 	// we use a metric sample to commit both for metrics & sketches
-	s.smsOut <- senderMetricSample{s.id, &metrics.MetricSample{}, true}
+	s.smsOut <- senderMetricSample{s.id, &metrics.MetricSample{}, true, nil}
 	s.cyclemetricStats()
 }
 
@@ -222,7 +231,7 @@ func (s *checkSender) cyclemetricStats() {
 // SendRawMetricSample sends the raw sample
 // Useful for testing - submitting precomputed samples.
 func (s *checkSender) SendRawMetricSample(sample *metrics.MetricSample) {
-	s.smsOut <- senderMetricSample{s.id, sample, false}
+	s.smsOut <- senderMetricSample{s.id, sample, false, nil}
 }
 
 func (s *checkSender) sendMetricSample(metric string, value float64, hostname string, tags []string, mType metrics.MetricType, flushFirstValue bool) {
@@ -245,7 +254,7 @@ func (s *checkSender) sendMetricSample(metric string, value float64, hostname st
 		metricSample.Host = s.defaultHostname
 	}
 
-	s.smsOut <- senderMetricSample{s.id, metricSample, false}
+	s.smsOut <- senderMetricSample{s.id, metricSample, false, nil}
 
 	s.statsLock.Lock()
 	s.metricStats.MetricSamples++
@@ -18,10 +18,20 @@ import (
 )
 
 const (
-	openmetricsCheckName  = "openmetrics"
-	openmetricsInitConfig = "{}"
+	openmetricsCheckName     = "openmetrics"
+	openmetricsCoreCheckName = "openmetrics_core"
+	openmetricsInitConfig    = "{}"
 )
 
+// getOpenmetricsCheckName returns the name of the check to schedule, the native
+// check being used when `prometheus_scrape.use_core_check` is enabled
+func getOpenmetricsCheckName() string {
+	if config.Datadog.GetBool("prometheus_scrape.use_core_check") {
+		return openmetricsCoreCheckName
+	}
+	return openmetricsCheckName
+}
+
 // buildInstances generates check config instances based on the Prometheus config and the object annotations
 // The second returned value is true if more than one instance is found
 func buildInstances(pc *types.PrometheusCheck, annotations map[string]string, namespacedName string) ([]integration.Data, bool) {
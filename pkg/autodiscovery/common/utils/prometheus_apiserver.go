@@ -38,7 +38,7 @@ func ConfigsForService(pc *types.PrometheusCheck, svc *v1.Service) []integration
 	if found {
 		serviceID := apiserver.EntityForService(svc)
 		configs = append(configs, integration.Config{
-			Name:          openmetricsCheckName,
+			Name:          getOpenmetricsCheckName(),
 			InitConfig:    integration.Data(openmetricsInitConfig),
 			Instances:     instances,
 			ClusterCheck:  true,
@@ -68,7 +68,7 @@ func ConfigsForServiceEndpoints(pc *types.PrometheusCheck, svc *v1.Service, ep *
 
 				epConfig := integration.Config{
 					ServiceID:     endpointsID,
-					Name:          openmetricsCheckName,
+					Name:          getOpenmetricsCheckName(),
 					InitConfig:    integration.Data(openmetricsInitConfig),
 					Instances:     instances,
 					ClusterCheck:  true,
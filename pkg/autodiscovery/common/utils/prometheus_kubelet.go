@@ -34,7 +34,7 @@ func ConfigsForPod(pc *types.PrometheusCheck, pod *kubelet.Pod) []integration.Co
 				continue
 			}
 			configs = append(configs, integration.Config{
-				Name:          openmetricsCheckName,
+				Name:          getOpenmetricsCheckName(),
 				InitConfig:    integration.Data(openmetricsInitConfig),
 				Instances:     instances,
 				Provider:      names.PrometheusPods,
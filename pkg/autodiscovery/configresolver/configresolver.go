@@ -36,6 +36,14 @@ var templateVariables = map[string]variableGetter{
 	"kube":     getAdditionalTplVariables,
 }
 
+// IsSupportedTemplateVariable returns whether a template variable can be
+// resolved, name being the part before the first underscore, e.g. env for
+// %%env_FOO%%.
+func IsSupportedTemplateVariable(name string) bool {
+	_, found := templateVariables[name]
+	return found
+}
+
 // SubstituteTemplateEnvVars replaces %%ENV_VARIABLE%% from environment
 // variables in the config init, instances, and logs config.
 // When there is an error, it continues replacing. When there are multiple
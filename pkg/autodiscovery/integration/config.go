@@ -10,6 +10,7 @@ import (
 	"hash/fnv"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/twmb/murmur3"
 	yaml "gopkg.in/yaml.v2"
@@ -84,6 +85,9 @@ type Config struct {
 	// NodeName is node name in case of an endpoint check backed by a pod
 	NodeName string `json:"node_name"` // (include in digest: true)
 
+	// NodeAffinity restricts the nodes a cluster check can be dispatched to
+	NodeAffinity *NodeAffinity `json:"node_affinity,omitempty"` // (include in digest: true)
+
 	// Source is the source of the configuration
 	Source string `json:"source"` // (include in digest: false)
 
@@ -107,6 +111,23 @@ type CommonInstanceConfig struct {
 	Service               string   `yaml:"service"`
 	Name                  string   `yaml:"name"`
 	Namespace             string   `yaml:"namespace"`
+
+	CronSchedule                string `yaml:"cron_schedule,omitempty"`
+	MinCollectionIntervalJitter int    `yaml:"min_collection_interval_jitter,omitempty"`
+	CheckTimeout                int    `yaml:"check_timeout,omitempty"`
+	ConcurrencyGroup            string `yaml:"concurrency_group,omitempty"`
+
+	HistogramAggregates     []string                           `yaml:"histogram_aggregates,omitempty"`
+	HistogramPercentiles    []float64                          `yaml:"histogram_percentiles,omitempty"`
+	HistogramAsDistribution bool                               `yaml:"histogram_as_distribution,omitempty"`
+	HistogramMetrics        map[string]HistogramInstanceConfig `yaml:"histogram_metrics,omitempty"`
+}
+
+// HistogramInstanceConfig holds the histogram aggregation overrides of a single metric
+type HistogramInstanceConfig struct {
+	Aggregates     []string  `yaml:"aggregates,omitempty"`
+	Percentiles    []float64 `yaml:"percentiles,omitempty"`
+	AsDistribution *bool     `yaml:"as_distribution,omitempty"`
 }
 
 // CommonGlobalConfig holds the reserved fields for the yaml init_config data
@@ -114,6 +135,71 @@ type CommonGlobalConfig struct {
 	Service string `yaml:"service"`
 }
 
+// NodeAffinity restricts the nodes a cluster check can be dispatched to,
+// based on the labels of the Kubernetes nodes of the agents.
+type NodeAffinity struct {
+	// Zone is the availability zone of the nodes, matched against the
+	// topology.kubernetes.io/zone label or its deprecated beta equivalent.
+	Zone string `json:"zone,omitempty" yaml:"zone,omitempty"`
+	// NodeSelector is a set of labels the nodes must have.
+	NodeSelector map[string]string `json:"node_selector,omitempty" yaml:"node_selector,omitempty"`
+}
+
+// Zone labels of the Kubernetes nodes, by order of precedence
+var zoneLabels = []string{
+	"topology.kubernetes.io/zone",
+	"failure-domain.beta.kubernetes.io/zone",
+}
+
+// Matches returns whether a node with the given labels satisfies the affinity.
+// A nil affinity matches every node.
+func (a *NodeAffinity) Matches(labels map[string]string) bool {
+	if a == nil {
+		return true
+	}
+
+	if a.Zone != "" {
+		zone := ""
+		for _, label := range zoneLabels {
+			if value, found := labels[label]; found {
+				zone = value
+				break
+			}
+		}
+		if zone != a.Zone {
+			return false
+		}
+	}
+
+	for key, value := range a.NodeSelector {
+		if labels[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns a stable representation of the affinity
+func (a *NodeAffinity) String() string {
+	if a == nil {
+		return ""
+	}
+
+	keys := make([]string, 0, len(a.NodeSelector))
+	for key := range a.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	selector := make([]string, 0, len(keys))
+	for _, key := range keys {
+		selector = append(selector, key+"="+a.NodeSelector[key])
+	}
+
+	return fmt.Sprintf("zone=%s,selector=%s", a.Zone, strings.Join(selector, ","))
+}
+
 // AdvancedADIdentifier contains user-defined autodiscovery information
 // It replaces ADIdentifiers for advanced use-cases. Typically, file-based k8s service and endpoint checks.
 type AdvancedADIdentifier struct {
@@ -392,6 +478,9 @@ func (c *Config) Digest() string {
 	h.Write([]byte(c.LogsConfig))                                  //nolint:errcheck
 	h.Write([]byte(c.ServiceID))                                   //nolint:errcheck
 	h.Write([]byte(strconv.FormatBool(c.IgnoreAutodiscoveryTags))) //nolint:errcheck
+	if c.NodeAffinity != nil {
+		h.Write([]byte(c.NodeAffinity.String())) //nolint:errcheck
+	}
 
 	return strconv.FormatUint(h.Sum64(), 16)
 }
@@ -415,6 +504,9 @@ func (c *Config) FastDigest() uint64 {
 	_, _ = h.Write([]byte(c.LogsConfig))
 	_, _ = h.Write([]byte(c.ServiceID))
 	_, _ = h.Write([]byte(strconv.FormatBool(c.IgnoreAutodiscoveryTags)))
+	if c.NodeAffinity != nil {
+		_, _ = h.Write([]byte(c.NodeAffinity.String()))
+	}
 
 	return h.Sum64()
 }
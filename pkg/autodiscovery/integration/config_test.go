@@ -236,6 +236,41 @@ func TestDigest(t *testing.T) {
 
 	// assert the ClusterCheck field is not taken into account
 	assert.NotEqual(t, simpleConfig.Digest(), simpleIngoreADTagsConfig.Digest())
+
+	simpleNodeAffinityConfig := &Config{
+		Name:         "foo",
+		InitConfig:   Data(""),
+		NodeAffinity: &NodeAffinity{Zone: "us-east-1a", NodeSelector: map[string]string{"disktype": "ssd", "pool": "db"}},
+	}
+	sameNodeAffinityConfig := &Config{
+		Name:         "foo",
+		InitConfig:   Data(""),
+		NodeAffinity: &NodeAffinity{Zone: "us-east-1a", NodeSelector: map[string]string{"pool": "db", "disktype": "ssd"}},
+	}
+
+	// assert the NodeAffinity field is taken into account, regardless of the selector order
+	assert.NotEqual(t, simpleConfig.Digest(), simpleNodeAffinityConfig.Digest())
+	assert.Equal(t, simpleNodeAffinityConfig.Digest(), sameNodeAffinityConfig.Digest())
+}
+
+func TestNodeAffinityMatches(t *testing.T) {
+	labels := map[string]string{
+		"topology.kubernetes.io/zone": "us-east-1a",
+		"disktype":                    "ssd",
+	}
+
+	var noAffinity *NodeAffinity
+	assert.True(t, noAffinity.Matches(labels))
+	assert.True(t, noAffinity.Matches(nil))
+
+	assert.True(t, (&NodeAffinity{Zone: "us-east-1a"}).Matches(labels))
+	assert.False(t, (&NodeAffinity{Zone: "us-east-1b"}).Matches(labels))
+	assert.False(t, (&NodeAffinity{Zone: "us-east-1a"}).Matches(nil))
+	assert.True(t, (&NodeAffinity{Zone: "us-east-1a"}).Matches(map[string]string{"failure-domain.beta.kubernetes.io/zone": "us-east-1a"}))
+
+	assert.True(t, (&NodeAffinity{NodeSelector: map[string]string{"disktype": "ssd"}}).Matches(labels))
+	assert.False(t, (&NodeAffinity{NodeSelector: map[string]string{"disktype": "hdd"}}).Matches(labels))
+	assert.False(t, (&NodeAffinity{Zone: "us-east-1a", NodeSelector: map[string]string{"pool": "db"}}).Matches(labels))
 }
 
 func TestGetNameForInstance(t *testing.T) {
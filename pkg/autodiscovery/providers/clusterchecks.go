@@ -16,6 +16,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/errors"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -31,6 +32,7 @@ type ClusterChecksConfigProvider struct {
 	lastChange       int64
 	identifier       string
 	flushedConfigs   bool
+	nodeLabels       map[string]string
 }
 
 // NewClusterChecksConfigProvider returns a new ConfigProvider collecting
@@ -104,8 +106,20 @@ func (c *ClusterChecksConfigProvider) IsUpToDate(ctx context.Context) (bool, err
 		}
 	}
 
+	// Node labels are sent to allow the cluster-agent to honor the
+	// node affinity of the configurations, retry until they are found
+	if c.nodeLabels == nil {
+		labels, err := hostinfo.GetNodeLabels(ctx)
+		if err != nil {
+			log.Debugf("Cannot get node labels, will retry later: %v", err)
+		} else {
+			c.nodeLabels = labels
+		}
+	}
+
 	status := types.NodeStatus{
 		LastChange: c.lastChange,
+		Labels:     c.nodeLabels,
 	}
 
 	reply, err := c.dcaClient.PostClusterCheckStatus(ctx, c.identifier, status)
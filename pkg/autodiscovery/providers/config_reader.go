@@ -30,6 +30,7 @@ type configFormat struct {
 	ADIdentifiers           []string                           `yaml:"ad_identifiers"`
 	AdvancedADIdentifiers   []integration.AdvancedADIdentifier `yaml:"advanced_ad_identifiers"`
 	ClusterCheck            bool                               `yaml:"cluster_check"`
+	NodeAffinity            *integration.NodeAffinity          `yaml:"node_affinity"`
 	InitConfig              interface{}                        `yaml:"init_config"`
 	MetricConfig            interface{}                        `yaml:"jmx_metrics"`
 	LogsConfig              interface{}                        `yaml:"logs"`
@@ -391,8 +392,9 @@ func GetIntegrationConfigFromFile(name, fpath string) (integration.Config, error
 	conf.ADIdentifiers = cf.ADIdentifiers
 	conf.AdvancedADIdentifiers = cf.AdvancedADIdentifiers
 
-	// Copy cluster_check status
+	// Copy cluster_check status and node affinity
 	conf.ClusterCheck = cf.ClusterCheck
+	conf.NodeAffinity = cf.NodeAffinity
 
 	// Copy ignore_autodiscovery_tags parameter
 	conf.IgnoreAutodiscoveryTags = cf.IgnoreAutodiscoveryTags
@@ -59,6 +59,11 @@ func TestGetIntegrationConfig(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, config.AdvancedADIdentifiers, []integration.AdvancedADIdentifier{{KubeService: integration.KubeNamespacedName{Name: "svc-name", Namespace: "svc-ns"}}})
 
+	// node affinity
+	config, err = GetIntegrationConfigFromFile("foo", "tests/node_affinity.yaml")
+	require.Nil(t, err)
+	assert.Equal(t, &integration.NodeAffinity{Zone: "us-east-1a", NodeSelector: map[string]string{"pool": "db"}}, config.NodeAffinity)
+
 	// autodiscovery: check if we correctly refuse to load if a 'docker_images' section is present
 	config, err = GetIntegrationConfigFromFile("foo", "tests/ad_deprecated.yaml")
 	assert.NotNil(t, err)
@@ -70,12 +75,12 @@ func TestReadConfigFiles(t *testing.T) {
 
 	configs, errors, err := ReadConfigFiles(GetAll)
 	require.Nil(t, err)
-	require.Equal(t, 17, len(configs))
+	require.Equal(t, 18, len(configs))
 	require.Equal(t, 3, len(errors))
 
 	configs, _, err = ReadConfigFiles(WithoutAdvancedAD)
 	require.Nil(t, err)
-	require.Equal(t, 16, len(configs))
+	require.Equal(t, 17, len(configs))
 
 	configs, _, err = ReadConfigFiles(WithAdvancedADOnly)
 	require.Nil(t, err)
@@ -50,6 +50,31 @@ func buildLabelSelectors(useNamespaceSelector bool) (namespaceSelector, objectSe
 	return nil, &labelSelector
 }
 
+// buildValidationLabelSelectors returns the validating webhooks object selector.
+// Validation applies to every pod that isn't explicitly filtered-out, regardless
+// of admission_controller.mutate_unlabelled.
+func buildValidationLabelSelectors(useNamespaceSelector bool) (namespaceSelector, objectSelector *metav1.LabelSelector) {
+	labelSelector := metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      common.EnabledLabelKey,
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"false"},
+			},
+		},
+	}
+
+	if config.Datadog.GetBool("admission_controller.add_aks_selectors") {
+		return aksSelectors(useNamespaceSelector, labelSelector)
+	}
+
+	if useNamespaceSelector {
+		return &labelSelector, nil
+	}
+
+	return nil, &labelSelector
+}
+
 // aksSelectors takes a label selector and builds a namespace and object
 // selector adapted for AKS. AKS adds automatically some selector requirements
 // if we don't, so we need to add them to avoid conflicts when updating the
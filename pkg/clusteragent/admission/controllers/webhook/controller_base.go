@@ -34,24 +34,25 @@ type Controller interface {
 // NewController returns the adequate implementation of the Controller interface.
 func NewController(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, admissionInterface admissionregistration.Interface, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config) Controller {
 	if config.useAdmissionV1() {
-		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
+		return NewControllerV1(client, secretInformer, admissionInterface.V1().MutatingWebhookConfigurations(), admissionInterface.V1().ValidatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
 	}
 
-	return NewControllerV1beta1(client, secretInformer, admissionInterface.V1beta1().MutatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
+	return NewControllerV1beta1(client, secretInformer, admissionInterface.V1beta1().MutatingWebhookConfigurations(), admissionInterface.V1beta1().ValidatingWebhookConfigurations(), isLeaderFunc, isLeaderNotif, config)
 }
 
 // controllerBase acts as a base class for ControllerV1 and ControllerV1beta1.
 // It contains the shared fields and provides shared methods.
 // For the nolint:structcheck see https://github.com/golangci/golangci-lint/issues/537
 type controllerBase struct {
-	clientSet      kubernetes.Interface //nolint:structcheck
-	config         Config
-	secretsLister  corelisters.SecretLister
-	secretsSynced  cache.InformerSynced //nolint:structcheck
-	webhooksSynced cache.InformerSynced //nolint:structcheck
-	queue          workqueue.RateLimitingInterface
-	isLeaderFunc   func() bool
-	isLeaderNotif  <-chan struct{}
+	clientSet                kubernetes.Interface //nolint:structcheck
+	config                   Config
+	secretsLister            corelisters.SecretLister
+	secretsSynced            cache.InformerSynced //nolint:structcheck
+	webhooksSynced           cache.InformerSynced //nolint:structcheck
+	validatingWebhooksSynced cache.InformerSynced //nolint:structcheck
+	queue                    workqueue.RateLimitingInterface
+	isLeaderFunc             func() bool
+	isLeaderNotif            <-chan struct{}
 }
 
 // enqueueOnLeaderNotif watches leader notifications and triggers a
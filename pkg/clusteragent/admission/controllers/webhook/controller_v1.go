@@ -35,12 +35,14 @@ import (
 // It uses the admissionregistration/v1 API.
 type ControllerV1 struct {
 	controllerBase
-	webhooksLister   admissionlisters.MutatingWebhookConfigurationLister
-	webhookTemplates []admiv1.MutatingWebhook
+	webhooksLister             admissionlisters.MutatingWebhookConfigurationLister
+	webhookTemplates           []admiv1.MutatingWebhook
+	validatingWebhooksLister   admissionlisters.ValidatingWebhookConfigurationLister
+	validatingWebhookTemplates []admiv1.ValidatingWebhook
 }
 
 // NewControllerV1 returns a new Webhook Controller using admissionregistration/v1.
-func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config) *ControllerV1 {
+func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.SecretInformer, webhookInformer admissioninformers.MutatingWebhookConfigurationInformer, validatingWebhookInformer admissioninformers.ValidatingWebhookConfigurationInformer, isLeaderFunc func() bool, isLeaderNotif <-chan struct{}, config Config) *ControllerV1 {
 	controller := &ControllerV1{}
 	controller.clientSet = client
 	controller.config = config
@@ -48,6 +50,8 @@ func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.S
 	controller.secretsSynced = secretInformer.Informer().HasSynced
 	controller.webhooksLister = webhookInformer.Lister()
 	controller.webhooksSynced = webhookInformer.Informer().HasSynced
+	controller.validatingWebhooksLister = validatingWebhookInformer.Lister()
+	controller.validatingWebhooksSynced = validatingWebhookInformer.Informer().HasSynced
 	controller.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "webhooks")
 	controller.isLeaderFunc = isLeaderFunc
 	controller.isLeaderNotif = isLeaderNotif
@@ -65,6 +69,12 @@ func NewControllerV1(client kubernetes.Interface, secretInformer coreinformers.S
 		DeleteFunc: controller.handleWebhook,
 	})
 
+	validatingWebhookInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.handleWebhook,
+		UpdateFunc: controller.handleValidatingWebhookUpdate,
+		DeleteFunc: controller.handleWebhook,
+	})
+
 	return controller
 }
 
@@ -76,7 +86,7 @@ func (c *ControllerV1) Run(stopCh <-chan struct{}) {
 	log.Infof("Starting webhook controller for secret %s/%s and webhook %s - Using admissionregistration/v1", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 	defer log.Infof("Stopping webhook controller for secret %s/%s and webhook %s", c.config.getSecretNs(), c.config.getSecretName(), c.config.getWebhookName())
 
-	if ok := cache.WaitForCacheSync(stopCh, c.secretsSynced, c.webhooksSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.secretsSynced, c.webhooksSynced, c.validatingWebhooksSynced); !ok {
 		return
 	}
 
@@ -121,13 +131,48 @@ func (c *ControllerV1) handleWebhookUpdate(oldObj, newObj interface{}) {
 	c.handleWebhook(newObj)
 }
 
-// reconcile creates/updates the webhook object on new events.
+// handleValidatingWebhookUpdate handles the new validating Webhook reported in update events.
+// It can be a callback function for update events.
+func (c *ControllerV1) handleValidatingWebhookUpdate(oldObj, newObj interface{}) {
+	if !c.isLeaderFunc() {
+		return
+	}
+
+	newWebhook, ok := newObj.(*admiv1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", newObj)
+		return
+	}
+
+	oldWebhook, ok := oldObj.(*admiv1.ValidatingWebhookConfiguration)
+	if !ok {
+		log.Debugf("Expected ValidatingWebhookConfiguration object, got: %v", oldObj)
+		return
+	}
+
+	if newWebhook.ResourceVersion == oldWebhook.ResourceVersion {
+		return
+	}
+
+	c.handleWebhook(newObj)
+}
+
+// reconcile creates/updates the webhook objects on new events.
 func (c *ControllerV1) reconcile() error {
 	secret, err := c.getSecret()
 	if err != nil {
 		return err
 	}
 
+	if err := c.reconcileMutatingWebhook(secret); err != nil {
+		return err
+	}
+
+	return c.reconcileValidatingWebhook(secret)
+}
+
+// reconcileMutatingWebhook creates/updates the MutatingWebhookConfiguration object.
+func (c *ControllerV1) reconcileMutatingWebhook(secret *corev1.Secret) error {
 	webhook, err := c.webhooksLister.Get(c.config.getWebhookName())
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -180,6 +225,74 @@ func (c *ControllerV1) newWebhooks(secret *corev1.Secret) []admiv1.MutatingWebho
 	return webhooks
 }
 
+// reconcileValidatingWebhook creates/updates the ValidatingWebhookConfiguration object,
+// or deletes it if no validation is enabled.
+func (c *ControllerV1) reconcileValidatingWebhook(secret *corev1.Secret) error {
+	webhook, err := c.validatingWebhooksLister.Get(c.config.getWebhookName())
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if len(c.validatingWebhookTemplates) == 0 {
+		if !found {
+			return nil
+		}
+		log.Infof("No validation is enabled, deleting the validating Webhook %s", c.config.getWebhookName())
+		err = c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Delete(context.TODO(), c.config.getWebhookName(), metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !found {
+		log.Infof("Validating Webhook %s was not found, creating it", c.config.getWebhookName())
+		return c.createValidatingWebhook(secret)
+	}
+
+	log.Debugf("The validating Webhook %s was found, updating it", c.config.getWebhookName())
+
+	return c.updateValidatingWebhook(secret, webhook)
+}
+
+// createValidatingWebhook creates a new ValidatingWebhookConfiguration object.
+func (c *ControllerV1) createValidatingWebhook(secret *corev1.Secret) error {
+	webhook := &admiv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: c.config.getWebhookName(),
+		},
+		Webhooks: c.newValidatingWebhooks(secret),
+	}
+
+	_, err := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Create(context.TODO(), webhook, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		log.Infof("Validating Webhook %s already exists", webhook.GetName())
+		return nil
+	}
+
+	return err
+}
+
+// updateValidatingWebhook stores a new configuration in the ValidatingWebhookConfiguration object.
+func (c *ControllerV1) updateValidatingWebhook(secret *corev1.Secret, webhook *admiv1.ValidatingWebhookConfiguration) error {
+	webhook = webhook.DeepCopy()
+	webhook.Webhooks = c.newValidatingWebhooks(secret)
+	_, err := c.clientSet.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(context.TODO(), webhook, metav1.UpdateOptions{})
+	return err
+}
+
+// newValidatingWebhooks generates ValidatingWebhook objects from config templates with updated CABundle from Secret.
+func (c *ControllerV1) newValidatingWebhooks(secret *corev1.Secret) []admiv1.ValidatingWebhook {
+	webhooks := []admiv1.ValidatingWebhook{}
+	for _, tpl := range c.validatingWebhookTemplates {
+		tpl.ClientConfig.CABundle = certificate.GetCABundle(secret.Data)
+		webhooks = append(webhooks, tpl)
+	}
+
+	return webhooks
+}
+
 func (c *ControllerV1) generateTemplates() {
 	webhooks := []admiv1.MutatingWebhook{}
 
@@ -195,7 +308,23 @@ func (c *ControllerV1) generateTemplates() {
 		webhooks = append(webhooks, webhook)
 	}
 
+	// Tracing libraries injection
+	if config.Datadog.GetBool("admission_controller.auto_instrumentation.enabled") {
+		webhook := c.getWebhookSkeleton("auto-instrumentation", config.Datadog.GetString("admission_controller.auto_instrumentation.endpoint"))
+		webhooks = append(webhooks, webhook)
+	}
+
 	c.webhookTemplates = webhooks
+
+	validatingWebhooks := []admiv1.ValidatingWebhook{}
+
+	// Autodiscovery annotations validation
+	if config.Datadog.GetBool("admission_controller.validate_ad_annotations.enabled") {
+		webhook := c.getValidatingWebhookSkeleton("ad-annotations", config.Datadog.GetString("admission_controller.validate_ad_annotations.endpoint"))
+		validatingWebhooks = append(validatingWebhooks, webhook)
+	}
+
+	c.validatingWebhookTemplates = validatingWebhooks
 }
 
 func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string) admiv1.MutatingWebhook {
@@ -240,6 +369,49 @@ func (c *ControllerV1) getWebhookSkeleton(nameSuffix, path string) admiv1.Mutati
 	return webhook
 }
 
+// getValidatingWebhookSkeleton returns a validating webhook for pods. Unlike the
+// mutating webhooks, it applies to all the pods which aren't explicitly excluded
+// with the admission label, whatever the mutate_unlabelled setting.
+func (c *ControllerV1) getValidatingWebhookSkeleton(nameSuffix, path string) admiv1.ValidatingWebhook {
+	matchPolicy := admiv1.Exact
+	sideEffects := admiv1.SideEffectClassNone
+	port := c.config.getServicePort()
+	timeout := c.config.getTimeout()
+	failurePolicy := c.getAdmiV1FailurePolicy()
+	webhook := admiv1.ValidatingWebhook{
+		Name: c.config.configName(nameSuffix),
+		ClientConfig: admiv1.WebhookClientConfig{
+			Service: &admiv1.ServiceReference{
+				Namespace: c.config.getServiceNs(),
+				Name:      c.config.getServiceName(),
+				Port:      &port,
+				Path:      &path,
+			},
+		},
+		Rules: []admiv1.RuleWithOperations{
+			{
+				Operations: []admiv1.OperationType{
+					admiv1.Create,
+				},
+				Rule: admiv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods"},
+				},
+			},
+		},
+		FailurePolicy:           &failurePolicy,
+		MatchPolicy:             &matchPolicy,
+		SideEffects:             &sideEffects,
+		TimeoutSeconds:          &timeout,
+		AdmissionReviewVersions: []string{"v1", "v1beta1"},
+	}
+
+	webhook.NamespaceSelector, webhook.ObjectSelector = buildValidationLabelSelectors(c.config.useNamespaceSelector())
+
+	return webhook
+}
+
 func (c *ControllerV1) getAdmiV1FailurePolicy() admiv1.FailurePolicyType {
 	policy := strings.ToLower(c.config.getFailurePolicy())
 	switch policy {
@@ -72,6 +72,93 @@ func TestCreateWebhookV1beta1(t *testing.T) {
 	}
 }
 
+func TestCreateValidatingWebhookV1beta1(t *testing.T) {
+	config.Datadog.Set("admission_controller.validate_ad_annotations.enabled", true)
+	defer config.Datadog.Set("admission_controller.validate_ad_annotations.enabled", false)
+
+	f := newFixtureV1beta1(t)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1beta1Cfg)
+	f.populateSecretsCache(secret)
+
+	c := f.run(t)
+
+	webhook, err := c.webhooksLister.Get(v1beta1Cfg.getWebhookName())
+	if err != nil {
+		t.Fatalf("Failed to get the Webhook: %v", err)
+	}
+
+	if err := validateV1beta1(webhook, secret); err != nil {
+		t.Fatalf("Invalid Webhook: %v", err)
+	}
+
+	validatingWebhook, err := c.validatingWebhooksLister.Get(v1beta1Cfg.getWebhookName())
+	if err != nil {
+		t.Fatalf("Failed to get the validating Webhook: %v", err)
+	}
+
+	if len(validatingWebhook.Webhooks) != 1 {
+		t.Fatalf("Validating Webhooks should contain 1 entry, got %d", len(validatingWebhook.Webhooks))
+	}
+
+	ad := validatingWebhook.Webhooks[0]
+	assert.Equal(t, "datadog.webhook.ad.annotations", ad.Name)
+	assert.Equal(t, certificate.GetCABundle(secret.Data), ad.ClientConfig.CABundle)
+	assert.Nil(t, ad.NamespaceSelector)
+	assert.Equal(t, &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "admission.datadoghq.com/enabled",
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"false"},
+			},
+		},
+	}, ad.ObjectSelector)
+
+	if c.queue.Len() != 0 {
+		t.Fatal("Work queue isn't empty")
+	}
+}
+
+func TestDeleteValidatingWebhookV1beta1(t *testing.T) {
+	f := newFixtureV1beta1(t)
+
+	data, err := certificate.GenerateSecretData(time.Now(), time.Now().Add(365*24*time.Hour), []string{"my.svc.dns"})
+	if err != nil {
+		t.Fatalf("Failed to create the Secret: %v", err)
+	}
+
+	secret := buildSecret(data, v1beta1Cfg)
+	f.populateSecretsCache(secret)
+
+	f.populateValidatingWebhooksCache(&admiv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: v1beta1Cfg.getWebhookName(),
+		},
+		Webhooks: []admiv1beta1.ValidatingWebhook{
+			{
+				Name: "datadog.webhook.ad.annotations",
+			},
+		},
+	})
+
+	c := f.run(t)
+
+	_, err = c.validatingWebhooksLister.Get(v1beta1Cfg.getWebhookName())
+	if !errors.IsNotFound(err) {
+		t.Fatal("Validating Webhook should have been deleted")
+	}
+
+	if c.queue.Len() != 0 {
+		t.Fatal("Work queue isn't empty")
+	}
+}
+
 func TestUpdateOutdatedWebhookV1beta1(t *testing.T) {
 	f := newFixtureV1beta1(t)
 
@@ -570,6 +657,7 @@ func (f *fixtureV1beta1) run(t *testing.T) *ControllerV1beta1 {
 		f.client,
 		factory.Core().V1().Secrets(),
 		factory.Admissionregistration().V1beta1().MutatingWebhookConfigurations(),
+		factory.Admissionregistration().V1beta1().ValidatingWebhookConfigurations(),
 		func() bool { return true },
 		make(chan struct{}),
 		v1beta1Cfg,
@@ -589,6 +677,12 @@ func (f *fixtureV1beta1) populateWebhooksCache(webhooks ...*admiv1beta1.Mutating
 	}
 }
 
+func (f *fixtureV1beta1) populateValidatingWebhooksCache(webhooks ...*admiv1beta1.ValidatingWebhookConfiguration) {
+	for _, w := range webhooks {
+		_, _ = f.client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Create(context.TODO(), w, metav1.CreateOptions{})
+	}
+}
+
 func validateV1beta1(w *admiv1beta1.MutatingWebhookConfiguration, s *corev1.Secret) error {
 	if len(w.Webhooks) != 2 {
 		return fmt.Errorf("Webhooks should contain 2 entries, got %d", len(w.Webhooks))
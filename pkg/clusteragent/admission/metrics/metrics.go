@@ -16,10 +16,13 @@ import (
 
 // Metric names
 const (
-	SecretControllerName   = "secrets"
-	WebhooksControllerName = "webhooks"
-	TagsMutationType       = "standard_tags"
-	ConfigMutationType     = "agent_config"
+	SecretControllerName     = "secrets"
+	WebhooksControllerName   = "webhooks"
+	TagsMutationType         = "standard_tags"
+	ConfigMutationType       = "agent_config"
+	LibInjectionMutationType = "lib_injection"
+
+	ADAnnotationsValidationType = "ad_annotations"
 )
 
 // Telemetry metrics
@@ -39,6 +42,9 @@ var (
 	MutationErrors = telemetry.NewGaugeWithOpts("admission_webhooks", "mutation_errors",
 		[]string{"mutation_type", "reason"}, "Number of mutation failures by mutation type (agent config, standard tags).",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	ValidationErrors = telemetry.NewCounterWithOpts("admission_webhooks", "validation_errors",
+		[]string{"validation_type"}, "Number of validation errors by validation type (autodiscovery annotations).",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 	WebhooksReceived = telemetry.NewCounterWithOpts("admission_webhooks", "webhooks_received",
 		[]string{}, "Number of mutation webhook requests received.",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
@@ -0,0 +1,235 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package mutate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// libVersionAnnotationKeyFormat is the format of the pod annotation requesting the injection of a
+	// tracing library, the value of the annotation being the version of the library
+	libVersionAnnotationKeyFormat = "admission.datadoghq.com/%s-lib.version"
+	// customLibAnnotationKeyFormat is the format of the pod annotation requesting the injection of a
+	// tracing library from a custom image
+	customLibAnnotationKeyFormat = "admission.datadoghq.com/%s-lib.custom-image"
+
+	// Volume of the tracing libraries
+	libVolumeName = "datadog-auto-instrumentation"
+	libMountPath  = "/datadog-lib"
+
+	initContainerNameFormat = "datadog-lib-%s-init"
+)
+
+type language string
+
+const (
+	java   language = "java"
+	js     language = "js"
+	python language = "python"
+)
+
+// libEnvVar describes how an env var is set to load a tracing library
+type libEnvVar struct {
+	name  string
+	value string
+	// separator joins the value to the existing value of the env var, if any
+	separator string
+	// prepend puts the value before the existing value of the env var
+	prepend bool
+}
+
+var (
+	supportedLanguages = []language{java, js, python}
+
+	libEnvVars = map[language][]libEnvVar{
+		java: {{
+			name:      "JAVA_TOOL_OPTIONS",
+			value:     "-javaagent:" + libMountPath + "/dd-java-agent.jar",
+			separator: " ",
+		}},
+		js: {{
+			name:      "NODE_OPTIONS",
+			value:     "--require=" + libMountPath + "/node_modules/dd-trace/init",
+			separator: " ",
+		}},
+		python: {{
+			name:      "PYTHONPATH",
+			value:     libMountPath + "/",
+			separator: ":",
+			prepend:   true,
+		}},
+	}
+)
+
+// libInfo describes the tracing library to inject into a pod
+type libInfo struct {
+	lang  language
+	image string
+}
+
+// InjectAutoInstrumentation injects the tracing libraries requested by the pod annotations
+func InjectAutoInstrumentation(rawPod []byte, ns string, dc dynamic.Interface) ([]byte, error) {
+	return mutate(rawPod, ns, injectAutoInstrumentation, dc)
+}
+
+// injectAutoInstrumentation adds an init container copying each requested tracing library into a shared
+// volume, and sets the env vars loading the libraries into the containers of the pod
+func injectAutoInstrumentation(pod *corev1.Pod, _ string, _ dynamic.Interface) error {
+	var injected bool
+	defer func() {
+		metrics.MutationAttempts.Inc(metrics.LibInjectionMutationType, strconv.FormatBool(injected))
+	}()
+
+	if pod == nil {
+		metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "nil pod")
+		return errors.New("cannot inject lib into nil pod")
+	}
+
+	libs := extractLibInfo(pod, config.Datadog.GetString("admission_controller.auto_instrumentation.container_registry"))
+	if len(libs) == 0 {
+		return nil
+	}
+
+	for _, lib := range libs {
+		if err := injectLibInitContainer(pod, lib); err != nil {
+			metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "init container")
+			return err
+		}
+
+		for _, envVar := range libEnvVars[lib.lang] {
+			if err := injectLibEnvVar(pod, envVar); err != nil {
+				metrics.MutationErrors.Inc(metrics.LibInjectionMutationType, "env var")
+				return err
+			}
+		}
+	}
+
+	volume := corev1.Volume{
+		Name: libVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      libVolumeName,
+		MountPath: libMountPath,
+	}
+	injectVolume(pod, volume, volumeMount)
+
+	injected = true
+
+	return nil
+}
+
+// extractLibInfo returns the tracing libraries requested by the pod annotations, sorted by language
+func extractLibInfo(pod *corev1.Pod, containerRegistry string) []libInfo {
+	var libs []libInfo
+	annotations := pod.GetAnnotations()
+
+	for _, lang := range supportedLanguages {
+		if image, found := annotations[fmt.Sprintf(customLibAnnotationKeyFormat, lang)]; found {
+			libs = append(libs, libInfo{lang: lang, image: image})
+			continue
+		}
+
+		if version, found := annotations[fmt.Sprintf(libVersionAnnotationKeyFormat, lang)]; found {
+			libs = append(libs, libInfo{lang: lang, image: libImageName(containerRegistry, lang, version)})
+		}
+	}
+
+	sort.Slice(libs, func(i, j int) bool { return libs[i].lang < libs[j].lang })
+
+	return libs
+}
+
+// libImageName returns the image of the init container of a tracing library
+func libImageName(registry string, lang language, version string) string {
+	return fmt.Sprintf("%s/dd-lib-%s-init:%s", strings.TrimSuffix(registry, "/"), lang, version)
+}
+
+// injectLibInitContainer adds the init container copying a tracing library into the shared volume
+func injectLibInitContainer(pod *corev1.Pod, lib libInfo) error {
+	name := fmt.Sprintf(initContainerNameFormat, lib.lang)
+
+	for _, container := range pod.Spec.InitContainers {
+		if container.Name == name {
+			log.Debugf("Ignoring pod %s: init container %q already exists", podString(pod), name)
+			return nil
+		}
+	}
+
+	if lib.image == "" {
+		return fmt.Errorf("no image defined for the %s library", lib.lang)
+	}
+
+	log.Debugf("Injecting init container %q with image %q into pod %s", name, lib.image, podString(pod))
+	pod.Spec.InitContainers = append([]corev1.Container{{
+		Name:    name,
+		Image:   lib.image,
+		Command: []string{"sh", "copy-lib.sh", libMountPath},
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      libVolumeName,
+			MountPath: libMountPath,
+		}},
+	}}, pod.Spec.InitContainers...)
+
+	return nil
+}
+
+// injectLibEnvVar sets an env var loading a tracing library into the containers of a pod. The value is
+// joined to the existing value of the env var, unless it already contains it.
+func injectLibEnvVar(pod *corev1.Pod, envVar libEnvVar) error {
+	for i, container := range pod.Spec.Containers {
+		index := -1
+		for j, env := range container.Env {
+			if env.Name == envVar.name {
+				index = j
+				break
+			}
+		}
+
+		if index == -1 {
+			pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, corev1.EnvVar{
+				Name:  envVar.name,
+				Value: envVar.value,
+			})
+			continue
+		}
+
+		env := &pod.Spec.Containers[i].Env[index]
+		if env.ValueFrom != nil {
+			return fmt.Errorf("%s is defined from another source in the container %q", envVar.name, container.Name)
+		}
+
+		switch {
+		case strings.Contains(env.Value, envVar.value):
+			continue
+		case env.Value == "":
+			env.Value = envVar.value
+		case envVar.prepend:
+			env.Value = envVar.value + envVar.separator + env.Value
+		default:
+			env.Value = env.Value + envVar.separator + envVar.value
+		}
+	}
+
+	return nil
+}
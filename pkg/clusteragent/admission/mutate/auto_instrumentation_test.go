@@ -0,0 +1,156 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package mutate
+
+import (
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_extractLibInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want []libInfo
+	}{
+		{
+			name: "java",
+			pod:  withAnnotations(fakePod("java-pod"), map[string]string{"admission.datadoghq.com/java-lib.version": "v1"}),
+			want: []libInfo{{lang: java, image: "registry/dd-lib-java-init:v1"}},
+		},
+		{
+			name: "custom image",
+			pod: withAnnotations(fakePod("python-pod"), map[string]string{
+				"admission.datadoghq.com/python-lib.version":      "v1",
+				"admission.datadoghq.com/python-lib.custom-image": "foo/bar:1",
+			}),
+			want: []libInfo{{lang: python, image: "foo/bar:1"}},
+		},
+		{
+			name: "several languages",
+			pod: withAnnotations(fakePod("pod"), map[string]string{
+				"admission.datadoghq.com/python-lib.version": "v2",
+				"admission.datadoghq.com/js-lib.version":     "v1",
+			}),
+			want: []libInfo{
+				{lang: js, image: "registry/dd-lib-js-init:v1"},
+				{lang: python, image: "registry/dd-lib-python-init:v2"},
+			},
+		},
+		{
+			name: "unsupported language",
+			pod:  withAnnotations(fakePod("ruby-pod"), map[string]string{"admission.datadoghq.com/ruby-lib.version": "v1"}),
+			want: nil,
+		},
+		{
+			name: "no annotations",
+			pod:  fakePod("pod"),
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractLibInfo(tt.pod, "registry/"))
+		})
+	}
+}
+
+func Test_injectLibEnvVar(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     []corev1.EnvVar
+		envVar  libEnvVar
+		want    []corev1.EnvVar
+		wantErr bool
+	}{
+		{
+			name:   "new env var",
+			envVar: libEnvVars[java][0],
+			want:   []corev1.EnvVar{fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-javaagent:/datadog-lib/dd-java-agent.jar")},
+		},
+		{
+			name:   "append",
+			env:    []corev1.EnvVar{fakeEnvWithValue("NODE_OPTIONS", "--max-old-space-size=512")},
+			envVar: libEnvVars[js][0],
+			want:   []corev1.EnvVar{fakeEnvWithValue("NODE_OPTIONS", "--max-old-space-size=512 --require=/datadog-lib/node_modules/dd-trace/init")},
+		},
+		{
+			name:   "prepend",
+			env:    []corev1.EnvVar{fakeEnvWithValue("PYTHONPATH", "/app")},
+			envVar: libEnvVars[python][0],
+			want:   []corev1.EnvVar{fakeEnvWithValue("PYTHONPATH", "/datadog-lib/:/app")},
+		},
+		{
+			name:   "already injected",
+			env:    []corev1.EnvVar{fakeEnvWithValue("PYTHONPATH", "/datadog-lib/:/app")},
+			envVar: libEnvVars[python][0],
+			want:   []corev1.EnvVar{fakeEnvWithValue("PYTHONPATH", "/datadog-lib/:/app")},
+		},
+		{
+			name: "value from another source",
+			env: []corev1.EnvVar{{
+				Name:      "JAVA_TOOL_OPTIONS",
+				ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+			}},
+			envVar:  libEnvVars[java][0],
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := fakePodWithContainer("pod", corev1.Container{Name: "container", Env: tt.env})
+			err := injectLibEnvVar(pod, tt.envVar)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, pod.Spec.Containers[0].Env)
+		})
+	}
+}
+
+func Test_injectAutoInstrumentation(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("admission_controller.auto_instrumentation.container_registry", "registry")
+
+	pod := withAnnotations(fakePod("pod"), map[string]string{
+		"admission.datadoghq.com/java-lib.version":   "v1",
+		"admission.datadoghq.com/python-lib.version": "v2",
+	})
+	require.NoError(t, injectAutoInstrumentation(pod, "", nil))
+
+	require.Len(t, pod.Spec.InitContainers, 2)
+	// the init containers are prepended, the last injected library comes first
+	assert.Equal(t, "datadog-lib-python-init", pod.Spec.InitContainers[0].Name)
+	assert.Equal(t, "registry/dd-lib-python-init:v2", pod.Spec.InitContainers[0].Image)
+	assert.Equal(t, "datadog-lib-java-init", pod.Spec.InitContainers[1].Name)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "datadog-auto-instrumentation", MountPath: "/datadog-lib"}}, pod.Spec.InitContainers[1].VolumeMounts)
+
+	container := pod.Spec.Containers[0]
+	assert.Contains(t, container.Env, fakeEnvWithValue("JAVA_TOOL_OPTIONS", "-javaagent:/datadog-lib/dd-java-agent.jar"))
+	assert.Contains(t, container.Env, fakeEnvWithValue("PYTHONPATH", "/datadog-lib/"))
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "datadog-auto-instrumentation", MountPath: "/datadog-lib"})
+
+	require.Len(t, pod.Spec.Volumes, 1)
+	assert.Equal(t, "datadog-auto-instrumentation", pod.Spec.Volumes[0].Name)
+	assert.NotNil(t, pod.Spec.Volumes[0].EmptyDir)
+
+	// a reinvocation of the webhook leaves the pod unchanged
+	require.NoError(t, injectAutoInstrumentation(pod, "", nil))
+	assert.Len(t, pod.Spec.InitContainers, 2)
+	assert.Len(t, pod.Spec.Volumes, 1)
+	assert.Len(t, pod.Spec.Containers[0].Env, 2)
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+}
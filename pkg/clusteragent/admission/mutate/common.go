@@ -11,6 +11,7 @@ package mutate
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
@@ -72,22 +73,62 @@ func injectEnv(pod *corev1.Pod, env corev1.EnvVar) bool {
 	return injected
 }
 
-// injectVolume injects a volume into a pod template if it doesn't exist
+// injectVolume injects a volume into a pod template if it doesn't exist.
+// A hostPath volume of the pod with the same path is reused instead of being duplicated,
+// and the containers already mounting a volume at the mount path are ignored.
 func injectVolume(pod *corev1.Pod, volume corev1.Volume, volumeMount corev1.VolumeMount) bool {
 	podStr := podString(pod)
-	for _, vol := range pod.Spec.Volumes {
+	var existing *corev1.Volume
+	for i, vol := range pod.Spec.Volumes {
 		if vol.Name == volume.Name {
 			log.Debugf("Ignoring pod %q: volume %q already exists", podStr, vol.Name)
 			return false
 		}
+		if existing == nil && sameHostPath(vol, volume) {
+			existing = &pod.Spec.Volumes[i]
+		}
+	}
+
+	injected := false
+	if existing != nil {
+		log.Debugf("Reusing volume %q of pod %q for host path %q", existing.Name, podStr, volume.HostPath.Path)
+		volumeMount.Name = existing.Name
+	} else {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		injected = true
 	}
 
-	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
-	for i := range pod.Spec.Containers {
+	for i, ctr := range pod.Spec.Containers {
+		if hasMountPath(ctr, volumeMount.MountPath) {
+			log.Debugf("Ignoring container '%s' in pod %s: a volume is already mounted at %q", ctr.Name, podStr, volumeMount.MountPath)
+			continue
+		}
 		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, volumeMount)
+		injected = true
+	}
+
+	return injected
+}
+
+// sameHostPath returns whether two volumes are hostPath volumes of the same path
+func sameHostPath(a, b corev1.Volume) bool {
+	if a.HostPath == nil || b.HostPath == nil {
+		return false
 	}
+	return filepath.Clean(a.HostPath.Path) == filepath.Clean(b.HostPath.Path)
+}
 
-	return true
+// hasMountPath returns whether a container mounts a volume at a given path
+func hasMountPath(ctr corev1.Container, mountPath string) bool {
+	if mountPath == "" {
+		return false
+	}
+	for _, mount := range ctr.VolumeMounts {
+		if filepath.Clean(mount.MountPath) == filepath.Clean(mountPath) {
+			return true
+		}
+	}
+	return false
 }
 
 // podString returns a string that helps identify the pod
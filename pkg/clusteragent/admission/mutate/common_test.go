@@ -163,6 +163,15 @@ func Test_injectVolume(t *testing.T) {
 			},
 			injected: false,
 		},
+		{
+			name: "host path already mounted",
+			args: args{
+				pod:         fakePodWithHostPathVolume("podfoo", "volumebar", "/var/run/datadog/"),
+				volume:      corev1.Volume{Name: "volumefoo", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/datadog"}}},
+				volumeMount: corev1.VolumeMount{Name: "volumefoo", MountPath: "/var/run/datadog"},
+			},
+			injected: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -170,3 +179,17 @@ func Test_injectVolume(t *testing.T) {
 		})
 	}
 }
+
+func Test_injectVolumeReusesHostPath(t *testing.T) {
+	pod := fakePodWithHostPathVolume("podfoo", "volumebar", "/var/run/datadog")
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "sidecar"})
+
+	volume := corev1.Volume{Name: "volumefoo", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/run/datadog"}}}
+	volumeMount := corev1.VolumeMount{Name: "volumefoo", MountPath: "/var/run/datadog"}
+	assert.True(t, injectVolume(pod, volume, volumeMount))
+
+	// the existing volume is mounted into the containers lacking it
+	assert.Len(t, pod.Spec.Volumes, 1)
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "volumebar", MountPath: "/var/run/datadog"}}, pod.Spec.Containers[1].VolumeMounts)
+}
@@ -26,9 +26,10 @@ import (
 
 const (
 	// Env vars
-	agentHostEnvVarName  = "DD_AGENT_HOST"
-	ddEntityIDEnvVarName = "DD_ENTITY_ID"
-	traceURLEnvVarName   = "DD_TRACE_AGENT_URL"
+	agentHostEnvVarName    = "DD_AGENT_HOST"
+	ddEntityIDEnvVarName   = "DD_ENTITY_ID"
+	traceURLEnvVarName     = "DD_TRACE_AGENT_URL"
+	dogstatsdURLEnvVarName = "DD_DOGSTATSD_URL"
 
 	// Config injection modes
 	hostIP  = "hostip"
@@ -65,6 +66,11 @@ var (
 		Value: config.Datadog.GetString("admission_controller.inject_config.trace_agent_socket"),
 	}
 
+	dogstatsdURLEnvVar = corev1.EnvVar{
+		Name:  dogstatsdURLEnvVarName,
+		Value: config.Datadog.GetString("admission_controller.inject_config.dogstatsd_socket"),
+	}
+
 	agentServiceEnvVar = corev1.EnvVar{
 		Name:  agentHostEnvVarName,
 		Value: config.Datadog.GetString("admission_controller.inject_config.local_service_name") + "." + apiCommon.GetMyNamespace() + ".svc.cluster.local",
@@ -77,7 +83,7 @@ func InjectConfig(rawPod []byte, ns string, dc dynamic.Interface) ([]byte, error
 }
 
 // injectConfig injects DD_AGENT_HOST and DD_ENTITY_ID into a pod template if needed
-func injectConfig(pod *corev1.Pod, _ string, _ dynamic.Interface) error {
+func injectConfig(pod *corev1.Pod, ns string, _ dynamic.Interface) error {
 	var injectedConfig, injectedEntity bool
 	defer func() {
 		metrics.MutationAttempts.Inc(metrics.ConfigMutationType, strconv.FormatBool(injectedConfig || injectedEntity))
@@ -93,6 +99,11 @@ func injectConfig(pod *corev1.Pod, _ string, _ dynamic.Interface) error {
 	}
 
 	mode := injectionMode(pod, config.Datadog.GetString("admission_controller.inject_config.mode"))
+	if mode == socket && !socketModeAllowed(pod, ns) {
+		log.Debugf("Socket mode is not allowed in the namespace of pod %s, defaulting to %q", podString(pod), hostIP)
+		mode = hostIP
+	}
+
 	switch mode {
 	case hostIP:
 		injectedConfig = injectEnv(pod, agentHostEnvVar)
@@ -101,8 +112,9 @@ func injectConfig(pod *corev1.Pod, _ string, _ dynamic.Interface) error {
 	case socket:
 		volume, volumeMount := buildVolume(datadogVolumeName, config.Datadog.GetString("admission_controller.inject_config.socket_path"))
 		injectedVol := injectVolume(pod, volume, volumeMount)
-		injectedEnv := injectEnv(pod, traceURLEnvVar)
-		injectedConfig = injectedEnv || injectedVol
+		injectedTraceEnv := injectEnv(pod, traceURLEnvVar)
+		injectedDogstatsdEnv := injectEnv(pod, dogstatsdURLEnvVar)
+		injectedConfig = injectedTraceEnv || injectedDogstatsdEnv || injectedVol
 	default:
 		metrics.MutationErrors.Inc(metrics.ConfigMutationType, "unknown mode")
 		return fmt.Errorf("invalid injection mode %q", mode)
@@ -146,6 +158,32 @@ func injectionMode(pod *corev1.Pod, globalMode string) string {
 	return globalMode
 }
 
+// socketModeAllowed returns whether the socket mode, which mounts a hostPath volume,
+// can be used for a pod based on the namespace allow and deny lists of the cluster agent config.
+// The deny list has precedence, an empty allow list allows every namespace.
+func socketModeAllowed(pod *corev1.Pod, ns string) bool {
+	if ns == "" {
+		ns = pod.GetNamespace()
+	}
+
+	for _, denied := range config.Datadog.GetStringSlice("admission_controller.inject_config.socket_denied_namespaces") {
+		if denied == ns {
+			return false
+		}
+	}
+
+	allowed := config.Datadog.GetStringSlice("admission_controller.inject_config.socket_allowed_namespaces")
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if a == ns {
+			return true
+		}
+	}
+	return false
+}
+
 func buildVolume(volumeName, path string) (corev1.Volume, corev1.VolumeMount) {
 	pathType := corev1.HostPathDirectoryOrCreate
 	volume := corev1.Volume{
@@ -142,3 +142,82 @@ func TestInjectSocket(t *testing.T) {
 	assert.Equal(t, pod.Spec.Volumes[0].VolumeSource.HostPath.Path, "/var/run/datadog")
 	assert.Equal(t, *pod.Spec.Volumes[0].VolumeSource.HostPath.Type, corev1.HostPathDirectoryOrCreate)
 }
+
+func TestInjectSocketDogstatsd(t *testing.T) {
+	pod := fakePodWithContainer("foo-pod", corev1.Container{})
+	pod = withLabels(pod, map[string]string{"admission.datadoghq.com/enabled": "true", "admission.datadoghq.com/config.mode": "socket"})
+	err := injectConfig(pod, "", nil)
+	assert.Nil(t, err)
+	assert.Contains(t, pod.Spec.Containers[0].Env, fakeEnvWithValue("DD_DOGSTATSD_URL", "unix:///var/run/datadog/dsd.socket"))
+	assert.Len(t, pod.Spec.Volumes, 1)
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+}
+
+func TestInjectSocketExistingVolume(t *testing.T) {
+	pod := fakePodWithHostPathVolume("foo-pod", "dsdsocket", "/var/run/datadog")
+	pod = withLabels(pod, map[string]string{"admission.datadoghq.com/enabled": "true", "admission.datadoghq.com/config.mode": "socket"})
+	err := injectConfig(pod, "", nil)
+	assert.Nil(t, err)
+	assert.Len(t, pod.Spec.Volumes, 1)
+	assert.Equal(t, pod.Spec.Volumes[0].Name, "dsdsocket")
+	assert.Len(t, pod.Spec.Containers[0].VolumeMounts, 1)
+	assert.True(t, contains(pod.Spec.Containers[0].Env, "DD_TRACE_AGENT_URL"))
+}
+
+func TestInjectSocketNamespaces(t *testing.T) {
+	mockConfig := config.Mock()
+	tests := []struct {
+		name       string
+		ns         string
+		allowed    []string
+		denied     []string
+		wantSocket bool
+	}{
+		{
+			name:       "no lists",
+			ns:         "foo",
+			wantSocket: true,
+		},
+		{
+			name:       "allowed",
+			ns:         "foo",
+			allowed:    []string{"foo", "bar"},
+			wantSocket: true,
+		},
+		{
+			name:       "not allowed",
+			ns:         "baz",
+			allowed:    []string{"foo", "bar"},
+			wantSocket: false,
+		},
+		{
+			name:       "denied",
+			ns:         "foo",
+			allowed:    []string{"foo"},
+			denied:     []string{"foo"},
+			wantSocket: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockConfig.Set("admission_controller.inject_config.socket_allowed_namespaces", tt.allowed)
+			mockConfig.Set("admission_controller.inject_config.socket_denied_namespaces", tt.denied)
+			defer mockConfig.Set("admission_controller.inject_config.socket_allowed_namespaces", []string{})
+			defer mockConfig.Set("admission_controller.inject_config.socket_denied_namespaces", []string{})
+
+			pod := fakePodWithContainer("foo-pod", corev1.Container{})
+			pod = withLabels(pod, map[string]string{"admission.datadoghq.com/enabled": "true", "admission.datadoghq.com/config.mode": "socket"})
+			err := injectConfig(pod, tt.ns, nil)
+			assert.Nil(t, err)
+
+			if tt.wantSocket {
+				assert.Len(t, pod.Spec.Volumes, 1)
+				assert.True(t, contains(pod.Spec.Containers[0].Env, "DD_TRACE_AGENT_URL"))
+			} else {
+				// the pod falls back to the hostip mode
+				assert.Len(t, pod.Spec.Volumes, 0)
+				assert.True(t, contains(pod.Spec.Containers[0].Env, "DD_AGENT_HOST"))
+			}
+		})
+	}
+}
@@ -53,6 +53,11 @@ func withLabels(pod *corev1.Pod, labels map[string]string) *corev1.Pod {
 	return pod
 }
 
+func withAnnotations(pod *corev1.Pod, annotations map[string]string) *corev1.Pod {
+	pod.Annotations = annotations
+	return pod
+}
+
 func fakePodWithLabel(k, v string) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -74,6 +79,18 @@ func fakePodWithVolume(podName, volumeName string) *corev1.Pod {
 	return pod
 }
 
+func fakePodWithHostPathVolume(podName, volumeName, path string) *corev1.Pod {
+	pod := fakePod(podName)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: volumeName,
+		VolumeSource: corev1.VolumeSource{
+			HostPath: &corev1.HostPathVolumeSource{Path: path},
+		},
+	})
+	pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: volumeName, MountPath: path})
+	return pod
+}
+
 func fakePod(name string) *corev1.Pod {
 	return fakePodWithContainer(name, corev1.Container{Name: name + "-container"})
 }
@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/configresolver"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/tmplvar"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	warnMode   = "warn"
+	rejectMode = "reject"
+
+	legacyAnnotationPrefix = "service-discovery.datadoghq.com/"
+)
+
+var checkNameRegexp = regexp.MustCompile(`^[\w.\-]+$`)
+
+// ValidateADAnnotations checks the autodiscovery annotations of a pod, parsed
+// the same way as the node Agent does. The problems found are returned as
+// admission warnings, or as an error to deny the pod in reject mode. Unknown
+// check names are only reported as warnings as they may be custom checks.
+func ValidateADAnnotations(rawPod []byte, ns string, _ dynamic.Interface) ([]string, error) {
+	var pod corev1.Pod
+	if err := json.Unmarshal(rawPod, &pod); err != nil {
+		return nil, fmt.Errorf("failed to decode raw object: %v", err)
+	}
+
+	knownChecks := make(map[string]struct{})
+	for _, name := range config.Datadog.GetStringSlice("admission_controller.validate_ad_annotations.known_checks") {
+		knownChecks[name] = struct{}{}
+	}
+
+	problems, warnings := validateADAnnotations(&pod, knownChecks)
+	if len(problems) == 0 && len(warnings) == 0 {
+		return nil, nil
+	}
+
+	podStr := fmt.Sprintf("%s/%s", ns, podName(&pod))
+	metrics.ValidationErrors.Add(float64(len(problems)), metrics.ADAnnotationsValidationType)
+	for _, problem := range problems {
+		log.Debugf("Invalid autodiscovery annotation in pod %s: %s", podStr, problem)
+	}
+
+	mode := strings.ToLower(config.Datadog.GetString("admission_controller.validate_ad_annotations.mode"))
+	if mode == rejectMode && len(problems) > 0 {
+		return warnings, errors.New(strings.Join(problems, "; "))
+	}
+	if mode != warnMode && mode != rejectMode {
+		log.Warnf("Unknown autodiscovery annotations validation mode %q - defaulting to %q", mode, warnMode)
+	}
+
+	return append(problems, warnings...), nil
+}
+
+// validateADAnnotations returns the problems making the node Agent ignore
+// autodiscovery annotations, and the warnings about the ones it may not support
+func validateADAnnotations(pod *corev1.Pod, knownChecks map[string]struct{}) (problems, warnings []string) {
+	if !hasADAnnotations(pod.Annotations) {
+		return nil, nil
+	}
+
+	containerIdentifiers := map[string]struct{}{}
+	containerNames := map[string]struct{}{}
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		adIdentifier := container.Name
+		if customADID, found := utils.ExtractCheckIDFromPodAnnotations(pod.Annotations, container.Name); found {
+			adIdentifier = customADID
+		}
+		containerIdentifiers[adIdentifier] = struct{}{}
+		containerNames[container.Name] = struct{}{}
+
+		configs, errs := utils.ExtractTemplatesFromPodAnnotations(podName(pod), pod.Annotations, adIdentifier)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("container %s: %v", container.Name, err))
+		}
+
+		for _, c := range configs {
+			if c.Name != "" && !checkNameRegexp.MatchString(c.Name) {
+				problems = append(problems, fmt.Sprintf("container %s: invalid check name %q", container.Name, c.Name))
+			} else if _, found := knownChecks[c.Name]; c.Name != "" && len(knownChecks) > 0 && !found {
+				warnings = append(warnings, fmt.Sprintf("container %s: unknown check %s", container.Name, c.Name))
+			}
+
+			for _, err := range validateTemplateVariables(c, container) {
+				problems = append(problems, fmt.Sprintf("container %s: %v", container.Name, err))
+			}
+		}
+	}
+
+	for _, err := range utils.ValidateAnnotationsMatching(pod.Annotations, containerIdentifiers, containerNames) {
+		problems = append(problems, err.Error())
+	}
+
+	sort.Strings(problems)
+	sort.Strings(warnings)
+	return problems, warnings
+}
+
+// validateTemplateVariables returns an error for each template variable of a
+// config that the node Agent cannot resolve for a container
+func validateTemplateVariables(c integration.Config, container corev1.Container) []error {
+	data := append([]integration.Data{c.InitConfig, c.LogsConfig}, c.Instances...)
+
+	var errs []error
+	for _, d := range data {
+		for _, v := range tmplvar.Parse(d) {
+			name, key := string(v.Name), string(v.Key)
+			if !configresolver.IsSupportedTemplateVariable(name) {
+				errs = append(errs, fmt.Errorf("check %s: unknown template variable %s", c.Name, v.Raw))
+				continue
+			}
+			if name == "port" {
+				if err := validatePort(key, container.Ports); err != nil {
+					errs = append(errs, fmt.Errorf("check %s: cannot resolve %s: %v", c.Name, v.Raw, err))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validatePort checks a %%port%% template variable key against the container ports
+func validatePort(key string, ports []corev1.ContainerPort) error {
+	if len(ports) == 0 {
+		return errors.New("the container exposes no port")
+	}
+	if key == "" {
+		return nil
+	}
+
+	idx, err := strconv.Atoi(key)
+	if err != nil {
+		for _, port := range ports {
+			if port.Name == key {
+				return nil
+			}
+		}
+		return fmt.Errorf("no port named %s", key)
+	}
+	if idx >= len(ports) {
+		return fmt.Errorf("port index %d is out of range, the container exposes %d port(s)", idx, len(ports))
+	}
+	return nil
+}
+
+// hasADAnnotations returns whether some annotations are autodiscovery annotations
+func hasADAnnotations(annotations map[string]string) bool {
+	for annotation := range annotations {
+		if strings.HasPrefix(annotation, utils.KubeAnnotationPrefix) || strings.HasPrefix(annotation, legacyAnnotationPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// podName returns the name of a pod, or its generate name if it is not set
+// yet as it is generally the case when it is created by a controller
+func podName(pod *corev1.Pod) string {
+	if pod.Name != "" {
+		return pod.Name
+	}
+	return pod.GenerateName
+}
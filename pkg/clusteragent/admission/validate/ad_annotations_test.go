@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package validate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func fakePod(annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo-pod",
+			Annotations: annotations,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "redis",
+					Ports: []corev1.ContainerPort{
+						{Name: "redis", ContainerPort: 6379},
+					},
+				},
+				{
+					Name: "sidecar",
+				},
+			},
+		},
+	}
+}
+
+func TestValidateADAnnotations(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		knownChecks  map[string]struct{}
+		wantProblems int
+		wantWarnings int
+	}{
+		{
+			name:        "no annotations",
+			annotations: map[string]string{"foo": "bar"},
+		},
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redisdb"]`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{"host": "%%host%%", "port": "%%port_redis%%"}]`,
+			},
+			knownChecks: map[string]struct{}{"redisdb": {}},
+		},
+		{
+			name: "invalid json",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redisdb"`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{"host": "%%host%%"}]`,
+			},
+			wantProblems: 1,
+		},
+		{
+			name: "unknown template variable",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redisdb"]`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{"host": "%%foo%%"}]`,
+			},
+			wantProblems: 1,
+		},
+		{
+			name: "unresolvable ports",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":    `["redisdb"]`,
+				"ad.datadoghq.com/redis.init_configs":   `[{}]`,
+				"ad.datadoghq.com/redis.instances":      `[{"url": "http://%%host%%:%%port_http%%", "port": "%%port_1%%"}]`,
+				"ad.datadoghq.com/sidecar.check_names":  `["http_check"]`,
+				"ad.datadoghq.com/sidecar.init_configs": `[{}]`,
+				"ad.datadoghq.com/sidecar.instances":    `[{"url": "http://%%host%%:%%port%%"}]`,
+			},
+			wantProblems: 3,
+		},
+		{
+			name: "unknown check",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redis"]`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{}]`,
+			},
+			knownChecks:  map[string]struct{}{"redisdb": {}},
+			wantWarnings: 1,
+		},
+		{
+			name: "no matching container",
+			annotations: map[string]string{
+				"ad.datadoghq.com/nginx.check_names":  `["nginx"]`,
+				"ad.datadoghq.com/nginx.init_configs": `[{}]`,
+				"ad.datadoghq.com/nginx.instances":    `[{}]`,
+			},
+			wantProblems: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems, warnings := validateADAnnotations(fakePod(tt.annotations), tt.knownChecks)
+			assert.Len(t, problems, tt.wantProblems, "problems: %v", problems)
+			assert.Len(t, warnings, tt.wantWarnings, "warnings: %v", warnings)
+		})
+	}
+}
+
+func TestValidateADAnnotationsMode(t *testing.T) {
+	mockConfig := config.Mock()
+	rawPod, err := json.Marshal(fakePod(map[string]string{
+		"ad.datadoghq.com/redis.check_names":  `["redisdb"]`,
+		"ad.datadoghq.com/redis.init_configs": `[{}]`,
+		"ad.datadoghq.com/redis.instances":    `[{"host": "%%foo%%"}]`,
+	}))
+	require.NoError(t, err)
+
+	mockConfig.Set("admission_controller.validate_ad_annotations.mode", "warn")
+	warnings, err := ValidateADAnnotations(rawPod, "default", nil)
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 1)
+
+	mockConfig.Set("admission_controller.validate_ad_annotations.mode", "reject")
+	warnings, err = ValidateADAnnotations(rawPod, "default", nil)
+	assert.Error(t, err)
+	assert.Len(t, warnings, 0)
+}
@@ -31,11 +31,20 @@ func (d *dispatcher) getState() (types.StateResponse, error) {
 		Warmup:   !d.store.active,
 		Dangling: makeConfigArray(d.store.danglingConfigs),
 	}
+	for _, config := range response.Dangling {
+		if config.NodeAffinity != nil && !d.hasNodeMatchingAffinity(config.NodeAffinity) {
+			response.Unschedulable = append(response.Unschedulable, config)
+		}
+	}
+
 	for _, node := range d.store.nodes {
+		node.RLock()
 		n := types.StateNodeResponse{
 			Name:    node.name,
+			Labels:  node.labels,
 			Configs: makeConfigArray(node.digestToConfig),
 		}
+		node.RUnlock()
 		response.Nodes = append(response.Nodes, n)
 	}
 
@@ -131,10 +131,14 @@ func (d *dispatcher) reschedule(configs []integration.Config) {
 
 // add stores and delegates a given configuration
 func (d *dispatcher) add(config integration.Config) {
-	target := d.getLeastBusyNode()
+	target := d.getLeastBusyNode(config.NodeAffinity)
 	if target == "" {
 		// If no node is found, store it in the danglingConfigs map for retrying later.
-		log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		if config.NodeAffinity != nil {
+			log.Warnf("No available node matching the node affinity (%s) to dispatch %s:%s on, will retry later", config.NodeAffinity, config.Name, config.Digest())
+		} else {
+			log.Warnf("No available node to dispatch %s:%s on, will retry later", config.Name, config.Digest())
+		}
 	} else {
 		log.Infof("Dispatching configuration %s:%s to node %s", config.Name, config.Digest(), target)
 	}
@@ -52,6 +52,9 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 	defer node.Unlock()
 	node.lastStatus = status
 	node.heartbeat = timestampNow()
+	if status.Labels != nil {
+		node.labels = status.Labels
+	}
 
 	if node.lastConfigChange == status.LastChange {
 		// Node-agent is up to date
@@ -70,9 +73,10 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 }
 
 // getLeastBusyNode returns the name of the node that is assigned
-// the lowest number of checks. In case of equality, one is chosen
-// randomly, based on map iterations being randomized.
-func (d *dispatcher) getLeastBusyNode() string {
+// the lowest number of checks among the nodes satisfying the node
+// affinity. In case of equality, one is chosen randomly, based on
+// map iterations being randomized.
+func (d *dispatcher) getLeastBusyNode(affinity *integration.NodeAffinity) string {
 	var leastBusyNode string
 	minCheckCount := int(-1)
 	minBusyness := int(-1)
@@ -84,6 +88,9 @@ func (d *dispatcher) getLeastBusyNode() string {
 		if name == "" {
 			continue
 		}
+		if !store.matchesAffinity(affinity) {
+			continue
+		}
 		if d.advancedDispatching && store.busyness > defaultBusynessValue {
 			// dispatching based on clc runners stats
 			// only when advancedDispatching is true and
@@ -103,6 +110,17 @@ func (d *dispatcher) getLeastBusyNode() string {
 	return leastBusyNode
 }
 
+// hasNodeMatchingAffinity returns whether a known node satisfies the node affinity.
+// The store lock is to be held by the caller.
+func (d *dispatcher) hasNodeMatchingAffinity(affinity *integration.NodeAffinity) bool {
+	for name, node := range d.store.nodes {
+		if name != "" && node.matchesAffinity(affinity) {
+			return true
+		}
+	}
+	return false
+}
+
 // expireNodes iterates over nodes and removes the ones that have not
 // reported for more than the expiration duration. The configurations
 // dispatched to these nodes will be moved to the danglingConfigs map.
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
 	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -30,6 +31,12 @@ type Weight struct {
 
 type Weights []Weight
 
+// clusterCheckWeight is the busyness caused by running a cluster check
+type clusterCheckWeight struct {
+	checkID string
+	weight  int
+}
+
 func (w Weights) Len() int           { return len(w) }
 func (w Weights) Less(i, j int) bool { return w[i].busyness > w[j].busyness }
 func (w Weights) Swap(i, j int)      { w[i], w[j] = w[j], w[i] }
@@ -101,21 +108,37 @@ func (d *dispatcher) updateDiff(avg int) map[string]int {
 	return diffMap
 }
 
-// pickCheckToMove select the most appropriate check to move from a node to another.
+// pickCheckToMove select the most appropriate check to move from a node to another,
+// and the node to move it to.
 // A check Xi running on a node N is chosen to move to another node if it satisfies the following
-// Weight(Xi) >  Weight(Xj) (for each j != i, 0 <= j < len(weights))
+// Weight(Xi) >  Weight(Xj) (for each j != i, 0 <= j < len(weights) and Xj can move)
 // where Weight(X) is the busyness value caused by running the check X.
-func (d *dispatcher) pickCheckToMove(nodeName string) (string, int, error) {
+// A check can move if another node matches its node affinity.
+func (d *dispatcher) pickCheckToMove(nodeName string, diffMap map[string]int) (string, int, string, error) {
 	d.store.RLock()
 	node, found := d.store.getNodeStore(nodeName)
 	d.store.RUnlock()
 
 	if !found {
 		log.Debugf("Node %s not found in store. Won't consider moving check", nodeName)
-		return "", -1, fmt.Errorf("node %s not found in store", nodeName)
+		return "", -1, "", fmt.Errorf("node %s not found in store", nodeName)
+	}
+
+	checks, err := node.GetClusterChecksByWeight(busynessFunc)
+	if err != nil {
+		return "", -1, "", err
 	}
 
-	return node.GetMostWeightedClusterCheck(busynessFunc)
+	for _, c := range checks {
+		config, _ := d.getConfigAndDigest(c.checkID)
+		destNodeName := pickNode(d.filterByAffinity(diffMap, config.NodeAffinity), nodeName)
+		if destNodeName != "" {
+			return c.checkID, c.weight, destNodeName, nil
+		}
+		log.Debugf("No node matching the node affinity of check %s to move it to", c.checkID)
+	}
+
+	return "", -1, "", fmt.Errorf("no cluster check on node %s can move to another node", nodeName)
 }
 
 // pickNode select the most appropriate node to receive a specific check.
@@ -140,6 +163,24 @@ func pickNode(diffMap map[string]int, sourceNode string) string {
 	return pickedNode
 }
 
+// filterByAffinity restricts a diff map to the nodes satisfying a node affinity
+func (d *dispatcher) filterByAffinity(diffMap map[string]int, affinity *integration.NodeAffinity) map[string]int {
+	if affinity == nil {
+		return diffMap
+	}
+
+	d.store.RLock()
+	defer d.store.RUnlock()
+
+	filtered := make(map[string]int, len(diffMap))
+	for nodeName, diff := range diffMap {
+		if node, found := d.store.nodes[nodeName]; found && node.matchesAffinity(affinity) {
+			filtered[nodeName] = diff
+		}
+	}
+	return filtered
+}
+
 // moveCheck moves a check by its ID from a node to another
 func (d *dispatcher) moveCheck(src, dest, checkID string) error {
 	log.Debugf("Moving %s from %s to %s", checkID, src, dest)
@@ -200,13 +241,12 @@ func (d *dispatcher) rebalance() []types.RebalanceResponse {
 		for diffMap[nodeWeight.nodeName] > 0 {
 			// try to move checks from a node only of the node busyness is above the average
 			sourceNodeName := nodeWeight.nodeName
-			checkID, checkWeight, err := d.pickCheckToMove(sourceNodeName)
+			checkID, checkWeight, destNodeName, err := d.pickCheckToMove(sourceNodeName, diffMap)
 			if err != nil {
 				log.Debugf("Cannot pick a check to move from node %s: %v", sourceNodeName, err)
 				break
 			}
 
-			destNodeName := pickNode(diffMap, sourceNodeName)
 			sourceDiff := diffMap[sourceNodeName]
 			destDiff := diffMap[destNodeName]
 
@@ -1383,6 +1383,130 @@ func TestRebalance(t *testing.T) {
 	}
 }
 
+func TestRebalanceNodeAffinity(t *testing.T) {
+	for i, tc := range []struct {
+		affinity *integration.NodeAffinity
+		dest     string
+	}{
+		{
+			affinity: nil,
+			dest:     "B",
+		},
+		{
+			affinity: &integration.NodeAffinity{Zone: "zone-b"},
+			dest:     "B",
+		},
+		{
+			affinity: &integration.NodeAffinity{Zone: "zone-a"},
+			dest:     "A",
+		},
+	} {
+		t.Run(fmt.Sprintf("case %d", i), func(t *testing.T) {
+			dispatcher := newDispatcher()
+			config := integration.Config{
+				Name: "check1",
+				Instances: []integration.Data{
+					integration.Data(""),
+				},
+				InitConfig:   integration.Data(""),
+				NodeAffinity: tc.affinity,
+			}
+			id := check.BuildID(config.Name, config.Instances[0], config.InitConfig)
+			otherConfig := integration.Config{
+				Name: "check2",
+				Instances: []integration.Data{
+					integration.Data(""),
+				},
+				InitConfig: integration.Data(""),
+			}
+			otherID := check.BuildID(otherConfig.Name, otherConfig.Instances[0], otherConfig.InitConfig)
+
+			// prepare store
+			dispatcher.store.active = true
+			dispatcher.store.nodes["A"] = newNodeStore("A", "")
+			dispatcher.store.nodes["A"].labels = map[string]string{"topology.kubernetes.io/zone": "zone-a"}
+			dispatcher.store.nodes["B"] = newNodeStore("B", "")
+			dispatcher.store.nodes["B"].labels = map[string]string{"topology.kubernetes.io/zone": "zone-b"}
+			dispatcher.addConfig(config, "A")
+			dispatcher.addConfig(otherConfig, "A")
+			dispatcher.store.nodes["A"].clcRunnerStats = types.CLCRunnersStats{
+				string(id): types.CLCRunnerStats{
+					AverageExecutionTime: 100,
+					MetricSamples:        10,
+					IsClusterCheck:       true,
+				},
+				string(otherID): types.CLCRunnerStats{
+					AverageExecutionTime: 50,
+					MetricSamples:        10,
+					IsClusterCheck:       true,
+				},
+			}
+
+			// rebalance checks
+			dispatcher.rebalance()
+
+			assert.Equal(t, tc.dest, dispatcher.store.digestToNode[config.Digest()])
+
+			requireNotLocked(t, dispatcher.store)
+		})
+	}
+}
+
+func TestRebalancePinnedHeaviestCheck(t *testing.T) {
+	dispatcher := newDispatcher()
+	pinnedConfig := integration.Config{
+		Name: "check1",
+		Instances: []integration.Data{
+			integration.Data(""),
+		},
+		InitConfig:   integration.Data(""),
+		NodeAffinity: &integration.NodeAffinity{Zone: "zone-a"},
+	}
+	pinnedID := check.BuildID(pinnedConfig.Name, pinnedConfig.Instances[0], pinnedConfig.InitConfig)
+	otherConfig := integration.Config{
+		Name: "check2",
+		Instances: []integration.Data{
+			integration.Data(""),
+		},
+		InitConfig: integration.Data(""),
+	}
+	otherID := check.BuildID(otherConfig.Name, otherConfig.Instances[0], otherConfig.InitConfig)
+
+	// prepare store
+	dispatcher.store.active = true
+	dispatcher.store.nodes["A"] = newNodeStore("A", "")
+	dispatcher.store.nodes["A"].labels = map[string]string{"topology.kubernetes.io/zone": "zone-a"}
+	dispatcher.store.nodes["B"] = newNodeStore("B", "")
+	dispatcher.store.nodes["B"].labels = map[string]string{"topology.kubernetes.io/zone": "zone-b"}
+	dispatcher.addConfig(pinnedConfig, "A")
+	dispatcher.addConfig(otherConfig, "A")
+	dispatcher.store.nodes["A"].clcRunnerStats = types.CLCRunnersStats{
+		string(pinnedID): types.CLCRunnerStats{
+			AverageExecutionTime: 100,
+			MetricSamples:        10,
+			IsClusterCheck:       true,
+		},
+		string(otherID): types.CLCRunnerStats{
+			AverageExecutionTime: 50,
+			MetricSamples:        10,
+			IsClusterCheck:       true,
+		},
+	}
+
+	// rebalance checks
+	checksMoved := dispatcher.rebalance()
+
+	// the heaviest check can't leave zone-a, the next one is moved instead
+	assert.Equal(t, "A", dispatcher.store.digestToNode[pinnedConfig.Digest()])
+	assert.Equal(t, "B", dispatcher.store.digestToNode[otherConfig.Digest()])
+	assert.Len(t, checksMoved, 1)
+	for _, moved := range checksMoved {
+		assert.Equal(t, string(otherID), moved.CheckID)
+	}
+
+	requireNotLocked(t, dispatcher.store)
+}
+
 func TestMoveCheck(t *testing.T) {
 	type checkInfo struct {
 		config integration.Config
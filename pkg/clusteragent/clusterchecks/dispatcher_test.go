@@ -242,22 +242,22 @@ func TestGetLeastBusyNode(t *testing.T) {
 	dispatcher := newDispatcher()
 
 	// No node registered -> empty string
-	assert.Equal(t, "", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "", dispatcher.getLeastBusyNode(nil))
 
 	// 1 config on node1, 2 on node2
 	dispatcher.addConfig(generateIntegration("A"), "node1")
 	dispatcher.addConfig(generateIntegration("B"), "node2")
 	dispatcher.addConfig(generateIntegration("C"), "node2")
-	assert.Equal(t, "node1", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node1", dispatcher.getLeastBusyNode(nil))
 
 	// 3 configs on node1, 2 on node2
 	dispatcher.addConfig(generateIntegration("D"), "node1")
 	dispatcher.addConfig(generateIntegration("E"), "node1")
-	assert.Equal(t, "node2", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node2", dispatcher.getLeastBusyNode(nil))
 
 	// Add an empty node3
 	dispatcher.processNodeStatus("node3", "10.0.0.3", types.NodeStatus{})
-	assert.Equal(t, "node3", dispatcher.getLeastBusyNode())
+	assert.Equal(t, "node3", dispatcher.getLeastBusyNode(nil))
 
 	requireNotLocked(t, dispatcher.store)
 }
@@ -407,6 +407,45 @@ func TestDanglingConfig(t *testing.T) {
 	assert.Equal(t, 0, len(dispatcher.store.danglingConfigs))
 }
 
+func TestNodeAffinity(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.processNodeStatus("nodeA", "10.0.0.1", types.NodeStatus{
+		Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1a"},
+	})
+	dispatcher.processNodeStatus("nodeB", "10.0.0.2", types.NodeStatus{
+		Labels: map[string]string{"topology.kubernetes.io/zone": "us-east-1b", "pool": "db"},
+	})
+
+	zoneConfig := generateIntegration("zone")
+	zoneConfig.NodeAffinity = &integration.NodeAffinity{Zone: "us-east-1a"}
+	selectorConfig := generateIntegration("selector")
+	selectorConfig.NodeAffinity = &integration.NodeAffinity{NodeSelector: map[string]string{"pool": "db"}}
+	unschedulableConfig := generateIntegration("unschedulable")
+	unschedulableConfig.NodeAffinity = &integration.NodeAffinity{Zone: "us-east-1c"}
+
+	dispatcher.Schedule([]integration.Config{zoneConfig, selectorConfig, unschedulableConfig})
+	assert.Equal(t, "nodeA", dispatcher.store.digestToNode[zoneConfig.Digest()])
+	assert.Equal(t, "nodeB", dispatcher.store.digestToNode[selectorConfig.Digest()])
+	assert.Equal(t, 1, len(dispatcher.store.danglingConfigs))
+
+	state, err := dispatcher.getState()
+	assert.NoError(t, err)
+	assert.Len(t, state.Dangling, 1)
+	assert.Len(t, state.Unschedulable, 1)
+	assert.Equal(t, "unschedulable", state.Unschedulable[0].Name)
+
+	// A node in the requested zone joins, the config is dispatched on it
+	dispatcher.processNodeStatus("nodeC", "10.0.0.3", types.NodeStatus{
+		Labels: map[string]string{"failure-domain.beta.kubernetes.io/zone": "us-east-1c"},
+	})
+	assert.True(t, dispatcher.shouldDispatchDanling())
+	dispatcher.reschedule(dispatcher.retrieveAndClearDangling())
+	assert.Equal(t, "nodeC", dispatcher.store.digestToNode[unschedulableConfig.Digest()])
+	assert.Equal(t, 0, len(dispatcher.store.danglingConfigs))
+
+	requireNotLocked(t, dispatcher.store)
+}
+
 func TestReset(t *testing.T) {
 	dispatcher := newDispatcher()
 	config := generateIntegration("cluster-check")
@@ -10,6 +10,7 @@ package clusterchecks
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
@@ -90,6 +91,7 @@ type nodeStore struct {
 	clientIP         string
 	clcRunnerStats   types.CLCRunnersStats
 	busyness         int
+	labels           map[string]string
 }
 
 func newNodeStore(name, clientIP string) *nodeStore {
@@ -119,6 +121,14 @@ func (s *nodeStore) removeConfig(digest string) {
 	dispatchedConfigs.Dec(s.name, le.JoinLeaderValue)
 }
 
+// matchesAffinity returns whether the node satisfies a node affinity
+// The nodeStore handles thread safety for this public method
+func (s *nodeStore) matchesAffinity(affinity *integration.NodeAffinity) bool {
+	s.RLock()
+	defer s.RUnlock()
+	return affinity.Matches(s.labels)
+}
+
 // AddRunnerStats stores runner stats for a check
 // The nodeStore handles thread safety for this public method
 func (s *nodeStore) AddRunnerStats(checkID string, stats types.CLCRunnerStats) {
@@ -164,30 +174,31 @@ func (s *nodeStore) GetBusyness(busynessFunc func(stats types.CLCRunnerStats) in
 	return busyness
 }
 
-// GetMostWeightedClusterCheck returns the Cluster Check with the most weight on the node
+// GetClusterChecksByWeight returns the Cluster Checks running on the node, the most weighted first
 // The nodeStore handles thread safety for this public method
-func (s *nodeStore) GetMostWeightedClusterCheck(busynessFunc func(stats types.CLCRunnerStats) int) (string, int, error) {
+func (s *nodeStore) GetClusterChecksByWeight(busynessFunc func(stats types.CLCRunnerStats) int) ([]clusterCheckWeight, error) {
 	s.RLock()
 	defer s.RUnlock()
 	if len(s.clcRunnerStats) == 0 {
 		log.Debugf("Node %s has no check stats", s.name)
-		return "", -1, fmt.Errorf("node %s has no check stats", s.name)
+		return nil, fmt.Errorf("node %s has no check stats", s.name)
 	}
-	firstItr := true
-	checkID := ""
-	checkWeight := 0
+	checks := []clusterCheckWeight{}
 	for id, stats := range s.clcRunnerStats {
-		busyness := busynessFunc(stats)
-		if (busyness > checkWeight || firstItr) && stats.IsClusterCheck {
+		if stats.IsClusterCheck {
 			// Only consider Cluster Checks
-			checkWeight = busyness
-			checkID = id
-			firstItr = false
+			checks = append(checks, clusterCheckWeight{checkID: id, weight: busynessFunc(stats)})
 		}
 	}
-	if firstItr {
+	if len(checks) == 0 {
 		log.Debugf("Node %s has no check stats for cluster checks: %v", s.name, s.clcRunnerStats)
-		return "", -1, fmt.Errorf("no cluster checks found on node %s", s.name)
+		return nil, fmt.Errorf("no cluster checks found on node %s", s.name)
 	}
-	return checkID, checkWeight, nil
+	sort.Slice(checks, func(i, j int) bool {
+		if checks[i].weight != checks[j].weight {
+			return checks[i].weight > checks[j].weight
+		}
+		return checks[i].checkID < checks[j].checkID
+	})
+	return checks, nil
 }
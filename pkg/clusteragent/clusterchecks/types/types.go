@@ -11,7 +11,8 @@ import (
 
 // NodeStatus holds the status report from the node-agent
 type NodeStatus struct {
-	LastChange int64 `json:"last_change"`
+	LastChange int64             `json:"last_change"`
+	Labels     map[string]string `json:"labels,omitempty"` // Labels of the Kubernetes node, used for node affinity
 }
 
 // StatusResponse holds the DCA response for a status report
@@ -39,15 +40,17 @@ type ConfigResponse struct {
 
 // StateResponse holds the DCA response for a dispatching state query
 type StateResponse struct {
-	NotRunning string               `json:"not_running"` // Reason why not running, empty if leading
-	Warmup     bool                 `json:"warmup"`
-	Nodes      []StateNodeResponse  `json:"nodes"`
-	Dangling   []integration.Config `json:"dangling"`
+	NotRunning    string               `json:"not_running"` // Reason why not running, empty if leading
+	Warmup        bool                 `json:"warmup"`
+	Nodes         []StateNodeResponse  `json:"nodes"`
+	Dangling      []integration.Config `json:"dangling"`
+	Unschedulable []integration.Config `json:"unschedulable"` // Dangling configs whose node affinity matches no node
 }
 
 // StateNodeResponse is a chunk of StateResponse
 type StateNodeResponse struct {
 	Name    string               `json:"name"`
+	Labels  map[string]string    `json:"labels,omitempty"`
 	Configs []integration.Config `json:"configs"`
 }
 
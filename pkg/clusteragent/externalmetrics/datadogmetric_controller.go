@@ -271,6 +271,7 @@ func (c *DatadogMetricController) syncDatadogMetric(ns, name, datadogMetricKey s
 	// Spec source of truth is Kubernetes object
 	// Status source of truth is our local store
 	datadogMetricInternal.UpdateFrom(datadogMetric.Spec)
+	datadogMetricInternal.UpdateFallbackFrom(datadogMetric.Annotations)
 	defer c.store.UnlockSet(datadogMetricInternal.ID, *datadogMetricInternal, ddmControllerStoreID)
 
 	if datadogMetricInternal.IsNewerThan(datadogMetric.Status) {
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package externalmetrics
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	fallbackSourcePodMetric = "pod_metric"
+	fallbackSourceValue     = "value"
+)
+
+var podMetricsGVR = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+// getFallbackValue returns the value of a fallback and its source, the pod
+// metric if it can be retrieved, the static value otherwise
+func getFallbackValue(client dynamic.Interface, ns string, fallback *model.DatadogMetricFallback) (float64, string, error) {
+	var podMetricErr error
+	if fallback.PodMetric != "" {
+		value, err := getPodMetric(client, ns, fallback)
+		if err == nil {
+			return value, fallbackSourcePodMetric, nil
+		}
+		podMetricErr = fmt.Errorf("unable to get %s usage of pods: %v", fallback.PodMetric, err)
+	}
+
+	if fallback.Value != nil {
+		if podMetricErr != nil {
+			log.Debugf("Using static fallback value: %v", podMetricErr)
+		}
+		return *fallback.Value, fallbackSourceValue, nil
+	}
+
+	return 0, "", podMetricErr
+}
+
+// getPodMetric sums the resource usage of the containers of the pods matching
+// the fallback selector, from the resource metrics API.
+// The CPU is in cores and the memory in bytes.
+func getPodMetric(client dynamic.Interface, ns string, fallback *model.DatadogMetricFallback) (float64, error) {
+	if client == nil {
+		return 0, fmt.Errorf("no client to query the resource metrics API")
+	}
+
+	podMetrics, err := client.Resource(podMetricsGVR).Namespace(ns).List(context.TODO(), metav1.ListOptions{LabelSelector: fallback.PodSelector.String()})
+	if err != nil {
+		return 0, err
+	}
+	if len(podMetrics.Items) == 0 {
+		return 0, fmt.Errorf("no pod metrics matching %q", fallback.PodSelector.String())
+	}
+
+	var sum float64
+	for _, podMetric := range podMetrics.Items {
+		containers, _, err := unstructured.NestedSlice(podMetric.Object, "containers")
+		if err != nil {
+			return 0, err
+		}
+		for _, container := range containers {
+			containerMap, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			usage, found, err := unstructured.NestedString(containerMap, "usage", string(fallback.PodMetric))
+			if err != nil || !found {
+				continue
+			}
+			quantity, err := resource.ParseQuantity(usage)
+			if err != nil {
+				return 0, fmt.Errorf("unable to parse usage of pod %s: %v", podMetric.GetName(), err)
+			}
+			sum += quantity.AsApproximateFloat64()
+		}
+	}
+
+	return sum, nil
+}
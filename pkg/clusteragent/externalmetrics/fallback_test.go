@@ -0,0 +1,214 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package externalmetrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
+
+	"github.com/kubernetes-sigs/custom-metrics-apiserver/pkg/provider"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPodMetrics(ns, name string, podLabels map[string]interface{}, usages ...map[string]interface{}) *unstructured.Unstructured {
+	containers := make([]interface{}, 0, len(usages))
+	for i, usage := range usages {
+		containers = append(containers, map[string]interface{}{
+			"name":  fmt.Sprintf("container-%d", i),
+			"usage": usage,
+		})
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "PodMetrics",
+			"metadata": map[string]interface{}{
+				"namespace": ns,
+				"name":      name,
+				"labels":    podLabels,
+			},
+			"containers": containers,
+		},
+	}
+}
+
+func newPodMetricsClient(t *testing.T, podMetrics ...*unstructured.Unstructured) dynamic.Interface {
+	t.Helper()
+
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podMetricsGVR: "PodMetricsList",
+	})
+	for _, podMetric := range podMetrics {
+		_, err := client.Resource(podMetricsGVR).Namespace(podMetric.GetNamespace()).Create(context.TODO(), podMetric, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	return client
+}
+
+func TestGetFallbackValue(t *testing.T) {
+	staticValue := 10.0
+	client := newPodMetricsClient(t,
+		newPodMetrics("ns", "nginx-0", map[string]interface{}{"app": "nginx"},
+			map[string]interface{}{"cpu": "250m", "memory": "64Mi"},
+			map[string]interface{}{"cpu": "50m", "memory": "16Mi"},
+		),
+		newPodMetrics("ns", "nginx-1", map[string]interface{}{"app": "nginx"},
+			map[string]interface{}{"cpu": "200m", "memory": "48Mi"},
+		),
+		newPodMetrics("ns", "redis-0", map[string]interface{}{"app": "redis"},
+			map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+		),
+		newPodMetrics("other-ns", "nginx-0", map[string]interface{}{"app": "nginx"},
+			map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+		),
+	)
+
+	tests := []struct {
+		desc           string
+		client         dynamic.Interface
+		fallback       *model.DatadogMetricFallback
+		expectedValue  float64
+		expectedSource string
+		expectedError  bool
+	}{
+		{
+			desc:           "static value",
+			client:         client,
+			fallback:       &model.DatadogMetricFallback{Value: &staticValue},
+			expectedValue:  10.0,
+			expectedSource: fallbackSourceValue,
+		},
+		{
+			desc: "pod cpu usage",
+			fallback: &model.DatadogMetricFallback{
+				PodMetric:   corev1.ResourceCPU,
+				PodSelector: labels.SelectorFromSet(labels.Set{"app": "nginx"}),
+			},
+			client:         client,
+			expectedValue:  0.5,
+			expectedSource: fallbackSourcePodMetric,
+		},
+		{
+			desc: "pod memory usage",
+			fallback: &model.DatadogMetricFallback{
+				PodMetric:   corev1.ResourceMemory,
+				PodSelector: labels.SelectorFromSet(labels.Set{"app": "nginx"}),
+			},
+			client:         client,
+			expectedValue:  128 * 1024 * 1024,
+			expectedSource: fallbackSourcePodMetric,
+		},
+		{
+			desc: "no matching pod, static value",
+			fallback: &model.DatadogMetricFallback{
+				Value:       &staticValue,
+				PodMetric:   corev1.ResourceCPU,
+				PodSelector: labels.SelectorFromSet(labels.Set{"app": "postgres"}),
+			},
+			client:         client,
+			expectedValue:  10.0,
+			expectedSource: fallbackSourceValue,
+		},
+		{
+			desc: "no client, no static value",
+			fallback: &model.DatadogMetricFallback{
+				PodMetric:   corev1.ResourceCPU,
+				PodSelector: labels.Everything(),
+			},
+			expectedError: true,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#%d %s", i, tt.desc), func(t *testing.T) {
+			value, source, err := getFallbackValue(tt.client, "ns", tt.fallback)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expectedValue, value, 0.0001)
+			assert.Equal(t, tt.expectedSource, source)
+		})
+	}
+}
+
+func TestGetExternalMetricsFallback(t *testing.T) {
+	staticValue := 10.0
+	datadogMetricProvider := datadogMetricProvider{
+		store:            NewDatadogMetricsInternalStore(),
+		autogenNamespace: "default",
+		fallbackClient: newPodMetricsClient(t,
+			newPodMetrics("ns", "nginx-0", map[string]interface{}{"app": "nginx"}, map[string]interface{}{"cpu": "250m"}),
+		),
+	}
+	for _, ddm := range []model.DatadogMetricInternal{
+		{
+			ID:         "ns/metric0",
+			UpdateTime: time.Now().UTC(),
+			Valid:      false,
+			Error:      fmt.Errorf("Some error"),
+			Fallback:   &model.DatadogMetricFallback{Value: &staticValue},
+		},
+		{
+			ID:         "ns/metric1",
+			UpdateTime: time.Now().UTC(),
+			Valid:      false,
+			Error:      fmt.Errorf("Some error"),
+			Fallback: &model.DatadogMetricFallback{
+				PodMetric:   corev1.ResourceCPU,
+				PodSelector: labels.SelectorFromSet(labels.Set{"app": "nginx"}),
+			},
+		},
+		{
+			ID:         "ns/metric2",
+			UpdateTime: time.Now().UTC(),
+			Valid:      false,
+			Error:      fmt.Errorf("Some error"),
+			Fallback: &model.DatadogMetricFallback{
+				PodMetric:   corev1.ResourceCPU,
+				PodSelector: labels.SelectorFromSet(labels.Set{"app": "redis"}),
+			},
+		},
+	} {
+		datadogMetricProvider.store.Set(ddm.ID, ddm, "utest")
+	}
+
+	for metricName, expectedValue := range map[string]resource.Quantity{
+		"datadogmetric@ns:metric0": resource.MustParse("10"),
+		"datadogmetric@ns:metric1": resource.MustParse("0.25"),
+	} {
+		externalMetrics, err := datadogMetricProvider.getExternalMetric("default", labels.Everything(), provider.ExternalMetricInfo{Metric: metricName})
+		require.NoError(t, err)
+		require.Len(t, externalMetrics.Items, 1)
+		assert.Equal(t, metricName, externalMetrics.Items[0].MetricName)
+		assert.Zero(t, expectedValue.Cmp(externalMetrics.Items[0].Value), "expected %s, got %s", expectedValue.String(), externalMetrics.Items[0].Value.String())
+	}
+
+	_, err := datadogMetricProvider.getExternalMetric("default", labels.Everything(), provider.ExternalMetricInfo{Metric: "datadogmetric@ns:metric2"})
+	assert.Error(t, err)
+}
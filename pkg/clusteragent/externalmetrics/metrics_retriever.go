@@ -26,20 +26,22 @@ const (
 )
 
 type MetricsRetriever struct {
-	refreshPeriod int64
-	metricsMaxAge int64
-	processor     autoscalers.ProcessorInterface
-	store         *DatadogMetricsInternalStore
-	isLeader      func() bool
+	refreshPeriod   int64
+	metricsMaxAge   int64
+	stalenessMaxAge int64
+	processor       autoscalers.ProcessorInterface
+	store           *DatadogMetricsInternalStore
+	isLeader        func() bool
 }
 
-func NewMetricsRetriever(refreshPeriod, metricsMaxAge int64, processor autoscalers.ProcessorInterface, isLeader func() bool, store *DatadogMetricsInternalStore) (*MetricsRetriever, error) {
+func NewMetricsRetriever(refreshPeriod, metricsMaxAge, stalenessMaxAge int64, processor autoscalers.ProcessorInterface, isLeader func() bool, store *DatadogMetricsInternalStore) (*MetricsRetriever, error) {
 	return &MetricsRetriever{
-		refreshPeriod: refreshPeriod,
-		metricsMaxAge: metricsMaxAge,
-		processor:     processor,
-		store:         store,
-		isLeader:      isLeader,
+		refreshPeriod:   refreshPeriod,
+		metricsMaxAge:   metricsMaxAge,
+		stalenessMaxAge: stalenessMaxAge,
+		processor:       processor,
+		store:           store,
+		isLeader:        isLeader,
 	}, nil
 }
 
@@ -101,10 +103,23 @@ func (mr *MetricsRetriever) retrieveMetricsValues() {
 					maxAge = time.Duration(mr.metricsMaxAge) * time.Second
 				}
 
-				if time.Duration(currentTime.Unix()-queryResult.Timestamp)*time.Second <= maxAge {
+				// With the last-value staleness policy, outdated data is still served up to another max age
+				stalenessMaxAge := datadogMetric.StalenessMaxAge
+				if stalenessMaxAge == 0 {
+					stalenessMaxAge = time.Duration(mr.stalenessMaxAge) * time.Second
+				}
+
+				dataAge := time.Duration(currentTime.Unix()-queryResult.Timestamp) * time.Second
+				if dataAge <= maxAge {
 					datadogMetricFromStore.Valid = true
 					datadogMetricFromStore.Error = nil
 					datadogMetricFromStore.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
+				} else if datadogMetric.StalenessPolicy == model.StalenessPolicyLastValue && dataAge <= stalenessMaxAge {
+					// Keep serving the outdated value, the error is only reported in the status.
+					// The update time stays the timestamp of the data as the value is not refreshed.
+					datadogMetricFromStore.Valid = true
+					datadogMetricFromStore.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, query)
+					datadogMetricFromStore.UpdateTime = time.Unix(queryResult.Timestamp, 0).UTC()
 				} else {
 					datadogMetricFromStore.Valid = false
 					datadogMetricFromStore.Error = fmt.Errorf(invalidMetricOutdatedErrorMessage, query)
@@ -43,12 +43,13 @@ type ddmWithQuery struct {
 }
 
 type metricsFixture struct {
-	desc         string
-	maxAge       int64
-	storeContent []ddmWithQuery
-	queryResults map[string]autoscalers.Point
-	queryError   error
-	expected     []ddmWithQuery
+	desc            string
+	maxAge          int64
+	stalenessMaxAge int64
+	storeContent    []ddmWithQuery
+	queryResults    map[string]autoscalers.Point
+	queryError      error
+	expected        []ddmWithQuery
 }
 
 func (f *metricsFixture) run(t *testing.T, testTime time.Time) {
@@ -66,7 +67,7 @@ func (f *metricsFixture) run(t *testing.T, testTime time.Time) {
 		points: f.queryResults,
 		err:    f.queryError,
 	}
-	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, &mockedProcessor, getIsLeaderFunction(true), &store)
+	metricsRetriever, err := NewMetricsRetriever(0, f.maxAge, f.stalenessMaxAge, &mockedProcessor, getIsLeaderFunction(true), &store)
 	assert.Nil(t, err)
 	metricsRetriever.retrieveMetricsValues()
 
@@ -483,6 +484,104 @@ func TestRetrieveMetricsErrorCases(t *testing.T) {
 				},
 			},
 		},
+		{
+			maxAge:          5,
+			stalenessMaxAge: 60,
+			desc:            "Test outdated data with the last-value staleness policy",
+			storeContent: []ddmWithQuery{
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric0",
+						Active:          true,
+						UpdateTime:      defaultPreviousUpdateTime,
+						Valid:           false,
+						Error:           nil,
+						StalenessPolicy: model.StalenessPolicyLastValue,
+					},
+					query: "query-metric0",
+				},
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric1",
+						Active:          true,
+						UpdateTime:      defaultPreviousUpdateTime,
+						Valid:           true,
+						Error:           nil,
+						StalenessPolicy: model.StalenessPolicyFallback,
+					},
+					query: "query-metric1",
+				},
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric2",
+						Active:          true,
+						UpdateTime:      defaultPreviousUpdateTime,
+						Valid:           true,
+						Error:           nil,
+						StalenessPolicy: model.StalenessPolicyLastValue,
+						StalenessMaxAge: 10 * time.Second,
+					},
+					query: "query-metric2",
+				},
+			},
+			queryResults: map[string]autoscalers.Point{
+				"query-metric0": {
+					Value:     10.0,
+					Timestamp: defaultPreviousUpdateTime.Unix(),
+					Valid:     true,
+				},
+				"query-metric1": {
+					Value:     11.0,
+					Timestamp: defaultPreviousUpdateTime.Unix(),
+					Valid:     true,
+				},
+				"query-metric2": {
+					Value:     12.0,
+					Timestamp: defaultPreviousUpdateTime.Unix(),
+					Valid:     true,
+				},
+			},
+			queryError: nil,
+			expected: []ddmWithQuery{
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric0",
+						Active:          true,
+						Value:           10.0,
+						Valid:           true,
+						Error:           fmt.Errorf(invalidMetricOutdatedErrorMessage, "query-metric0"),
+						StalenessPolicy: model.StalenessPolicyLastValue,
+						UpdateTime:      defaultPreviousUpdateTime,
+					},
+					query: "query-metric0",
+				},
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric1",
+						Active:          true,
+						Value:           11.0,
+						Valid:           false,
+						Error:           fmt.Errorf(invalidMetricOutdatedErrorMessage, "query-metric1"),
+						StalenessPolicy: model.StalenessPolicyFallback,
+						// UpdateTime not set as it will not be compared directly
+					},
+					query: "query-metric1",
+				},
+				{
+					ddm: model.DatadogMetricInternal{
+						ID:              "metric2",
+						Active:          true,
+						Value:           12.0,
+						Valid:           false,
+						Error:           fmt.Errorf(invalidMetricOutdatedErrorMessage, "query-metric2"),
+						StalenessPolicy: model.StalenessPolicyLastValue,
+						StalenessMaxAge: 10 * time.Second,
+						// UpdateTime not set as it will not be compared directly
+					},
+					query: "query-metric2",
+				},
+			},
+		},
 	}
 
 	for i, fixture := range fixtures {
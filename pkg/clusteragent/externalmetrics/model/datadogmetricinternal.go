@@ -42,6 +42,9 @@ type DatadogMetricInternal struct {
 	UpdateTime           time.Time
 	Error                error
 	MaxAge               time.Duration
+	Fallback             *DatadogMetricFallback
+	StalenessPolicy      StalenessPolicy
+	StalenessMaxAge      time.Duration
 }
 
 // NewDatadogMetricInternal returns a `DatadogMetricInternal` object from a `DatadogMetric` CRD Object
@@ -77,6 +80,7 @@ func NewDatadogMetricInternal(id string, datadogMetric datadoghq.DatadogMetric)
 	}
 
 	internal.resolveQuery(internal.query)
+	internal.UpdateFallbackFrom(datadogMetric.Annotations)
 
 	// If UpdateTime is not set, it means it's a newly created DatadogMetric
 	// We'll need a proper update time to generate status, so setting to current time
@@ -202,7 +206,16 @@ func (d *DatadogMetricInternal) ToExternalMetricFormat(externalMetricName string
 		return nil, fmt.Errorf("DatadogMetric is invalid, err: %v", d.Error)
 	}
 
-	quantity, err := resource.ParseQuantity(fmt.Sprintf("%v", d.Value))
+	return newExternalMetricValue(externalMetricName, d.Value, d.UpdateTime)
+}
+
+// ToFallbackExternalMetricFormat returns the fallback value of the current DatadogMetric in the format used by Kubernetes
+func (d *DatadogMetricInternal) ToFallbackExternalMetricFormat(externalMetricName string, value float64) (*external_metrics.ExternalMetricValue, error) {
+	return newExternalMetricValue(externalMetricName, value, time.Now().UTC())
+}
+
+func newExternalMetricValue(externalMetricName string, value float64, timestamp time.Time) (*external_metrics.ExternalMetricValue, error) {
+	quantity, err := resource.ParseQuantity(fmt.Sprintf("%v", value))
 	if err != nil {
 		return nil, err
 	}
@@ -211,7 +224,7 @@ func (d *DatadogMetricInternal) ToExternalMetricFormat(externalMetricName string
 		MetricName:   externalMetricName,
 		MetricLabels: nil,
 		Value:        quantity,
-		Timestamp:    metav1.NewTime(d.UpdateTime),
+		Timestamp:    metav1.NewTime(timestamp),
 	}, nil
 }
 
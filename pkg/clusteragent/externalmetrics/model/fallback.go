@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Annotations of `DatadogMetric` objects configuring what to return when the query fails
+const (
+	FallbackValueAnnotation       = "external-metrics.datadoghq.com/fallback-value"
+	FallbackPodMetricAnnotation   = "external-metrics.datadoghq.com/fallback-pod-metric"
+	FallbackPodSelectorAnnotation = "external-metrics.datadoghq.com/fallback-pod-selector"
+	StalenessPolicyAnnotation     = "external-metrics.datadoghq.com/staleness-policy"
+	StalenessMaxAgeAnnotation     = "external-metrics.datadoghq.com/staleness-max-age"
+)
+
+// StalenessPolicy defines what to do when the query returns outdated data,
+// the empty value being StalenessPolicyFallback
+type StalenessPolicy string
+
+const (
+	// StalenessPolicyFallback invalidates the outdated data, the fallback is used if defined
+	StalenessPolicyFallback StalenessPolicy = "fallback"
+	// StalenessPolicyLastValue keeps serving the last value returned by the query,
+	// until it's older than the staleness max age
+	StalenessPolicyLastValue StalenessPolicy = "last-value"
+)
+
+// DatadogMetricFallback defines the value returned for an invalid `DatadogMetric`.
+// The pod metric, summed over the pods matching the selector in the namespace
+// of the `DatadogMetric`, is used if defined, otherwise or if it cannot be
+// retrieved, the static value is used.
+type DatadogMetricFallback struct {
+	Value       *float64
+	PodMetric   corev1.ResourceName
+	PodSelector labels.Selector
+}
+
+// UpdateFallbackFrom updates the fallback, the staleness policy and its max age from the `DatadogMetric` annotations
+func (d *DatadogMetricInternal) UpdateFallbackFrom(annotations map[string]string) {
+	fallback, err := parseFallback(annotations)
+	if err != nil {
+		log.Errorf("Invalid fallback for DatadogMetric %s, ignoring it: %v", d.ID, err)
+	}
+	d.Fallback = fallback
+
+	d.StalenessPolicy = ""
+	if policy, found := annotations[StalenessPolicyAnnotation]; found {
+		switch StalenessPolicy(strings.ToLower(policy)) {
+		case StalenessPolicyFallback:
+			d.StalenessPolicy = StalenessPolicyFallback
+		case StalenessPolicyLastValue:
+			d.StalenessPolicy = StalenessPolicyLastValue
+		default:
+			log.Errorf("Unknown staleness policy %q for DatadogMetric %s, defaulting to %q", policy, d.ID, StalenessPolicyFallback)
+		}
+	}
+
+	d.StalenessMaxAge = 0
+	if rawMaxAge, found := annotations[StalenessMaxAgeAnnotation]; found {
+		maxAge, err := time.ParseDuration(rawMaxAge)
+		if err != nil || maxAge <= 0 {
+			log.Errorf("Invalid staleness max age %q for DatadogMetric %s, using the default one", rawMaxAge, d.ID)
+		} else {
+			d.StalenessMaxAge = maxAge
+		}
+	}
+}
+
+func parseFallback(annotations map[string]string) (*DatadogMetricFallback, error) {
+	rawValue, hasValue := annotations[FallbackValueAnnotation]
+	podMetric, hasPodMetric := annotations[FallbackPodMetricAnnotation]
+	if !hasValue && !hasPodMetric {
+		return nil, nil
+	}
+
+	fallback := &DatadogMetricFallback{}
+	if hasValue {
+		value, err := strconv.ParseFloat(rawValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %v", FallbackValueAnnotation, err)
+		}
+		fallback.Value = &value
+	}
+
+	if hasPodMetric {
+		switch corev1.ResourceName(podMetric) {
+		case corev1.ResourceCPU, corev1.ResourceMemory:
+			fallback.PodMetric = corev1.ResourceName(podMetric)
+		default:
+			return nil, fmt.Errorf("unsupported %s %q, expected %s or %s", FallbackPodMetricAnnotation, podMetric, corev1.ResourceCPU, corev1.ResourceMemory)
+		}
+
+		selector, err := labels.Parse(annotations[FallbackPodSelectorAnnotation])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %v", FallbackPodSelectorAnnotation, err)
+		}
+		fallback.PodSelector = selector
+	}
+
+	return fallback, nil
+}
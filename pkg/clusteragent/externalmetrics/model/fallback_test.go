@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func TestDatadogMetricInternal_UpdateFallbackFrom(t *testing.T) {
+	value := 42.5
+	tests := []struct {
+		name                    string
+		annotations             map[string]string
+		expectedFallback        *DatadogMetricFallback
+		expectedStalenessPolicy StalenessPolicy
+		expectedStalenessMaxAge time.Duration
+	}{
+		{
+			name:        "no fallback",
+			annotations: map[string]string{"foo": "bar"},
+		},
+		{
+			name: "static value",
+			annotations: map[string]string{
+				FallbackValueAnnotation: "42.5",
+			},
+			expectedFallback: &DatadogMetricFallback{Value: &value},
+		},
+		{
+			name: "pod metric and static value",
+			annotations: map[string]string{
+				FallbackValueAnnotation:       "42.5",
+				FallbackPodMetricAnnotation:   "cpu",
+				FallbackPodSelectorAnnotation: "app=nginx",
+				StalenessPolicyAnnotation:     "last-value",
+				StalenessMaxAgeAnnotation:     "15m",
+			},
+			expectedFallback: &DatadogMetricFallback{
+				Value:       &value,
+				PodMetric:   corev1.ResourceCPU,
+				PodSelector: labels.SelectorFromSet(labels.Set{"app": "nginx"}),
+			},
+			expectedStalenessPolicy: StalenessPolicyLastValue,
+			expectedStalenessMaxAge: 15 * time.Minute,
+		},
+		{
+			name: "invalid static value",
+			annotations: map[string]string{
+				FallbackValueAnnotation: "foo",
+			},
+		},
+		{
+			name: "unsupported pod metric",
+			annotations: map[string]string{
+				FallbackPodMetricAnnotation: "storage",
+			},
+		},
+		{
+			name: "invalid pod selector",
+			annotations: map[string]string{
+				FallbackPodMetricAnnotation:   "memory",
+				FallbackPodSelectorAnnotation: "app in nginx",
+			},
+		},
+		{
+			name: "unknown staleness policy",
+			annotations: map[string]string{
+				StalenessPolicyAnnotation: "ignore",
+			},
+		},
+		{
+			name: "invalid staleness max age",
+			annotations: map[string]string{
+				StalenessPolicyAnnotation: "last-value",
+				StalenessMaxAgeAnnotation: "15",
+			},
+			expectedStalenessPolicy: StalenessPolicyLastValue,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ddm := &DatadogMetricInternal{ID: "default/dd-metric-0"}
+			ddm.UpdateFallbackFrom(tt.annotations)
+			assert.Equal(t, tt.expectedFallback, ddm.Fallback)
+			assert.Equal(t, tt.expectedStalenessPolicy, ddm.StalenessPolicy)
+			assert.Equal(t, tt.expectedStalenessMaxAge, ddm.StalenessMaxAge)
+		})
+	}
+}
@@ -17,8 +17,11 @@ import (
 	"github.com/kubernetes-sigs/custom-metrics-apiserver/pkg/provider"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/metrics/pkg/apis/external_metrics"
 
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/externalmetrics/model"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/common"
@@ -35,6 +38,7 @@ type datadogMetricProvider struct {
 	apiCl            *apiserver.APIClient
 	store            DatadogMetricsInternalStore
 	autogenNamespace string
+	fallbackClient   dynamic.Interface
 }
 
 func NewDatadogMetricProvider(ctx context.Context, apiCl *apiserver.APIClient) (provider.ExternalMetricsProvider, error) {
@@ -53,12 +57,14 @@ func NewDatadogMetricProvider(ctx context.Context, apiCl *apiserver.APIClient) (
 
 	refreshPeriod := config.Datadog.GetInt64("external_metrics_provider.refresh_period")
 	retrieverMetricsMaxAge := int64(math.Max(config.Datadog.GetFloat64("external_metrics_provider.max_age"), float64(3*rollup)))
+	retrieverStalenessMaxAge := config.Datadog.GetInt64("external_metrics_provider.staleness_max_age")
 	autogenNamespace := common.GetResourcesNamespace()
 
 	provider := &datadogMetricProvider{
 		apiCl:            apiCl,
 		store:            NewDatadogMetricsInternalStore(),
 		autogenNamespace: autogenNamespace,
+		fallbackClient:   apiCl.DynamicCl,
 	}
 
 	// Start MetricsRetriever, only leader will do refresh metrics
@@ -67,7 +73,7 @@ func NewDatadogMetricProvider(ctx context.Context, apiCl *apiserver.APIClient) (
 		return nil, fmt.Errorf("Unable to create DatadogMetricProvider as DatadogClient failed with: %v", err)
 	}
 
-	metricsRetriever, err := NewMetricsRetriever(refreshPeriod, retrieverMetricsMaxAge, autoscalers.NewProcessor(datadogClient), le.IsLeader, &provider.store)
+	metricsRetriever, err := NewMetricsRetriever(refreshPeriod, retrieverMetricsMaxAge, retrieverStalenessMaxAge, autoscalers.NewProcessor(datadogClient), le.IsLeader, &provider.store)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to create DatadogMetricProvider as MetricsRetriever failed with: %v", err)
 	}
@@ -135,6 +141,15 @@ func (p *datadogMetricProvider) getExternalMetric(namespace string, metricSelect
 	}
 
 	externalMetric, err := datadogMetric.ToExternalMetricFormat(info.Metric)
+	if err != nil && datadogMetric.Fallback != nil {
+		externalMetric, err = p.getFallbackExternalMetric(datadogMetric, info.Metric, err)
+	} else if err == nil && datadogMetric.Error != nil {
+		// Outdated value kept by the last-value staleness policy
+		log.Debugf("DatadogMetric %s is outdated, using its last value: %v", datadogMetric.ID, datadogMetric.Error)
+		if ns, name, splitErr := cache.SplitMetaNamespaceKey(datadogMetric.ID); splitErr == nil {
+			incDatadogMetricStaleTelemetry(ns, name)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +159,23 @@ func (p *datadogMetricProvider) getExternalMetric(namespace string, metricSelect
 	}, nil
 }
 
+// getFallbackExternalMetric returns the fallback value of an invalid DatadogMetric
+func (p *datadogMetricProvider) getFallbackExternalMetric(datadogMetric *model.DatadogMetricInternal, metricName string, invalidErr error) (*external_metrics.ExternalMetricValue, error) {
+	ns, name, err := cache.SplitMetaNamespaceKey(datadogMetric.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%v, fallback failed: %v", invalidErr, err)
+	}
+
+	value, source, err := getFallbackValue(p.fallbackClient, ns, datadogMetric.Fallback)
+	if err != nil {
+		return nil, fmt.Errorf("%v, fallback failed: %v", invalidErr, err)
+	}
+
+	log.Debugf("DatadogMetric %s is invalid, using fallback value from %s: %v", datadogMetric.ID, source, value)
+	incDatadogMetricFallbackTelemetry(ns, name, source)
+	return datadogMetric.ToFallbackExternalMetricFormat(metricName, value)
+}
+
 func (p *datadogMetricProvider) ListAllExternalMetrics() []provider.ExternalMetricInfo {
 	datadogMetrics := p.store.GetAll()
 	results := make([]provider.ExternalMetricInfo, 0, len(datadogMetrics))
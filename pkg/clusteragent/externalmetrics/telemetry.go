@@ -25,6 +25,14 @@ var (
 	ddmTelemetry = telemetry.NewGaugeWithOpts("external_metrics", "datadog_metrics",
 		[]string{"namespace", "name", "valid", le.JoinLeaderLabel}, "The label valid is true if the DatadogMetric CR is valid, false otherwise",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+
+	ddmFallbackTelemetry = telemetry.NewCounterWithOpts("external_metrics", "datadog_metrics_fallbacks",
+		[]string{"namespace", "name", "source", le.JoinLeaderLabel}, "Count the external metric requests answered with the fallback of an invalid DatadogMetric, by fallback source",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+
+	ddmStaleTelemetry = telemetry.NewCounterWithOpts("external_metrics", "datadog_metrics_stale_values",
+		[]string{"namespace", "name", le.JoinLeaderLabel}, "Count the external metric requests answered with the outdated value of a DatadogMetric with the last-value staleness policy",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 )
 
 func setDatadogMetricTelemetry(ddm *datadoghq.DatadogMetric) {
@@ -55,3 +63,11 @@ func isDatadogMetricValid(ddm *datadoghq.DatadogMetric) bool {
 
 	return false
 }
+
+func incDatadogMetricFallbackTelemetry(ns, name, source string) {
+	ddmFallbackTelemetry.Inc(ns, name, source, le.JoinLeaderValue)
+}
+
+func incDatadogMetricStaleTelemetry(ns, name string) {
+	ddmStaleTelemetry.Inc(ns, name, le.JoinLeaderValue)
+}
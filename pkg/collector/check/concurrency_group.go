@@ -0,0 +1,14 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+// GroupedCheck is implemented by checks that can be assigned to a concurrency group,
+// limiting the number of checks of that group running at the same time
+type GroupedCheck interface {
+	Check
+	// ConcurrencyGroup returns the name of the group of the check, empty if it has none
+	ConcurrencyGroup() string
+}
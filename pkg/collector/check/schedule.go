@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+)
+
+// Schedule holds the scheduling options of a check instance, on top of its interval
+type Schedule struct {
+	// Cron is a cron expression (e.g. `5 * * * *`) that, when set, replaces the interval
+	Cron string
+	// Jitter is the maximum random delay added to each scheduled run
+	Jitter time.Duration
+}
+
+// IsCustom returns whether the schedule differs from a plain interval-based one
+func (s Schedule) IsCustom() bool {
+	return s.Cron != "" || s.Jitter > 0
+}
+
+// NewSchedule builds the Schedule of a check instance from its common options
+func NewSchedule(instance integration.CommonInstanceConfig) Schedule {
+	return Schedule{
+		Cron:   instance.CronSchedule,
+		Jitter: time.Duration(instance.MinCollectionIntervalJitter) * time.Second,
+	}
+}
+
+// ScheduledCheck is implemented by checks that support a custom Schedule
+type ScheduledCheck interface {
+	Check
+	// Schedule returns the scheduling options of the check
+	Schedule() Schedule
+}
@@ -94,6 +94,9 @@ type Stats struct {
 	ExecutionTimes           [32]int64 // circular buffer of recent run durations, most recent at [(TotalRuns+31) % 32]
 	AverageExecutionTime     int64     // average run duration
 	LastExecutionTime        int64     // most recent run duration, provided for convenience
+	LastQueueTime            int64     // time spent waiting for a concurrency group slot before the most recent run
+	TotalQueuedRuns          uint64    // number of runs that waited for a concurrency group slot
+	TotalSkippedRuns         uint64    // number of runs dropped while waiting for a concurrency group slot
 	LastSuccessDate          int64     // most recent successful execution date, unix timestamp in seconds
 	LastError                string    // error that occurred in the last run, if any
 	LastWarnings             []string  // warnings that occurred in the last run, if any
@@ -209,6 +212,26 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 	}
 }
 
+// AddQueueTime tracks the time the most recent run waited for a concurrency group slot
+func (cs *Stats) AddQueueTime(t time.Duration) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	// store queue times in Milliseconds
+	cs.LastQueueTime = t.Nanoseconds() / 1e6
+	if t > 0 {
+		cs.TotalQueuedRuns++
+	}
+}
+
+// AddSkippedRun tracks a run dropped while waiting for a concurrency group slot
+func (cs *Stats) AddSkippedRun() {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	cs.TotalSkippedRuns++
+}
+
 type aggStats struct {
 	EventPlatformEvents       map[string]interface{}
 	EventPlatformEventsErrors map[string]interface{}
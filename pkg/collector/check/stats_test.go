@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -62,6 +63,28 @@ func TestNewStats(t *testing.T) {
 	assert.Equal(t, stats.CheckConfigSource, "checkConfigSrc")
 }
 
+func TestStatsAddQueueTime(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.AddQueueTime(1500 * time.Millisecond)
+	assert.Equal(t, int64(1500), stats.LastQueueTime)
+	assert.Equal(t, uint64(1), stats.TotalQueuedRuns)
+
+	// Runs that didn't wait only reset the last queue time
+	stats.AddQueueTime(0)
+	assert.Equal(t, int64(0), stats.LastQueueTime)
+	assert.Equal(t, uint64(1), stats.TotalQueuedRuns)
+}
+
+func TestStatsAddSkippedRun(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.AddSkippedRun()
+	stats.AddSkippedRun()
+	assert.Equal(t, uint64(2), stats.TotalSkippedRuns)
+	assert.Equal(t, uint64(0), stats.TotalRuns)
+}
+
 func TestNewStatsStateTelemetryIgnoredWhenGloballyDisabled(t *testing.T) {
 	mockConfig := agentConfig.Mock()
 	mockConfig.Set("telemetry.enabled", false)
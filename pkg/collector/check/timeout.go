@@ -0,0 +1,20 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"time"
+)
+
+// InterruptibleCheck is implemented by checks that support a run timeout
+type InterruptibleCheck interface {
+	Check
+	// Timeout returns the maximum duration of a run, 0 meaning no timeout
+	Timeout() time.Duration
+	// Interrupt asks a running check to stop as soon as possible. It is called
+	// when the run exceeds its timeout, and may block until the check is reachable.
+	Interrupt()
+}
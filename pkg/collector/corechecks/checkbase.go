@@ -6,7 +6,9 @@
 package corechecks
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	yaml "gopkg.in/yaml.v2"
@@ -15,6 +17,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/collector/check/defaults"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 	telemetry_utils "github.com/DataDog/datadog-agent/pkg/telemetry/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -37,15 +40,30 @@ import (
 //
 // If custom tags are set in the instance configuration, they will
 // be automatically appended to each send done by this check.
+//
+// If a `check_timeout` is set in the instance configuration, runs
+// exceeding it are interrupted by cancelling the context returned
+// by Context(), which checks should pass to their blocking calls.
 type CheckBase struct {
 	checkName      string
 	checkID        check.ID
 	latestWarnings []error
 	checkInterval  time.Duration
+	schedule       check.Schedule
+	timeout        time.Duration
+	group          string
+	runContext     *runContext
 	source         string
 	telemetry      bool
 }
 
+// runContext holds the context of the check runs, cancelled when a run is interrupted
+type runContext struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+}
+
 // NewCheckBase returns a check base struct with a given check name
 func NewCheckBase(name string) CheckBase {
 	return NewCheckBaseWithInterval(name, defaults.DefaultCheckInterval)
@@ -57,6 +75,7 @@ func NewCheckBaseWithInterval(name string, defaultInterval time.Duration) CheckB
 		checkName:     name,
 		checkID:       check.ID(name),
 		checkInterval: defaultInterval,
+		runContext:    &runContext{},
 		telemetry:     telemetry_utils.IsCheckEnabled(name),
 	}
 }
@@ -118,6 +137,16 @@ func (c *CheckBase) CommonConfigure(instance integration.Data, source string) er
 		c.checkInterval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 	}
 
+	// See if a cron schedule or a jitter was specified
+	c.schedule = check.NewSchedule(commonOptions)
+
+	// See if a run timeout was specified
+	if commonOptions.CheckTimeout > 0 {
+		c.timeout = time.Duration(commonOptions.CheckTimeout) * time.Second
+	}
+
+	c.group = commonOptions.ConcurrencyGroup
+
 	// Disable default hostname if specified
 	if commonOptions.EmptyDefaultHostname {
 		s, err := c.GetSender()
@@ -148,6 +177,16 @@ func (c *CheckBase) CommonConfigure(instance integration.Data, source string) er
 		s.SetCheckService(commonOptions.Service)
 	}
 
+	// Set histogram aggregation overrides configured for this check
+	if histogramConfig := metrics.NewHistogramConfig(commonOptions); histogramConfig != nil {
+		s, err := c.GetSender()
+		if err != nil {
+			log.Errorf("failed to retrieve a sender for check %s: %s", string(c.ID()), err)
+			return err
+		}
+		s.SetCheckHistogramConfig(histogramConfig)
+	}
+
 	c.source = source
 	return nil
 }
@@ -192,6 +231,55 @@ func (c *CheckBase) Interval() time.Duration {
 	return c.checkInterval
 }
 
+// Schedule returns the cron expression and jitter configured for the check,
+// they are ignored for long-running checks.
+func (c *CheckBase) Schedule() check.Schedule {
+	return c.schedule
+}
+
+// Timeout returns the maximum duration of a run, 0 meaning no timeout
+func (c *CheckBase) Timeout() time.Duration {
+	return c.timeout
+}
+
+// ConcurrencyGroup returns the concurrency group of the check, if any
+func (c *CheckBase) ConcurrencyGroup() string {
+	return c.group
+}
+
+// Context returns the context of the current run, which is cancelled when
+// the run exceeds the check timeout. Checks should get it at the start of
+// their Run method.
+func (c *CheckBase) Context() context.Context {
+	if c.runContext == nil {
+		return context.Background()
+	}
+
+	c.runContext.mu.Lock()
+	defer c.runContext.mu.Unlock()
+
+	if c.runContext.ctx == nil {
+		c.runContext.ctx, c.runContext.cancel = context.WithCancel(context.Background())
+	}
+	return c.runContext.ctx
+}
+
+// Interrupt cancels the context of the current run. The following runs
+// get a new context.
+func (c *CheckBase) Interrupt() {
+	if c.runContext == nil {
+		return
+	}
+
+	c.runContext.mu.Lock()
+	defer c.runContext.mu.Unlock()
+
+	if c.runContext.cancel != nil {
+		c.runContext.cancel()
+	}
+	c.runContext.ctx, c.runContext.cancel = nil, nil
+}
+
 // String returns the name of the check, the same for every instance
 func (c *CheckBase) String() string {
 	return c.checkName
@@ -47,14 +47,15 @@ const (
 
 // KubeASConfig is the config of the API server.
 type KubeASConfig struct {
-	CollectEvent             bool     `yaml:"collect_events"`
-	CollectOShiftQuotas      bool     `yaml:"collect_openshift_clusterquotas"`
-	FilteredEventTypes       []string `yaml:"filtered_event_types"`
-	EventCollectionTimeoutMs int      `yaml:"kubernetes_event_read_timeout_ms"`
-	MaxEventCollection       int      `yaml:"max_events_per_run"`
-	LeaderSkip               bool     `yaml:"skip_leader_election"`
-	ResyncPeriodEvents       int      `yaml:"kubernetes_event_resync_period_s"`
-	UseComponentStatus       bool     `yaml:"use_component_status"`
+	CollectEvent             bool              `yaml:"collect_events"`
+	CollectOShiftQuotas      bool              `yaml:"collect_openshift_clusterquotas"`
+	FilteredEventTypes       []string          `yaml:"filtered_event_types"`
+	EventFilters             []EventFilterRule `yaml:"event_filters"`
+	EventCollectionTimeoutMs int               `yaml:"kubernetes_event_read_timeout_ms"`
+	MaxEventCollection       int               `yaml:"max_events_per_run"`
+	LeaderSkip               bool              `yaml:"skip_leader_election"`
+	ResyncPeriodEvents       int               `yaml:"kubernetes_event_resync_period_s"`
+	UseComponentStatus       bool              `yaml:"use_component_status"`
 }
 
 // EventC holds the information pertaining to which event we collected last and when we last re-synced.
@@ -69,9 +70,11 @@ type KubeASCheck struct {
 	instance        *KubeASConfig
 	eventCollection EventC
 	ignoredEvents   string
+	eventFilter     *eventFilter
 	ac              *apiserver.APIClient
 	oshiftAPILevel  apiserver.OpenShiftAPILevel
 	providerIDCache *cache.Cache
+	eventCounts     *cache.Cache
 }
 
 func (c *KubeASConfig) parse(data []byte) error {
@@ -88,7 +91,9 @@ func NewKubeASCheck(base core.CheckBase, instance *KubeASConfig) *KubeASCheck {
 	return &KubeASCheck{
 		CheckBase:       base,
 		instance:        instance,
+		eventFilter:     &eventFilter{defaultAction: eventActionInclude},
 		providerIDCache: cache.New(defaultCacheExpire, defaultCachePurge),
+		eventCounts:     cache.New(eventCountCacheExpire, eventCountCachePurge),
 	}
 }
 
@@ -119,6 +124,11 @@ func (k *KubeASCheck) Configure(config, initConfig integration.Data, source stri
 	}
 	k.ignoredEvents = convertFilter(k.instance.FilteredEventTypes)
 
+	k.eventFilter, err = newEventFilter(k.instance.EventFilters)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -190,7 +200,7 @@ func (k *KubeASCheck) Run() error {
 			k.Warnf("Could not collect control plane status from ComponentStatus: %s", err.Error()) //nolint:errcheck
 		}
 	} else {
-		err = k.controlPlaneHealthCheck(context.TODO(), sender)
+		err = k.controlPlaneHealthCheck(k.Context(), sender)
 		if err != nil {
 			k.Warnf("Could not collect control plane status from health checks: %s", err.Error()) //nolint:errcheck
 		}
@@ -296,12 +306,21 @@ func (k *KubeASCheck) parseComponentStatus(sender aggregator.Sender, componentsS
 
 // processEvents:
 // - iterates over the Kubernetes Events
+// - applies the event filtering rules, counting some events as metrics
 // - extracts some attributes and builds a structure ready to be submitted as a Datadog event (bundle)
 // - formats the bundle and submit the Datadog event
 func (k *KubeASCheck) processEvents(sender aggregator.Sender, events []*v1.Event) error {
 	eventsByObject := make(map[string]*kubernetesEventBundle)
 
 	for _, event := range events {
+		switch k.eventFilter.action(event) {
+		case eventActionExclude:
+			continue
+		case eventActionMetric:
+			submitEventCount(sender, k.eventCounts, event)
+			continue
+		}
+
 		id := bundleID(event)
 		bundle, found := eventsByObject[id]
 		if found == false {
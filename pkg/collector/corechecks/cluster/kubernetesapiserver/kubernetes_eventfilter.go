@@ -0,0 +1,135 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cache "github.com/patrickmn/go-cache"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+)
+
+// Actions of the event filtering rules
+const (
+	eventActionInclude = "include"
+	eventActionExclude = "exclude"
+	eventActionMetric  = "metric"
+
+	eventCountMetric = "kubernetes_apiserver.events.count"
+
+	// Kubernetes events are garbage collected after an hour by default,
+	// their last seen count doesn't need to be kept longer.
+	eventCountCacheExpire = time.Hour
+	eventCountCachePurge  = 10 * time.Minute
+)
+
+// EventFilterRule matches the Kubernetes events to include, exclude, or
+// count as metrics instead of submitting them as events.
+// An empty list of a rule matches any value.
+type EventFilterRule struct {
+	Action     string   `yaml:"action"`
+	Reasons    []string `yaml:"reasons"`
+	Types      []string `yaml:"types"`
+	Namespaces []string `yaml:"namespaces"`
+	Kinds      []string `yaml:"kinds"`
+}
+
+// eventFilter applies the event filtering rules in order, the first rule
+// matching an event decides of its action. The events matching no rule are
+// submitted, unless an include rule is configured.
+type eventFilter struct {
+	rules         []EventFilterRule
+	defaultAction string
+}
+
+func newEventFilter(rules []EventFilterRule) (*eventFilter, error) {
+	f := &eventFilter{
+		defaultAction: eventActionInclude,
+	}
+	for i, rule := range rules {
+		rule.Action = strings.ToLower(rule.Action)
+		switch rule.Action {
+		case eventActionInclude:
+			f.defaultAction = eventActionExclude
+		case eventActionExclude, eventActionMetric:
+		default:
+			return nil, fmt.Errorf("invalid action %q for event filter #%d, expected one of %s, %s, %s", rule.Action, i, eventActionInclude, eventActionExclude, eventActionMetric)
+		}
+		f.rules = append(f.rules, rule)
+	}
+	return f, nil
+}
+
+// action returns what to do with an event
+func (f *eventFilter) action(event *v1.Event) string {
+	for _, rule := range f.rules {
+		if rule.matches(event) {
+			return rule.Action
+		}
+	}
+	return f.defaultAction
+}
+
+func (r *EventFilterRule) matches(event *v1.Event) bool {
+	return matchesAny(r.Reasons, event.Reason) &&
+		matchesAny(r.Types, event.Type) &&
+		matchesAny(r.Namespaces, event.InvolvedObject.Namespace) &&
+		matchesAny(r.Kinds, event.InvolvedObject.Kind)
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// submitEventCount counts the new occurrences of an event as a metric,
+// without the name of its involved object to keep the cardinality low.
+// The count of a Kubernetes event is cumulative, so only the increase since
+// the last time the event was seen is submitted.
+func submitEventCount(sender aggregator.Sender, counts *cache.Cache, event *v1.Event) {
+	count := event.Count
+	if count == 0 {
+		count = 1
+	}
+
+	delta := count
+	key := string(event.UID)
+	if last, found := counts.Get(key); found {
+		if lastCount := last.(int32); lastCount <= count {
+			delta = count - lastCount
+		}
+	}
+	counts.Set(key, count, cache.DefaultExpiration)
+
+	if delta == 0 {
+		return
+	}
+
+	tags := []string{
+		fmt.Sprintf("reason:%s", event.Reason),
+		fmt.Sprintf("event_type:%s", event.Type),
+		fmt.Sprintf("kubernetes_kind:%s", event.InvolvedObject.Kind),
+		fmt.Sprintf("source_component:%s", event.Source.Component),
+	}
+	if event.InvolvedObject.Namespace != "" {
+		tags = append(tags, fmt.Sprintf("kube_namespace:%s", event.InvolvedObject.Namespace))
+	}
+	sender.Count(eventCountMetric, float64(delta), "", tags)
+}
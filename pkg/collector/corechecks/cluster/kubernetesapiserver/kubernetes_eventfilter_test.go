@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestEventFilterAction(t *testing.T) {
+	backOff := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	scheduled := createEvent(2, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "default-scheduler", "machine-blue", "Scheduled", "Successfully assigned dca-789976f5d7-2ljx6 to ip-10-0-0-54", "Normal", 709662600)
+	nodeReady := createEvent(1, "", "machine-blue", "Node", "e63e74fa-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "NodeReady", "Node machine-blue status is now: NodeReady", "Normal", 709662600)
+
+	for _, tc := range []struct {
+		name  string
+		rules []EventFilterRule
+		want  []string
+	}{
+		{
+			name: "no rules",
+			want: []string{eventActionInclude, eventActionInclude, eventActionInclude},
+		},
+		{
+			name: "exclude and count",
+			rules: []EventFilterRule{
+				{Action: "metric", Reasons: []string{"BackOff"}},
+				{Action: "exclude", Types: []string{"normal"}, Kinds: []string{"Pod"}},
+			},
+			want: []string{eventActionMetric, eventActionExclude, eventActionInclude},
+		},
+		{
+			name: "include",
+			rules: []EventFilterRule{
+				{Action: "Include", Namespaces: []string{"default"}, Types: []string{"Warning"}},
+			},
+			want: []string{eventActionInclude, eventActionExclude, eventActionExclude},
+		},
+		{
+			name: "first matching rule wins",
+			rules: []EventFilterRule{
+				{Action: "include", Reasons: []string{"BackOff"}},
+				{Action: "exclude", Kinds: []string{"Pod"}},
+				{Action: "include", Kinds: []string{"Node"}},
+			},
+			want: []string{eventActionInclude, eventActionExclude, eventActionInclude},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newEventFilter(tc.rules)
+			require.NoError(t, err)
+			got := []string{f.action(backOff), f.action(scheduled), f.action(nodeReady)}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := newEventFilter([]EventFilterRule{{Action: "drop"}})
+	assert.Error(t, err)
+}
+
+func TestProcessEventsFilter(t *testing.T) {
+	ev1 := createEvent(2, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "default-scheduler", "machine-blue", "Scheduled", "Successfully assigned dca-789976f5d7-2ljx6 to ip-10-0-0-54", "Normal", 709662600)
+	ev2 := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	ev3 := createEvent(3, "default", "dca-789976f5d7-6xc8p", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf5", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	ev4 := createEvent(1, "kube-system", "coredns-5d78c9869d-j9zwl", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf6", "kubelet", "machine-blue", "Unhealthy", "Readiness probe failed", "Warning", 709662600)
+	ev2.UID = "2bd9e6b6-3f52-4c84-8e2a-5a0d7e6f1c01"
+	ev3.UID = "2bd9e6b6-3f52-4c84-8e2a-5a0d7e6f1c02"
+
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{})
+	err := kubeASCheck.Configure([]byte(`
+event_filters:
+  - action: metric
+    reasons: [BackOff]
+  - action: exclude
+    namespaces: [kube-system]
+`), nil, "")
+	require.NoError(t, err)
+
+	mocked := mocksender.NewMockSender(kubeASCheck.ID())
+	mocked.On("Event", mock.AnythingOfType("metrics.Event"))
+	mocked.On("Count", eventCountMetric, mock.AnythingOfType("float64"), "", mock.AnythingOfType("[]string"))
+
+	kubeASCheck.processEvents(mocked, []*v1.Event{ev1, ev2, ev3, ev4})
+
+	mocked.AssertNumberOfCalls(t, "Event", 1)
+	assert.Contains(t, mocked.Calls[2].Arguments.Get(0).(metrics.Event).Text, "2 **Scheduled**")
+
+	mocked.AssertNumberOfCalls(t, "Count", 2)
+	tags := []string{"reason:BackOff", "event_type:Warning", "kubernetes_kind:Pod", "source_component:kubelet", "kube_namespace:default"}
+	mocked.AssertMetric(t, "Count", eventCountMetric, 4, "", tags)
+	mocked.AssertMetric(t, "Count", eventCountMetric, 3, "", tags)
+	mocked.AssertExpectations(t)
+}
+
+func TestProcessEventsFilterCountDelta(t *testing.T) {
+	backOff := createEvent(2, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	backOff.UID = "2bd9e6b6-3f52-4c84-8e2a-5a0d7e6f1c01"
+	updated := backOff.DeepCopy()
+	updated.Count = 5
+
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{})
+	err := kubeASCheck.Configure([]byte(`
+event_filters:
+  - action: metric
+    reasons: [BackOff]
+`), nil, "")
+	require.NoError(t, err)
+
+	mocked := mocksender.NewMockSender(kubeASCheck.ID())
+	mocked.On("Count", eventCountMetric, mock.AnythingOfType("float64"), "", mock.AnythingOfType("[]string"))
+
+	tags := []string{"reason:BackOff", "event_type:Warning", "kubernetes_kind:Pod", "source_component:kubelet", "kube_namespace:default"}
+
+	kubeASCheck.processEvents(mocked, []*v1.Event{backOff})
+	mocked.AssertNumberOfCalls(t, "Count", 1)
+	mocked.AssertMetric(t, "Count", eventCountMetric, 2, "", tags)
+
+	// The same event seen again with a higher count only submits the new occurrences
+	mocked.ResetCalls()
+	kubeASCheck.processEvents(mocked, []*v1.Event{updated})
+	mocked.AssertNumberOfCalls(t, "Count", 1)
+	mocked.AssertMetric(t, "Count", eventCountMetric, 3, "", tags)
+
+	// Nothing is submitted if the count didn't change
+	mocked.ResetCalls()
+	kubeASCheck.processEvents(mocked, []*v1.Event{updated})
+	mocked.AssertNumberOfCalls(t, "Count", 0)
+}
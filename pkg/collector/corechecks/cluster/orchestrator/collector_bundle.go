@@ -14,6 +14,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors/inventory"
+	k8sCollectors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors/k8s"
 	"github.com/DataDog/datadog-agent/pkg/orchestrator"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -72,6 +73,13 @@ func (cb *CollectorBundle) prepare() {
 
 // prepareCollectors initializes the bundle collector list.
 func (cb *CollectorBundle) prepareCollectors() {
+	cb.prepareInventoryCollectors()
+	cb.prepareCRCollectors()
+}
+
+// prepareInventoryCollectors adds the collectors of the inventory to the
+// bundle collector list.
+func (cb *CollectorBundle) prepareInventoryCollectors() {
 	// No collector configured in the check configuration.
 	// Use the list of stable collectors as the default.
 	if len(cb.check.instance.Collectors) == 0 {
@@ -93,6 +101,27 @@ func (cb *CollectorBundle) prepareCollectors() {
 	}
 }
 
+// prepareCRCollectors adds a collector for each resource configured in the
+// check configuration to be collected through the dynamic client.
+func (cb *CollectorBundle) prepareCRCollectors() {
+	seen := make(map[string]struct{})
+	for _, resource := range cb.check.instance.CRDCollectors {
+		gvr, err := k8sCollectors.ParseGroupVersionResource(resource)
+		if err != nil {
+			_ = cb.check.Warnf("Unsupported custom resource collector: %s", err)
+			continue
+		}
+
+		name := k8sCollectors.CRCollectorName(gvr)
+		if _, found := seen[name]; found {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		cb.collectors = append(cb.collectors, k8sCollectors.NewCRCollector(gvr))
+	}
+}
+
 // prepareExtraSyncTimeout initializes the bundle extra sync timeout.
 func (cb *CollectorBundle) prepareExtraSyncTimeout() {
 	// No extra timeout set in the check configuration.
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+// +build kubeapiserver,orchestrator
+
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CRCollector is a collector for the Kubernetes resources of a group, version
+// and resource, generally custom resources, collected through the dynamic
+// client.
+type CRCollector struct {
+	gvr         schema.GroupVersionResource
+	discoveryCl discovery.DiscoveryInterface
+	informer    informers.GenericInformer
+	lister      cache.GenericLister
+	metadata    *collectors.CollectorMetadata
+	processor   *processors.Processor
+}
+
+// NewCRCollector creates a new collector for the Kubernetes resources of a
+// group, version and resource.
+func NewCRCollector(gvr schema.GroupVersionResource) *CRCollector {
+	return &CRCollector{
+		gvr: gvr,
+		metadata: &collectors.CollectorMetadata{
+			IsStable: false,
+			Name:     CRCollectorName(gvr),
+			NodeType: orchestrator.K8sCR,
+		},
+		processor: processors.NewProcessor(new(k8sProcessors.CRHandlers)),
+	}
+}
+
+// ParseGroupVersionResource parses a resource configured as
+// "<group>/<version>/<resource>", or "<version>/<resource>" for the resources
+// of the core group.
+func ParseGroupVersionResource(s string) (schema.GroupVersionResource, error) {
+	parts := strings.Split(s, "/")
+	for _, part := range parts {
+		if part == "" {
+			return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q, expected <group>/<version>/<resource>", s)
+		}
+	}
+
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionResource{Version: parts[0], Resource: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionResource{Group: parts[0], Version: parts[1], Resource: parts[2]}, nil
+	default:
+		return schema.GroupVersionResource{}, fmt.Errorf("invalid resource %q, expected <group>/<version>/<resource>", s)
+	}
+}
+
+// CRCollectorName returns the name of the collector of a group, version and
+// resource.
+func CRCollectorName(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Version + "/" + gvr.Resource
+	}
+	return gvr.Group + "/" + gvr.Version + "/" + gvr.Resource
+}
+
+// Informer returns the shared informer.
+func (c *CRCollector) Informer() cache.SharedInformer {
+	return c.informer.Informer()
+}
+
+// Init is used to initialize the collector.
+func (c *CRCollector) Init(rcfg *collectors.CollectorRunConfig) {
+	resyncPeriod := time.Duration(config.Datadog.GetInt64("kubernetes_informers_resync_period")) * time.Second
+	factory := dynamicinformer.NewDynamicSharedInformerFactory(rcfg.APIClient.DynamicCl, resyncPeriod)
+
+	c.discoveryCl = rcfg.APIClient.DiscoveryCl
+	c.informer = factory.ForResource(c.gvr)
+	c.lister = c.informer.Lister()
+}
+
+// IsAvailable returns whether the collector is available, that is whether the
+// resource is served by the API server.
+func (c *CRCollector) IsAvailable() bool {
+	resources, err := c.discoveryCl.ServerResourcesForGroupVersion(c.gvr.GroupVersion().String())
+	if err != nil {
+		log.Debugf("Cannot discover the resources of %s: %v", c.gvr.GroupVersion(), err)
+		return false
+	}
+
+	for _, resource := range resources.APIResources {
+		if resource.Name == c.gvr.Resource {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Metadata is used to access information about the collector.
+func (c *CRCollector) Metadata() *collectors.CollectorMetadata {
+	return c.metadata
+}
+
+// Run triggers the collection process.
+func (c *CRCollector) Run(rcfg *collectors.CollectorRunConfig) (*collectors.CollectorRunResult, error) {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, collectors.NewListingError(err)
+	}
+
+	ctx := &processors.ProcessorContext{
+		APIClient:  rcfg.APIClient,
+		Cfg:        rcfg.Config,
+		ClusterID:  rcfg.ClusterID,
+		MsgGroupID: atomic.AddInt32(rcfg.MsgGroupRef, 1),
+		NodeType:   c.metadata.NodeType,
+	}
+
+	messages, processed := c.processor.Process(ctx, list)
+
+	if processed == -1 {
+		return nil, collectors.ErrProcessingPanic
+	}
+
+	result := &collectors.CollectorRunResult{
+		Messages:           messages,
+		ResourcesListed:    len(list),
+		ResourcesProcessed: processed,
+	}
+
+	return result, nil
+}
@@ -45,7 +45,14 @@ type OrchestratorInstance struct {
 	// collectors:
 	//   - nodes
 	//   - services
-	Collectors              []string `yaml:"collectors"`
+	Collectors []string `yaml:"collectors"`
+	// CRDCollectors defines the resources collected through the dynamic
+	// client, as <group>/<version>/<resource>. They are sent as manifests.
+	// Example: Enable Argo Rollouts and cert-manager Certificates collectors.
+	// crd_collectors:
+	//   - argoproj.io/v1alpha1/rollouts
+	//   - cert-manager.io/v1/certificates
+	CRDCollectors           []string `yaml:"crd_collectors"`
 	ExtraSyncTimeoutSeconds int      `yaml:"extra_sync_timeout_seconds"`
 }
 
@@ -127,7 +127,7 @@ func (c *ContainerdCheck) runProcessor(sender aggregator.Sender) error {
 }
 
 func (c *ContainerdCheck) runContainerdCustom(sender aggregator.Sender, cl cutil.ContainerdItf) error {
-	namespaces, err := cutil.NamespacesToWatch(context.TODO(), c.client)
+	namespaces, err := cutil.NamespacesToWatch(c.Context(), c.client)
 	if err != nil {
 		return err
 	}
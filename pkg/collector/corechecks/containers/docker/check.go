@@ -122,13 +122,15 @@ func (d *DockerCheck) Run() error {
 		return err
 	}
 
+	ctx := d.Context()
+
 	collectContainerSize := false
 	if d.instance.CollectContainerSize {
 		collectContainerSize = d.collectContainerSizeCounter == 0
 		d.collectContainerSizeCounter = (d.collectContainerSizeCounter + 1) % d.instance.CollectContainerSizeFreq
 	}
 
-	rawContainerList, err := du.RawContainerList(context.TODO(), dockerTypes.ContainerListOptions{All: true, Size: collectContainerSize})
+	rawContainerList, err := du.RawContainerList(ctx, dockerTypes.ContainerListOptions{All: true, Size: collectContainerSize})
 	if err != nil {
 		sender.ServiceCheck(DockerServiceUp, coreMetrics.ServiceCheckCritical, "", nil, err.Error())
 		_ = d.Warnf("Error collecting containers: %s", err)
@@ -139,7 +141,7 @@ func (d *DockerCheck) Run() error {
 		_ = d.Warnf("Error collecting metrics: %s", err)
 	}
 
-	return d.runDockerCustom(sender, du, rawContainerList)
+	return d.runDockerCustom(ctx, sender, du, rawContainerList)
 }
 
 func (d *DockerCheck) runProcessor(sender aggregator.Sender) error {
@@ -152,7 +154,7 @@ type containerPerImage struct {
 	stopped int64
 }
 
-func (d *DockerCheck) runDockerCustom(sender aggregator.Sender, du docker.Client, rawContainerList []dockerTypes.Container) error {
+func (d *DockerCheck) runDockerCustom(ctx context.Context, sender aggregator.Sender, du docker.Client, rawContainerList []dockerTypes.Container) error {
 	// Container metrics
 	var containersRunning, containersStopped uint64
 	containersPerImage := map[string]*containerPerImage{}
@@ -175,7 +177,7 @@ func (d *DockerCheck) runDockerCustom(sender aggregator.Sender, du docker.Client
 		}
 
 		// Resolve container image, if possible
-		resolvedImageName, err := du.ResolveImageName(context.TODO(), rawContainer.ImageID)
+		resolvedImageName, err := du.ResolveImageName(ctx, rawContainer.ImageID)
 		if err != nil {
 			log.Tracef("Unable to resolve ImageID '%s' to an image name, will use: %s", rawContainer.ImageID, rawContainer.Image)
 			resolvedImageName = rawContainer.Image
@@ -248,35 +250,35 @@ func (d *DockerCheck) runDockerCustom(sender aggregator.Sender, du docker.Client
 	sender.Gauge("docker.containers.stopped.total", float64(containersStopped), "", nil)
 
 	// Image metrics
-	if err := d.collectImageMetrics(sender, du, containersPerImage); err != nil {
+	if err := d.collectImageMetrics(ctx, sender, du, containersPerImage); err != nil {
 		return err
 	}
 
 	// Disk metrics
-	d.collectDiskMetrics(sender, du)
+	d.collectDiskMetrics(ctx, sender, du)
 
 	// Docker volumes metrics
-	d.collectVolumeMetrics(sender, du)
+	d.collectVolumeMetrics(ctx, sender, du)
 
 	// All metrics collected, setting servicecheck to ok
 	sender.ServiceCheck(DockerServiceUp, coreMetrics.ServiceCheckOK, "", nil, "")
 
 	// Collecting events
-	d.collectEvents(sender, du)
+	d.collectEvents(ctx, sender, du)
 
 	sender.Commit()
 	return nil
 }
 
-func (d *DockerCheck) collectImageMetrics(sender aggregator.Sender, du docker.Client, containersPerImage map[string]*containerPerImage) error {
-	availableImages, err := du.Images(context.TODO(), false)
+func (d *DockerCheck) collectImageMetrics(ctx context.Context, sender aggregator.Sender, du docker.Client, containersPerImage map[string]*containerPerImage) error {
+	availableImages, err := du.Images(ctx, false)
 	if err != nil {
 		log.Warnf("Unable to list Docker images, err: %v", err)
 		_ = d.Warnf("Unable to list Docker images, err: %v", err)
 		sender.ServiceCheck(DockerServiceUp, coreMetrics.ServiceCheckCritical, "", nil, err.Error())
 		return err
 	}
-	allImages, err := du.Images(context.TODO(), true)
+	allImages, err := du.Images(ctx, true)
 	if err != nil {
 		log.Warnf("Unable to list Docker images, err: %v", err)
 		_ = d.Warnf("Unable to list Docker images, err: %v", err)
@@ -302,9 +304,9 @@ func (d *DockerCheck) collectImageMetrics(sender aggregator.Sender, du docker.Cl
 	return nil
 }
 
-func (d *DockerCheck) collectEvents(sender aggregator.Sender, du docker.Client) {
+func (d *DockerCheck) collectEvents(ctx context.Context, sender aggregator.Sender, du docker.Client) {
 	if d.instance.CollectEvent || d.instance.CollectExitCodes {
-		events, err := d.retrieveEvents(du)
+		events, err := d.retrieveEvents(ctx, du)
 		if err != nil {
 			d.Warnf("Error collecting events: %s", err) //nolint:errcheck
 		} else {
@@ -321,9 +323,9 @@ func (d *DockerCheck) collectEvents(sender aggregator.Sender, du docker.Client)
 	}
 }
 
-func (d *DockerCheck) collectDiskMetrics(sender aggregator.Sender, du docker.Client) {
+func (d *DockerCheck) collectDiskMetrics(ctx context.Context, sender aggregator.Sender, du docker.Client) {
 	if d.instance.CollectDiskStats {
-		stats, err := du.GetStorageStats(context.TODO())
+		stats, err := du.GetStorageStats(ctx)
 		if err != nil {
 			d.Warnf("Error collecting disk stats: %s", err) //nolint:errcheck
 		} else {
@@ -350,9 +352,9 @@ func (d *DockerCheck) collectDiskMetrics(sender aggregator.Sender, du docker.Cli
 	}
 }
 
-func (d *DockerCheck) collectVolumeMetrics(sender aggregator.Sender, du docker.Client) {
+func (d *DockerCheck) collectVolumeMetrics(ctx context.Context, sender aggregator.Sender, du docker.Client) {
 	if d.instance.CollectVolumeCount {
-		attached, dangling, err := du.CountVolumes(context.TODO())
+		attached, dangling, err := du.CountVolumes(ctx)
 		if err != nil {
 			d.Warnf("Error collecting volume stats: %s", err) //nolint:errcheck
 		} else {
@@ -9,6 +9,7 @@
 package docker
 
 import (
+	"context"
 	"regexp"
 	"testing"
 
@@ -202,7 +203,7 @@ func TestDockerCustomPart(t *testing.T) {
 		},
 	}
 
-	err := check.runDockerCustom(mockSender, &dockerClient, dockerClient.FakeContainerList)
+	err := check.runDockerCustom(context.Background(), mockSender, &dockerClient, dockerClient.FakeContainerList)
 	assert.NoError(t, err)
 
 	mockSender.AssertNumberOfCalls(t, "Gauge", 14)
@@ -25,11 +25,11 @@ import (
 )
 
 // reportEvents handles the event retrieval logic
-func (d *DockerCheck) retrieveEvents(du docker.Client) ([]*docker.ContainerEvent, error) {
+func (d *DockerCheck) retrieveEvents(ctx context.Context, du docker.Client) ([]*docker.ContainerEvent, error) {
 	if d.lastEventTime.IsZero() {
 		d.lastEventTime = time.Now().Add(-60 * time.Second)
 	}
-	events, latest, err := du.LatestContainerEvents(context.TODO(), d.lastEventTime, d.containerFilter)
+	events, latest, err := du.LatestContainerEvents(ctx, d.lastEventTime, d.containerFilter)
 	if err != nil {
 		return events, err
 	}
@@ -169,7 +169,7 @@ func (c *NTPCheck) Run() error {
 	serviceCheckMessage := ""
 	offsetThreshold := c.cfg.instance.OffsetThreshold
 
-	clockOffset, err := c.queryOffset()
+	clockOffset, err := c.queryOffset(c.Context())
 	if err != nil {
 		log.Info(err)
 		serviceCheckStatus = metrics.ServiceCheckUnknown
@@ -195,10 +195,15 @@ func (c *NTPCheck) Run() error {
 	return nil
 }
 
-func (c *NTPCheck) queryOffset() (float64, error) {
+func (c *NTPCheck) queryOffset(ctx context.Context) (float64, error) {
 	offsets := []float64{}
 
 	for _, host := range c.cfg.instance.Hosts {
+		// Stop querying the remaining hosts once the run is interrupted
+		if err := ctx.Err(); err != nil {
+			return .0, err
+		}
+
 		response, err := ntpQuery(host, ntp.QueryOptions{Version: c.cfg.instance.Version, Port: c.cfg.instance.Port, Timeout: time.Duration(c.cfg.instance.Timeout) * time.Second})
 		if err != nil {
 			if c.errCount >= 10 {
@@ -0,0 +1,266 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	defaultTimeout         = 10 // seconds
+	defaultBearerTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// instanceConfig holds the options of the `openmetrics` integration supported by the
+// core check. Both the openmetrics v1 and v2 option names are accepted, the v2 ones
+// taking precedence.
+type instanceConfig struct {
+	OpenMetricsEndpoint string `yaml:"openmetrics_endpoint"`
+	PrometheusURL       string `yaml:"prometheus_url"`
+	Namespace           string `yaml:"namespace"`
+
+	// Metrics items are either a pattern, or a `raw_name: name` mapping
+	Metrics                 []interface{} `yaml:"metrics"`
+	ExcludeMetrics          []string      `yaml:"exclude_metrics"`
+	IgnoreMetrics           []string      `yaml:"ignore_metrics"`
+	RawMetricPrefix         string        `yaml:"raw_metric_prefix"`
+	PrometheusMetricsPrefix string        `yaml:"prometheus_metrics_prefix"`
+
+	RenameLabels  map[string]string `yaml:"rename_labels"`
+	LabelsMapper  map[string]string `yaml:"labels_mapper"`
+	ExcludeLabels []string          `yaml:"exclude_labels"`
+
+	CollectHistogramBuckets         *bool `yaml:"collect_histogram_buckets"`
+	SendHistogramsBuckets           *bool `yaml:"send_histograms_buckets"`
+	HistogramBucketsAsDistributions bool  `yaml:"histogram_buckets_as_distributions"`
+	SendDistributionBuckets         bool  `yaml:"send_distribution_buckets"`
+
+	EnableHealthServiceCheck *bool `yaml:"enable_health_service_check"`
+	HealthServiceCheck       *bool `yaml:"health_service_check"`
+
+	Headers         map[string]string `yaml:"headers"`
+	ExtraHeaders    map[string]string `yaml:"extra_headers"`
+	Timeout         int               `yaml:"timeout"`
+	TLSVerify       *bool             `yaml:"tls_verify"`
+	BearerTokenAuth bool              `yaml:"bearer_token_auth"`
+	BearerTokenPath string            `yaml:"bearer_token_path"`
+}
+
+// scrapeConfig is the parsed configuration of an instance
+type scrapeConfig struct {
+	endpoint  string
+	namespace string
+	rawPrefix string
+	// v1 is set for the openmetrics v1 instances, configured with `prometheus_url`,
+	// which keep the v1 metric patterns and counter names
+	v1 bool
+
+	// mappings holds the metrics renamed by the instance, keyed by raw name
+	mappings map[string]string
+	// includes and excludes match the raw names of the metrics to collect or to ignore
+	includes []*regexp.Regexp
+	excludes []*regexp.Regexp
+
+	renameLabels  map[string]string
+	excludeLabels map[string]struct{}
+
+	collectBuckets           bool
+	bucketsAsDistributions   bool
+	enableHealthServiceCheck bool
+
+	headers         map[string]string
+	timeout         int
+	tlsVerify       bool
+	bearerTokenPath string
+}
+
+func boolOption(v2, v1 *bool, defaultValue bool) bool {
+	if v2 != nil {
+		return *v2
+	}
+	if v1 != nil {
+		return *v1
+	}
+	return defaultValue
+}
+
+// globToRegexp converts an openmetrics v1 wildcard pattern to a regular expression
+func globToRegexp(pattern string) string {
+	var b strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return b.String()
+}
+
+// compilePatterns compiles the metric name patterns. openmetrics v2 instances use regular
+// expressions, while v1 ones (configured with `prometheus_url`) use wildcards.
+func compilePatterns(patterns []string, wildcards bool) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		if wildcards {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("invalid metric pattern '%s': %v", p, err)
+			}
+			p = globToRegexp(p)
+		}
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric pattern '%s': %v", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func parseConfig(data []byte) (*scrapeConfig, error) {
+	instance := instanceConfig{}
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	c := &scrapeConfig{
+		endpoint:                 instance.OpenMetricsEndpoint,
+		namespace:                instance.Namespace,
+		rawPrefix:                instance.RawMetricPrefix,
+		mappings:                 make(map[string]string),
+		renameLabels:             instance.RenameLabels,
+		excludeLabels:            make(map[string]struct{}, len(instance.ExcludeLabels)),
+		collectBuckets:           boolOption(instance.CollectHistogramBuckets, instance.SendHistogramsBuckets, true),
+		bucketsAsDistributions:   instance.HistogramBucketsAsDistributions || instance.SendDistributionBuckets,
+		enableHealthServiceCheck: boolOption(instance.EnableHealthServiceCheck, instance.HealthServiceCheck, true),
+		headers:                  make(map[string]string),
+		timeout:                  instance.Timeout,
+		tlsVerify:                boolOption(instance.TLSVerify, nil, true),
+	}
+
+	if c.endpoint == "" {
+		c.endpoint = instance.PrometheusURL
+		c.v1 = true
+	}
+	if c.endpoint == "" {
+		return nil, fmt.Errorf("openmetrics_endpoint or prometheus_url must be set")
+	}
+
+	if c.rawPrefix == "" {
+		c.rawPrefix = instance.PrometheusMetricsPrefix
+	}
+	if c.renameLabels == nil {
+		c.renameLabels = instance.LabelsMapper
+	}
+	for _, l := range instance.ExcludeLabels {
+		c.excludeLabels[l] = struct{}{}
+	}
+
+	var includes []string
+	for _, m := range instance.Metrics {
+		switch item := m.(type) {
+		case string:
+			includes = append(includes, item)
+		case map[interface{}]interface{}:
+			for raw, name := range item {
+				rawName, ok := raw.(string)
+				newName, ok2 := name.(string)
+				if !ok || !ok2 {
+					return nil, fmt.Errorf("invalid metrics mapping %v: only `raw_name: name` mappings are supported", item)
+				}
+				c.mappings[rawName] = newName
+			}
+		default:
+			return nil, fmt.Errorf("invalid metrics item %v", m)
+		}
+	}
+	if len(includes) == 0 && len(c.mappings) == 0 {
+		return nil, fmt.Errorf("at least one metric must be set in metrics")
+	}
+
+	var err error
+	if c.includes, err = compilePatterns(includes, c.v1); err != nil {
+		return nil, err
+	}
+
+	excludes := instance.ExcludeMetrics
+	if excludes == nil {
+		excludes = instance.IgnoreMetrics
+	}
+	if c.excludes, err = compilePatterns(excludes, c.v1); err != nil {
+		return nil, err
+	}
+
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	for k, v := range instance.Headers {
+		c.headers[k] = v
+	}
+	for k, v := range instance.ExtraHeaders {
+		c.headers[k] = v
+	}
+	if instance.BearerTokenAuth {
+		c.bearerTokenPath = instance.BearerTokenPath
+		if c.bearerTokenPath == "" {
+			c.bearerTokenPath = defaultBearerTokenPath
+		}
+	}
+
+	return c, nil
+}
+
+// metricName returns the name to submit a metric with, and whether it should be collected.
+// The raw name is stripped from the raw metric prefix before matching.
+func (c *scrapeConfig) metricName(rawName string) (string, bool) {
+	if c.rawPrefix != "" {
+		if !strings.HasPrefix(rawName, c.rawPrefix) {
+			return "", false
+		}
+		rawName = strings.TrimPrefix(rawName, c.rawPrefix)
+	}
+
+	for _, re := range c.excludes {
+		if re.MatchString(rawName) {
+			return "", false
+		}
+	}
+
+	name, found := c.mappings[rawName]
+	if !found {
+		for _, re := range c.includes {
+			if re.MatchString(rawName) {
+				name, found = rawName, true
+				break
+			}
+		}
+	}
+	if !found {
+		return "", false
+	}
+
+	if c.namespace != "" {
+		name = c.namespace + "." + name
+	}
+	return name, true
+}
+
+// counterName returns the name to submit a counter with: openmetrics v2 instances
+// suffix it with `.count`, while v1 ones submit it under the metric name.
+func (c *scrapeConfig) counterName(name string) string {
+	if c.v1 {
+		return name
+	}
+	return name + ".count"
+}
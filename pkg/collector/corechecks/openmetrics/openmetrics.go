@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+const (
+	// CheckName is the name of the check, to be used in place of the python `openmetrics` check
+	CheckName = "openmetrics_core"
+
+	healthServiceCheckName = "openmetrics.health"
+
+	// acceptHeader prefers the protobuf exposition format, falling back to the text one
+	acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3,*/*;q=0.1`
+)
+
+// Check scrapes an endpoint exposing metrics in the Prometheus text or protobuf
+// formats. It supports the most common options of the python `openmetrics` check
+// and submits metrics following its v2 conventions.
+type Check struct {
+	core.CheckBase
+	config *scrapeConfig
+	client *http.Client
+}
+
+func factory() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+	}
+}
+
+func init() {
+	core.RegisterCheck(CheckName, factory)
+}
+
+// Configure parses the check configuration and initializes the check
+func (c *Check) Configure(data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(data, initConfig)
+
+	err := c.CommonConfigure(data, source)
+	if err != nil {
+		return err
+	}
+
+	c.config, err = parseConfig(data)
+	if err != nil {
+		return err
+	}
+
+	c.client = &http.Client{
+		Timeout: time.Duration(c.config.timeout) * time.Second,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: !c.config.tlsVerify}, //nolint:gosec
+		},
+	}
+
+	return nil
+}
+
+// Run scrapes the endpoint and submits its metrics
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	families, err := c.scrape()
+
+	if c.config.enableHealthServiceCheck {
+		status, message := metrics.ServiceCheckOK, ""
+		if err != nil {
+			status, message = metrics.ServiceCheckCritical, err.Error()
+		}
+		sender.ServiceCheck(c.namespaced(healthServiceCheckName), status, "", []string{"endpoint:" + c.config.endpoint}, message)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, family := range families {
+		c.submitFamily(sender, family)
+	}
+
+	return nil
+}
+
+// scrape fetches and decodes the metric families exposed on the endpoint
+func (c *Check) scrape() ([]*dto.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(c.Context(), http.MethodGet, c.config.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", acceptHeader)
+	for k, v := range c.config.headers {
+		req.Header.Set(k, v)
+	}
+	if c.config.bearerTokenPath != "" {
+		token, err := os.ReadFile(c.config.bearerTokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the bearer token: %v", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, c.config.endpoint)
+	}
+
+	decoder := expfmt.NewDecoder(resp.Body, expfmt.ResponseFormat(resp.Header))
+
+	var families []*dto.MetricFamily
+	for {
+		family := &dto.MetricFamily{}
+		if err := decoder.Decode(family); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("unable to parse the metrics from %s: %v", c.config.endpoint, err)
+		}
+		families = append(families, family)
+	}
+
+	return families, nil
+}
+
+// namespaced prefixes the name with the namespace, if any
+func (c *Check) namespaced(name string) string {
+	if c.config.namespace == "" {
+		return name
+	}
+	return c.config.namespace + "." + name
+}
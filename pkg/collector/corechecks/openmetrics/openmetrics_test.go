@@ -0,0 +1,253 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+const testPayload = `# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200"} 1027
+# HELP temperature The current temperature.
+# TYPE temperature gauge
+temperature{room="kitchen",pod="foo"} 21.5
+# HELP go_goroutines Number of goroutines.
+# TYPE go_goroutines gauge
+go_goroutines 12
+# HELP rpc_duration_seconds A summary of the RPC duration in seconds.
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 4773
+rpc_duration_seconds{quantile="0.99"} 76656
+rpc_duration_seconds_sum 1.7560473e+07
+rpc_duration_seconds_count 2693
+# HELP request_duration_seconds A histogram of the request duration.
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 10
+request_duration_seconds_bucket{le="1"} 25
+request_duration_seconds_bucket{le="+Inf"} 30
+request_duration_seconds_sum 53.4
+request_duration_seconds_count 30
+`
+
+func newTestServer(t *testing.T, protobuf bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !protobuf {
+			w.Header().Set("Content-Type", string(expfmt.FmtText))
+			fmt.Fprint(w, testPayload)
+			return
+		}
+
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(strings.NewReader(testPayload))
+		require.NoError(t, err)
+
+		format := expfmt.Negotiate(r.Header)
+		require.Equal(t, expfmt.FmtProtoDelim, format)
+		w.Header().Set("Content-Type", string(format))
+		encoder := expfmt.NewEncoder(w, format)
+		for _, family := range families {
+			require.NoError(t, encoder.Encode(family))
+		}
+	}))
+}
+
+func runCheck(t *testing.T, instance string) *mocksender.MockSender {
+	c := factory().(*Check)
+	require.NoError(t, c.Configure(integration.Data(instance), nil, "test"))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	require.NoError(t, c.Run())
+
+	return sender
+}
+
+func TestRun(t *testing.T) {
+	for _, protobuf := range []bool{false, true} {
+		t.Run(fmt.Sprintf("protobuf=%t", protobuf), func(t *testing.T) {
+			server := newTestServer(t, protobuf)
+			defer server.Close()
+
+			endpoint := "endpoint:" + server.URL
+			sender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s
+namespace: test
+metrics:
+  - http_requests_total: http.requests
+  - temperature
+  - rpc_.*
+  - request_duration_seconds
+rename_labels:
+  room: location
+exclude_labels:
+  - pod
+`, server.URL))
+
+			sender.AssertServiceCheck(t, "test.openmetrics.health", metrics.ServiceCheckOK, "", []string{endpoint}, "")
+			sender.AssertMetric(t, "MonotonicCount", "test.http.requests.count", 1027, "", []string{endpoint, "code:200", "method:post"})
+			sender.AssertMetric(t, "Gauge", "test.temperature", 21.5, "", []string{endpoint, "location:kitchen"})
+			sender.AssertNotCalled(t, "Gauge", "test.go_goroutines", 12.0, "", []string{endpoint})
+
+			sender.AssertMetric(t, "MonotonicCount", "test.rpc_duration_seconds.count", 2693, "", []string{endpoint})
+			sender.AssertMetric(t, "MonotonicCount", "test.rpc_duration_seconds.sum", 1.7560473e+07, "", []string{endpoint})
+			sender.AssertMetric(t, "Gauge", "test.rpc_duration_seconds.quantile", 4773, "", []string{endpoint, "quantile:0.5"})
+			sender.AssertMetric(t, "Gauge", "test.rpc_duration_seconds.quantile", 76656, "", []string{endpoint, "quantile:0.99"})
+
+			sender.AssertMetric(t, "MonotonicCount", "test.request_duration_seconds.count", 30, "", []string{endpoint})
+			sender.AssertMetric(t, "MonotonicCount", "test.request_duration_seconds.sum", 53.4, "", []string{endpoint})
+			sender.AssertMetric(t, "MonotonicCount", "test.request_duration_seconds.bucket", 10, "", []string{endpoint, "upper_bound:0.1"})
+			sender.AssertMetric(t, "MonotonicCount", "test.request_duration_seconds.bucket", 25, "", []string{endpoint, "upper_bound:1"})
+			sender.AssertMetric(t, "MonotonicCount", "test.request_duration_seconds.bucket", 30, "", []string{endpoint, "upper_bound:inf"})
+		})
+	}
+}
+
+func TestRunHistogramAsDistribution(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	endpoint := "endpoint:" + server.URL
+	sender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s
+metrics:
+  - request_duration_seconds
+histogram_buckets_as_distributions: true
+`, server.URL))
+
+	sender.AssertHistogramBucket(t, "HistogramBucket", "request_duration_seconds", 10, 0, 0.1, true, "", []string{endpoint}, false)
+	sender.AssertHistogramBucket(t, "HistogramBucket", "request_duration_seconds", 15, 0.1, 1, true, "", []string{endpoint}, false)
+	// The +Inf bucket is clamped to its lower bound
+	sender.AssertHistogramBucket(t, "HistogramBucket", "request_duration_seconds", 5, 1, 1, true, "", []string{endpoint}, false)
+	sender.AssertNotCalled(t, "MonotonicCount", "request_duration_seconds.bucket", 10.0, "", []string{endpoint, "upper_bound:0.1"})
+}
+
+func TestSubmitHistogramNegativeBucketsAsDistribution(t *testing.T) {
+	c := &Check{config: &scrapeConfig{collectBuckets: true, bucketsAsDistributions: true}}
+	sender := mocksender.NewMockSender("openmetrics_negative_buckets")
+	sender.SetupAcceptAll()
+
+	bucket := func(upperBound float64, count uint64) *dto.Bucket {
+		return &dto.Bucket{UpperBound: &upperBound, CumulativeCount: &count}
+	}
+	c.submitHistogram(sender, "temperature_delta", &dto.Histogram{
+		Bucket: []*dto.Bucket{
+			bucket(-1, 3),
+			bucket(0, 5),
+			bucket(math.Inf(1), 6),
+		},
+	}, nil)
+
+	// The first bucket has no finite lower bound, it's a single point
+	sender.AssertHistogramBucket(t, "HistogramBucket", "temperature_delta", 3, -1, -1, true, "", nil, false)
+	sender.AssertHistogramBucket(t, "HistogramBucket", "temperature_delta", 2, -1, 0, true, "", nil, false)
+	sender.AssertHistogramBucket(t, "HistogramBucket", "temperature_delta", 1, 0, 0, true, "", nil, false)
+}
+
+func TestRunV1Options(t *testing.T) {
+	server := newTestServer(t, false)
+	defer server.Close()
+
+	endpoint := "endpoint:" + server.URL
+	sender := runCheck(t, fmt.Sprintf(`
+prometheus_url: %s
+metrics:
+  - "*"
+ignore_metrics:
+  - "rpc_*"
+  - request_duration_seconds
+labels_mapper:
+  room: location
+health_service_check: false
+`, server.URL))
+
+	sender.AssertMetric(t, "Gauge", "go_goroutines", 12, "", []string{endpoint})
+	// Counters keep their v1 name, without the `.count` suffix
+	sender.AssertMetric(t, "MonotonicCount", "http_requests_total", 1027, "", []string{endpoint, "code:200", "method:post"})
+	sender.AssertNotCalled(t, "MonotonicCount", "http_requests_total.count", 1027.0, "", []string{endpoint, "method:post", "code:200"})
+	sender.AssertMetric(t, "Gauge", "temperature", 21.5, "", []string{endpoint, "location:kitchen", "pod:foo"})
+	sender.AssertNotCalled(t, "MonotonicCount", "rpc_duration_seconds.count", 2693.0, "", []string{endpoint})
+	sender.AssertNotCalled(t, "ServiceCheck", "openmetrics.health", metrics.ServiceCheckOK, "", []string{endpoint}, "")
+}
+
+func TestRunEndpointError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	c := factory().(*Check)
+	require.NoError(t, c.Configure(integration.Data("openmetrics_endpoint: "+server.URL+"\nmetrics: [\".*\"]"), nil, "test"))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	assert.Error(t, c.Run())
+
+	sender.AssertServiceCheck(t, "openmetrics.health", metrics.ServiceCheckCritical, "", []string{"endpoint:" + server.URL}, "unexpected status code 500 from "+server.URL)
+}
+
+func TestParseConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{name: "missing endpoint", config: `metrics: [".*"]`, wantErr: true},
+		{name: "missing metrics", config: `openmetrics_endpoint: http://localhost`, wantErr: true},
+		{name: "invalid regex", config: "openmetrics_endpoint: http://localhost\nmetrics: [\"(\"]", wantErr: true},
+		{name: "invalid mapping", config: "openmetrics_endpoint: http://localhost\nmetrics: [{foo: {name: bar}}]", wantErr: true},
+		{name: "valid", config: "openmetrics_endpoint: http://localhost\nmetrics: [\".*\", {foo: bar}]"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfig([]byte(tt.config))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	c, err := parseConfig([]byte(`
+openmetrics_endpoint: http://localhost
+namespace: ns
+raw_metric_prefix: app_
+metrics:
+  - requests.*
+  - latency: request.latency
+exclude_metrics:
+  - requests_failed
+`))
+	require.NoError(t, err)
+
+	for raw, expected := range map[string]string{
+		"app_requests_total":  "ns.requests_total",
+		"app_latency":         "ns.request.latency",
+		"app_requests_failed": "",
+		"requests_total":      "",
+		"app_other":           "",
+	} {
+		name, ok := c.metricName(raw)
+		assert.Equal(t, expected != "", ok, raw)
+		assert.Equal(t, expected, name, raw)
+	}
+}
@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"math"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+)
+
+// submitFamily submits the metrics of a family, following the openmetrics v2 conventions:
+// counters are monotonic counts suffixed with `.count` (except for v1 instances),
+// summaries and histograms are split into `.count`, `.sum` and `.quantile` or `.bucket` metrics.
+func (c *Check) submitFamily(sender aggregator.Sender, family *dto.MetricFamily) {
+	name, ok := c.config.metricName(family.GetName())
+	if !ok {
+		return
+	}
+
+	for _, metric := range family.GetMetric() {
+		tags := c.labelsToTags(metric.GetLabel())
+
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			sender.MonotonicCount(c.config.counterName(name), metric.GetCounter().GetValue(), "", tags)
+		case dto.MetricType_GAUGE:
+			sender.Gauge(name, metric.GetGauge().GetValue(), "", tags)
+		case dto.MetricType_UNTYPED:
+			sender.Gauge(name, metric.GetUntyped().GetValue(), "", tags)
+		case dto.MetricType_SUMMARY:
+			c.submitSummary(sender, name, metric.GetSummary(), tags)
+		case dto.MetricType_HISTOGRAM:
+			c.submitHistogram(sender, name, metric.GetHistogram(), tags)
+		}
+	}
+}
+
+func (c *Check) submitSummary(sender aggregator.Sender, name string, summary *dto.Summary, tags []string) {
+	sender.MonotonicCount(name+".count", float64(summary.GetSampleCount()), "", tags)
+	sender.MonotonicCount(name+".sum", summary.GetSampleSum(), "", tags)
+
+	for _, q := range summary.GetQuantile() {
+		if math.IsNaN(q.GetValue()) {
+			continue
+		}
+		quantileTags := append(copyTags(tags), "quantile:"+formatFloat(q.GetQuantile()))
+		sender.Gauge(name+".quantile", q.GetValue(), "", quantileTags)
+	}
+}
+
+func (c *Check) submitHistogram(sender aggregator.Sender, name string, histogram *dto.Histogram, tags []string) {
+	sender.MonotonicCount(name+".count", float64(histogram.GetSampleCount()), "", tags)
+	sender.MonotonicCount(name+".sum", histogram.GetSampleSum(), "", tags)
+
+	if !c.config.collectBuckets {
+		return
+	}
+
+	if c.config.bucketsAsDistributions {
+		// Buckets are cumulative, distributions need the count of each bucket.
+		// Distributions can't interpolate infinite bounds, so like the python
+		// base the first bucket starts at 0, or is a single point if its upper
+		// bound isn't positive, and the +Inf bucket is clamped to its lower bound.
+		var lowerBound float64
+		var previousCount uint64
+		for i, bucket := range histogram.GetBucket() {
+			upperBound := bucket.GetUpperBound()
+			if i == 0 && upperBound <= 0 {
+				lowerBound = upperBound
+			}
+			if math.IsInf(upperBound, 1) {
+				upperBound = lowerBound
+			}
+			count := bucket.GetCumulativeCount() - previousCount
+			sender.HistogramBucket(name, int64(count), lowerBound, upperBound, true, "", tags, false)
+			lowerBound, previousCount = upperBound, bucket.GetCumulativeCount()
+		}
+		return
+	}
+
+	for _, bucket := range histogram.GetBucket() {
+		bucketTags := append(copyTags(tags), "upper_bound:"+formatFloat(bucket.GetUpperBound()))
+		sender.MonotonicCount(name+".bucket", float64(bucket.GetCumulativeCount()), "", bucketTags)
+	}
+}
+
+// labelsToTags converts the labels to tags, applying the exclusions and renames
+func (c *Check) labelsToTags(labels []*dto.LabelPair) []string {
+	tags := make([]string, 0, len(labels)+1)
+	tags = append(tags, "endpoint:"+c.config.endpoint)
+
+	for _, l := range labels {
+		name := l.GetName()
+		if _, excluded := c.config.excludeLabels[name]; excluded {
+			continue
+		}
+		if renamed, found := c.config.renameLabels[name]; found {
+			name = renamed
+		}
+		tags = append(tags, name+":"+l.GetValue())
+	}
+
+	return tags
+}
+
+func copyTags(tags []string) []string {
+	res := make([]string, len(tags), len(tags)+1)
+	copy(res, tags)
+	return res
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "inf"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
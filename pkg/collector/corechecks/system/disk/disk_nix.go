@@ -9,6 +9,7 @@
 package disk
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -39,7 +40,7 @@ func (c *Check) Run() error {
 		return err
 	}
 
-	err = c.collectPartitionMetrics(sender)
+	err = c.collectPartitionMetrics(c.Context(), sender)
 	if err != nil {
 		return err
 	}
@@ -52,13 +53,19 @@ func (c *Check) Run() error {
 	return nil
 }
 
-func (c *Check) collectPartitionMetrics(sender aggregator.Sender) error {
+func (c *Check) collectPartitionMetrics(ctx context.Context, sender aggregator.Sender) error {
 	partitions, err := diskPartitions(true)
 	if err != nil {
 		return err
 	}
 
 	for _, partition := range partitions {
+		// Stop at the next partition when the run is interrupted, as querying
+		// the usage of an unresponsive mount point can block
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		if c.excludeDisk(partition.Mountpoint, partition.Device, partition.Fstype) {
 			continue
 		}
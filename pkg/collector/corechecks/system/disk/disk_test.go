@@ -248,3 +248,31 @@ func TestDiskCheckTags(t *testing.T) {
 	mock.AssertNumberOfCalls(t, "Rate", expectedRates)
 	mock.AssertNumberOfCalls(t, "Commit", 1)
 }
+
+func TestDiskCheckInterrupted(t *testing.T) {
+	diskCheck := diskFactory().(*Check)
+	diskCheck.Configure(nil, nil, "test")
+
+	// The run is interrupted while querying the usage of the first partition
+	usageCalls := 0
+	diskPartitions = diskSampler
+	diskUsage = func(mountpoint string) (*disk.UsageStat, error) {
+		usageCalls++
+		diskCheck.Interrupt()
+		return diskUsageSamples[mountpoint], nil
+	}
+	defer func() { diskUsage = diskUsageSampler }()
+	ioCounters = diskIoSampler
+
+	mock := mocksender.NewMockSender(diskCheck.ID())
+	mock.SetupAcceptAll()
+
+	err := diskCheck.Run()
+	if err == nil {
+		t.Fatal("the interrupted run should fail")
+	}
+	if usageCalls != 1 {
+		t.Errorf("the remaining partitions should be skipped, got %d usage queries", usageCalls)
+	}
+	mock.AssertNotCalled(t, "Commit")
+}
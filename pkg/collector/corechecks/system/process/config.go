@@ -0,0 +1,132 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package process
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+// instanceConfig holds the definition of a process group. The option names are the ones
+// of the python `process` integration.
+type instanceConfig struct {
+	Name string `yaml:"name"`
+	// SearchString holds regular expressions matched against the command line of the processes
+	SearchString []string `yaml:"search_string"`
+	// ExactMatch matches the search strings against the whole command line instead of a part of it
+	ExactMatch bool   `yaml:"exact_match"`
+	User       string `yaml:"user"`
+
+	// Thresholds holds the `critical` and `warning` ranges of the number of processes of the group
+	Thresholds map[string][]int `yaml:"thresholds"`
+}
+
+// threshold is an inclusive range of process counts
+type threshold struct {
+	min, max int
+}
+
+func (t *threshold) contains(count int) bool {
+	return count >= t.min && count <= t.max
+}
+
+// groupConfig is the parsed configuration of an instance
+type groupConfig struct {
+	name     string
+	patterns []*regexp.Regexp
+	user     string
+
+	critical *threshold
+	warning  *threshold
+}
+
+func parseConfig(data []byte) (*groupConfig, error) {
+	instance := instanceConfig{}
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return nil, err
+	}
+
+	if instance.Name == "" {
+		return nil, fmt.Errorf("the name of the process group is required")
+	}
+	if len(instance.SearchString) == 0 && instance.User == "" {
+		return nil, fmt.Errorf("process group %s: at least one of search_string or user is required", instance.Name)
+	}
+
+	config := &groupConfig{
+		name: instance.Name,
+		user: instance.User,
+	}
+
+	for _, s := range instance.SearchString {
+		pattern := s
+		if instance.ExactMatch {
+			pattern = "^(?:" + s + ")$"
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("process group %s: invalid search_string %q: %v", instance.Name, s, err)
+		}
+		config.patterns = append(config.patterns, re)
+	}
+
+	for level, bounds := range instance.Thresholds {
+		if len(bounds) != 2 || bounds[0] > bounds[1] {
+			return nil, fmt.Errorf("process group %s: the %s threshold must be a [min, max] range", instance.Name, level)
+		}
+		t := &threshold{min: bounds[0], max: bounds[1]}
+		switch strings.ToLower(level) {
+		case "critical":
+			config.critical = t
+		case "warning":
+			config.warning = t
+		default:
+			return nil, fmt.Errorf("process group %s: unknown threshold %s, expected critical or warning", instance.Name, level)
+		}
+	}
+
+	return config, nil
+}
+
+// matchCmdline returns whether the command line matches one of the search strings,
+// or true if none is configured
+func (c *groupConfig) matchCmdline(cmdline []string) bool {
+	if len(c.patterns) == 0 {
+		return true
+	}
+
+	joined := strings.Join(cmdline, " ")
+	for _, re := range c.patterns {
+		if re.MatchString(joined) {
+			return true
+		}
+	}
+	return false
+}
+
+// status returns the status of the service check from the number of processes of the group.
+// Without thresholds, the group is healthy when at least one process is running.
+func (c *groupConfig) status(count int) (metrics.ServiceCheckStatus, string) {
+	if c.critical == nil && c.warning == nil {
+		if count == 0 {
+			return metrics.ServiceCheckCritical, fmt.Sprintf("No process found for %s", c.name)
+		}
+		return metrics.ServiceCheckOK, ""
+	}
+
+	if c.critical != nil && !c.critical.contains(count) {
+		return metrics.ServiceCheckCritical, fmt.Sprintf("%d processes found for %s, expected between %d and %d", count, c.name, c.critical.min, c.critical.max)
+	}
+	if c.warning != nil && !c.warning.contains(count) {
+		return metrics.ServiceCheckWarning, fmt.Sprintf("%d processes found for %s, expected between %d and %d", count, c.name, c.warning.min, c.warning.max)
+	}
+	return metrics.ServiceCheckOK, ""
+}
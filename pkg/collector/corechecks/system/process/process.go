@@ -0,0 +1,286 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package process
+
+import (
+	"os/user"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// CheckName is the name of the check, a native alternative to the python `process` integration
+	CheckName = "process_core"
+
+	upServiceCheck = "process.up"
+)
+
+// For testing purpose
+var (
+	virtualMemory = mem.VirtualMemory
+	timeNow       = time.Now
+	newProbe      = func() procutil.Probe { return procutil.NewProcessProbe(procutil.WithPermission(true)) }
+	lookupUser    = func(uid string) (string, error) {
+		u, err := user.LookupId(uid)
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	}
+)
+
+// cpuSample holds the CPU time of a process at the previous run
+type cpuSample struct {
+	createTime int64
+	total      float64 // seconds
+}
+
+// Check reports the resources used by a group of processes selected by their
+// command line and user. It submits the metrics of the python `process` integration.
+type Check struct {
+	core.CheckBase
+	config *groupConfig
+	probe  procutil.Probe
+
+	lastRun   time.Time
+	lastCPU   map[int32]cpuSample
+	usernames map[int32]string
+}
+
+func factory() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+	}
+}
+
+func init() {
+	core.RegisterCheck(CheckName, factory)
+}
+
+// Configure parses the process group definition and initializes the check
+func (c *Check) Configure(data integration.Data, initConfig integration.Data, source string) error {
+	c.BuildID(data, initConfig)
+
+	err := c.CommonConfigure(data, source)
+	if err != nil {
+		return err
+	}
+
+	c.config, err = parseConfig(data)
+	if err != nil {
+		return err
+	}
+
+	c.probe = newProbe()
+	c.usernames = make(map[int32]string)
+	return nil
+}
+
+// Cancel releases the process probe of the check
+func (c *Check) Cancel() {
+	if c.probe != nil {
+		c.probe.Close()
+	}
+	c.CommonCancel()
+}
+
+// Run collects the processes of the group and submits their metrics
+func (c *Check) Run() error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	now := timeNow()
+	procs, err := c.probe.ProcessesByPID(now, true)
+	if err != nil {
+		sender.ServiceCheck(upServiceCheck, metrics.ServiceCheckUnknown, "", c.serviceCheckTags(), err.Error())
+		return err
+	}
+
+	var group []*procutil.Process
+	for _, proc := range procs {
+		if c.config.matchCmdline(proc.Cmdline) && c.matchUser(proc) {
+			group = append(group, proc)
+		}
+	}
+
+	status, message := c.config.status(len(group))
+	sender.ServiceCheck(upServiceCheck, status, "", c.serviceCheckTags(), message)
+
+	tags := []string{"process_name:" + c.config.name}
+	sender.Gauge("system.processes.number", float64(len(group)), "", tags)
+	c.submitStats(sender, group, now, tags)
+
+	return nil
+}
+
+// submitStats submits the resources used by the processes of the group
+func (c *Check) submitStats(sender aggregator.Sender, group []*procutil.Process, now time.Time, tags []string) {
+	var (
+		cpuPct                                       float64
+		rss, vms, realMem                            uint64
+		threads, fds, voluntary, involuntary         int64
+		readCount, writeCount, readBytes, writeBytes int64
+		hasFds, hasIO, hasCtxSwitches                bool
+		minRunTime, maxRunTime, sumRunTime           float64
+		withStats                                    int
+	)
+
+	elapsed := now.Sub(c.lastRun).Seconds()
+	cpuSamples := make(map[int32]cpuSample, len(group))
+
+	for _, proc := range group {
+		stats := proc.Stats
+		if stats == nil {
+			continue
+		}
+
+		if stats.CPUTime != nil {
+			sample := cpuSample{createTime: stats.CreateTime, total: stats.CPUTime.User + stats.CPUTime.System}
+			if last, ok := c.lastCPU[proc.Pid]; ok && last.createTime == sample.createTime && elapsed > 0 {
+				cpuPct += (sample.total - last.total) / elapsed * 100
+			}
+			cpuSamples[proc.Pid] = sample
+		}
+
+		if stats.MemInfo != nil {
+			rss += stats.MemInfo.RSS
+			vms += stats.MemInfo.VMS
+			if stats.MemInfoEx != nil && stats.MemInfoEx.Shared <= stats.MemInfo.RSS {
+				realMem += stats.MemInfo.RSS - stats.MemInfoEx.Shared
+			}
+		}
+
+		threads += int64(stats.NumThreads)
+		// negative values mean that the agent is not allowed to read the stat
+		if stats.OpenFdCount >= 0 {
+			fds += int64(stats.OpenFdCount)
+			hasFds = true
+		}
+		if io := stats.IOStat; io != nil && io.ReadCount >= 0 && io.WriteCount >= 0 && io.ReadBytes >= 0 && io.WriteBytes >= 0 {
+			readCount += io.ReadCount
+			writeCount += io.WriteCount
+			readBytes += io.ReadBytes
+			writeBytes += io.WriteBytes
+			hasIO = true
+		}
+		if stats.CtxSwitches != nil {
+			voluntary += stats.CtxSwitches.Voluntary
+			involuntary += stats.CtxSwitches.Involuntary
+			hasCtxSwitches = true
+		}
+
+		// the create time is in milliseconds
+		runTime := now.Sub(time.Unix(0, stats.CreateTime*int64(time.Millisecond))).Seconds()
+		if withStats == 0 || runTime < minRunTime {
+			minRunTime = runTime
+		}
+		if withStats == 0 || runTime > maxRunTime {
+			maxRunTime = runTime
+		}
+		sumRunTime += runTime
+		withStats++
+	}
+
+	// the CPU usage is computed from the CPU time consumed since the previous run
+	if !c.lastRun.IsZero() {
+		sender.Gauge("system.processes.cpu.pct", cpuPct, "", tags)
+		sender.Gauge("system.processes.cpu.normalized_pct", cpuPct/float64(runtime.NumCPU()), "", tags)
+	}
+	c.lastRun = now
+	c.lastCPU = cpuSamples
+
+	if withStats == 0 {
+		return
+	}
+
+	sender.Gauge("system.processes.mem.rss", float64(rss), "", tags)
+	sender.Gauge("system.processes.mem.vms", float64(vms), "", tags)
+	if runtime.GOOS == "linux" {
+		sender.Gauge("system.processes.mem.real", float64(realMem), "", tags)
+	}
+	if v, err := virtualMemory(); err != nil {
+		log.Debugf("process_core: unable to get the total memory: %s", err)
+	} else if v.Total > 0 {
+		sender.Gauge("system.processes.mem.pct", float64(rss)/float64(v.Total)*100, "", tags)
+	}
+
+	sender.Gauge("system.processes.threads", float64(threads), "", tags)
+	if hasFds {
+		sender.Gauge("system.processes.open_file_descriptors", float64(fds), "", tags)
+	}
+	if hasIO {
+		sender.Gauge("system.processes.ioread_count", float64(readCount), "", tags)
+		sender.Gauge("system.processes.iowrite_count", float64(writeCount), "", tags)
+		sender.Gauge("system.processes.ioread_bytes", float64(readBytes), "", tags)
+		sender.Gauge("system.processes.iowrite_bytes", float64(writeBytes), "", tags)
+	}
+	if hasCtxSwitches {
+		sender.Gauge("system.processes.voluntary_ctx_switches", float64(voluntary), "", tags)
+		sender.Gauge("system.processes.involuntary_ctx_switches", float64(involuntary), "", tags)
+	}
+
+	sender.Gauge("system.processes.run_time.avg", sumRunTime/float64(withStats), "", tags)
+	sender.Gauge("system.processes.run_time.max", maxRunTime, "", tags)
+	sender.Gauge("system.processes.run_time.min", minRunTime, "", tags)
+}
+
+// matchUser returns whether the process runs as the user of the group, if any
+func (c *Check) matchUser(proc *procutil.Process) bool {
+	if c.config.user == "" {
+		return true
+	}
+
+	// the user name is only collected on Windows, where it may be prefixed by the domain
+	if proc.Username != "" {
+		username := proc.Username
+		if idx := strings.LastIndexByte(username, '\\'); idx != -1 {
+			username = username[idx+1:]
+		}
+		return strings.EqualFold(username, c.config.user) || strings.EqualFold(proc.Username, c.config.user)
+	}
+
+	if len(proc.Uids) == 0 {
+		return false
+	}
+	uid := proc.Uids[0]
+	if strconv.Itoa(int(uid)) == c.config.user {
+		return true
+	}
+	return c.username(uid) == c.config.user
+}
+
+// username resolves the name of a user, the names are cached for the lifetime of the check
+func (c *Check) username(uid int32) string {
+	if name, ok := c.usernames[uid]; ok {
+		return name
+	}
+
+	name, err := lookupUser(strconv.Itoa(int(uid)))
+	if err != nil {
+		log.Debugf("process_core: unable to resolve the name of the user %d: %s", uid, err)
+	}
+	c.usernames[uid] = name
+	return name
+}
+
+func (c *Check) serviceCheckTags() []string {
+	return []string{"process:" + c.config.name}
+}
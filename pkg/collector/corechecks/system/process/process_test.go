@@ -0,0 +1,239 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package process
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+const mb = 1024 * 1024
+
+type fakeProbe struct {
+	procs map[int32]*procutil.Process
+	err   error
+}
+
+func (p *fakeProbe) Close() {}
+
+func (p *fakeProbe) StatsForPIDs(_ []int32, _ time.Time) (map[int32]*procutil.Stats, error) {
+	return nil, nil
+}
+
+func (p *fakeProbe) ProcessesByPID(_ time.Time, _ bool) (map[int32]*procutil.Process, error) {
+	return p.procs, p.err
+}
+
+func (p *fakeProbe) StatsWithPermByPID(_ []int32) (map[int32]*procutil.StatsWithPerm, error) {
+	return nil, nil
+}
+
+func newTestProcess(pid int32, uid int32, cmdline []string, createTime time.Time, cpuTime float64) *procutil.Process {
+	return &procutil.Process{
+		Pid:     pid,
+		Cmdline: cmdline,
+		Uids:    []int32{uid},
+		Stats: &procutil.Stats{
+			CreateTime:  createTime.UnixNano() / int64(time.Millisecond),
+			CPUTime:     &procutil.CPUTimesStat{User: cpuTime * 2 / 3, System: cpuTime / 3},
+			MemInfo:     &procutil.MemoryInfoStat{RSS: 100 * mb, VMS: 200 * mb},
+			MemInfoEx:   &procutil.MemoryInfoExStat{Shared: 10 * mb},
+			NumThreads:  2,
+			OpenFdCount: 20,
+			IOStat:      &procutil.IOCountersStat{ReadCount: 1, WriteCount: 2, ReadBytes: 3, WriteBytes: 4},
+			CtxSwitches: &procutil.NumCtxSwitchesStat{Voluntary: 5, Involuntary: 6},
+		},
+	}
+}
+
+func setupCheck(t *testing.T, instance string, probe *fakeProbe, now *time.Time) (*Check, *mocksender.MockSender) {
+	origProbe, origTimeNow, origVirtualMemory, origLookupUser := newProbe, timeNow, virtualMemory, lookupUser
+	t.Cleanup(func() {
+		newProbe, timeNow, virtualMemory, lookupUser = origProbe, origTimeNow, origVirtualMemory, origLookupUser
+	})
+
+	newProbe = func() procutil.Probe { return probe }
+	timeNow = func() time.Time { return *now }
+	virtualMemory = func() (*mem.VirtualMemoryStat, error) {
+		return &mem.VirtualMemoryStat{Total: 1000 * mb}, nil
+	}
+	lookupUser = func(uid string) (string, error) {
+		switch uid {
+		case "0":
+			return "root", nil
+		case "999":
+			return "postgres", nil
+		}
+		return "", fmt.Errorf("unknown user %s", uid)
+	}
+
+	c := factory().(*Check)
+	require.NoError(t, c.Configure(integration.Data(instance), nil, "test"))
+
+	sender := mocksender.NewMockSender(c.ID())
+	sender.SetupAcceptAll()
+	return c, sender
+}
+
+func TestRun(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	probe := &fakeProbe{procs: map[int32]*procutil.Process{
+		10: newTestProcess(10, 999, []string{"postgres", "-D", "/var/lib/postgresql/data"}, now.Add(-100*time.Second), 15),
+		11: newTestProcess(11, 999, []string{"postgres: checkpointer"}, now.Add(-50*time.Second), 3),
+		// not matching the command line
+		12: newTestProcess(12, 999, []string{"psql", "-c", "SELECT 1"}, now, 1),
+		// not matching the user
+		13: newTestProcess(13, 0, []string{"postgres", "-D", "/tmp/data"}, now, 1),
+	}}
+	// the agent is not allowed to read the fds and the IO of this process
+	probe.procs[11].Stats.OpenFdCount = -1
+	probe.procs[11].Stats.IOStat = &procutil.IOCountersStat{ReadCount: -1, WriteCount: -1, ReadBytes: -1, WriteBytes: -1}
+
+	c, sender := setupCheck(t, `
+name: postgres
+search_string: ["^postgres"]
+user: postgres
+`, probe, &now)
+	require.NoError(t, c.Run())
+
+	tags := []string{"process_name:postgres"}
+	sender.AssertServiceCheck(t, "process.up", metrics.ServiceCheckOK, "", []string{"process:postgres"}, "")
+	sender.AssertMetric(t, "Gauge", "system.processes.number", 2, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.mem.rss", 200*mb, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.mem.vms", 400*mb, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.mem.pct", 20, "", tags)
+	if runtime.GOOS == "linux" {
+		sender.AssertMetric(t, "Gauge", "system.processes.mem.real", 180*mb, "", tags)
+	}
+	sender.AssertMetric(t, "Gauge", "system.processes.threads", 4, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.open_file_descriptors", 20, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.ioread_count", 1, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.iowrite_bytes", 4, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.voluntary_ctx_switches", 10, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.involuntary_ctx_switches", 12, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.run_time.avg", 75, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.run_time.max", 100, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.run_time.min", 50, "", tags)
+	// the CPU usage is only known from the second run
+	sender.AssertNotCalled(t, "Gauge", "system.processes.cpu.pct", 0.0, "", tags)
+
+	// 3 seconds of CPU time are consumed in 10 seconds
+	now = now.Add(10 * time.Second)
+	probe.procs[10].Stats.CPUTime = &procutil.CPUTimesStat{User: 12, System: 5}
+	probe.procs[11].Stats.CPUTime = &procutil.CPUTimesStat{User: 2, System: 2}
+	require.NoError(t, c.Run())
+
+	sender.AssertMetric(t, "Gauge", "system.processes.cpu.pct", 30, "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.cpu.normalized_pct", 30/float64(runtime.NumCPU()), "", tags)
+	sender.AssertMetric(t, "Gauge", "system.processes.run_time.max", 110, "", tags)
+}
+
+func TestRunPIDReused(t *testing.T) {
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	probe := &fakeProbe{procs: map[int32]*procutil.Process{
+		10: newTestProcess(10, 999, []string{"redis-server"}, now.Add(-time.Minute), 50),
+	}}
+
+	c, sender := setupCheck(t, "name: redis\nsearch_string: [redis-server]", probe, &now)
+	require.NoError(t, c.Run())
+
+	// the PID is reused by a new process, its CPU time is not compared with the previous one
+	now = now.Add(10 * time.Second)
+	probe.procs[10] = newTestProcess(10, 999, []string{"redis-server"}, now, 1)
+	require.NoError(t, c.Run())
+
+	sender.AssertMetric(t, "Gauge", "system.processes.cpu.pct", 0, "", []string{"process_name:redis"})
+}
+
+func TestRunNoProcess(t *testing.T) {
+	now := time.Now()
+	probe := &fakeProbe{procs: map[int32]*procutil.Process{
+		10: newTestProcess(10, 999, []string{"postgres"}, now, 1),
+	}}
+
+	c, sender := setupCheck(t, "name: nginx\nsearch_string: [nginx]", probe, &now)
+	require.NoError(t, c.Run())
+
+	sender.AssertServiceCheck(t, "process.up", metrics.ServiceCheckCritical, "", []string{"process:nginx"}, "No process found for nginx")
+	sender.AssertMetric(t, "Gauge", "system.processes.number", 0, "", []string{"process_name:nginx"})
+	sender.AssertNotCalled(t, "Gauge", "system.processes.mem.rss", 0.0, "", []string{"process_name:nginx"})
+}
+
+func TestRunProbeError(t *testing.T) {
+	now := time.Now()
+	probe := &fakeProbe{err: fmt.Errorf("permission denied")}
+
+	c, sender := setupCheck(t, "name: nginx\nsearch_string: [nginx]", probe, &now)
+	assert.Error(t, c.Run())
+
+	sender.AssertServiceCheck(t, "process.up", metrics.ServiceCheckUnknown, "", []string{"process:nginx"}, "permission denied")
+}
+
+func TestStatus(t *testing.T) {
+	config, err := parseConfig([]byte(`
+name: nginx
+search_string: [nginx]
+thresholds:
+  critical: [1, 10]
+  warning: [2, 4]
+`))
+	require.NoError(t, err)
+
+	for count, expected := range map[int]metrics.ServiceCheckStatus{
+		0:  metrics.ServiceCheckCritical,
+		1:  metrics.ServiceCheckWarning,
+		3:  metrics.ServiceCheckOK,
+		5:  metrics.ServiceCheckWarning,
+		11: metrics.ServiceCheckCritical,
+	} {
+		status, _ := config.status(count)
+		assert.Equal(t, expected, status, "%d processes", count)
+	}
+
+	status, message := config.status(0)
+	assert.Equal(t, metrics.ServiceCheckCritical, status)
+	assert.Equal(t, "0 processes found for nginx, expected between 1 and 10", message)
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := parseConfig([]byte("name: java\nsearch_string: ['java .*kafka\\.Kafka']\nexact_match: true"))
+	require.NoError(t, err)
+	assert.True(t, config.matchCmdline([]string{"java", "-Xmx1G", "kafka.Kafka"}))
+	assert.False(t, config.matchCmdline([]string{"java", "-Xmx1G", "kafka.Kafka", "server.properties"}))
+	assert.False(t, config.matchCmdline([]string{"/usr/bin/java", "kafka.Kafka"}))
+
+	config, err = parseConfig([]byte("name: java\nsearch_string: ['java .*kafka\\.Kafka']"))
+	require.NoError(t, err)
+	assert.True(t, config.matchCmdline([]string{"/usr/bin/java", "kafka.Kafka"}))
+
+	// all the processes of a user
+	config, err = parseConfig([]byte("name: postgres\nuser: postgres"))
+	require.NoError(t, err)
+	assert.True(t, config.matchCmdline([]string{"anything"}))
+
+	for _, instance := range []string{
+		"search_string: [nginx]",
+		"name: nginx",
+		"name: nginx\nsearch_string: ['(']",
+		"name: nginx\nsearch_string: [nginx]\nthresholds:\n  critical: [2, 1]",
+		"name: nginx\nsearch_string: [nginx]\nthresholds:\n  warning: [1]",
+		"name: nginx\nsearch_string: [nginx]\nthresholds:\n  unknown: [1, 2]",
+	} {
+		_, err := parseConfig([]byte(instance))
+		assert.Error(t, err, instance)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/collector/check/defaults"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 	telemetry_utils "github.com/DataDog/datadog-agent/pkg/telemetry/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -44,6 +45,9 @@ type PythonCheck struct {
 	class        *C.rtloader_pyobject_t
 	ModuleName   string
 	interval     time.Duration
+	schedule     check.Schedule
+	timeout      time.Duration
+	group        string
 	lastWarnings []error
 	source       string
 	telemetry    bool // whether or not the telemetry is enabled for this check
@@ -138,6 +142,34 @@ func (c *PythonCheck) Cancel() {
 	aggregator.DestroySender(c.id)
 }
 
+// Interrupt raises an exception in the thread running the python check. The check
+// stops the next time it runs python code, so a check blocked in a call outside of
+// the interpreter stops once the call returns. Acquiring the GIL may block until
+// the running check releases it.
+func (c *PythonCheck) Interrupt() {
+	gstate, err := newStickyLock()
+	if err != nil {
+		log.Warnf("failed to interrupt check %s: %s", c.id, err)
+		return
+	}
+	defer gstate.unlock()
+
+	C.interrupt_check(rtloader, c.instance)
+	if err := getRtLoaderError(); err != nil {
+		log.Warnf("failed to interrupt check %s: %s", c.id, err)
+	}
+}
+
+// Timeout returns the maximum duration of a run, 0 meaning no timeout
+func (c *PythonCheck) Timeout() time.Duration {
+	return c.timeout
+}
+
+// ConcurrencyGroup returns the concurrency group of the check, if any
+func (c *PythonCheck) ConcurrencyGroup() string {
+	return c.group
+}
+
 // String representation (for debug and logging)
 func (c *PythonCheck) String() string {
 	return c.ModuleName
@@ -227,6 +259,16 @@ func (c *PythonCheck) Configure(data integration.Data, initConfig integration.Da
 		c.interval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 	}
 
+	// See if a cron schedule or a jitter was specified
+	c.schedule = check.NewSchedule(commonOptions)
+
+	// See if a run timeout was specified
+	if commonOptions.CheckTimeout > 0 {
+		c.timeout = time.Duration(commonOptions.CheckTimeout) * time.Second
+	}
+
+	c.group = commonOptions.ConcurrencyGroup
+
 	// Disable default hostname if specified
 	if commonOptions.EmptyDefaultHostname {
 		s, err := aggregator.GetSender(c.id)
@@ -247,6 +289,16 @@ func (c *PythonCheck) Configure(data integration.Data, initConfig integration.Da
 		}
 	}
 
+	// Set histogram aggregation overrides configured for this check
+	if histogramConfig := metrics.NewHistogramConfig(commonOptions); histogramConfig != nil {
+		s, err := aggregator.GetSender(c.id)
+		if err != nil {
+			log.Errorf("failed to retrieve a sender for check %s: %s", string(c.id), err)
+		} else {
+			s.SetCheckHistogramConfig(histogramConfig)
+		}
+	}
+
 	cInitConfig := TrackedCString(string(initConfig))
 	cInstance := TrackedCString(string(data))
 	cCheckID := TrackedCString(string(c.id))
@@ -311,6 +363,11 @@ func (c *PythonCheck) Interval() time.Duration {
 	return c.interval
 }
 
+// Schedule returns the cron expression and jitter configured for the check
+func (c *PythonCheck) Schedule() check.Schedule {
+	return c.schedule
+}
+
 // ID returns the ID of the check
 func (c *PythonCheck) ID() check.ID {
 	return c.id
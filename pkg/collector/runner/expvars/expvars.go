@@ -21,9 +21,12 @@ const (
 	// Nested keys
 	checksExpvarKey        = "Checks"
 	errorsExpvarKey        = "Errors"
+	queuedChecksExpvarKey  = "QueuedChecks"
 	runningChecksExpvarKey = "RunningChecks"
 	runsExpvarKey          = "Runs"
 	runningExpvarKey       = "Running"
+	skippedRunsExpvarKey   = "SkippedRuns"
+	timeoutsExpvarKey      = "Timeouts"
 	warningsExpvarKey      = "Warnings"
 )
 
@@ -77,8 +80,11 @@ func Reset() {
 	// Clear top-level expvars on the runner
 	for _, key := range []string{
 		errorsExpvarKey,
+		queuedChecksExpvarKey,
 		runsExpvarKey,
 		runningChecksExpvarKey,
+		skippedRunsExpvarKey,
+		timeoutsExpvarKey,
 		warningsExpvarKey,
 	} {
 		runnerStats.Delete(key)
@@ -119,13 +125,33 @@ func AddCheckStats(
 	mStats check.SenderStats,
 ) {
 
-	var s *check.Stats
+	log.Tracef("Adding stats for %s", string(c.ID()))
+
+	getOrCreateCheckStats(c).Add(execTime, err, warnings, mStats)
+}
+
+// AddCheckQueueTime adds the time spent waiting for a concurrency group slot to
+// the check's expvars, if the check already has stats
+func AddCheckQueueTime(id check.ID, queueTime time.Duration) {
+	s, found := CheckStats(id)
+	if !found {
+		return
+	}
+
+	s.AddQueueTime(queueTime)
+}
+
+// AddCheckSkippedRun adds a run skipped while waiting for a concurrency group slot
+// to the check's expvars, creating them if the check never ran
+func AddCheckSkippedRun(c check.Check) {
+	getOrCreateCheckStats(c).AddSkippedRun()
+}
 
+// getOrCreateCheckStats returns the stats of a check, creating them if needed
+func getOrCreateCheckStats(c check.Check) *check.Stats {
 	checkStats.statsLock.Lock()
 	defer checkStats.statsLock.Unlock()
 
-	log.Tracef("Adding stats for %s", string(c.ID()))
-
 	checkName := check.IDToCheckName(c.ID())
 	stats, found := checkStats.stats[checkName]
 	if !found {
@@ -133,13 +159,13 @@ func AddCheckStats(
 		checkStats.stats[checkName] = stats
 	}
 
-	s, found = stats[c.ID()]
+	s, found := stats[c.ID()]
 	if !found {
 		s = check.NewStats(c)
 		stats[c.ID()] = s
 	}
 
-	s.Add(execTime, err, warnings, mStats)
+	return s
 }
 
 // RemoveCheckStats removes a check from the check stats map
@@ -221,6 +247,36 @@ func GetRunningCheckCount() int64 {
 	return count.(*expvar.Int).Value()
 }
 
+// AddQueuedCheckCount is used to increment and decrement the 'QueuedChecks' expvar
+func AddQueuedCheckCount(amount int) {
+	runnerStats.Add(queuedChecksExpvarKey, int64(amount))
+}
+
+// GetQueuedCheckCount is used to get the value of 'QueuedChecks' expvar
+func GetQueuedCheckCount() int64 {
+	count := runnerStats.Get(queuedChecksExpvarKey)
+	if count == nil {
+		return 0
+	}
+
+	return count.(*expvar.Int).Value()
+}
+
+// AddSkippedRunsCount is used to increment the 'SkippedRuns' expvar
+func AddSkippedRunsCount(amount int) {
+	runnerStats.Add(skippedRunsExpvarKey, int64(amount))
+}
+
+// GetSkippedRunsCount is used to get the value of 'SkippedRuns' expvar
+func GetSkippedRunsCount() int64 {
+	count := runnerStats.Get(skippedRunsExpvarKey)
+	if count == nil {
+		return 0
+	}
+
+	return count.(*expvar.Int).Value()
+}
+
 // AddRunsCount is used to increment and decrement the 'Runs' expvar
 func AddRunsCount(amount int) {
 	runnerStats.Add(runsExpvarKey, int64(amount))
@@ -262,3 +318,17 @@ func GetErrorsCount() int64 {
 	}
 	return count.(*expvar.Int).Value()
 }
+
+// AddTimeoutsCount is used to increment the 'Timeouts' expvar
+func AddTimeoutsCount(amount int) {
+	runnerStats.Add(timeoutsExpvarKey, int64(amount))
+}
+
+// GetTimeoutsCount is used to get the value of 'Timeouts' expvar
+func GetTimeoutsCount() int64 {
+	count := runnerStats.Get(timeoutsExpvarKey)
+	if count == nil {
+		return 0
+	}
+	return count.(*expvar.Int).Value()
+}
@@ -376,15 +376,21 @@ func TestExpvarsToplevelKeys(t *testing.T) {
 
 	getters := map[string]func() int64{
 		"Errors":        GetErrorsCount,
+		"QueuedChecks":  GetQueuedCheckCount,
 		"Runs":          GetRunsCount,
 		"RunningChecks": GetRunningCheckCount,
+		"SkippedRuns":   GetSkippedRunsCount,
+		"Timeouts":      GetTimeoutsCount,
 		"Warnings":      GetWarningsCount,
 	}
 
 	for keyName, setter := range map[string]func(int){
 		"Errors":        AddErrorsCount,
+		"QueuedChecks":  AddQueuedCheckCount,
 		"Runs":          AddRunsCount,
 		"RunningChecks": AddRunningCheckCount,
+		"SkippedRuns":   AddSkippedRunsCount,
+		"Timeouts":      AddTimeoutsCount,
 		"Warnings":      AddWarningsCount,
 	} {
 
@@ -29,11 +29,12 @@ var (
 // the individual `worker.Worker` instance expvar stats
 type WorkerStats struct {
 	Utilization float64
+	QueueTime   float64 // time the checks run by the worker waited for a concurrency group slot, per second
 }
 
 // String is used by expvar package to print the variables
 func (ws *WorkerStats) String() string {
-	return fmt.Sprintf("{\"Utilization\": %.2f}", ws.Utilization)
+	return fmt.Sprintf("{\"Utilization\": %.2f, \"QueueTime\": %.2f}", ws.Utilization, ws.QueueTime)
 }
 
 func newWorkersExpvar(parent *expvar.Map) {
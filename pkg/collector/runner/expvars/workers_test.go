@@ -129,7 +129,7 @@ func TestWorkersInstances(t *testing.T) {
 	// Sanity check to ensure that the output is exactly what we expect
 	require.Equal(
 		t,
-		"{\"stats1\": {\"Utilization\": 1.03}, \"stats2\": {\"Utilization\": 2.01}}",
+		"{\"stats1\": {\"Utilization\": 1.03, \"QueueTime\": 0.00}, \"stats2\": {\"Utilization\": 2.01, \"QueueTime\": 0.00}}",
 		getWorkerInstancesStats(t),
 	)
 
@@ -46,6 +46,7 @@ type Runner struct {
 	isStaticWorkerCount bool                          // Flag indicating if numWorkers is dynamically updated
 	pendingChecksChan   chan check.Check              // The channel where checks come from
 	checksTracker       *tracker.RunningChecksTracker // Tracker in charge of maintaining the running check list
+	concurrencyGroups   *worker.ConcurrencyGroups     // Limits on the checks of a same group running concurrently
 	scheduler           *scheduler.Scheduler          // Scheduler runner operates on
 	schedulerLock       sync.RWMutex                  // Lock around operations on the scheduler
 }
@@ -61,6 +62,7 @@ func NewRunner() *Runner {
 		isStaticWorkerCount: numWorkers != 0,
 		pendingChecksChan:   make(chan check.Check),
 		checksTracker:       tracker.NewRunningChecksTracker(),
+		concurrencyGroups:   worker.NewConcurrencyGroups(getConcurrencyGroupLimits()),
 	}
 
 	if !r.isStaticWorkerCount {
@@ -72,6 +74,17 @@ func NewRunner() *Runner {
 	return r
 }
 
+// getConcurrencyGroupLimits returns the limits of the check concurrency groups set in the configuration
+func getConcurrencyGroupLimits() map[string]int {
+	limits := make(map[string]int)
+	if err := config.Datadog.UnmarshalKey("check_concurrency_groups", &limits); err != nil {
+		log.Errorf("Unable to parse check_concurrency_groups, concurrency groups will run one check at a time: %s", err)
+		return map[string]int{}
+	}
+
+	return limits
+}
+
 // EnsureMinWorkers increases the number of workers to match the
 // `desiredNumWorkers` parameter
 func (r *Runner) ensureMinWorkers(desiredNumWorkers int) {
@@ -119,6 +132,7 @@ func (r *Runner) newWorker() (*worker.Worker, error) {
 		r.pendingChecksChan,
 		r.checksTracker,
 		r.ShouldAddCheckStats,
+		r.concurrencyGroups,
 	)
 	if err != nil {
 		log.Errorf("Runner %d was unable to instantiate a worker: %s", r.id, err)
@@ -176,6 +190,7 @@ func (r *Runner) Stop() {
 	}
 
 	log.Infof("Runner %d is shutting down...", r.id)
+	r.concurrencyGroups.Stop()
 	close(r.pendingChecksChan)
 
 	wg := sync.WaitGroup{}
@@ -49,10 +49,11 @@ type Scheduler struct {
 	mu               sync.Mutex                  // To protect critical sections in struct's fields
 
 	checkToQueue map[check.ID]*jobQueue // Keep track of what is the queue for any Check
+	timedJobs    map[check.ID]*timedJob // Checks with a custom schedule (cron or jitter) don't use the queues
 	// To protect checkToQueue. Using mu would create a deadlock when stopping the Scheduler. 'jobQueue' is calling
 	// 'IsCheckScheduled' right when then 'Stop' function is called and mu is already lock. for this reason we have
 	// to lock: one for the Scheduler and a dedicated one for the 'IsCheckScheduled' method. This way 'jobQueue' and
-	// metadata provider can call 'IsCheckScheduled' without creating a deadlock.
+	// metadata provider can call 'IsCheckScheduled' without creating a deadlock. It also protects timedJobs.
 	checkToQueueMutex sync.RWMutex
 
 	cancelOneTime chan bool      // Used to internally communicate a cancel signal to one-time schedule goroutines
@@ -68,6 +69,7 @@ func NewScheduler(checksPipe chan<- check.Check) *Scheduler {
 		started:          make(chan bool),
 		jobQueues:        make(map[time.Duration]*jobQueue),
 		checkToQueue:     make(map[check.ID]*jobQueue),
+		timedJobs:        make(map[check.ID]*timedJob),
 		tlmTrackedChecks: make(map[check.ID]string),
 		running:          0,
 		cancelOneTime:    make(chan bool),
@@ -77,6 +79,8 @@ func NewScheduler(checksPipe chan<- check.Check) *Scheduler {
 
 // Enter schedules a `Check`s for execution accordingly to the `Check.Interval()` value.
 // If the interval is 0, the check is supposed to run only once.
+// Checks implementing `check.ScheduledCheck` can instead run on a cron expression,
+// or have a random jitter added to their interval.
 func (s *Scheduler) Enter(check check.Check) error {
 	// enqueue immediately if this is a one-time schedule
 	if check.Interval() == 0 {
@@ -84,6 +88,10 @@ func (s *Scheduler) Enter(check check.Check) error {
 		return nil
 	}
 
+	if schedule := customSchedule(check); schedule.IsCustom() {
+		return s.enterTimed(check, schedule)
+	}
+
 	if check.Interval() < minAllowedInterval {
 		return fmt.Errorf("Schedule interval must be greater than %v or 0", minAllowedInterval)
 	}
@@ -129,17 +137,21 @@ func (s *Scheduler) Cancel(id check.ID) error {
 
 	log.Infof("Unscheduling check %s", string(id))
 
-	if _, ok := s.checkToQueue[id]; !ok {
+	if job, ok := s.timedJobs[id]; ok {
+		// don't wait for the job to stop: it may be waiting on checkToQueueMutex
+		job.halt()
+		delete(s.timedJobs, id)
+	} else if _, ok := s.checkToQueue[id]; ok {
+		// remove it from the queue
+		err := s.checkToQueue[id].removeJob(id)
+		if err != nil {
+			return fmt.Errorf("unable to remove the Job from the queue: %s", err)
+		}
+		delete(s.checkToQueue, id)
+	} else {
 		return nil
 	}
 
-	// remove it from the queue
-	err := s.checkToQueue[id].removeJob(id)
-	if err != nil {
-		return fmt.Errorf("unable to remove the Job from the queue: %s", err)
-	}
-	delete(s.checkToQueue, id)
-
 	schedulerChecksEntered.Add(-1)
 	if checkName, ok := s.tlmTrackedChecks[id]; ok {
 		delete(s.tlmTrackedChecks, id)
@@ -214,10 +226,65 @@ func (s *Scheduler) IsCheckScheduled(id check.ID) bool {
 	s.checkToQueueMutex.RLock()
 	defer s.checkToQueueMutex.RUnlock()
 
+	if _, found := s.timedJobs[id]; found {
+		return true
+	}
 	_, found := s.checkToQueue[id]
 	return found
 }
 
+// enterTimed schedules a check with a custom schedule in its own timedJob
+func (s *Scheduler) enterTimed(c check.Check, schedule check.Schedule) error {
+	job, err := newTimedJob(c, schedule)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Cron != "" {
+		log.Infof("Scheduling check %v with the cron schedule '%s' and a jitter of %v", c, schedule.Cron, schedule.Jitter)
+	} else {
+		log.Infof("Scheduling check %v with an interval of %v and a jitter of %v", c, c.Interval(), schedule.Jitter)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.checkToQueueMutex.Lock()
+	_, timedFound := s.timedJobs[c.ID()]
+	_, queuedFound := s.checkToQueue[c.ID()]
+	if timedFound || queuedFound {
+		s.checkToQueueMutex.Unlock()
+		return fmt.Errorf("check %s is already scheduled", c.ID())
+	}
+	s.timedJobs[c.ID()] = job
+	s.checkToQueueMutex.Unlock()
+
+	job.start(s)
+
+	schedulerChecksEntered.Add(1)
+	if c.IsTelemetryEnabled() {
+		checkName := c.String()
+		s.tlmTrackedChecks[c.ID()] = checkName
+		tlmChecksEntered.Inc(checkName)
+	}
+	schedulerExpvars.Set("NextRuns", expvar.Func(expNextRuns(s)))
+	return nil
+}
+
+// NextRun returns the time of the next run of a check with a custom schedule.
+// It returns false for checks scheduled in the interval queues, or not scheduled at all.
+func (s *Scheduler) NextRun(id check.ID) (time.Time, bool) {
+	s.checkToQueueMutex.RLock()
+	defer s.checkToQueueMutex.RUnlock()
+
+	job, found := s.timedJobs[id]
+	if !found {
+		return time.Time{}, false
+	}
+	nextRun := job.getNextRun()
+	return nextRun, !nextRun.IsZero()
+}
+
 // stopQueues shuts down the timers for each active queue
 // Blocks until all the queues have fully stopped
 func (s *Scheduler) stopQueues() {
@@ -235,6 +302,18 @@ func (s *Scheduler) stopQueues() {
 			q.running = false
 		}
 	}
+
+	s.checkToQueueMutex.RLock()
+	jobs := make([]*timedJob, 0, len(s.timedJobs))
+	for _, j := range s.timedJobs {
+		jobs = append(jobs, j)
+	}
+	s.checkToQueueMutex.RUnlock()
+
+	log.Debugf("Stopping %v timed job(s)", len(jobs))
+	for _, j := range jobs {
+		<-j.halt()
+	}
 }
 
 // startQueues loads the timer for each queue
@@ -246,6 +325,12 @@ func (s *Scheduler) startQueues() {
 	for _, q := range s.jobQueues {
 		s.startQueue(q)
 	}
+
+	s.checkToQueueMutex.RLock()
+	defer s.checkToQueueMutex.RUnlock()
+	for _, j := range s.timedJobs {
+		j.start(s)
+	}
 }
 
 // startQueue starts a queue (non-blocking operation) if it's not running yet
@@ -274,6 +359,30 @@ func (s *Scheduler) enqueueOnce(check check.Check) {
 	schedulerChecksEntered.Add(1)
 }
 
+// customSchedule returns the custom schedule of the check, if any
+func customSchedule(c check.Check) check.Schedule {
+	if sc, ok := c.(check.ScheduledCheck); ok {
+		return sc.Schedule()
+	}
+	return check.Schedule{}
+}
+
+// expNextRuns return a function to get the next run of checks with a custom schedule
+func expNextRuns(s *Scheduler) func() interface{} {
+	return func() interface{} {
+		s.checkToQueueMutex.RLock()
+		defer s.checkToQueueMutex.RUnlock()
+
+		nextRuns := make(map[string]int64, len(s.timedJobs))
+		for id, job := range s.timedJobs {
+			if nextRun := job.getNextRun(); !nextRun.IsZero() {
+				nextRuns[string(id)] = nextRun.Unix()
+			}
+		}
+		return nextRuns
+	}
+}
+
 // expQueues return a function to get the stats for the queues
 func expQueues(s *Scheduler) func() interface{} {
 	return func() interface{} {
@@ -0,0 +1,152 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// cronParser accepts standard 5-field cron expressions and descriptors like `@hourly`
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// timedJob schedules a single check with a custom schedule: either a cron expression,
+// or its interval with a random jitter added to each run.
+type timedJob struct {
+	check    check.Check
+	schedule cron.Schedule
+	cron     bool
+	jitter   time.Duration
+	nextRun  time.Time
+	stop     chan bool // closed to stop this job
+	stopped  chan bool // closed when this job has stopped
+	running  bool
+	mu       sync.RWMutex // to protect critical sections in struct's fields
+}
+
+// newTimedJob creates a new timedJob instance, returning an error if the cron expression is invalid
+func newTimedJob(c check.Check, s check.Schedule) (*timedJob, error) {
+	j := &timedJob{
+		check:  c,
+		jitter: s.Jitter,
+	}
+
+	if s.Cron != "" {
+		schedule, err := cronParser.Parse(s.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule '%s': %s", s.Cron, err)
+		}
+		j.schedule = schedule
+		j.cron = true
+	} else {
+		j.schedule = cron.Every(c.Interval())
+	}
+
+	return j, nil
+}
+
+// next returns the next time the job should run, given the previous scheduled time
+func (j *timedJob) next(previous time.Time) time.Time {
+	now := time.Now()
+	if previous.IsZero() && !j.cron {
+		// interval-based checks run right away, like in job queues
+		return now
+	}
+	next := j.schedule.Next(previous)
+	if next.Before(now) {
+		// we are running behind the schedule, skip the missed runs
+		next = j.schedule.Next(now)
+	}
+	return next
+}
+
+func (j *timedJob) randomJitter() time.Duration {
+	if j.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(j.jitter)))
+}
+
+// getNextRun returns the time of the next run, zero if the job isn't running
+func (j *timedJob) getNextRun() time.Time {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	return j.nextRun
+}
+
+// start schedules the check by posting it to the execution pipeline.
+// Not blocking, runs in a new goroutine.
+func (j *timedJob) start(s *Scheduler) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.running {
+		return
+	}
+	j.running = true
+	j.stop = make(chan bool)
+	j.stopped = make(chan bool)
+
+	go j.run(s, j.stop, j.stopped)
+}
+
+func (j *timedJob) run(s *Scheduler, stop <-chan bool, stopped chan<- bool) {
+	defer close(stopped)
+
+	var scheduled time.Time
+	for {
+		scheduled = j.next(scheduled)
+		runAt := scheduled.Add(j.randomJitter())
+
+		j.mu.Lock()
+		j.nextRun = runAt
+		j.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(runAt))
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.IsCheckScheduled(j.check.ID()) {
+			continue
+		}
+
+		log.Tracef("Enqueuing check %v scheduled at %v", j.check, runAt)
+		select {
+		// blocking, we'll be here as long as it takes
+		case s.checksPipe <- j.check:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// halt stops the job, and returns a channel closed once it has fully stopped
+func (j *timedJob) halt() <-chan bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if !j.running {
+		done := make(chan bool)
+		close(done)
+		return done
+	}
+	j.running = false
+	j.nextRun = time.Time{}
+	close(j.stop)
+	return j.stopped
+}
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+)
+
+// FIXTURE
+type TestScheduledCheck struct {
+	TestCheck
+	schedule check.Schedule
+}
+
+func (c *TestScheduledCheck) Schedule() check.Schedule { return c.schedule }
+
+func TestNewTimedJobInvalidCron(t *testing.T) {
+	c := &TestScheduledCheck{TestCheck: TestCheck{intl: time.Minute}, schedule: check.Schedule{Cron: "not a cron"}}
+	_, err := newTimedJob(c, c.schedule)
+	assert.Error(t, err)
+
+	s := getScheduler()
+	assert.Error(t, s.Enter(c))
+	assert.False(t, s.IsCheckScheduled(c.ID()))
+}
+
+func TestTimedJobNext(t *testing.T) {
+	c := &TestScheduledCheck{TestCheck: TestCheck{intl: time.Minute}, schedule: check.Schedule{Cron: "5 * * * *"}}
+	j, err := newTimedJob(c, c.schedule)
+	require.NoError(t, err)
+
+	next := j.next(time.Time{})
+	assert.Equal(t, 5, next.Minute())
+	assert.Equal(t, 0, next.Second())
+	assert.True(t, next.After(time.Now()))
+	assert.True(t, next.Before(time.Now().Add(time.Hour+time.Second)))
+
+	// the following run is one hour later
+	assert.Equal(t, next.Add(time.Hour), j.next(next))
+
+	// interval-based jobs run right away, then every interval
+	c.schedule = check.Schedule{Jitter: 10 * time.Second}
+	j, err = newTimedJob(c, c.schedule)
+	require.NoError(t, err)
+	first := j.next(time.Time{})
+	assert.WithinDuration(t, time.Now(), first, time.Second)
+	assert.WithinDuration(t, first.Add(time.Minute), j.next(first), time.Second)
+
+	for i := 0; i < 100; i++ {
+		jitter := j.randomJitter()
+		assert.True(t, jitter >= 0 && jitter < 10*time.Second)
+	}
+}
+
+func TestEnterTimed(t *testing.T) {
+	ch := make(chan check.Check)
+	s := NewScheduler(ch)
+
+	c := &TestScheduledCheck{TestCheck: TestCheck{intl: time.Minute}, schedule: check.Schedule{Cron: "@hourly"}}
+	require.NoError(t, s.Enter(c))
+	assert.Len(t, s.jobQueues, 0)
+	assert.True(t, s.IsCheckScheduled(c.ID()))
+
+	s.Run()
+	assert.Eventually(t, func() bool {
+		nextRun, ok := s.NextRun(c.ID())
+		return ok && nextRun.Minute() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, s.Cancel(c.ID()))
+	assert.False(t, s.IsCheckScheduled(c.ID()))
+	_, ok := s.NextRun(c.ID())
+	assert.False(t, ok)
+
+	assert.Nil(t, s.Stop())
+}
+
+func TestEnterTimedTwice(t *testing.T) {
+	ch := make(chan check.Check)
+	s := NewScheduler(ch)
+
+	c := &TestScheduledCheck{TestCheck: TestCheck{intl: time.Minute}, schedule: check.Schedule{Cron: "@hourly"}}
+	require.NoError(t, s.Enter(c))
+	job := s.timedJobs[c.ID()]
+
+	// the running job is kept, not replaced by a new one
+	assert.Error(t, s.Enter(c))
+	assert.Len(t, s.timedJobs, 1)
+	assert.Same(t, job, s.timedJobs[c.ID()])
+
+	require.NoError(t, s.Cancel(c.ID()))
+	assert.False(t, s.IsCheckScheduled(c.ID()))
+	require.NoError(t, s.Enter(c))
+	assert.True(t, s.IsCheckScheduled(c.ID()))
+	require.NoError(t, s.Cancel(c.ID()))
+}
+
+func TestTimedJobEnqueues(t *testing.T) {
+	ch := make(chan check.Check)
+	s := NewScheduler(ch)
+	defer s.Stop()
+
+	// a check without a cron expression runs right away, delayed by the jitter
+	c := &TestScheduledCheck{TestCheck: TestCheck{intl: time.Minute}, schedule: check.Schedule{Jitter: 10 * time.Millisecond}}
+	require.NoError(t, s.Enter(c))
+	s.Run()
+
+	select {
+	case enqueued := <-ch:
+		assert.Equal(t, c.ID(), enqueued.ID())
+	case <-time.After(time.Second):
+		assert.Fail(t, "check was not enqueued")
+	}
+
+	nextRun, ok := s.NextRun(c.ID())
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), nextRun, time.Second)
+}
+
+func TestTimedJobIgnoredForOneTimeChecks(t *testing.T) {
+	s := getScheduler()
+	defer s.Stop()
+
+	c := &TestScheduledCheck{schedule: check.Schedule{Cron: "@hourly"}}
+	require.NoError(t, s.Enter(c))
+	assert.Len(t, s.timedJobs, 0)
+}
@@ -0,0 +1,187 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// defaultConcurrencyGroupLimit is the limit of the groups without a configured one
+const defaultConcurrencyGroupLimit = 1
+
+// queuedRun is a run of a check waiting for a slot of its concurrency group
+type queuedRun struct {
+	check    check.Check
+	group    string
+	queuedAt time.Time
+}
+
+// concurrencyGroup holds the free slots of a group and the runs waiting for one
+type concurrencyGroup struct {
+	freeSlots int
+	pending   []*queuedRun
+}
+
+// ConcurrencyGroups limits the number of checks of a same concurrency group
+// running at the same time, across all the workers sharing the instance.
+// The runs of a full group are queued without holding a worker: the slot freed
+// by a run is handed over to the oldest queued run of the group, which is then
+// run by the worker that freed it.
+type ConcurrencyGroups struct {
+	limits  map[string]int
+	groups  map[string]*concurrencyGroup
+	stopped bool
+
+	// ready holds the queued runs handed a slot outside of a worker loop, i.e. freed
+	// by a run that returned after timing out. readyNotify wakes up a worker to run them.
+	ready       []*queuedRun
+	readyNotify chan struct{}
+
+	m sync.Mutex
+}
+
+// NewConcurrencyGroups returns a `ConcurrencyGroups` enforcing the given limits
+func NewConcurrencyGroups(limits map[string]int) *ConcurrencyGroups {
+	return &ConcurrencyGroups{
+		limits:      limits,
+		groups:      make(map[string]*concurrencyGroup),
+		readyNotify: make(chan struct{}, 1),
+	}
+}
+
+// group returns a group, creating it with its configured limit if needed.
+// It must be called with the lock held.
+func (g *ConcurrencyGroups) group(name string) *concurrencyGroup {
+	group, found := g.groups[name]
+	if !found {
+		limit, configured := g.limits[name]
+		if !configured {
+			limit = defaultConcurrencyGroupLimit
+		} else if limit < 1 {
+			log.Warnf("Invalid limit %d for concurrency group %s, using %d instead", limit, name, defaultConcurrencyGroupLimit)
+			limit = defaultConcurrencyGroupLimit
+		}
+		group = &concurrencyGroup{freeSlots: limit}
+		g.groups[name] = group
+	}
+	return group
+}
+
+// acquireOrQueue takes a slot of the group for the check if one is available. Otherwise
+// the run is queued until a slot is handed over to it, unless a run of the same check is
+// already queued, in which case queued is false and the run should be skipped.
+func (g *ConcurrencyGroups) acquireOrQueue(name string, c check.Check) (acquired bool, queued bool) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	group := g.group(name)
+	if group.freeSlots > 0 {
+		group.freeSlots--
+		return true, false
+	}
+
+	if g.stopped {
+		return false, false
+	}
+	for _, run := range group.pending {
+		if run.check.ID() == c.ID() {
+			return false, false
+		}
+	}
+
+	group.pending = append(group.pending, &queuedRun{check: c, group: name, queuedAt: time.Now()})
+	expvars.AddQueuedCheckCount(1)
+	return false, true
+}
+
+// release frees a slot taken by `acquireOrQueue`. If runs of the group are queued, the
+// slot is handed over to the oldest one, which is returned. The queued runs which waited
+// longer than the interval of their check are dropped and returned as expired, as the
+// check has been scheduled again since.
+func (g *ConcurrencyGroups) release(name string) (next *queuedRun, expired []*queuedRun) {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	group := g.group(name)
+	for len(group.pending) > 0 {
+		run := group.pending[0]
+		group.pending[0] = nil
+		group.pending = group.pending[1:]
+		expvars.AddQueuedCheckCount(-1)
+
+		if interval := run.check.Interval(); interval > 0 && time.Since(run.queuedAt) > interval {
+			expired = append(expired, run)
+			continue
+		}
+		return run, expired
+	}
+
+	group.freeSlots++
+	return nil, expired
+}
+
+// releaseToReady frees a slot like `release`, the run the slot is handed over to
+// being added to the ready runs for the workers to pick it up
+func (g *ConcurrencyGroups) releaseToReady(name string) []*queuedRun {
+	next, expired := g.release(name)
+	if next == nil {
+		return expired
+	}
+
+	g.m.Lock()
+	g.ready = append(g.ready, next)
+	g.m.Unlock()
+
+	g.notifyReady()
+	return expired
+}
+
+// notifyReady wakes up a worker, without blocking if one was already notified
+func (g *ConcurrencyGroups) notifyReady() {
+	select {
+	case g.readyNotify <- struct{}{}:
+	default:
+	}
+}
+
+// popReady returns the oldest ready run, if any
+func (g *ConcurrencyGroups) popReady() *queuedRun {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	if len(g.ready) == 0 {
+		return nil
+	}
+	run := g.ready[0]
+	g.ready[0] = nil
+	g.ready = g.ready[1:]
+
+	// Other workers may be needed for the remaining runs
+	if len(g.ready) > 0 {
+		g.notifyReady()
+	}
+	return run
+}
+
+// Stop drops the queued runs and stops queueing new ones, it is called when the
+// runner stops so that the queued checks aren't run after being stopped
+func (g *ConcurrencyGroups) Stop() {
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	g.stopped = true
+
+	g.ready = nil
+	for _, group := range g.groups {
+		expvars.AddQueuedCheckCount(-len(group.pending))
+		group.pending = nil
+	}
+}
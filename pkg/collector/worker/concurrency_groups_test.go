@@ -0,0 +1,157 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
+)
+
+func newGroupedCheck(t *testing.T, id string, group string) *testGroupedCheck {
+	return &testGroupedCheck{
+		testCheck: testCheck{t: t, id: id},
+		group:     group,
+		interval:  time.Minute,
+	}
+}
+
+func TestConcurrencyGroupsLimits(t *testing.T) {
+	groups := NewConcurrencyGroups(map[string]int{
+		"db":      2,
+		"invalid": 0,
+	})
+	c := newGroupedCheck(t, "check:123", "")
+
+	// Configured limit
+	acquired, _ := groups.acquireOrQueue("db", c)
+	assert.True(t, acquired)
+	acquired, _ = groups.acquireOrQueue("db", c)
+	assert.True(t, acquired)
+	acquired, queued := groups.acquireOrQueue("db", c)
+	assert.False(t, acquired)
+	assert.True(t, queued)
+
+	// Unconfigured and invalid limits fall back to the default limit
+	for _, group := range []string{"other", "invalid"} {
+		acquired, _ := groups.acquireOrQueue(group, c)
+		assert.True(t, acquired)
+		acquired, _ = groups.acquireOrQueue(group, c)
+		assert.False(t, acquired)
+	}
+}
+
+func TestConcurrencyGroupsQueue(t *testing.T) {
+	expvars.Reset()
+	groups := NewConcurrencyGroups(nil)
+
+	running := newGroupedCheck(t, "running:123", "db")
+	first := newGroupedCheck(t, "first:123", "db")
+	second := newGroupedCheck(t, "second:123", "db")
+
+	acquired, _ := groups.acquireOrQueue("db", running)
+	require.True(t, acquired)
+	for _, c := range []*testGroupedCheck{first, second} {
+		acquired, queued := groups.acquireOrQueue("db", c)
+		assert.False(t, acquired)
+		assert.True(t, queued)
+	}
+	assert.Equal(t, int64(2), expvars.GetQueuedCheckCount())
+
+	// A check is queued only once
+	acquired, queued := groups.acquireOrQueue("db", first)
+	assert.False(t, acquired)
+	assert.False(t, queued)
+
+	// The slots are handed over to the queued runs in order
+	next, expired := groups.release("db")
+	require.NotNil(t, next)
+	assert.Equal(t, check.ID("first:123"), next.check.ID())
+	assert.Empty(t, expired)
+
+	next, _ = groups.release("db")
+	require.NotNil(t, next)
+	assert.Equal(t, check.ID("second:123"), next.check.ID())
+	assert.Equal(t, int64(0), expvars.GetQueuedCheckCount())
+
+	// The last release frees the slot
+	next, _ = groups.release("db")
+	assert.Nil(t, next)
+	acquired, _ = groups.acquireOrQueue("db", first)
+	assert.True(t, acquired)
+}
+
+func TestConcurrencyGroupsQueueExpired(t *testing.T) {
+	expvars.Reset()
+	groups := NewConcurrencyGroups(nil)
+
+	running := newGroupedCheck(t, "running:123", "db")
+	stale := newGroupedCheck(t, "stale:123", "db")
+	stale.interval = time.Millisecond
+	waiting := newGroupedCheck(t, "waiting:123", "db")
+
+	acquired, _ := groups.acquireOrQueue("db", running)
+	require.True(t, acquired)
+	groups.acquireOrQueue("db", stale)
+	groups.acquireOrQueue("db", waiting)
+
+	// The runs which waited longer than their interval are dropped
+	time.Sleep(10 * time.Millisecond)
+	next, expired := groups.release("db")
+	require.NotNil(t, next)
+	assert.Equal(t, check.ID("waiting:123"), next.check.ID())
+	require.Len(t, expired, 1)
+	assert.Equal(t, check.ID("stale:123"), expired[0].check.ID())
+}
+
+func TestConcurrencyGroupsReady(t *testing.T) {
+	groups := NewConcurrencyGroups(nil)
+
+	running := newGroupedCheck(t, "running:123", "db")
+	waiting := newGroupedCheck(t, "waiting:123", "db")
+
+	groups.acquireOrQueue("db", running)
+	groups.acquireOrQueue("db", waiting)
+	assert.Nil(t, groups.popReady())
+
+	// The slot freed outside of a worker loop is handed over through the ready runs
+	assert.Empty(t, groups.releaseToReady("db"))
+	select {
+	case <-groups.readyNotify:
+	default:
+		assert.Fail(t, "the workers weren't notified of the ready run")
+	}
+	run := groups.popReady()
+	require.NotNil(t, run)
+	assert.Equal(t, check.ID("waiting:123"), run.check.ID())
+	assert.Nil(t, groups.popReady())
+}
+
+func TestConcurrencyGroupsStop(t *testing.T) {
+	expvars.Reset()
+	groups := NewConcurrencyGroups(nil)
+
+	running := newGroupedCheck(t, "running:123", "db")
+	waiting := newGroupedCheck(t, "waiting:123", "db")
+
+	groups.acquireOrQueue("db", running)
+	groups.acquireOrQueue("db", waiting)
+	groups.Stop()
+	assert.Equal(t, int64(0), expvars.GetQueuedCheckCount())
+
+	// The queued runs are dropped and no run is queued anymore
+	next, _ := groups.release("db")
+	assert.Nil(t, next)
+	groups.acquireOrQueue("db", running)
+	acquired, queued := groups.acquireOrQueue("db", waiting)
+	assert.False(t, acquired)
+	assert.False(t, queued)
+}
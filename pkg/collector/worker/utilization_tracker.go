@@ -29,10 +29,21 @@ type utilizationTracker struct {
 	pollingFunc      util.PollingFunc
 	statsUpdateFunc  util.StatsUpdateFunc
 
+	queueStats           util.SlidingWindow
+	queuePollingFunc     util.PollingFunc
+	queueStatsUpdateFunc util.StatsUpdateFunc
+
+	// workerStats holds the latest values of both windows. It has its own lock
+	// as the update functions are invoked while the windows hold theirs.
+	workerStats expvars.WorkerStats
+	statsLock   sync.Mutex
+
 	clock              clock.Clock
 	busyDuration       time.Duration
 	checkStart         time.Time
 	windowStart        time.Time
+	queuedDuration     time.Duration
+	queueWindowStart   time.Time
 	isRunningLongCheck bool
 }
 
@@ -46,6 +57,10 @@ type UtilizationTracker interface {
 	// expvars.
 	Stop() error
 
+	// CheckQueued tracks the time a check about to be run by the worker
+	// waited for a slot of its concurrency group.
+	CheckQueued(time.Duration)
+
 	// CheckStarted starts tracking the start time of a check.
 	CheckStarted(bool)
 
@@ -86,14 +101,21 @@ func newUtilizationTrackerWithClock(
 		return nil, err
 	}
 
+	queueSw, err := util.NewSlidingWindowWithClock(windowSize, pollingInterval, clk)
+	if err != nil {
+		return nil, err
+	}
+
 	ut := &utilizationTracker{
 		workerName:       workerName,
 		utilizationStats: sw,
+		queueStats:       queueSw,
 
-		clock:        clk,
-		busyDuration: time.Duration(0),
-		checkStart:   time.Time{},
-		windowStart:  clk.Now(),
+		clock:            clk,
+		busyDuration:     time.Duration(0),
+		checkStart:       time.Time{},
+		windowStart:      clk.Now(),
+		queueWindowStart: clk.Now(),
 	}
 
 	ut.pollingFunc = func() float64 {
@@ -120,9 +142,38 @@ func newUtilizationTrackerWithClock(
 	ut.statsUpdateFunc = func(sw util.SlidingWindow) {
 		utilization := sw.Average()
 
-		expvars.SetWorkerStats(workerName, &expvars.WorkerStats{
-			Utilization: utilization,
-		})
+		ut.statsLock.Lock()
+		defer ut.statsLock.Unlock()
+
+		ut.workerStats.Utilization = utilization
+		stats := ut.workerStats
+		expvars.SetWorkerStats(workerName, &stats)
+	}
+
+	ut.queuePollingFunc = func() float64 {
+		ut.Lock()
+		defer ut.Unlock()
+
+		currentTime := clk.Now()
+
+		pollingWindowDuration := currentTime.Sub(ut.queueWindowStart)
+		ut.queueWindowStart = currentTime
+
+		queueTime := float64(ut.queuedDuration) / float64(pollingWindowDuration)
+		ut.queuedDuration = time.Duration(0)
+
+		return queueTime
+	}
+
+	ut.queueStatsUpdateFunc = func(sw util.SlidingWindow) {
+		queueTime := sw.Average()
+
+		ut.statsLock.Lock()
+		defer ut.statsLock.Unlock()
+
+		ut.workerStats.QueueTime = queueTime
+		stats := ut.workerStats
+		expvars.SetWorkerStats(workerName, &stats)
 	}
 
 	return ut, nil
@@ -149,12 +200,18 @@ func (ut *utilizationTracker) Start() error {
 		},
 	)
 
-	// Start the ticker
+	// Start the tickers
 	err := ut.utilizationStats.Start(ut.pollingFunc, ut.statsUpdateFunc)
 	if err != nil {
 		return err
 	}
 
+	err = ut.queueStats.Start(ut.queuePollingFunc, ut.queueStatsUpdateFunc)
+	if err != nil {
+		ut.utilizationStats.Stop()
+		return err
+	}
+
 	ut.started = true
 
 	return nil
@@ -177,11 +234,24 @@ func (ut *utilizationTracker) Stop() error {
 	ut.stopped = true
 
 	ut.utilizationStats.Stop()
+	ut.queueStats.Stop()
 	expvars.DeleteWorkerStats(ut.workerName)
 
 	return nil
 }
 
+// CheckQueued should be invoked when a worker is about to run a check
+// which waited for a slot of its concurrency group, so that we can track
+// the time the checks run by the worker spend queued. The checks wait
+// without holding the worker.
+func (ut *utilizationTracker) CheckQueued(queueTime time.Duration) {
+	ut.Lock()
+
+	ut.queuedDuration += queueTime
+
+	ut.Unlock()
+}
+
 // CheckStarted should be invoked when a worker's check is about to
 // run so that we can track the start time and the utilization. Long-running
 // flag is to indicate if we should be worried about showing warnings
@@ -20,9 +20,8 @@ import (
 
 // Helpers
 
-// getWorkerUtilizationExpvar returns the utilization as presented by expvars
-// for a named worker.
-func getWorkerUtilizationExpvar(t *testing.T, name string) float64 {
+// getWorkerStatsExpvar returns the stats as presented by expvars for a named worker.
+func getWorkerStatsExpvar(t *testing.T, name string) *expvars.WorkerStats {
 	runnerMapExpvar := expvar.Get("runner")
 	require.NotNil(t, runnerMapExpvar)
 
@@ -38,7 +37,19 @@ func getWorkerUtilizationExpvar(t *testing.T, name string) float64 {
 	workerStats := workerStatsExpvar.(*expvars.WorkerStats)
 	require.NotNil(t, workerStats)
 
-	return workerStats.Utilization
+	return workerStats
+}
+
+// getWorkerUtilizationExpvar returns the utilization as presented by expvars
+// for a named worker.
+func getWorkerUtilizationExpvar(t *testing.T, name string) float64 {
+	return getWorkerStatsExpvar(t, name).Utilization
+}
+
+// getWorkerQueueTimeExpvar returns the queue time as presented by expvars
+// for a named worker.
+func getWorkerQueueTimeExpvar(t *testing.T, name string) float64 {
+	return getWorkerStatsExpvar(t, name).QueueTime
 }
 
 func newTracker(t *testing.T) (UtilizationTracker, *clock.Mock) {
@@ -211,6 +222,32 @@ func TestUtilizationTrackerCheckLifecycle(t *testing.T) {
 	}
 }
 
+func TestUtilizationTrackerQueuedCheck(t *testing.T) {
+	windowSize := 250 * time.Millisecond
+	pollingInterval := 50 * time.Millisecond
+
+	clk := clock.NewMock()
+	ut, err := newUtilizationTrackerWithClock("worker", windowSize, pollingInterval, clk)
+	require.Nil(t, err)
+
+	require.NoError(t, ut.Start())
+	defer func() {
+		ut.Stop()
+		AssertAsyncWorkerCount(t, 0)
+	}()
+
+	// The time a check waited for a concurrency group slot is reported
+	// once, and doesn't count as utilization
+	ut.CheckQueued(windowSize)
+
+	clk.Add(pollingInterval)
+	assert.Greater(t, getWorkerQueueTimeExpvar(t, "worker"), 0.0)
+	assert.InDelta(t, getWorkerUtilizationExpvar(t, "worker"), 0, 0)
+
+	clk.Add(windowSize * 2)
+	assert.InDelta(t, getWorkerQueueTimeExpvar(t, "worker"), 0, 0)
+}
+
 func TestUtilizationTrackerAccuracy(t *testing.T) {
 	windowSize := 3000 * time.Millisecond
 	pollingInterval := 50 * time.Millisecond
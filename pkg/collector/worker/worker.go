@@ -34,6 +34,7 @@ type Worker struct {
 	Name string
 
 	checksTracker           *tracker.RunningChecksTracker
+	concurrencyGroups       *ConcurrencyGroups
 	getDefaultSenderFunc    func() (aggregator.Sender, error)
 	pendingChecksChan       chan check.Check
 	runnerID                int
@@ -41,13 +42,16 @@ type Worker struct {
 	utilizationTracker      UtilizationTracker
 }
 
-// NewWorker returns an instance of a `Worker` after parameter sanity checks are passed
+// NewWorker returns an instance of a `Worker` after parameter sanity checks are passed.
+// The `concurrencyGroups` are shared by all the workers of a runner, a nil value
+// disabling the concurrency limits.
 func NewWorker(
 	runnerID int,
 	ID int,
 	pendingChecksChan chan check.Check,
 	checksTracker *tracker.RunningChecksTracker,
 	shouldAddCheckStatsFunc func(id check.ID) bool,
+	concurrencyGroups *ConcurrencyGroups,
 ) (*Worker, error) {
 
 	if checksTracker == nil {
@@ -62,7 +66,7 @@ func NewWorker(
 		return nil, fmt.Errorf("worker cannot initialize using a nil shouldAddCheckStatsFunc")
 	}
 
-	return newWorkerWithOptions(
+	worker, err := newWorkerWithOptions(
 		runnerID,
 		ID,
 		pendingChecksChan,
@@ -72,6 +76,13 @@ func NewWorker(
 		windowSize,
 		pollingInterval,
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	worker.concurrencyGroups = concurrencyGroups
+
+	return worker, nil
 }
 
 // newWorkerWithOptions returns an instance of a `Worker` with an override for the
@@ -110,6 +121,115 @@ func newWorkerWithOptions(
 	}, nil
 }
 
+// runCheck runs the check, interrupting it if it exceeds its timeout. It returns a
+// channel closed once the run actually returned, and whether the run timed out.
+// Long-running checks are never interrupted.
+func runCheck(c check.Check, longRunning bool) (<-chan struct{}, bool, error) {
+	done := make(chan struct{})
+
+	ic, ok := c.(check.InterruptibleCheck)
+	if !ok || longRunning || ic.Timeout() <= 0 {
+		err := c.Run()
+		close(done)
+		return done, false, err
+	}
+
+	var err error
+	go func() {
+		err = c.Run()
+		close(done)
+	}()
+
+	timer := time.NewTimer(ic.Timeout())
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return done, false, err
+	case <-timer.C:
+		// interrupting may block (e.g. on the python GIL), don't hold the worker
+		go ic.Interrupt()
+		return done, true, fmt.Errorf("check run timed out after %v", ic.Timeout())
+	}
+}
+
+// acquireConcurrencyGroup takes a slot of the concurrency group of the check, if it
+// has one. If the group is full the run is queued, without holding the worker, until
+// a slot is handed over to it. It returns the group and whether the check can run.
+// Long-running checks are not limited, as they would hold their slot forever.
+func (w *Worker) acquireConcurrencyGroup(c check.Check, longRunning bool) (string, bool) {
+	if w.concurrencyGroups == nil || longRunning {
+		return "", true
+	}
+
+	gc, ok := c.(check.GroupedCheck)
+	if !ok || gc.ConcurrencyGroup() == "" {
+		return "", true
+	}
+	group := gc.ConcurrencyGroup()
+
+	acquired, queued := w.concurrencyGroups.acquireOrQueue(group, c)
+	if acquired {
+		return group, true
+	}
+
+	if queued {
+		log.Debugf("Runner %d, worker %d: check %s queued, waiting for concurrency group %s", w.runnerID, w.ID, c.ID(), group)
+	} else {
+		log.Debugf("Runner %d, worker %d: check %s is already queued for concurrency group %s, skipping execution...", w.runnerID, w.ID, c.ID(), group)
+		w.addSkippedRun(c)
+	}
+	return group, false
+}
+
+// releaseConcurrencyGroup frees the slot taken by `acquireConcurrencyGroup`, if any.
+// It returns the queued run the slot is handed over to, if any.
+func (w *Worker) releaseConcurrencyGroup(group string) *queuedRun {
+	if group == "" {
+		return nil
+	}
+
+	next, expired := w.concurrencyGroups.release(group)
+	w.addExpiredRuns(expired)
+	return next
+}
+
+// addExpiredRuns reports the queued runs dropped after waiting longer than the interval of their check
+func (w *Worker) addExpiredRuns(expired []*queuedRun) {
+	for _, run := range expired {
+		log.Debugf("Runner %d, worker %d: check %s waited for concurrency group %s longer than its interval, skipping execution...", w.runnerID, w.ID, run.check.ID(), run.group)
+		w.addSkippedRun(run.check)
+	}
+}
+
+// addSkippedRun reports a run dropped while waiting for a concurrency group slot
+func (w *Worker) addSkippedRun(c check.Check) {
+	if w.shouldAddCheckStatsFunc(c.ID()) {
+		expvars.AddCheckSkippedRun(c)
+	}
+	expvars.AddSkippedRunsCount(1)
+}
+
+// deleteCheckWhenDone removes the check from the running list, and frees its concurrency
+// group slot, once its run has returned
+func (w *Worker) deleteCheckWhenDone(id check.ID, group string, done <-chan struct{}) {
+	<-done
+	if group != "" {
+		// The workers may be busy, the run the slot is handed over to is picked up by the first available one
+		w.addExpiredRuns(w.concurrencyGroups.releaseToReady(group))
+	}
+	w.checksTracker.DeleteCheck(id)
+	log.Debugf("Runner %d, worker %d: check %s returned after timing out", w.runnerID, w.ID, id)
+}
+
+// readyChan returns the channel notifying the worker of queued runs ready to be run
+func (w *Worker) readyChan() <-chan struct{} {
+	if w.concurrencyGroups == nil {
+		return nil
+	}
+	return w.concurrencyGroups.readyNotify
+}
+
 // Run waits for checks and run them as long as they arrive on the channel
 func (w *Worker) Run() {
 	log.Debugf("Runner %d, worker %d: Ready to process checks...", w.runnerID, w.ID)
@@ -123,79 +243,146 @@ func (w *Worker) Run() {
 		}
 	}()
 
-	for check := range w.pendingChecksChan {
-		checkLogger := CheckLogger{Check: check}
-		longRunning := check.Interval() == 0
+	for {
+		select {
+		case check, ok := <-w.pendingChecksChan:
+			if !ok {
+				log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
+				return
+			}
+			w.processCheck(check)
+
+		case <-w.readyChan():
+			if run := w.concurrencyGroups.popReady(); run != nil {
+				w.runQueued(run)
+			}
+		}
+	}
+}
+
+// processCheck runs a check received from the pending channel, unless it's already
+// running or has to wait for a slot of its concurrency group
+func (w *Worker) processCheck(check check.Check) {
+	checkLogger := CheckLogger{Check: check}
+	longRunning := check.Interval() == 0
+
+	// Add check to tracker if it's not already running
+	if !w.checksTracker.AddCheck(check) {
+		checkLogger.Debug("Check is already running, skipping execution...")
+		return
+	}
 
-		// Add check to tracker if it's not already running
-		if !w.checksTracker.AddCheck(check) {
-			checkLogger.Debug("Check is already running, skipping execution...")
+	// Queue the run if the check's concurrency group is full
+	group, acquired := w.acquireConcurrencyGroup(check, longRunning)
+	if !acquired {
+		w.checksTracker.DeleteCheck(check.ID())
+		return
+	}
+
+	if w.runCheckAndLog(check, group, longRunning, 0) {
+		w.runQueued(w.releaseConcurrencyGroup(group))
+	}
+}
+
+// runQueued runs the queued runs handed the concurrency group slot, each freeing it
+// for the next queued run of the group once done
+func (w *Worker) runQueued(run *queuedRun) {
+	for run != nil {
+		// The check may have been unscheduled while it was waiting
+		if !w.shouldAddCheckStatsFunc(run.check.ID()) || !w.checksTracker.AddCheck(run.check) {
+			log.Debugf("Runner %d, worker %d: check %s is no longer scheduled or already running, skipping execution...", w.runnerID, w.ID, run.check.ID())
+			run = w.releaseConcurrencyGroup(run.group)
 			continue
 		}
 
-		checkStartTime := time.Now()
+		queueTime := time.Since(run.queuedAt)
+		w.utilizationTracker.CheckQueued(queueTime)
+		if !w.runCheckAndLog(run.check, run.group, false, queueTime) {
+			return
+		}
+		run = w.releaseConcurrencyGroup(run.group)
+	}
+}
+
+// runCheckAndLog runs a check added to the tracker, and publishes the results of the run.
+// It returns false if the run timed out, in which case the check is removed from the tracker,
+// and its concurrency group slot freed, once the run actually returns. Otherwise the check is
+// removed from the tracker and the caller has to free the slot.
+func (w *Worker) runCheckAndLog(check check.Check, group string, longRunning bool, queueTime time.Duration) bool {
+	checkLogger := CheckLogger{Check: check}
+	checkStartTime := time.Now()
 
-		checkLogger.CheckStarted()
+	checkLogger.CheckStarted()
 
-		expvars.AddRunningCheckCount(1)
-		expvars.SetRunningStats(check.ID(), checkStartTime)
+	expvars.AddRunningCheckCount(1)
+	expvars.SetRunningStats(check.ID(), checkStartTime)
 
-		w.utilizationTracker.CheckStarted(longRunning)
+	w.utilizationTracker.CheckStarted(longRunning)
 
-		// Run the check
-		var checkErr error
-		checkErr = check.Run()
+	// Run the check
+	checkDone, timedOut, checkErr := runCheck(check, longRunning)
 
-		w.utilizationTracker.CheckFinished()
+	w.utilizationTracker.CheckFinished()
 
-		expvars.DeleteRunningStats(check.ID())
+	expvars.DeleteRunningStats(check.ID())
 
-		checkWarnings := check.GetWarnings()
+	checkWarnings := check.GetWarnings()
 
-		// Use the default sender for the service checks
-		sender, err := w.getDefaultSenderFunc()
-		if err != nil {
-			log.Errorf("Error getting default sender: %v. Not sending status check for %s", err, check)
-		}
-		serviceCheckTags := []string{fmt.Sprintf("check:%s", check.String())}
-		serviceCheckStatus := metrics.ServiceCheckOK
+	// Use the default sender for the service checks
+	sender, err := w.getDefaultSenderFunc()
+	if err != nil {
+		log.Errorf("Error getting default sender: %v. Not sending status check for %s", err, check)
+	}
+	serviceCheckTags := []string{fmt.Sprintf("check:%s", check.String())}
+	serviceCheckStatus := metrics.ServiceCheckOK
+	serviceCheckMessage := ""
 
-		hostname, _ := util.GetHostname(context.TODO())
+	hostname, _ := util.GetHostname(context.TODO())
 
-		if len(checkWarnings) != 0 {
-			expvars.AddWarningsCount(len(checkWarnings))
-			serviceCheckStatus = metrics.ServiceCheckWarning
-		}
+	if len(checkWarnings) != 0 {
+		expvars.AddWarningsCount(len(checkWarnings))
+		serviceCheckStatus = metrics.ServiceCheckWarning
+	}
 
-		if checkErr != nil {
-			checkLogger.Error(checkErr)
-			expvars.AddErrorsCount(1)
-			serviceCheckStatus = metrics.ServiceCheckCritical
-		}
+	if checkErr != nil {
+		checkLogger.Error(checkErr)
+		expvars.AddErrorsCount(1)
+		serviceCheckStatus = metrics.ServiceCheckCritical
+	}
 
-		if sender != nil && !longRunning {
-			sender.ServiceCheck(serviceCheckStatusKey, serviceCheckStatus, hostname, serviceCheckTags, "")
-			sender.Commit()
-		}
+	if timedOut {
+		expvars.AddTimeoutsCount(1)
+		serviceCheckMessage = checkErr.Error()
+	}
 
-		// Remove the check from the running list
-		w.checksTracker.DeleteCheck(check.ID())
+	if sender != nil && !longRunning {
+		sender.ServiceCheck(serviceCheckStatusKey, serviceCheckStatus, hostname, serviceCheckTags, serviceCheckMessage)
+		sender.Commit()
+	}
 
-		// Publish statistics about this run
-		expvars.AddRunningCheckCount(-1)
-		expvars.AddRunsCount(1)
+	// Remove the check from the running list. A check that timed out is kept
+	// until its run actually returns, so that it's not run concurrently.
+	if timedOut {
+		go w.deleteCheckWhenDone(check.ID(), group, checkDone)
+	} else {
+		w.checksTracker.DeleteCheck(check.ID())
+	}
 
-		if !longRunning || len(checkWarnings) != 0 || checkErr != nil {
-			// If the scheduler isn't assigned (it should), just add stats
-			// otherwise only do so if the check is in the scheduler
-			if w.shouldAddCheckStatsFunc(check.ID()) {
-				sStats, _ := check.GetSenderStats()
-				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
-			}
+	// Publish statistics about this run
+	expvars.AddRunningCheckCount(-1)
+	expvars.AddRunsCount(1)
+
+	if !longRunning || len(checkWarnings) != 0 || checkErr != nil {
+		// If the scheduler isn't assigned (it should), just add stats
+		// otherwise only do so if the check is in the scheduler
+		if w.shouldAddCheckStatsFunc(check.ID()) {
+			sStats, _ := check.GetSenderStats()
+			expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
+			expvars.AddCheckQueueTime(check.ID(), queueTime)
 		}
-
-		checkLogger.CheckFinished()
 	}
 
-	log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
+	checkLogger.CheckFinished()
+
+	return !timedOut
 }
@@ -116,16 +116,16 @@ func TestWorkerInit(t *testing.T) {
 	pendingChecksChan := make(chan check.Check, 1)
 	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
 
-	_, err := NewWorker(1, 2, nil, checksTracker, mockShouldAddStatsFunc)
+	_, err := NewWorker(1, 2, nil, checksTracker, mockShouldAddStatsFunc, nil)
 	require.NotNil(t, err)
 
-	_, err = NewWorker(1, 2, pendingChecksChan, nil, mockShouldAddStatsFunc)
+	_, err = NewWorker(1, 2, pendingChecksChan, nil, mockShouldAddStatsFunc, nil)
 	require.NotNil(t, err)
 
-	_, err = NewWorker(1, 2, pendingChecksChan, checksTracker, nil)
+	_, err = NewWorker(1, 2, pendingChecksChan, checksTracker, nil, nil)
 	require.NotNil(t, err)
 
-	worker, err := NewWorker(1, 2, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(1, 2, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
 	assert.Nil(t, err)
 	assert.NotNil(t, worker)
 }
@@ -144,7 +144,7 @@ func TestWorkerInitExpvarStats(t *testing.T) {
 		go func(idx int) {
 			defer wg.Done()
 
-			worker, err := NewWorker(1, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+			worker, err := NewWorker(1, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
 			assert.Nil(t, err)
 
 			worker.Run()
@@ -166,7 +166,7 @@ func TestWorkerName(t *testing.T) {
 
 	for _, id := range []int{1, 100, 500} {
 		expectedName := fmt.Sprintf("worker_%d", id)
-		worker, err := NewWorker(1, id, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+		worker, err := NewWorker(1, id, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
 		assert.Nil(t, err)
 		assert.NotNil(t, worker)
 
@@ -218,7 +218,7 @@ func TestWorker(t *testing.T) {
 	pendingChecksChan <- testCheck1
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
 	require.Nil(t, err)
 
 	wg.Add(1)
@@ -344,7 +344,7 @@ func TestWorkerErrorAndWarningHandling(t *testing.T) {
 	}
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
 	require.Nil(t, err)
 	AssertAsyncWorkerCount(t, 0)
 
@@ -389,7 +389,7 @@ func TestWorkerConcurrentCheckScheduling(t *testing.T) {
 	pendingChecksChan <- testCheck
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, nil)
 	require.Nil(t, err)
 
 	worker.Run()
@@ -443,7 +443,7 @@ func TestWorkerStatsAddition(t *testing.T) {
 	pendingChecksChan <- squelchedStatsCheck
 	close(pendingChecksChan)
 
-	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, shouldAddStatsFunc)
+	worker, err := NewWorker(100, 200, pendingChecksChan, checksTracker, shouldAddStatsFunc, nil)
 	require.Nil(t, err)
 
 	worker.Run()
@@ -617,3 +617,277 @@ func TestWorkerServiceCheckSendingLongRunningTasks(t *testing.T) {
 	mockSender.AssertNumberOfCalls(t, "Commit", 0)
 	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 0)
 }
+
+type testInterruptibleCheck struct {
+	testCheck
+	timeout     time.Duration
+	interrupted chan struct{}
+}
+
+func (c *testInterruptibleCheck) Timeout() time.Duration { return c.timeout }
+func (c *testInterruptibleCheck) Interrupt()             { close(c.interrupted) }
+
+func TestWorkerCheckTimeout(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("hostname", "myhost")
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
+
+	release := make(chan struct{})
+	stuckCheck := &testInterruptibleCheck{
+		testCheck: testCheck{
+			t:       t,
+			id:      "stuckcheck:123",
+			runFunc: func(check.ID) { <-release },
+		},
+		timeout:     50 * time.Millisecond,
+		interrupted: make(chan struct{}),
+	}
+	goodCheck := newCheck(t, "goodcheck:123", false, nil)
+
+	pendingChecksChan <- stuckCheck
+	pendingChecksChan <- goodCheck
+	close(pendingChecksChan)
+
+	mockSender := mocksender.NewMockSender("")
+	mockSender.On("Commit").Return().Times(2)
+	mockSender.On(
+		"ServiceCheck",
+		serviceCheckStatusKey,
+		metrics.ServiceCheckCritical,
+		"myhost",
+		[]string{"check:stuckcheck"},
+		"check run timed out after 50ms",
+	).Return().Times(1)
+	mockSender.On(
+		"ServiceCheck",
+		serviceCheckStatusKey,
+		metrics.ServiceCheckOK,
+		"myhost",
+		[]string{"check:goodcheck"},
+		"",
+	).Return().Times(1)
+
+	worker, err := newWorkerWithOptions(
+		100,
+		200,
+		pendingChecksChan,
+		checksTracker,
+		mockShouldAddStatsFunc,
+		func() (aggregator.Sender, error) {
+			return mockSender, nil
+		},
+		windowSize,
+		pollingInterval,
+	)
+	require.Nil(t, err)
+
+	// The worker is freed even though the stuck check never returns
+	worker.Run()
+
+	select {
+	case <-stuckCheck.interrupted:
+	case <-time.After(time.Second):
+		assert.Fail(t, "stuck check was not interrupted")
+	}
+
+	assert.Equal(t, 1, goodCheck.RunCount())
+	assert.Equal(t, 1, int(expvars.GetTimeoutsCount()))
+	assert.Equal(t, 1, int(expvars.GetErrorsCount()))
+	assertErrorCount(t, stuckCheck, 1)
+	mockSender.AssertExpectations(t)
+
+	// The stuck check is tracked as running until it actually returns
+	assert.False(t, checksTracker.AddCheck(stuckCheck))
+	close(release)
+	assert.Eventually(t, func() bool {
+		_, running := checksTracker.Check(stuckCheck.ID())
+		return !running
+	}, time.Second, 10*time.Millisecond)
+}
+
+type testGroupedCheck struct {
+	testCheck
+	group    string
+	interval time.Duration
+}
+
+func (c *testGroupedCheck) ConcurrencyGroup() string { return c.group }
+func (c *testGroupedCheck) Interval() time.Duration  { return c.interval }
+
+func TestWorkerConcurrencyGroups(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("hostname", "myhost")
+
+	var wg sync.WaitGroup
+	var running, maxRunning, maxQueued int64
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
+	concurrencyGroups := NewConcurrencyGroups(map[string]int{"db": 1})
+
+	runFunc := func(id check.ID) {
+		current := atomic.AddInt64(&running, 1)
+		if current > atomic.LoadInt64(&maxRunning) {
+			atomic.StoreInt64(&maxRunning, current)
+		}
+		if queued := expvars.GetQueuedCheckCount(); queued > atomic.LoadInt64(&maxQueued) {
+			atomic.StoreInt64(&maxQueued, queued)
+		}
+		time.Sleep(100 * time.Millisecond)
+		atomic.AddInt64(&running, -1)
+	}
+
+	var groupedChecks []*testGroupedCheck
+	for _, id := range []string{"db1:123", "db2:123", "db3:123"} {
+		c := &testGroupedCheck{
+			testCheck: testCheck{t: t, id: id, runFunc: runFunc},
+			group:     "db",
+			interval:  time.Minute,
+		}
+		groupedChecks = append(groupedChecks, c)
+		pendingChecksChan <- c
+	}
+	close(pendingChecksChan)
+
+	for idx := 0; idx < 3; idx++ {
+		worker, err := NewWorker(100, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, concurrencyGroups)
+		require.Nil(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.Run()
+		}()
+	}
+
+	wg.Wait()
+
+	// Checks of the group ran one at a time even though workers were available
+	assert.Equal(t, int64(1), atomic.LoadInt64(&maxRunning))
+	assert.True(t, atomic.LoadInt64(&maxQueued) > 0)
+	assert.Equal(t, int64(0), expvars.GetQueuedCheckCount())
+	assert.Equal(t, int64(3), expvars.GetRunsCount())
+	assert.Equal(t, int64(0), expvars.GetSkippedRunsCount())
+
+	var queuedRuns uint64
+	for _, c := range groupedChecks {
+		assert.Equal(t, 1, c.RunCount())
+		_, running := checksTracker.Check(c.ID())
+		assert.False(t, running)
+
+		stats, found := expvars.CheckStats(c.ID())
+		require.True(t, found)
+		queuedRuns += stats.TotalQueuedRuns
+	}
+	assert.Equal(t, uint64(2), queuedRuns)
+}
+
+func TestWorkerConcurrencyGroupsDontStarveWorkers(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("hostname", "myhost")
+
+	var wg sync.WaitGroup
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
+	concurrencyGroups := NewConcurrencyGroups(nil)
+
+	// The first check of the group holds its slot until released
+	release := make(chan struct{})
+	for idx, id := range []string{"db1:123", "db2:123", "db3:123", "db4:123"} {
+		runFunc := func(id check.ID) {}
+		if idx == 0 {
+			runFunc = func(id check.ID) { <-release }
+		}
+		pendingChecksChan <- &testGroupedCheck{
+			testCheck: testCheck{t: t, id: id, runFunc: runFunc},
+			group:     "db",
+			interval:  time.Minute,
+		}
+	}
+
+	ungroupedRan := make(chan struct{})
+	pendingChecksChan <- newCheck(t, "ungrouped:123", false, func(id check.ID) { close(ungroupedRan) })
+	close(pendingChecksChan)
+
+	// There are more checks of the group queued than workers
+	for idx := 0; idx < 2; idx++ {
+		worker, err := NewWorker(100, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, concurrencyGroups)
+		require.Nil(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.Run()
+		}()
+	}
+
+	// The ungrouped check runs while the group is still full
+	select {
+	case <-ungroupedRan:
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "the ungrouped check didn't run while the group was full")
+	}
+	assert.Equal(t, int64(3), expvars.GetQueuedCheckCount())
+
+	// The queued checks are run once the slot is freed, even though
+	// the workers which queued them have exited
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int64(0), expvars.GetQueuedCheckCount())
+	assert.Equal(t, int64(5), expvars.GetRunsCount())
+	assert.Equal(t, int64(0), expvars.GetSkippedRunsCount())
+}
+
+func TestWorkerConcurrencyGroupsSkippedRun(t *testing.T) {
+	expvars.Reset()
+	config.Datadog.Set("hostname", "myhost")
+
+	var wg sync.WaitGroup
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(id check.ID) bool { return true }
+	concurrencyGroups := NewConcurrencyGroups(nil)
+
+	release := make(chan struct{})
+	pendingChecksChan <- &testGroupedCheck{
+		testCheck: testCheck{t: t, id: "db1:123", runFunc: func(id check.ID) { <-release }},
+		group:     "db",
+		interval:  time.Minute,
+	}
+	// The second run of the check waiting for the group is skipped
+	waiting := &testGroupedCheck{testCheck: testCheck{t: t, id: "db2:123"}, group: "db", interval: time.Minute}
+	pendingChecksChan <- waiting
+	pendingChecksChan <- waiting
+
+	for idx := 0; idx < 2; idx++ {
+		worker, err := NewWorker(100, idx, pendingChecksChan, checksTracker, mockShouldAddStatsFunc, concurrencyGroups)
+		require.Nil(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.Run()
+		}()
+	}
+
+	// The stats of the check are created with its first skipped run
+	assert.Eventually(t, func() bool { return expvars.GetSkippedRunsCount() == 1 }, time.Second, 10*time.Millisecond)
+	stats, found := expvars.CheckStats(waiting.ID())
+	require.True(t, found)
+	assert.Equal(t, uint64(1), stats.TotalSkippedRuns)
+	assert.Equal(t, uint64(0), stats.TotalRuns)
+
+	close(release)
+	close(pendingChecksChan)
+	wg.Wait()
+
+	assert.Equal(t, 1, waiting.RunCount())
+}
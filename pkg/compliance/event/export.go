@@ -0,0 +1,227 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ExportInterval is the interval at which the compliance agent writes the reports of an ExportReporter
+const ExportInterval = time.Minute
+
+// sortEvents returns the events sorted by framework, rule and resource
+func sortEvents(events []*Event) []*Event {
+	sorted := make([]*Event, len(events))
+	copy(sorted, events)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.AgentFrameworkID != b.AgentFrameworkID {
+			return a.AgentFrameworkID < b.AgentFrameworkID
+		}
+		if a.AgentRuleID != b.AgentRuleID {
+			return a.AgentRuleID < b.AgentRuleID
+		}
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		return a.ResourceID < b.ResourceID
+	})
+
+	return sorted
+}
+
+// eventSummary returns a one line description of the result of a rule check
+func eventSummary(e *Event) string {
+	summary := fmt.Sprintf("Rule %s %s for %s %s", e.AgentRuleID, e.Result, e.ResourceType, e.ResourceID)
+
+	var data map[string]interface{}
+	switch d := e.Data.(type) {
+	case Data:
+		data = d
+	case map[string]interface{}:
+		data = d
+	}
+	if err, ok := data["error"]; ok {
+		summary += fmt.Sprintf(": %v", err)
+	}
+
+	return summary
+}
+
+// eventDetails returns the evaluation details of a rule check as indented JSON
+func eventDetails(e *Event) string {
+	if e.Data == nil {
+		return ""
+	}
+
+	details, err := json.MarshalIndent(e.Data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", e.Data)
+	}
+	return string(details)
+}
+
+type exportKey struct {
+	ruleID       string
+	frameworkID  string
+	resourceType string
+	resourceID   string
+}
+
+// ExportReporter keeps the latest result of each rule and resource, and periodically writes them as
+// SARIF and JUnit reports. The events are forwarded to the wrapped reporter, if any.
+type ExportReporter struct {
+	reporter     Reporter
+	agentVersion string
+	sarifPath    string
+	junitPath    string
+
+	sync.Mutex
+	events map[exportKey]*Event
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewExportReporter returns a reporter writing the SARIF and JUnit reports to the given paths. An empty
+// path disables the matching report.
+func NewExportReporter(reporter Reporter, agentVersion, sarifPath, junitPath string) *ExportReporter {
+	return &ExportReporter{
+		reporter:     reporter,
+		agentVersion: agentVersion,
+		sarifPath:    sarifPath,
+		junitPath:    junitPath,
+		events:       make(map[exportKey]*Event),
+	}
+}
+
+// Report keeps the event for the next reports and forwards it
+func (r *ExportReporter) Report(event *Event) {
+	key := exportKey{
+		ruleID:       event.AgentRuleID,
+		frameworkID:  event.AgentFrameworkID,
+		resourceType: event.ResourceType,
+		resourceID:   event.ResourceID,
+	}
+
+	r.Lock()
+	r.events[key] = event
+	r.Unlock()
+
+	if r.reporter != nil {
+		r.reporter.Report(event)
+	}
+}
+
+// ReportRaw forwards the raw content
+func (r *ExportReporter) ReportRaw(content []byte, service string, tags ...string) {
+	if r.reporter != nil {
+		r.reporter.ReportRaw(content, service, tags...)
+	}
+}
+
+// Events returns the latest events of each rule and resource, the expired ones are dropped
+func (r *ExportReporter) Events() []*Event {
+	r.Lock()
+	defer r.Unlock()
+
+	now := time.Now()
+
+	events := make([]*Event, 0, len(r.events))
+	for key, e := range r.events {
+		if !e.ExpireAt.IsZero() && e.ExpireAt.Before(now) {
+			delete(r.events, key)
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// Flush writes the reports
+func (r *ExportReporter) Flush() error {
+	events := r.Events()
+
+	if r.sarifPath != "" {
+		if err := writeReportFile(r.sarifPath, func(w io.Writer) error {
+			return WriteSARIF(w, r.agentVersion, events)
+		}); err != nil {
+			return fmt.Errorf("failed to write SARIF report: %w", err)
+		}
+	}
+
+	if r.junitPath != "" {
+		if err := writeReportFile(r.junitPath, func(w io.Writer) error {
+			return WriteJUnit(w, events)
+		}); err != nil {
+			return fmt.Errorf("failed to write JUnit report: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Start writes the reports at every interval until the reporter is stopped
+func (r *ExportReporter) Start(interval time.Duration) {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.Flush(); err != nil {
+					log.Errorf("Failed to export compliance reports: %v", err)
+				}
+			case <-r.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic writing of the reports and writes them a last time
+func (r *ExportReporter) Stop() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+	}
+
+	if err := r.Flush(); err != nil {
+		log.Errorf("Failed to export compliance reports: %v", err)
+	}
+}
+
+// writeReportFile writes a report to a temporary file which then replaces the report, so that the
+// readers never see a partial report
+func writeReportFile(path string, write func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,180 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents() []*Event {
+	return []*Event{
+		{
+			AgentRuleID:      "cis-docker-1.2.0-5.4",
+			AgentFrameworkID: "cis-docker",
+			Result:           Failed,
+			ResourceType:     "docker_container",
+			ResourceID:       "3c0ba1f8a8e9",
+			Data:             Data{"container.privileged": true},
+			Evaluator:        "rego",
+		},
+		{
+			AgentRuleID:      "cis-docker-1.2.0-1.2.1",
+			AgentFrameworkID: "cis-docker",
+			Result:           Passed,
+			ResourceType:     "docker_daemon",
+			ResourceID:       "host",
+			Evaluator:        "legacy",
+		},
+		{
+			AgentRuleID:      "cis-kubernetes-1.5.1-1.1.1",
+			AgentFrameworkID: "cis-kubernetes",
+			Result:           Error,
+			ResourceType:     "kubernetes_master_node",
+			ResourceID:       "host",
+			Data:             Data{"error": "no such file"},
+			Evaluator:        "rego",
+		},
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSARIF(&buf, "7.37.0", testEvents()))
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "7.37.0", run.Tool.Driver.Version)
+	require.Len(t, run.Tool.Driver.Rules, 3)
+	require.Len(t, run.Results, 3)
+
+	// the results are sorted by framework and rule
+	assert.Equal(t, "cis-docker-1.2.0-1.2.1", run.Results[0].RuleID)
+	assert.Equal(t, "pass", run.Results[0].Kind)
+	assert.Equal(t, "none", run.Results[0].Level)
+
+	failed := run.Results[1]
+	assert.Equal(t, "cis-docker-1.2.0-5.4", failed.RuleID)
+	assert.Equal(t, 1, failed.RuleIndex)
+	assert.Equal(t, "fail", failed.Kind)
+	assert.Equal(t, "error", failed.Level)
+	assert.Equal(t, "3c0ba1f8a8e9", failed.Locations[0].LogicalLocations[0].Name)
+	assert.Equal(t, "docker_container", failed.Locations[0].LogicalLocations[0].Kind)
+	assert.Equal(t, "cis-docker", failed.Properties.Framework)
+	assert.Equal(t, map[string]interface{}{"container.privileged": true}, failed.Properties.Data)
+
+	errored := run.Results[2]
+	assert.Equal(t, "review", errored.Kind)
+	assert.Equal(t, "Rule cis-kubernetes-1.5.1-1.1.1 error for kubernetes_master_node host: no such file", errored.Message.Text)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(&buf, testEvents()))
+
+	var report junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Errors)
+	require.Len(t, report.Suites, 2)
+
+	docker := report.Suites[0]
+	assert.Equal(t, "cis-docker", docker.Name)
+	assert.Equal(t, 2, docker.Tests)
+	require.Len(t, docker.TestCases, 2)
+	assert.Nil(t, docker.TestCases[0].Failure)
+
+	failed := docker.TestCases[1]
+	assert.Equal(t, "cis-docker-1.2.0-5.4: docker_container 3c0ba1f8a8e9", failed.Name)
+	assert.Equal(t, "cis-docker.cis-docker-1.2.0-5.4", failed.ClassName)
+	require.NotNil(t, failed.Failure)
+	assert.Contains(t, failed.Failure.Details, `"container.privileged": true`)
+	assert.Contains(t, failed.Properties, junitProperty{Name: "resource_type", Value: "docker_container"})
+
+	kubernetes := report.Suites[1]
+	assert.Equal(t, "cis-kubernetes", kubernetes.Name)
+	require.NotNil(t, kubernetes.TestCases[0].Error)
+	assert.Equal(t, 1, kubernetes.Errors)
+}
+
+type recordingReporter struct {
+	events []*Event
+}
+
+func (r *recordingReporter) Report(event *Event) {
+	r.events = append(r.events, event)
+}
+
+func (r *recordingReporter) ReportRaw(content []byte, service string, tags ...string) {
+}
+
+func TestExportReporter(t *testing.T) {
+	dir := t.TempDir()
+	sarifPath := filepath.Join(dir, "compliance.sarif")
+	junitPath := filepath.Join(dir, "compliance.xml")
+
+	forwarded := &recordingReporter{}
+	reporter := NewExportReporter(forwarded, "7.37.0", sarifPath, junitPath)
+
+	for _, e := range testEvents() {
+		reporter.Report(e)
+	}
+
+	// only the latest result of a rule for a resource is kept
+	reporter.Report(&Event{
+		AgentRuleID:      "cis-docker-1.2.0-5.4",
+		AgentFrameworkID: "cis-docker",
+		Result:           Passed,
+		ResourceType:     "docker_container",
+		ResourceID:       "3c0ba1f8a8e9",
+	})
+
+	// the expired results are dropped
+	reporter.Report(&Event{
+		AgentRuleID:      "cis-docker-1.2.0-5.4",
+		AgentFrameworkID: "cis-docker",
+		Result:           Failed,
+		ResourceType:     "docker_container",
+		ResourceID:       "deleted",
+		ExpireAt:         time.Now().Add(-time.Minute),
+	})
+
+	assert.Len(t, forwarded.events, 5)
+
+	events := reporter.Events()
+	assert.Len(t, events, 3)
+	for _, e := range events {
+		assert.NotEqual(t, Failed, e.Result)
+	}
+
+	require.NoError(t, reporter.Flush())
+
+	content, err := os.ReadFile(sarifPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"ruleId": "cis-docker-1.2.0-5.4"`)
+
+	content, err = os.ReadFile(junitPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `<testsuites name="datadog-compliance" tests="3" failures="0" errors="1">`)
+
+	_, err = os.Stat(sarifPath + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package event
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitResult    `xml:"failure,omitempty"`
+	Error      *junitResult    `xml:"error,omitempty"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+// WriteJUnit writes the events as a JUnit XML report, with one test suite per framework and one test
+// case per rule and resource
+func WriteJUnit(w io.Writer, events []*Event) error {
+	report := junitTestSuites{Name: toolName}
+
+	suiteIndexes := make(map[string]int)
+	for _, e := range sortEvents(events) {
+		suiteIndex, found := suiteIndexes[e.AgentFrameworkID]
+		if !found {
+			suiteIndex = len(report.Suites)
+			suiteIndexes[e.AgentFrameworkID] = suiteIndex
+			report.Suites = append(report.Suites, junitTestSuite{Name: e.AgentFrameworkID})
+		}
+		suite := &report.Suites[suiteIndex]
+
+		details := eventDetails(e)
+		testCase := junitTestCase{
+			Name:      e.AgentRuleID + ": " + e.ResourceType + " " + e.ResourceID,
+			ClassName: e.AgentFrameworkID + "." + e.AgentRuleID,
+			Properties: []junitProperty{
+				{Name: "rule_id", Value: e.AgentRuleID},
+				{Name: "framework", Value: e.AgentFrameworkID},
+				{Name: "resource_type", Value: e.ResourceType},
+				{Name: "resource_id", Value: e.ResourceID},
+				{Name: "evaluator", Value: e.Evaluator},
+			},
+		}
+
+		switch e.Result {
+		case Passed:
+			testCase.SystemOut = details
+		case Failed:
+			testCase.Failure = &junitResult{Message: eventSummary(e), Type: Failed, Details: details}
+			suite.Failures++
+			report.Failures++
+		default:
+			testCase.Error = &junitResult{Message: eventSummary(e), Type: Error, Details: details}
+			suite.Errors++
+			report.Errors++
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+		suite.Tests++
+		report.Tests++
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,161 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package event
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	toolName           = "datadog-compliance"
+	toolInformationURI = "https://docs.datadoghq.com/security_platform/cspm/"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID         string              `json:"id"`
+	Properties sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	Framework string `json:"framework,omitempty"`
+	Version   int    `json:"version,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string                `json:"ruleId"`
+	RuleIndex  int                   `json:"ruleIndex"`
+	Kind       string                `json:"kind"`
+	Level      string                `json:"level"`
+	Message    sarifMessage          `json:"message"`
+	Locations  []sarifLocation       `json:"locations"`
+	Properties sarifResultProperties `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+type sarifResultProperties struct {
+	Framework    string      `json:"framework,omitempty"`
+	ResourceType string      `json:"resource_type,omitempty"`
+	ResourceID   string      `json:"resource_id,omitempty"`
+	Result       string      `json:"result"`
+	Evaluator    string      `json:"evaluator,omitempty"`
+	Data         interface{} `json:"data,omitempty"`
+	Tags         []string    `json:"tags,omitempty"`
+}
+
+// sarifKindAndLevel maps the result of a rule check to the kind and level of a SARIF result. A check
+// which could not be evaluated is reported for review, SARIF requires the level of the results which
+// are not failures to be "none".
+func sarifKindAndLevel(result string) (string, string) {
+	switch result {
+	case Passed:
+		return "pass", "none"
+	case Failed:
+		return "fail", "error"
+	default:
+		return "review", "none"
+	}
+}
+
+// WriteSARIF writes the events as a SARIF 2.1.0 log, with one result per event
+func WriteSARIF(w io.Writer, agentVersion string, events []*Event) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				Version:        agentVersion,
+				InformationURI: toolInformationURI,
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndexes := make(map[string]int)
+	for _, e := range sortEvents(events) {
+		ruleIndex, found := ruleIndexes[e.AgentRuleID]
+		if !found {
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[e.AgentRuleID] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID: e.AgentRuleID,
+				Properties: sarifRuleProperties{
+					Framework: e.AgentFrameworkID,
+					Version:   e.AgentRuleVersion,
+				},
+			})
+		}
+
+		kind, level := sarifKindAndLevel(e.Result)
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    e.AgentRuleID,
+			RuleIndex: ruleIndex,
+			Kind:      kind,
+			Level:     level,
+			Message:   sarifMessage{Text: eventSummary(e)},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name: e.ResourceID,
+					Kind: e.ResourceType,
+				}},
+			}},
+			Properties: sarifResultProperties{
+				Framework:    e.AgentFrameworkID,
+				ResourceType: e.ResourceType,
+				ResourceID:   e.ResourceID,
+				Result:       e.Result,
+				Evaluator:    e.Evaluator,
+				Data:         e.Data,
+				Tags:         e.Tags,
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{run},
+	})
+}
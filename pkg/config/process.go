@@ -153,6 +153,13 @@ func setupProcesses(config Config) {
 
 	procBindEnvAndSetDefault(config, "process_config.drop_check_payloads", []string{})
 
+	// Listening sockets of the processes
+	procBindEnvAndSetDefault(config, "process_config.listening_sockets.enabled", false)
+
+	// Exec and exit events of the processes
+	procBindEnvAndSetDefault(config, "process_config.event_collection.enabled", false)
+	procBindEnvAndSetDefault(config, "process_config.event_collection.datadog_events.enabled", false)
+
 	processesAddOverrideOnce.Do(func() {
 		AddOverrideFunc(loadProcessTransforms)
 	})
@@ -207,6 +207,24 @@ func TestEnvVarOverride(t *testing.T) {
 			value:    "true",
 			expected: true,
 		},
+		{
+			key:      "process_config.listening_sockets.enabled",
+			env:      "DD_PROCESS_CONFIG_LISTENING_SOCKETS_ENABLED",
+			value:    "true",
+			expected: true,
+		},
+		{
+			key:      "process_config.event_collection.enabled",
+			env:      "DD_PROCESS_CONFIG_EVENT_COLLECTION_ENABLED",
+			value:    "true",
+			expected: true,
+		},
+		{
+			key:      "process_config.event_collection.datadog_events.enabled",
+			env:      "DD_PROCESS_CONFIG_EVENT_COLLECTION_DATADOG_EVENTS_ENABLED",
+			value:    "true",
+			expected: true,
+		},
 		{
 			key:      "process_config.enabled",
 			env:      "DD_PROCESS_CONFIG_ENABLED",
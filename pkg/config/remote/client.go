@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/api/security"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/config/remote/data"
 	"github.com/DataDog/datadog-agent/pkg/config/remote/meta"
 	"github.com/DataDog/datadog-agent/pkg/proto/pbgo"
@@ -35,6 +36,7 @@ type Client struct {
 	pollInterval time.Duration
 
 	grpc           pbgo.AgentSecureClient
+	local          *LocalSource
 	stateClient    *client.Client
 	currentConfigs client.Configs
 
@@ -43,8 +45,12 @@ type Client struct {
 	apmSamplingUpdates chan []client.ConfigAPMSamling
 }
 
-// NewClient creates a new client
+// NewClient creates a new client. The configurations are read from the local
+// directory set by remote_configuration.local_dir if any, from the Agent otherwise.
 func NewClient(agentName string, products []data.Product) (*Client, error) {
+	if dir := config.Datadog.GetString("remote_configuration.local_dir"); dir != "" {
+		return NewLocalClient(agentName, products, dir, config.Datadog.GetBool("remote_configuration.local_dir_allow_unsigned")), nil
+	}
 	client, err := newClient(agentName, products)
 	if err != nil {
 		return nil, err
@@ -53,6 +59,28 @@ func NewClient(agentName string, products []data.Product) (*Client, error) {
 	return client, nil
 }
 
+// NewLocalClient creates a new client reading the configurations from a local directory.
+// Unsigned configurations are only applied if allowUnsigned is set.
+func NewLocalClient(agentName string, products []data.Product, dir string, allowUnsigned bool) *Client {
+	client := newLocalClient(agentName, products, dir, allowUnsigned)
+	go client.pollLoop()
+	return client
+}
+
+func newLocalClient(agentName string, products []data.Product, dir string, allowUnsigned bool) *Client {
+	ctx, close := context.WithCancel(context.Background())
+	return &Client{
+		ctx:                ctx,
+		agentName:          agentName,
+		products:           data.ProductListToString(products),
+		local:              NewLocalSource(dir, allowUnsigned),
+		close:              close,
+		pollInterval:       1 * time.Second,
+		stateClient:        client.NewClient(meta.RootsDirector().Last(), data.ProductListToString(products)),
+		apmSamplingUpdates: make(chan []client.ConfigAPMSamling, 8),
+	}
+}
+
 func newClient(agentName string, products []data.Product, dialOpts ...grpc.DialOption) (*Client, error) {
 	token, err := security.FetchAuthToken()
 	if err != nil {
@@ -105,6 +133,9 @@ func (c *Client) pollLoop() {
 func (c *Client) poll() error {
 	c.m.Lock()
 	defer c.m.Unlock()
+	if c.local != nil {
+		return c.local.Update(c.stateClient)
+	}
 	state := c.stateClient.State()
 	lastPollErr := ""
 	if c.lastPollErr != nil {
@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package remote
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config/remote/data"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	localRootsDir    = "roots"
+	localTargetsFile = "targets.json"
+	localMetaSuffix  = ".meta.json"
+)
+
+// LocalSource reads the configurations from a local directory instead of
+// polling the Agent, for environments without access to the backend and for
+// testing. The directory holds the target files at their path, e.g.
+// datadog/2/APM_SAMPLING/<config_id>/<name>, and either:
+//   - a signed targets.json, and optionally the director roots as roots/<version>.json,
+//     in which case the files are verified like the ones fetched from the backend
+//   - no targets.json, in which case the files are trusted as is, only if
+//     unsigned configurations are explicitly allowed. The custom metadata of a
+//     file, e.g. {"v": 3}, is read from <file>.meta.json if it exists, the
+//     version defaults to the modification time of the file.
+type LocalSource struct {
+	dir           string
+	allowUnsigned bool
+	lastDigest    [32]byte
+}
+
+// NewLocalSource creates a new source reading the configurations from dir
+func NewLocalSource(dir string, allowUnsigned bool) *LocalSource {
+	return &LocalSource{
+		dir:           dir,
+		allowUnsigned: allowUnsigned,
+	}
+}
+
+// Update feeds the configurations found in the directory to a client, if they
+// changed since the last successful update
+func (s *LocalSource) Update(c *client.Client) error {
+	files, digest, err := s.readDir()
+	if err != nil {
+		return err
+	}
+	if digest == s.lastDigest {
+		return nil
+	}
+
+	if targets, found := files[localTargetsFile]; found {
+		err = c.Update(client.Update{
+			Roots:       newRoots(files, c.State().RootVersion),
+			Targets:     targets.Raw,
+			TargetFiles: targetFiles(files),
+		})
+	} else if s.allowUnsigned {
+		log.Warnf("No %s in %s, applying the remote configurations without verifying them", localTargetsFile, s.dir)
+		err = c.UpdateUnverified(unverifiedFiles(files))
+	} else {
+		err = fmt.Errorf("no %s in %s, set remote_configuration.local_dir_allow_unsigned to apply unsigned remote configurations", localTargetsFile, s.dir)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Remote configurations updated from %s", s.dir)
+	s.lastDigest = digest
+	return nil
+}
+
+type localFile struct {
+	Raw     []byte
+	ModTime int64
+}
+
+// readDir reads all the files of the directory, keyed by their slash separated
+// relative path, and returns a digest of their paths and contents
+func (s *LocalSource) readDir() (map[string]localFile, [32]byte, error) {
+	files := make(map[string]localFile)
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		raw, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relPath)] = localFile{Raw: raw, ModTime: info.ModTime().Unix()}
+		return nil
+	})
+	if err != nil {
+		return nil, [32]byte{}, fmt.Errorf("could not read remote configurations from %s: %v", s.dir, err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	digest := sha256.New()
+	for _, path := range paths {
+		fileHash := sha256.Sum256(files[path].Raw)
+		digest.Write([]byte(path))
+		digest.Write([]byte(strconv.FormatInt(files[path].ModTime, 10)))
+		digest.Write(fileHash[:])
+	}
+	var sum [32]byte
+	copy(sum[:], digest.Sum(nil))
+	return files, sum, nil
+}
+
+// newRoots returns the roots more recent than the current version, in order
+func newRoots(files map[string]localFile, currentVersion int64) [][]byte {
+	versions := make([]int64, 0)
+	for path := range files {
+		if !strings.HasPrefix(path, localRootsDir+"/") || !strings.HasSuffix(path, ".json") {
+			continue
+		}
+		version, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(path, localRootsDir+"/"), ".json"), 10, 64)
+		if err != nil {
+			log.Warnf("Ignoring root %s, expected roots/<version>.json", path)
+			continue
+		}
+		if version > currentVersion {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	roots := make([][]byte, 0, len(versions))
+	for _, version := range versions {
+		roots = append(roots, files[fmt.Sprintf("%s/%d.json", localRootsDir, version)].Raw)
+	}
+	return roots
+}
+
+// targetFiles returns the contents of the files whose path is a configuration path
+func targetFiles(files map[string]localFile) map[string][]byte {
+	targetFiles := make(map[string][]byte)
+	for path, file := range files {
+		if _, err := data.ParseConfigPath(path); err == nil {
+			targetFiles[path] = file.Raw
+		}
+	}
+	return targetFiles
+}
+
+type localCustom struct {
+	Version uint64 `json:"v"`
+}
+
+// unverifiedFiles returns the files whose path is a configuration path along
+// with their custom metadata
+func unverifiedFiles(files map[string]localFile) map[string]client.UnverifiedFile {
+	unverifiedFiles := make(map[string]client.UnverifiedFile)
+	for path, file := range files {
+		if _, err := data.ParseConfigPath(path); err != nil || strings.HasSuffix(path, localMetaSuffix) {
+			continue
+		}
+		custom := files[path+localMetaSuffix].Raw
+		if custom == nil {
+			custom, _ = json.Marshal(localCustom{Version: uint64(file.ModTime)})
+		}
+		unverifiedFiles[path] = client.UnverifiedFile{
+			Custom: custom,
+			Raw:    file.Raw,
+		}
+	}
+	return unverifiedFiles
+}
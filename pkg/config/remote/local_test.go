@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2022-present Datadog, Inc.
+
+package remote
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	rdata "github.com/DataDog/datadog-agent/pkg/config/remote/data"
+	"github.com/DataDog/datadog-agent/pkg/remoteconfig/client/products/apmsampling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/theupdateframework/go-tuf/data"
+)
+
+func writeLocalFile(t *testing.T, dir string, path string, content []byte) {
+	path = filepath.Join(dir, filepath.FromSlash(path))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, content, 0600))
+}
+
+func TestLocalClientUnverified(t *testing.T) {
+	dir := t.TempDir()
+	apmConfig := apmsampling.APMSampling{
+		TargetTPS: []apmsampling.TargetTPS{{Service: "service1", Env: "env1", Value: 4}},
+	}
+	rawApmConfig, err := apmConfig.MarshalMsg(nil)
+	require.NoError(t, err)
+	writeLocalFile(t, dir, "datadog/2/APM_SAMPLING/config-id-1/1", rawApmConfig)
+	writeLocalFile(t, dir, "datadog/2/APM_SAMPLING/config-id-1/1.meta.json", []byte(`{"v": 3}`))
+	writeLocalFile(t, dir, "datadog/2/TESTING1/config-id-2/2", []byte("ignored"))
+	writeLocalFile(t, dir, "README", []byte("ignored"))
+
+	c := newLocalClient("test-agent", []rdata.Product{rdata.ProductAPMSampling}, dir, true)
+	require.NoError(t, c.poll())
+	c.updateConfigs()
+	apmUpdates := c.APMSamplingUpdates()
+	require.Len(t, apmUpdates, 1)
+	apmUpdate := <-apmUpdates
+	require.Len(t, apmUpdate, 1)
+	assert.Equal(t, "config-id-1", apmUpdate[0].ID)
+	assert.Equal(t, uint64(3), apmUpdate[0].Version)
+	assert.Equal(t, apmConfig, apmUpdate[0].Config)
+
+	// nothing changed
+	require.NoError(t, c.poll())
+	c.updateConfigs()
+	assert.Len(t, apmUpdates, 0)
+
+	// the config is removed
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "datadog/2/APM_SAMPLING")))
+	require.NoError(t, c.poll())
+	c.updateConfigs()
+	require.Len(t, apmUpdates, 1)
+	assert.Len(t, <-apmUpdates, 0)
+}
+
+func TestLocalClientUnsignedNotAllowed(t *testing.T) {
+	dir := t.TempDir()
+	apmConfig := apmsampling.APMSampling{
+		TargetTPS: []apmsampling.TargetTPS{{Service: "service1", Env: "env1", Value: 4}},
+	}
+	rawApmConfig, err := apmConfig.MarshalMsg(nil)
+	require.NoError(t, err)
+	writeLocalFile(t, dir, "datadog/2/APM_SAMPLING/config-id-1/1", rawApmConfig)
+
+	c := newLocalClient("test-agent", []rdata.Product{rdata.ProductAPMSampling}, dir, false)
+	assert.Error(t, c.poll())
+	assert.Empty(t, c.stateClient.GetConfigs(time.Now().Unix()).APMSamplingConfigs)
+}
+
+func TestLocalClientSigned(t *testing.T) {
+	dir := t.TempDir()
+	targetsKey := generateKey()
+	embeddedRoot := generateRoot(generateKey(), 1, targetsKey)
+	config.Datadog.Set("remote_configuration.director_root", embeddedRoot)
+	defer config.Datadog.Set("remote_configuration.director_root", "")
+
+	apmConfig := apmsampling.APMSampling{
+		TargetTPS: []apmsampling.TargetTPS{{Service: "service1", Env: "env1", Value: 4}},
+	}
+	rawApmConfig, err := apmConfig.MarshalMsg(nil)
+	require.NoError(t, err)
+	writeLocalFile(t, dir, "datadog/2/APM_SAMPLING/config-id-1/1", rawApmConfig)
+	writeLocalFile(t, dir, "targets.json", generateTargets(targetsKey, 1, data.TargetFiles{
+		"datadog/2/APM_SAMPLING/config-id-1/1": generateTarget(rawApmConfig, 5),
+	}))
+
+	c := newLocalClient("test-agent", []rdata.Product{rdata.ProductAPMSampling}, dir, false)
+	require.NoError(t, c.poll())
+	c.updateConfigs()
+	apmUpdates := c.APMSamplingUpdates()
+	require.Len(t, apmUpdates, 1)
+	apmUpdate := <-apmUpdates
+	require.Len(t, apmUpdate, 1)
+	assert.Equal(t, uint64(5), apmUpdate[0].Version)
+	assert.Equal(t, apmConfig, apmUpdate[0].Config)
+
+	// targets signed with another key are rejected
+	writeLocalFile(t, dir, "targets.json", generateTargets(generateKey(), 2, data.TargetFiles{
+		"datadog/2/APM_SAMPLING/config-id-1/1": generateTarget(rawApmConfig, 6),
+	}))
+	assert.Error(t, c.poll())
+}
@@ -9,6 +9,8 @@
 package resolver
 
 import (
+	"sync"
+
 	"github.com/DataDog/datadog-agent/pkg/forwarder/endpoints"
 	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
 )
@@ -30,6 +32,8 @@ type DomainResolver interface {
 	Resolve(endpoint transaction.Endpoint) (string, DestinationType)
 	// GetAPIKeys returns the list of API Keys associated with this `DomainResolver`
 	GetAPIKeys() []string
+	// SetAPIKeys replaces the list of API Keys associated with this `DomainResolver`
+	SetAPIKeys(apiKeys []string)
 	// GetBaseDomain returns the base domain for this `DomainResolver`
 	GetBaseDomain() string
 	// GetAlternateDomains returns all the domains that can be returned by `Resolve()` minus the base domain
@@ -42,13 +46,14 @@ type DomainResolver interface {
 type SingleDomainResolver struct {
 	domain  string
 	apiKeys []string
+	mu      sync.RWMutex
 }
 
 // NewSingleDomainResolver creates a SingleDomainResolver with its destination domain & API keys
 func NewSingleDomainResolver(domain string, apiKeys []string) *SingleDomainResolver {
 	return &SingleDomainResolver{
-		domain,
-		apiKeys,
+		domain:  domain,
+		apiKeys: apiKeys,
 	}
 }
 
@@ -73,9 +78,18 @@ func (r *SingleDomainResolver) GetBaseDomain() string {
 
 // GetAPIKeys returns the slice of API keys associated with this SingleDomainResolver
 func (r *SingleDomainResolver) GetAPIKeys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.apiKeys
 }
 
+// SetAPIKeys replaces the slice of API keys associated with this SingleDomainResolver
+func (r *SingleDomainResolver) SetAPIKeys(apiKeys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiKeys = apiKeys
+}
+
 // SetBaseDomain sets the only destination available for a SingleDomainResolver
 func (r *SingleDomainResolver) SetBaseDomain(domain string) {
 	r.domain = domain
@@ -97,23 +111,33 @@ type MultiDomainResolver struct {
 	apiKeys             []string
 	overrides           map[string]destination
 	alternateDomainList []string
+	mu                  sync.RWMutex
 }
 
 // NewMultiDomainResolver initializes a MultiDomainResolver with its API keys and base destination
 func NewMultiDomainResolver(baseDomain string, apiKeys []string) *MultiDomainResolver {
 	return &MultiDomainResolver{
-		baseDomain,
-		apiKeys,
-		make(map[string]destination),
-		[]string{},
+		baseDomain:          baseDomain,
+		apiKeys:             apiKeys,
+		overrides:           make(map[string]destination),
+		alternateDomainList: []string{},
 	}
 }
 
 // GetAPIKeys returns the slice of API keys associated with this SingleDomainResolver
 func (r *MultiDomainResolver) GetAPIKeys() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.apiKeys
 }
 
+// SetAPIKeys replaces the slice of API keys associated with this MultiDomainResolver
+func (r *MultiDomainResolver) SetAPIKeys(apiKeys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.apiKeys = apiKeys
+}
+
 // Resolve returns the destiation for a given request endpoint
 func (r *MultiDomainResolver) Resolve(endpoint transaction.Endpoint) (string, DestinationType) {
 	if d, ok := r.overrides[endpoint.Name]; ok {
@@ -24,11 +24,13 @@ var Server = struct {
 	GetValue         http.HandlerFunc
 	SetValue         http.HandlerFunc
 	ListConfigurable http.HandlerFunc
+	Reload           http.HandlerFunc
 }{
 	GetFull:          getFullConfig,
 	GetValue:         getConfigValue,
 	SetValue:         setConfigValue,
 	ListConfigurable: listConfigurableSettings,
+	Reload:           reloadConfig,
 }
 
 func getFullConfig(namespaces ...string) http.HandlerFunc {
@@ -147,3 +149,24 @@ func setConfigValue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func reloadConfig(w http.ResponseWriter, _ *http.Request) {
+	log.Info("Got a request to reload the configuration")
+
+	response, err := settings.ReloadConfig()
+	if err != nil {
+		log.Errorf("Unable to reload the configuration: %s", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		log.Errorf("Unable to marshal configuration reload response: %s", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,189 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var reloadHandlers = make(map[string]ReloadHandler)
+
+// ReloadHandler represents a component able to apply configuration changes without a restart.
+type ReloadHandler interface {
+	// Name returns the name of the component
+	Name() string
+	// Keys returns the configuration keys, or sections, the component supports reloading
+	Keys() []string
+	// Reload applies the changed keys, their new values are already set in the configuration
+	Reload(changed []string) error
+}
+
+// ReloadResponse is used to communicate the outcome of a configuration reload
+type ReloadResponse struct {
+	Applied         []string          `json:"applied"`
+	RestartRequired []string          `json:"restart_required"`
+	Errors          map[string]string `json:"errors,omitempty"`
+}
+
+type reloadHandler struct {
+	name   string
+	keys   []string
+	reload func(changed []string) error
+}
+
+// NewReloadHandler returns a ReloadHandler calling reload with the changed keys among keys
+func NewReloadHandler(name string, keys []string, reload func(changed []string) error) ReloadHandler {
+	return &reloadHandler{
+		name:   name,
+		keys:   keys,
+		reload: reload,
+	}
+}
+
+func (h *reloadHandler) Name() string {
+	return h.name
+}
+
+func (h *reloadHandler) Keys() []string {
+	return h.keys
+}
+
+func (h *reloadHandler) Reload(changed []string) error {
+	return h.reload(changed)
+}
+
+// RegisterReloadHandler keeps track of the components supporting configuration reloads
+func RegisterReloadHandler(handler ReloadHandler) error {
+	if _, ok := reloadHandlers[handler.Name()]; ok {
+		return errors.New("duplicated reload handlers detected")
+	}
+	reloadHandlers[handler.Name()] = handler
+	return nil
+}
+
+// ReloadConfig reads the configuration file again and applies the changed keys
+// supported by the runtime settings and the reload handlers. The other changed
+// keys are left untouched and reported as requiring a restart.
+func ReloadConfig() (ReloadResponse, error) {
+	fresh, _, err := config.LoadFromFile(config.Datadog.ConfigFileUsed(), true)
+	if err != nil {
+		return ReloadResponse{}, err
+	}
+	return reloadConfig(config.Datadog, fresh), nil
+}
+
+func reloadConfig(current, fresh config.Config) ReloadResponse {
+	response := ReloadResponse{
+		Applied:         []string{},
+		RestartRequired: []string{},
+		Errors:          map[string]string{},
+	}
+
+	changedPerHandler := make(map[string][]string)
+	handlersPerKey := make(map[string][]string)
+	previousValues := make(map[string]interface{})
+	var handledKeys []string
+	for _, key := range diffConfigs(current, fresh) {
+		if setting, found := runtimeSettings[key]; found {
+			if err := setting.Set(fresh.Get(key)); err != nil {
+				response.Errors[key] = err.Error()
+				continue
+			}
+			response.Applied = append(response.Applied, key)
+			continue
+		}
+
+		handlers := handlersForKey(key)
+		if len(handlers) == 0 {
+			response.RestartRequired = append(response.RestartRequired, key)
+			continue
+		}
+
+		// the handlers read the new values from the configuration
+		previousValues[key] = current.Get(key)
+		current.Set(key, fresh.Get(key))
+		handledKeys = append(handledKeys, key)
+		handlersPerKey[key] = handlers
+		for _, name := range handlers {
+			changedPerHandler[name] = append(changedPerHandler[name], key)
+		}
+	}
+
+	handlerErrors := make(map[string]error)
+	for name, changed := range changedPerHandler {
+		log.Infof("Reloading %s with the changed configuration keys: %s", name, strings.Join(changed, ", "))
+		if err := reloadHandlers[name].Reload(changed); err != nil {
+			log.Errorf("Cannot reload %s: %v", name, err)
+			handlerErrors[name] = err
+		}
+	}
+
+	// a key is only applied if all its handlers succeeded, otherwise its previous
+	// value is restored for the configuration to match what the components use
+	for _, key := range handledKeys {
+		var errs []string
+		for _, name := range handlersPerKey[key] {
+			if err, failed := handlerErrors[name]; failed {
+				errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+		if len(errs) > 0 {
+			current.Set(key, previousValues[key])
+			response.Errors[key] = strings.Join(errs, "; ")
+			continue
+		}
+		response.Applied = append(response.Applied, key)
+	}
+	sort.Strings(response.Applied)
+
+	if len(response.RestartRequired) > 0 {
+		log.Warnf("The following configuration keys changed and require a restart to be applied: %s", strings.Join(response.RestartRequired, ", "))
+	}
+
+	return response
+}
+
+// handlersForKey returns the names of the reload handlers supporting a key
+func handlersForKey(key string) []string {
+	var names []string
+	for name, handler := range reloadHandlers {
+		for _, k := range handler.Keys() {
+			if key == k || strings.HasPrefix(key, k+".") {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffConfigs returns the sorted keys whose value differs between two configurations
+func diffConfigs(current, fresh config.Config) []string {
+	keys := make(map[string]struct{})
+	for _, key := range current.AllKeys() {
+		keys[key] = struct{}{}
+	}
+	for _, key := range fresh.AllKeys() {
+		keys[key] = struct{}{}
+	}
+
+	var changed []string
+	for key := range keys {
+		if !reflect.DeepEqual(current.Get(key), fresh.Get(key)) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupConfFromYAML(t *testing.T, yamlConfig string) config.Config {
+	conf := setupConf()
+	conf.SetConfigType("yaml")
+	require.NoError(t, conf.ReadConfig(bytes.NewBufferString(yamlConfig)))
+	return conf
+}
+
+func cleanReloadHandlers() {
+	reloadHandlers = make(map[string]ReloadHandler)
+}
+
+func TestRegisterReloadHandler(t *testing.T) {
+	cleanReloadHandlers()
+	defer cleanReloadHandlers()
+
+	handler := NewReloadHandler("test", []string{"tags"}, func([]string) error { return nil })
+	assert.NoError(t, RegisterReloadHandler(handler))
+	assert.Error(t, RegisterReloadHandler(handler))
+}
+
+func TestDiffConfigs(t *testing.T) {
+	current := setupConfFromYAML(t, `
+tags: ["a:b"]
+logs_config:
+  processing_rules:
+    - type: exclude_at_match
+      name: exclude_healthchecks
+      pattern: healthcheck
+`)
+	fresh := setupConfFromYAML(t, `
+tags: ["a:b", "c:d"]
+dogstatsd_port: 8126
+logs_config:
+  processing_rules:
+    - type: exclude_at_match
+      name: exclude_healthchecks
+      pattern: healthcheck
+`)
+
+	assert.Empty(t, diffConfigs(current, current))
+	assert.Equal(t, []string{"dogstatsd_port", "tags"}, diffConfigs(current, fresh))
+}
+
+func TestReloadConfig(t *testing.T) {
+	cleanRuntimeSetting()
+	cleanReloadHandlers()
+	defer cleanReloadHandlers()
+
+	current := setupConfFromYAML(t, `
+tags: ["a:b"]
+dogstatsd_tags: ["e:f"]
+`)
+	fresh := setupConfFromYAML(t, `
+tags: ["a:b", "c:d"]
+dogstatsd_tags: ["e:f", "g:h"]
+statsd_metric_namespace: "ns"
+dogstatsd_port: 8126
+`)
+
+	var tagsChanged, dogstatsdChanged []string
+	RegisterReloadHandler(NewReloadHandler("tags", []string{"tags"}, func(changed []string) error { //nolint:errcheck
+		tagsChanged = changed
+		return nil
+	}))
+	RegisterReloadHandler(NewReloadHandler("dogstatsd", []string{"dogstatsd_tags", "statsd_metric_namespace"}, func(changed []string) error { //nolint:errcheck
+		dogstatsdChanged = changed
+		return errors.New("boom")
+	}))
+
+	response := reloadConfig(current, fresh)
+	assert.Equal(t, []string{"tags"}, response.Applied)
+	assert.Equal(t, []string{"dogstatsd_port"}, response.RestartRequired)
+	assert.Equal(t, map[string]string{
+		"dogstatsd_tags":          "dogstatsd: boom",
+		"statsd_metric_namespace": "dogstatsd: boom",
+	}, response.Errors)
+	assert.Equal(t, []string{"tags"}, tagsChanged)
+	assert.Equal(t, []string{"dogstatsd_tags", "statsd_metric_namespace"}, dogstatsdChanged)
+
+	// applied keys are updated, the keys of the failed handler are restored
+	// and the others are left untouched
+	assert.Equal(t, []string{"a:b", "c:d"}, current.GetStringSlice("tags"))
+	assert.Equal(t, []string{"e:f"}, current.GetStringSlice("dogstatsd_tags"))
+	assert.Equal(t, "", current.GetString("statsd_metric_namespace"))
+	assert.Equal(t, 8125, current.GetInt("dogstatsd_port"))
+}
+
+func TestReloadConfigSharedKey(t *testing.T) {
+	cleanRuntimeSetting()
+	cleanReloadHandlers()
+	defer cleanReloadHandlers()
+
+	current := setupConfFromYAML(t, `
+api_key: "old"
+`)
+	fresh := setupConfFromYAML(t, `
+api_key: "new"
+`)
+
+	var seenByForwarder string
+	RegisterReloadHandler(NewReloadHandler("forwarder", []string{"api_key"}, func([]string) error { //nolint:errcheck
+		seenByForwarder = current.GetString("api_key")
+		return errors.New("invalid api key")
+	}))
+	RegisterReloadHandler(NewReloadHandler("logs", []string{"api_key"}, func([]string) error { //nolint:errcheck
+		return nil
+	}))
+
+	// the key is not applied as one of its handlers failed
+	response := reloadConfig(current, fresh)
+	assert.Empty(t, response.Applied)
+	assert.Equal(t, map[string]string{"api_key": "forwarder: invalid api key"}, response.Errors)
+	assert.Equal(t, "new", seenByForwarder)
+	assert.Equal(t, "old", current.GetString("api_key"))
+}
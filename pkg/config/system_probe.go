@@ -145,9 +145,17 @@ func InitSystemProbeConfig(cfg Config) {
 		}
 		return out
 	})
+	cfg.BindEnvAndSetDefault(join(netNS, "http_normalize_path_segments"), false, "DD_SYSTEM_PROBE_NETWORK_HTTP_NORMALIZE_PATH_SEGMENTS")
+	cfg.BindEnvAndSetDefault(join(netNS, "enable_kafka_monitoring"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_KAFKA_MONITORING")
+	cfg.BindEnvAndSetDefault(join(netNS, "enable_postgres_monitoring"), false, "DD_SYSTEM_PROBE_NETWORK_ENABLE_POSTGRES_MONITORING")
+	cfg.BindEnvAndSetDefault(join(netNS, "kafka_ports"), []string{"9092"}, "DD_SYSTEM_PROBE_NETWORK_KAFKA_PORTS")
+	cfg.BindEnvAndSetDefault(join(netNS, "postgres_ports"), []string{"5432"}, "DD_SYSTEM_PROBE_NETWORK_POSTGRES_PORTS")
 
 	// list of DNS query types to be recorded
 	cfg.BindEnvAndSetDefault(join(netNS, "dns_recorded_query_types"), []string{})
+	// glob patterns of the domains whose DNS queries are recorded in the DNS stats, or excluded from them
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_domain_includes"), []string{}, "DD_SYSTEM_PROBE_NETWORK_DNS_DOMAIN_INCLUDES")
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_domain_excludes"), []string{}, "DD_SYSTEM_PROBE_NETWORK_DNS_DOMAIN_EXCLUDES")
 	// (temporary) enable submitting DNS stats by query type.
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_by_querytype"), false)
 
@@ -0,0 +1,266 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationSeverity is the severity of a configuration validation issue
+type ValidationSeverity string
+
+const (
+	// ValidationError is reported for settings the Agent cannot use as is
+	ValidationError ValidationSeverity = "error"
+	// ValidationWarning is reported for settings ignored by the Agent or about to be
+	ValidationWarning ValidationSeverity = "warning"
+)
+
+// ValidationIssue is a problem found in a configuration file
+type ValidationIssue struct {
+	Key      string
+	Severity ValidationSeverity
+	Message  string
+}
+
+// deprecatedKeys maps the deprecated configuration keys to their replacement
+var deprecatedKeys = map[string]string{
+	"forwarder_retry_queue_max_size": "forwarder_retry_queue_payloads_max_size",
+	"log_enabled":                    "logs_enabled",
+	"process_config.enabled":         "process_config.process_collection.enabled and process_config.container_collection.enabled",
+	"tracemalloc_blacklist":          "tracemalloc_exclude",
+	"tracemalloc_whitelist":          "tracemalloc_include",
+}
+
+// ValidateFile checks a datadog.yaml file against the known configuration keys.
+// Unknown and deprecated keys are reported as warnings, values whose type does
+// not match the one of the default value are reported as errors.
+func ValidateFile(path string) ([]ValidationIssue, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return validateYAML(data)
+}
+
+func validateYAML(data []byte) ([]ValidationIssue, error) {
+	// the raw configuration holds the keys set in the file only
+	raw := NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	raw.SetConfigType("yaml")
+	if err := raw.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	reference := NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	InitConfig(reference)
+
+	return validateConfig(raw, reference), nil
+}
+
+// validateConfig checks the keys set in raw against the keys declared in reference
+func validateConfig(raw, reference Config) []ValidationIssue {
+	knownKeys := reference.GetKnownKeys()
+
+	var issues []ValidationIssue
+	for _, key := range raw.AllKeys() {
+		if replacement, found := deprecatedKeys[key]; found {
+			issues = append(issues, ValidationIssue{
+				Key:      key,
+				Severity: ValidationWarning,
+				Message:  fmt.Sprintf("%s is deprecated, use %s instead", key, replacement),
+			})
+		}
+
+		if _, found := knownKeys[key]; !found {
+			if !isFreeFormKey(key, knownKeys, reference) {
+				message := fmt.Sprintf("unknown key %s", key)
+				if suggestion := closestKey(key, knownKeys); suggestion != "" {
+					message += fmt.Sprintf(", did you mean %s?", suggestion)
+				}
+				issues = append(issues, ValidationIssue{
+					Key:      key,
+					Severity: ValidationWarning,
+					Message:  message,
+				})
+			}
+			continue
+		}
+
+		value := raw.Get(key)
+		expected := reference.Get(key)
+		if s, ok := value.(string); ok && strings.HasPrefix(s, "ENC[") {
+			// secrets are only resolved when the Agent starts
+			continue
+		}
+		if value == nil || expected == nil || matchesType(expected, value) {
+			continue
+		}
+		issues = append(issues, ValidationIssue{
+			Key:      key,
+			Severity: ValidationError,
+			Message:  fmt.Sprintf("invalid value for %s: expected a %s, got %v", key, typeName(expected), value),
+		})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Key < issues[j].Key
+	})
+	return issues
+}
+
+// isFreeFormKey returns whether an unknown key is allowed anyway, either
+// because it matches a '.*' wildcard or because it is the sub-key of a known
+// key holding a map of user-defined entries, e.g. docker_labels_as_tags.
+// Known keys with known sub-keys, like apm_config, are sections and not maps.
+func isFreeFormKey(key string, knownKeys map[string]interface{}, reference Config) bool {
+	splitPath := strings.Split(key, ".")
+	for j := 1; j < len(splitPath); j++ {
+		prefix := strings.Join(splitPath[:j], ".")
+		if _, found := knownKeys[prefix+".*"]; found {
+			return true
+		}
+		if _, found := knownKeys[prefix]; !found || isSection(prefix, knownKeys) {
+			continue
+		}
+		expected := reference.Get(prefix)
+		if expected == nil || reflect.ValueOf(expected).Kind() == reflect.Map {
+			return true
+		}
+	}
+	return false
+}
+
+// isSection returns whether some known keys are nested under prefix
+func isSection(prefix string, knownKeys map[string]interface{}) bool {
+	for known := range knownKeys {
+		if strings.HasPrefix(known, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// closestKey returns the known key closest to key, or an empty string if none
+// is close enough to be a likely typo.
+func closestKey(key string, knownKeys map[string]interface{}) string {
+	closest := ""
+	// allow roughly one mistake every four characters
+	minDistance := len(key)/4 + 1
+	for known := range knownKeys {
+		if strings.HasSuffix(known, ".*") {
+			continue
+		}
+		distance := levenshtein(key, known)
+		if distance < minDistance || (distance == minDistance && closest != "" && known < closest) {
+			closest = known
+			minDistance = distance
+		}
+	}
+	return closest
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// matchesType returns whether a value read from a configuration file can be
+// used for a setting whose default value is expected. Scalars given as strings
+// are accepted as long as they can be parsed, like environment variables are.
+func matchesType(expected, value interface{}) bool {
+	valueKind := reflect.ValueOf(value).Kind()
+	s, isString := value.(string)
+
+	if _, ok := expected.(time.Duration); ok {
+		if isString {
+			if _, err := time.ParseDuration(s); err == nil {
+				return true
+			}
+		}
+		return isNumber(valueKind) || (isString && isNumeric(s))
+	}
+
+	switch kind := reflect.ValueOf(expected).Kind(); {
+	case kind == reflect.Bool:
+		if isString {
+			_, err := strconv.ParseBool(s)
+			return err == nil
+		}
+		return valueKind == reflect.Bool
+	case isNumber(kind):
+		return isNumber(valueKind) || (isString && isNumeric(s))
+	case kind == reflect.String:
+		return valueKind != reflect.Slice && valueKind != reflect.Map
+	case kind == reflect.Slice:
+		return valueKind == reflect.Slice || isString
+	case kind == reflect.Map:
+		return valueKind == reflect.Map
+	default:
+		return true
+	}
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func typeName(expected interface{}) string {
+	if _, ok := expected.(time.Duration); ok {
+		return "duration"
+	}
+	switch kind := reflect.ValueOf(expected).Kind(); {
+	case kind == reflect.Bool:
+		return "boolean"
+	case isNumber(kind):
+		return "number"
+	case kind == reflect.Slice:
+		return "list"
+	case kind == reflect.Map:
+		return "map"
+	default:
+		return "string"
+	}
+}
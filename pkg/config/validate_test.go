@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateYAML(t *testing.T) {
+	issues, err := validateYAML([]byte(`
+api_key: ENC[api_key]
+dogstatsd_prot: 8125
+dogstatsd_port: "not a port"
+log_enabled: true
+logs_enabled: "true"
+tags: "env:prod"
+use_dogstatsd: yes please
+docker_labels_as_tags:
+  app: app_name
+apm_config:
+  enabled: true
+  analyzed_rate_by_service:
+    web: 1
+  no_such_option: 1
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []ValidationIssue{
+		{
+			Key:      "apm_config.no_such_option",
+			Severity: ValidationWarning,
+			Message:  "unknown key apm_config.no_such_option",
+		},
+		{
+			Key:      "dogstatsd_port",
+			Severity: ValidationError,
+			Message:  "invalid value for dogstatsd_port: expected a number, got not a port",
+		},
+		{
+			Key:      "dogstatsd_prot",
+			Severity: ValidationWarning,
+			Message:  "unknown key dogstatsd_prot, did you mean dogstatsd_port?",
+		},
+		{
+			Key:      "log_enabled",
+			Severity: ValidationWarning,
+			Message:  "log_enabled is deprecated, use logs_enabled instead",
+		},
+		{
+			Key:      "use_dogstatsd",
+			Severity: ValidationError,
+			Message:  "invalid value for use_dogstatsd: expected a boolean, got yes please",
+		},
+	}, issues)
+}
+
+func TestValidateYAMLInvalid(t *testing.T) {
+	_, err := validateYAML([]byte("api_key: [\n"))
+	assert.Error(t, err)
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("site", "site"))
+	assert.Equal(t, 1, levenshtein("log_level", "logs_level"))
+	assert.Equal(t, 2, levenshtein("dogstatsd_prot", "dogstatsd_port"))
+	assert.Equal(t, 4, levenshtein("", "site"))
+}
@@ -136,13 +136,10 @@ type Server struct {
 	Started                   bool
 	stopChan                  chan bool
 	health                    *health.Handle
-	metricPrefix              string
-	metricPrefixBlacklist     []string
-	metricBlocklist           []string
+	settings                  atomic.Value // *serverSettings
 	defaultHostname           string
 	histToDist                bool
 	histToDistPrefix          string
-	extraTags                 []string
 	Debug                     *dsdServerDebug
 	debugTagsAccumulator      *tagset.HashingTagsAccumulator
 	TCapture                  *replay.TrafficCapture
@@ -167,6 +164,16 @@ type Server struct {
 	UdsListenerRunning bool
 }
 
+// serverSettings holds the settings of the server which can be reloaded at
+// runtime. A new value is stored on each reload, it must not be modified once
+// stored as the workers may be reading it.
+type serverSettings struct {
+	metricPrefix          string
+	metricPrefixBlacklist []string
+	metricBlocklist       []string
+	extraTags             []string
+}
+
 // metricStat holds how many times a metric has been
 // processed and when was the last time.
 type metricStat struct {
@@ -266,15 +273,6 @@ func NewServer(demultiplexer aggregator.Demultiplexer, serverless bool) (*Server
 		return nil, fmt.Errorf("listening on neither udp nor socket, please check your configuration")
 	}
 
-	// check configuration for custom namespace
-	metricPrefix := config.Datadog.GetString("statsd_metric_namespace")
-	if metricPrefix != "" && !strings.HasSuffix(metricPrefix, ".") {
-		metricPrefix = metricPrefix + "."
-	}
-
-	metricPrefixBlacklist := config.Datadog.GetStringSlice("statsd_metric_namespace_blacklist")
-	metricBlocklist := config.Datadog.GetStringSlice("statsd_metric_blocklist")
-
 	defaultHostname, err := util.GetHostname(context.TODO())
 	if err != nil {
 		log.Errorf("Dogstatsd: unable to determine default hostname: %s", err.Error())
@@ -283,15 +281,6 @@ func NewServer(demultiplexer aggregator.Demultiplexer, serverless bool) (*Server
 	histToDist := config.Datadog.GetBool("histogram_copy_to_distribution")
 	histToDistPrefix := config.Datadog.GetString("histogram_copy_to_distribution_prefix")
 
-	extraTags := config.Datadog.GetStringSlice("dogstatsd_tags")
-
-	// if the server is running in a context where static tags are required, add those
-	// to extraTags.
-	if staticTags := util.GetStaticTagsSlice(context.TODO()); staticTags != nil {
-		extraTags = append(extraTags, staticTags...)
-	}
-	util.SortUniqInPlace(extraTags)
-
 	entityIDPrecedenceEnabled := config.Datadog.GetBool("dogstatsd_entity_id_precedence")
 
 	eolTerminationUDP := false
@@ -323,13 +312,9 @@ func NewServer(demultiplexer aggregator.Demultiplexer, serverless bool) (*Server
 		stopChan:                  make(chan bool),
 		serverlessFlushChan:       make(chan bool),
 		health:                    health.RegisterLiveness("dogstatsd-main"),
-		metricPrefix:              metricPrefix,
-		metricPrefixBlacklist:     metricPrefixBlacklist,
-		metricBlocklist:           metricBlocklist,
 		defaultHostname:           defaultHostname,
 		histToDist:                histToDist,
 		histToDistPrefix:          histToDistPrefix,
-		extraTags:                 extraTags,
 		eolTerminationUDP:         eolTerminationUDP,
 		eolTerminationUDS:         eolTerminationUDS,
 		eolTerminationNamedPipe:   eolTerminationNamedPipe,
@@ -349,6 +334,7 @@ func NewServer(demultiplexer aggregator.Demultiplexer, serverless bool) (*Server
 		cachedTlmOriginIds: make(map[string]cachedTagsOriginMap),
 		ServerlessMode:     serverless,
 	}
+	s.settings.Store(loadServerSettings())
 
 	// packets forwarding
 	// ----------------------
@@ -521,6 +507,7 @@ func (s *Server) eolEnabled(sourceType packets.SourceType) bool {
 
 // workers are running this function in their goroutine
 func (s *Server) parsePackets(batcher *batcher, parser *parser, packets []*packets.Packet, samples []metrics.MetricSample) []metrics.MetricSample {
+	settings := s.getSettings()
 	for _, packet := range packets {
 		log.Tracef("Dogstatsd receive: %q", packet.Contents)
 		for {
@@ -538,14 +525,14 @@ func (s *Server) parsePackets(batcher *batcher, parser *parser, packets []*packe
 
 			switch messageType {
 			case serviceCheckType:
-				serviceCheck, err := s.parseServiceCheckMessage(parser, message, packet.Origin)
+				serviceCheck, err := s.parseServiceCheckMessage(settings, parser, message, packet.Origin)
 				if err != nil {
 					s.errLog("Dogstatsd: error parsing service check '%q': %s", message, err)
 					continue
 				}
 				batcher.appendServiceCheck(serviceCheck)
 			case eventType:
-				event, err := s.parseEventMessage(parser, message, packet.Origin)
+				event, err := s.parseEventMessage(settings, parser, message, packet.Origin)
 				if err != nil {
 					s.errLog("Dogstatsd: error parsing event '%q': %s", message, err)
 					continue
@@ -557,7 +544,7 @@ func (s *Server) parsePackets(batcher *batcher, parser *parser, packets []*packe
 
 				debugEnabled := atomic.LoadUint64(&s.Debug.Enabled) == 1
 
-				samples, err = s.parseMetricMessage(samples, parser, message, packet.Origin, debugEnabled)
+				samples, err = s.parseMetricMessage(settings, samples, parser, message, packet.Origin, debugEnabled)
 				if err != nil {
 					s.errLog("Dogstatsd: error parsing metric message '%q': %s", message, err)
 					continue
@@ -622,7 +609,7 @@ func (s *Server) createOriginTagMaps(origin string) cachedTagsOriginMap {
 	return maps
 }
 
-func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser *parser, message []byte, origin string, telemetry bool) ([]metrics.MetricSample, error) {
+func (s *Server) parseMetricMessage(settings *serverSettings, metricSamples []metrics.MetricSample, parser *parser, message []byte, origin string, telemetry bool) ([]metrics.MetricSample, error) {
 	okCnt := tlmProcessedOk
 	errorCnt := tlmProcessedError
 	if origin != "" && telemetry {
@@ -650,7 +637,7 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 			sample.tags = append(sample.tags, mapResult.Tags...)
 		}
 	}
-	metricSamples = enrichMetricSample(metricSamples, sample, s.metricPrefix, s.metricPrefixBlacklist, s.metricBlocklist, s.defaultHostname, origin, s.entityIDPrecedenceEnabled, s.ServerlessMode)
+	metricSamples = enrichMetricSample(metricSamples, sample, settings.metricPrefix, settings.metricPrefixBlacklist, settings.metricBlocklist, s.defaultHostname, origin, s.entityIDPrecedenceEnabled, s.ServerlessMode)
 
 	if len(sample.values) > 0 {
 		s.sharedFloat64List.put(sample.values)
@@ -660,7 +647,7 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		// All metricSamples already share the same Tags slice. We can
 		// extends the first one and reuse it for the rest.
 		if idx == 0 {
-			metricSamples[idx].Tags = append(metricSamples[idx].Tags, s.extraTags...)
+			metricSamples[idx].Tags = append(metricSamples[idx].Tags, settings.extraTags...)
 		} else {
 			metricSamples[idx].Tags = metricSamples[0].Tags
 		}
@@ -670,7 +657,7 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 	return metricSamples, nil
 }
 
-func (s *Server) parseEventMessage(parser *parser, message []byte, origin string) (*metrics.Event, error) {
+func (s *Server) parseEventMessage(settings *serverSettings, parser *parser, message []byte, origin string) (*metrics.Event, error) {
 	sample, err := parser.parseEvent(message)
 	if err != nil {
 		dogstatsdEventParseErrors.Add(1)
@@ -678,13 +665,13 @@ func (s *Server) parseEventMessage(parser *parser, message []byte, origin string
 		return nil, err
 	}
 	event := enrichEvent(sample, s.defaultHostname, origin, s.entityIDPrecedenceEnabled)
-	event.Tags = append(event.Tags, s.extraTags...)
+	event.Tags = append(event.Tags, settings.extraTags...)
 	tlmProcessed.Inc("events", "ok", "")
 	dogstatsdEventPackets.Add(1)
 	return event, nil
 }
 
-func (s *Server) parseServiceCheckMessage(parser *parser, message []byte, origin string) (*metrics.ServiceCheck, error) {
+func (s *Server) parseServiceCheckMessage(settings *serverSettings, parser *parser, message []byte, origin string) (*metrics.ServiceCheck, error) {
 	sample, err := parser.parseServiceCheck(message)
 	if err != nil {
 		dogstatsdServiceCheckParseErrors.Add(1)
@@ -692,7 +679,7 @@ func (s *Server) parseServiceCheckMessage(parser *parser, message []byte, origin
 		return nil, err
 	}
 	serviceCheck := enrichServiceCheck(sample, s.defaultHostname, origin, s.entityIDPrecedenceEnabled)
-	serviceCheck.Tags = append(serviceCheck.Tags, s.extraTags...)
+	serviceCheck.Tags = append(serviceCheck.Tags, settings.extraTags...)
 	dogstatsdServiceCheckPackets.Add(1)
 	tlmProcessed.Inc("service_checks", "ok", "")
 	return serviceCheck, nil
@@ -866,7 +853,53 @@ func FormatDebugStats(stats []byte) (string, error) {
 	return buf.String(), nil
 }
 
+// ReloadSettings applies the tags and metric filtering settings of the configuration
+// to the running server. Other settings require a restart.
+func (s *Server) ReloadSettings() {
+	s.settings.Store(loadServerSettings())
+	log.Info("Dogstatsd: settings reloaded")
+}
+
+// getSettings returns the current settings of the server
+func (s *Server) getSettings() *serverSettings {
+	return s.settings.Load().(*serverSettings)
+}
+
+// loadServerSettings reads the reloadable settings from the configuration
+func loadServerSettings() *serverSettings {
+	return &serverSettings{
+		metricPrefix:          getMetricPrefix(),
+		metricPrefixBlacklist: config.Datadog.GetStringSlice("statsd_metric_namespace_blacklist"),
+		metricBlocklist:       config.Datadog.GetStringSlice("statsd_metric_blocklist"),
+		extraTags:             getExtraTags(),
+	}
+}
+
+// getMetricPrefix returns the custom namespace of the metrics, if any
+func getMetricPrefix() string {
+	metricPrefix := config.Datadog.GetString("statsd_metric_namespace")
+	if metricPrefix != "" && !strings.HasSuffix(metricPrefix, ".") {
+		metricPrefix = metricPrefix + "."
+	}
+	return metricPrefix
+}
+
+// getExtraTags returns the tags added to all metrics, events and service checks
+func getExtraTags() []string {
+	extraTags := config.Datadog.GetStringSlice("dogstatsd_tags")
+
+	// if the server is running in a context where static tags are required, add those
+	// to extraTags.
+	if staticTags := util.GetStaticTagsSlice(context.TODO()); staticTags != nil {
+		extraTags = append(extraTags, staticTags...)
+	}
+	util.SortUniqInPlace(extraTags)
+	return extraTags
+}
+
 // SetExtraTags sets extra tags. All metrics sent to the DogstatsD will be tagged with them.
 func (s *Server) SetExtraTags(tags []string) {
-	s.extraTags = tags
+	settings := *s.getSettings()
+	settings.extraTags = tags
+	s.settings.Store(&settings)
 }
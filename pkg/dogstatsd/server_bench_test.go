@@ -114,7 +114,7 @@ func BenchmarkPbarseMetricMessage(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		samplesBench = make([]metrics.MetricSample, 0, 512)
 		for pb.Next() {
-			s.parseMetricMessage(samplesBench, parser, message, "", false)
+			s.parseMetricMessage(s.getSettings(), samplesBench, parser, message, "", false)
 			samplesBench = samplesBench[0:0]
 		}
 	})
@@ -690,7 +690,7 @@ func TestNoMappingsConfig(t *testing.T) {
 	assert.Nil(t, s.mapper)
 
 	parser := newParser(newFloat64ListPool())
-	samples, err = s.parseMetricMessage(samples, parser, []byte("test.metric:666|g"), "", false)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("test.metric:666|g"), "", false)
 	assert.NoError(t, err)
 	assert.Len(t, samples, 1)
 }
@@ -806,7 +806,7 @@ dogstatsd_mapper_profiles:
 			var actualSamples []MetricSample
 			for _, p := range scenario.packets {
 				parser := newParser(newFloat64ListPool())
-				samples, err := s.parseMetricMessage(samples, parser, []byte(p), "", false)
+				samples, err := s.parseMetricMessage(s.getSettings(), samples, parser, []byte(p), "", false)
 				assert.NoError(t, err, "Case `%s` failed. parseMetricMessage should not return error %v", err)
 				for _, sample := range samples {
 					actualSamples = append(actualSamples, MetricSample{Name: sample.Name, Tags: sample.Tags, Mtype: sample.Mtype, Value: sample.Value})
@@ -843,7 +843,7 @@ func TestNewServerExtraTags(t *testing.T) {
 	demux := mockDemultiplexer()
 	s, err := NewServer(demux, false)
 	require.NoError(err, "starting the DogStatsD server shouldn't fail")
-	require.Len(s.extraTags, 0, "no tags should have been read")
+	require.Len(s.getSettings().extraTags, 0, "no tags should have been read")
 	s.Stop()
 	demux.Stop(false)
 
@@ -852,7 +852,7 @@ func TestNewServerExtraTags(t *testing.T) {
 	demux = mockDemultiplexer()
 	s, err = NewServer(demux, false)
 	require.NoError(err, "starting the DogStatsD server shouldn't fail")
-	require.Len(s.extraTags, 0, "no tags should have been read")
+	require.Len(s.getSettings().extraTags, 0, "no tags should have been read")
 	s.Stop()
 	demux.Stop(false)
 
@@ -861,9 +861,9 @@ func TestNewServerExtraTags(t *testing.T) {
 	demux = mockDemultiplexer()
 	s, err = NewServer(demux, false)
 	require.NoError(err, "starting the DogStatsD server shouldn't fail")
-	require.Len(s.extraTags, 2, "two tags should have been read")
-	require.Equal(s.extraTags[0], "extra:tags", "the tag extra:tags should be set")
-	require.Equal(s.extraTags[1], "hello:world", "the tag hello:world should be set")
+	require.Len(s.getSettings().extraTags, 2, "two tags should have been read")
+	require.Equal(s.getSettings().extraTags[0], "extra:tags", "the tag extra:tags should be set")
+	require.Equal(s.getSettings().extraTags[1], "hello:world", "the tag hello:world should be set")
 	s.Stop()
 	demux.Stop(false)
 }
@@ -882,12 +882,12 @@ func TestProcessedMetricsOrigin(t *testing.T) {
 
 	parser := newParser(newFloat64ListPool())
 	samples := []metrics.MetricSample{}
-	samples, err = s.parseMetricMessage(samples, parser, []byte("test.metric:666|g"), "container_id://test_container", false)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("test.metric:666|g"), "container_id://test_container", false)
 	assert.NoError(err)
 	assert.Len(samples, 1)
 
 	// one thing should have been stored when we parse a metric
-	samples, err = s.parseMetricMessage(samples, parser, []byte("test.metric:555|g"), "container_id://test_container", true)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("test.metric:555|g"), "container_id://test_container", true)
 	assert.NoError(err)
 	assert.Len(samples, 2)
 	assert.Len(s.cachedTlmOriginIds, 1, "one entry should have been cached")
@@ -895,7 +895,7 @@ func TestProcessedMetricsOrigin(t *testing.T) {
 	assert.Equal(s.cachedOrder[0].origin, "container_id://test_container")
 
 	// when we parse another metric (different value) with same origin, cache should contain only one entry
-	samples, err = s.parseMetricMessage(samples, parser, []byte("test.second_metric:525|g"), "container_id://test_container", true)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("test.second_metric:525|g"), "container_id://test_container", true)
 	assert.NoError(err)
 	assert.Len(samples, 3)
 	assert.Len(s.cachedTlmOriginIds, 1, "one entry should have been cached")
@@ -905,7 +905,7 @@ func TestProcessedMetricsOrigin(t *testing.T) {
 	assert.Equal(s.cachedOrder[0].err, map[string]string{"message_type": "metrics", "state": "error", "origin": "container_id://test_container"})
 
 	// when we parse another metric (different value) but with a different origin, we should store a new entry
-	samples, err = s.parseMetricMessage(samples, parser, []byte("test.second_metric:525|g"), "container_id://another_container", true)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("test.second_metric:525|g"), "container_id://another_container", true)
 	assert.NoError(err)
 	assert.Len(samples, 4)
 	assert.Len(s.cachedTlmOriginIds, 2, "two entries should have been cached")
@@ -919,7 +919,7 @@ func TestProcessedMetricsOrigin(t *testing.T) {
 
 	// oldest one should be removed once we reach the limit of the cache
 	maxOriginTagsCached = 2
-	samples, err = s.parseMetricMessage(samples, parser, []byte("yetanothermetric:525|g"), "third_origin", true)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("yetanothermetric:525|g"), "third_origin", true)
 	assert.NoError(err)
 	assert.Len(samples, 5)
 	assert.Len(s.cachedTlmOriginIds, 2, "two entries should have been cached, one has been evicted already")
@@ -933,7 +933,7 @@ func TestProcessedMetricsOrigin(t *testing.T) {
 
 	// oldest one should be removed once we reach the limit of the cache
 	maxOriginTagsCached = 2
-	samples, err = s.parseMetricMessage(samples, parser, []byte("blablabla:555|g"), "fourth_origin", true)
+	samples, err = s.parseMetricMessage(s.getSettings(), samples, parser, []byte("blablabla:555|g"), "fourth_origin", true)
 	assert.NoError(err)
 	assert.Len(samples, 6)
 	assert.Len(s.cachedTlmOriginIds, 2, "two entries should have been cached, two have been evicted already")
@@ -957,20 +957,73 @@ func TestContainerIDParsing(t *testing.T) {
 	parser.dsdOriginEnabled = true
 
 	// Metric
-	metrics, err := s.parseMetricMessage(nil, parser, []byte("metric.name:123|g|c:metric-container"), "", false)
+	metrics, err := s.parseMetricMessage(s.getSettings(), nil, parser, []byte("metric.name:123|g|c:metric-container"), "", false)
 	assert.NoError(err)
 	assert.Len(metrics, 1)
 	assert.Equal("container_id://metric-container", metrics[0].OriginFromClient)
 
 	// Event
-	event, err := s.parseEventMessage(parser, []byte("_e{10,10}:event title|test\\ntext|c:event-container"), "")
+	event, err := s.parseEventMessage(s.getSettings(), parser, []byte("_e{10,10}:event title|test\\ntext|c:event-container"), "")
 	assert.NoError(err)
 	assert.NotNil(event)
 	assert.Equal("container_id://event-container", event.OriginFromClient)
 
 	// Service check
-	serviceCheck, err := s.parseServiceCheckMessage(parser, []byte("_sc|service-check.name|0|c:service-check-container"), "")
+	serviceCheck, err := s.parseServiceCheckMessage(s.getSettings(), parser, []byte("_sc|service-check.name|0|c:service-check-container"), "")
 	assert.NoError(err)
 	assert.NotNil(serviceCheck)
 	assert.Equal("container_id://service-check-container", serviceCheck.OriginFromClient)
 }
+
+func TestReloadSettings(t *testing.T) {
+	p := config.Datadog.Get("dogstatsd_port")
+	defer func() {
+		config.Datadog.SetDefault("dogstatsd_port", p)
+		config.Datadog.Set("dogstatsd_tags", []string{})
+		config.Datadog.Set("statsd_metric_namespace", "")
+		config.Datadog.Set("statsd_metric_blocklist", []string{})
+	}()
+
+	require := require.New(t)
+	port, err := getAvailableUDPPort()
+	require.NoError(err)
+	config.Datadog.SetDefault("dogstatsd_port", port)
+
+	demux := aggregator.InitTestAgentDemultiplexerWithFlushInterval(10 * time.Millisecond)
+	defer demux.Stop(false)
+	s, err := NewServer(demux, false)
+	require.NoError(err, "starting the DogStatsD server shouldn't fail")
+	defer s.Stop()
+	require.Len(s.getSettings().extraTags, 0)
+	require.Equal("", s.getSettings().metricPrefix)
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(err, "cannot connect to DSD socket")
+	defer conn.Close()
+
+	// the settings are reloaded while the workers are processing packets
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			conn.Write([]byte("daemon:666|g"))
+		}
+	}()
+
+	config.Datadog.Set("dogstatsd_tags", []string{"hello:world", "extra:tags"})
+	config.Datadog.Set("statsd_metric_namespace", "ns")
+	config.Datadog.Set("statsd_metric_blocklist", []string{"blocked.metric"})
+	s.ReloadSettings()
+	<-done
+
+	require.Equal([]string{"extra:tags", "hello:world"}, s.getSettings().extraTags)
+	require.Equal("ns.", s.getSettings().metricPrefix)
+	require.Equal([]string{"blocked.metric"}, s.getSettings().metricBlocklist)
+
+	require.Eventually(func() bool {
+		demux.Reset()
+		conn.Write([]byte("daemon:666|g"))
+		samples := demux.WaitForSamples(100 * time.Millisecond)
+		return len(samples) == 1 && samples[0].Name == "ns.daemon" && assert.ObjectsAreEqual([]string{"extra:tags", "hello:world"}, samples[0].Tags)
+	}, 2*time.Second, 10*time.Millisecond)
+}
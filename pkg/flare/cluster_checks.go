@@ -79,6 +79,16 @@ func GetClusterChecks(w io.Writer, checkName string) error {
 		fmt.Fprintln(w, "")
 	}
 
+	// Print configs whose node affinity no agent satisfies
+	if len(cr.Unschedulable) > 0 {
+		fmt.Fprintln(w, fmt.Sprintf("=== %s configurations ===", color.RedString("Unschedulable")))
+		fmt.Fprintln(w, "No reporting agent satisfies the node affinity of these configurations")
+		for _, c := range cr.Unschedulable {
+			PrintConfig(w, c, checkName)
+		}
+		fmt.Fprintln(w, "")
+	}
+
 	// Print summary of agents
 	if len(cr.Nodes) == 0 {
 		fmt.Fprintln(w, fmt.Sprintf("=== %s agent reporting ===", color.RedString("Zero")))
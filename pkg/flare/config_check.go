@@ -119,6 +119,9 @@ func PrintConfig(w io.Writer, c integration.Config, checkName string) {
 	} else {
 		fmt.Fprintln(w, fmt.Sprintf("%s: %s", color.BlueString("Configuration source"), color.RedString("Unknown configuration source")))
 	}
+	if c.NodeAffinity != nil {
+		fmt.Fprintln(w, fmt.Sprintf("%s: %s", color.BlueString("Node affinity"), color.CyanString(c.NodeAffinity.String())))
+	}
 	for _, inst := range c.Instances {
 		ID := string(check.BuildID(c.Name, inst, c.InitConfig))
 		fmt.Fprintln(w, fmt.Sprintf("%s: %s", color.BlueString("Instance ID"), color.CyanString(ID)))
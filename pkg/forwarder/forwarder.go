@@ -411,6 +411,37 @@ func (f *DefaultForwarder) Stop() {
 
 }
 
+// UpdateAPIKeys replaces the API keys used for each domain. The forwarded
+// domains can't change without a restart.
+func (f *DefaultForwarder) UpdateAPIKeys(keysPerDomain map[string][]string) error {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	apiKeys := make(map[string][]string, len(keysPerDomain))
+	for domain, keys := range keysPerDomain {
+		if len(keys) == 0 {
+			// domains without API keys are dropped
+			continue
+		}
+		domain, _ := config.AddAgentVersionToDomain(domain, "app")
+		if _, found := f.domainResolvers[domain]; !found {
+			return fmt.Errorf("a restart is required to forward to the new domain '%s'", domain)
+		}
+		apiKeys[domain] = keys
+	}
+	for domain := range f.domainResolvers {
+		if _, found := apiKeys[domain]; !found {
+			return fmt.Errorf("a restart is required to stop forwarding to the domain '%s'", domain)
+		}
+	}
+
+	for domain, keys := range apiKeys {
+		f.domainResolvers[domain].SetAPIKeys(keys)
+	}
+	log.Infof("Forwarder API keys updated for %d endpoint(s)", len(apiKeys))
+	return nil
+}
+
 // State returns the internal state of the forwarder (Started or Stopped)
 func (f *DefaultForwarder) State() uint32 {
 	// Lock so we can't start/stop a Forwarder while getting its state
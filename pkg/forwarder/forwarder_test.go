@@ -58,6 +58,31 @@ func TestNewDefaultForwarder(t *testing.T) {
 	assert.Equal(t, forwarder.State(), forwarder.internalState.Load())
 }
 
+func TestUpdateAPIKeys(t *testing.T) {
+	forwarder := NewDefaultForwarder(NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysWithMultipleDomains)))
+
+	err := forwarder.UpdateAPIKeys(map[string][]string{
+		testDomain:    {"api-key-4"},
+		"datadog.bar": {"api-key-3", "api-key-5"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-key-4"}, forwarder.domainResolvers[testVersionDomain].GetAPIKeys())
+	assert.Equal(t, []string{"api-key-3", "api-key-5"}, forwarder.domainResolvers["datadog.bar"].GetAPIKeys())
+
+	// adding or removing a domain requires a restart
+	assert.Error(t, forwarder.UpdateAPIKeys(map[string][]string{
+		testDomain:    {"api-key-4"},
+		"datadog.bar": {"api-key-3"},
+		"datadog.baz": {"api-key-6"},
+	}))
+	assert.Error(t, forwarder.UpdateAPIKeys(map[string][]string{
+		testDomain:    {"api-key-4"},
+		"datadog.bar": nil,
+	}))
+	assert.Equal(t, []string{"api-key-4"}, forwarder.domainResolvers[testVersionDomain].GetAPIKeys())
+	assert.Equal(t, []string{"api-key-3", "api-key-5"}, forwarder.domainResolvers["datadog.bar"].GetAPIKeys())
+}
+
 func TestFeature(t *testing.T) {
 	var featureSet Features
 
@@ -156,6 +156,11 @@ func (a *Agent) Start() {
 	starter.Start()
 }
 
+// SetProcessingRules replaces the global processing rules applied to all logs.
+func (a *Agent) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	a.pipelineProvider.SetProcessingRules(processingRules)
+}
+
 // Flush flushes synchronously the pipelines managed by the Logs Agent.
 func (a *Agent) Flush(ctx context.Context) {
 	a.pipelineProvider.Flush(ctx)
@@ -77,12 +77,19 @@ func (p *Processor) run() {
 		p.done <- struct{}{}
 	}()
 	for msg := range p.inputChan {
-		p.processMessage(msg)
 		p.mu.Lock() // block here if we're trying to flush synchronously
+		p.processMessage(msg)
 		p.mu.Unlock()
 	}
 }
 
+// SetProcessingRules replaces the global processing rules applied to all logs.
+func (p *Processor) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.processingRules = processingRules
+}
+
 func (p *Processor) processMessage(msg *message.Message) {
 	metrics.LogsDecoded.Add(1)
 	metrics.TlmLogsDecoded.Inc()
@@ -89,6 +89,20 @@ func TestExclusionWithInclusion(t *testing.T) {
 	assert.Nil(t, redactedMessage)
 }
 
+func TestSetProcessingRules(t *testing.T) {
+	p := &Processor{processingRules: []*config.ProcessingRule{newProcessingRule("exclude_at_match", "", "world")}}
+	source := config.LogSource{Config: &config.LogsConfig{}}
+
+	shouldProcess, _ := p.applyRedactingRules(newMessage([]byte("hello world"), &source, ""))
+	assert.Equal(t, false, shouldProcess)
+
+	p.SetProcessingRules([]*config.ProcessingRule{newProcessingRule("exclude_at_match", "", "hello")})
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("world"), &source, ""))
+	assert.Equal(t, true, shouldProcess)
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("hello"), &source, ""))
+	assert.Equal(t, false, shouldProcess)
+}
+
 func TestMask(t *testing.T) {
 	p := &Processor{}
 
@@ -167,6 +167,30 @@ func Flush(ctx context.Context) {
 	log.Debug("Flush in the logs-agent done.")
 }
 
+// ReloadProcessingRules applies the global processing rules of the configuration
+// to the running instance of the Logs Agent.
+func ReloadProcessingRules() error {
+	if !IsAgentRunning() || agent == nil {
+		return errors.New("the logs-agent is not running")
+	}
+
+	processingRules, err := config.GlobalProcessingRules()
+	if err != nil {
+		return fmt.Errorf("invalid processing rules: %v", err)
+	}
+
+	if config.HasMultiLineRule(processingRules) {
+		log.Warn(multiLineWarning)
+		status.AddGlobalWarning(invalidProcessingRules, multiLineWarning)
+	} else {
+		status.RemoveGlobalWarning(invalidProcessingRules)
+	}
+
+	agent.SetProcessingRules(processingRules)
+	log.Info("logs-agent global processing rules reloaded")
+	return nil
+}
+
 // IsAgentRunning returns true if the logs-agent is running.
 func IsAgentRunning() bool {
 	return status.Get().IsRunning
@@ -68,6 +68,11 @@ func NewPipeline(outputChan chan *message.Payload,
 	}
 }
 
+// SetProcessingRules replaces the global processing rules of the pipeline
+func (p *Pipeline) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.processor.SetProcessingRules(processingRules)
+}
+
 // Start launches the pipeline
 func (p *Pipeline) Start() {
 	p.sender.Start()
@@ -7,6 +7,7 @@ package pipeline
 
 import (
 	"context"
+	"sync"
 
 	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
 	"go.uber.org/atomic"
@@ -25,6 +26,8 @@ type Provider interface {
 	NextPipelineChan() chan *message.Message
 	// Flush flushes all pipeline contained in this Provider
 	Flush(ctx context.Context)
+	// SetProcessingRules replaces the global processing rules of all pipelines
+	SetProcessingRules(processingRules []*config.ProcessingRule)
 }
 
 // provider implements providing logic
@@ -33,10 +36,14 @@ type provider struct {
 	auditor                   auditor.Auditor
 	diagnosticMessageReceiver diagnostic.MessageReceiver
 	outputChan                chan *message.Payload
-	processingRules           []*config.ProcessingRule
 	endpoints                 *config.Endpoints
 
-	pipelines            []*Pipeline
+	// mu guards the processing rules and the pipelines, so that the rules
+	// can be replaced while the pipelines are being started or stopped
+	mu              sync.Mutex
+	processingRules []*config.ProcessingRule
+	pipelines       []*Pipeline
+
 	currentPipelineIndex *atomic.Uint32
 	destinationsContext  *client.DestinationsContext
 
@@ -69,6 +76,9 @@ func newProvider(numberOfPipelines int, auditor auditor.Auditor, diagnosticMessa
 
 // Start initializes the pipelines
 func (p *provider) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	// This requires the auditor to be started before.
 	p.outputChan = p.auditor.Channel()
 
@@ -82,6 +92,9 @@ func (p *provider) Start() {
 // Stop stops all pipelines in parallel,
 // this call blocks until all pipelines are stopped
 func (p *provider) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	stopper := startstop.NewParallelStopper()
 	for _, pipeline := range p.pipelines {
 		stopper.Add(pipeline)
@@ -113,3 +126,14 @@ func (p *provider) Flush(ctx context.Context) {
 		}
 	}
 }
+
+// SetProcessingRules replaces the global processing rules of all the pipelines of this provider.
+func (p *provider) SetProcessingRules(processingRules []*config.ProcessingRule) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.processingRules = processingRules
+	for _, pipeline := range p.pipelines {
+		pipeline.SetProcessingRules(processingRules)
+	}
+}
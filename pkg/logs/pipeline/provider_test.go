@@ -26,7 +26,7 @@ type ProviderTestSuite struct {
 }
 
 func (suite *ProviderTestSuite) SetupTest() {
-	suite.a = auditor.New("", auditor.DefaultRegistryFilename, time.Hour, health.RegisterLiveness("fake"))
+	suite.a = auditor.New(suite.T().TempDir(), auditor.DefaultRegistryFilename, time.Hour, health.RegisterLiveness("fake"))
 	suite.p = &provider{
 		numberOfPipelines:    3,
 		auditor:              suite.a,
@@ -63,6 +63,26 @@ func (suite *ProviderTestSuite) TestProvider() {
 	suite.Nil(suite.p.NextPipelineChan())
 }
 
+func (suite *ProviderTestSuite) TestSetProcessingRulesWhileStarting() {
+	rules := []*config.ProcessingRule{{Type: config.ExcludeAtMatch, Name: "exclude_foo", Pattern: "foo"}}
+
+	suite.a.Start()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		suite.p.SetProcessingRules(rules)
+	}()
+	suite.p.Start()
+	<-done
+
+	suite.p.mu.Lock()
+	suite.Equal(rules, suite.p.processingRules)
+	suite.p.mu.Unlock()
+
+	suite.p.Stop()
+	suite.a.Stop()
+}
+
 func TestProviderTestSuite(t *testing.T) {
 	suite.Run(t, new(ProviderTestSuite))
 }
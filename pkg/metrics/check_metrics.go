@@ -47,6 +47,7 @@ type CheckMetrics struct {
 	statefulTimeout float64
 	metrics         ContextMetrics
 	deadlines       map[ckey.ContextKey]float64
+	histogramConfig *HistogramConfig
 }
 
 // NewCheckMetrics returns new CheckMetrics instance.
@@ -69,9 +70,41 @@ func (cm *CheckMetrics) AddSample(contextKey ckey.ContextKey, sample *MetricSamp
 	if cm.deadlines != nil {
 		delete(cm.deadlines, contextKey)
 	}
+	if sample.Mtype == HistogramType && cm.histogramConfig != nil {
+		cm.initHistogram(contextKey, sample.Name, interval)
+	}
 	return cm.metrics.AddSample(contextKey, sample, timestamp, interval, checkMetricsAddSampleTelemetry)
 }
 
+// SetHistogramConfig sets the histogram overrides applied to histograms created from now on.
+func (cm *CheckMetrics) SetHistogramConfig(c *HistogramConfig) {
+	cm.histogramConfig = c
+}
+
+// initHistogram creates the histogram for contextKey with the configured overrides, if it
+// doesn't exist yet.
+func (cm *CheckMetrics) initHistogram(contextKey ckey.ContextKey, name string, interval int64) {
+	if _, ok := cm.metrics[contextKey]; ok {
+		return
+	}
+	aggregates, percentiles := cm.histogramConfig.forMetric(name)
+	if aggregates == nil && percentiles == nil {
+		return
+	}
+
+	h := NewHistogram(interval)
+	if aggregates == nil {
+		aggregates = h.aggregates
+	}
+	if percentiles == nil {
+		percentiles = h.percentiles
+	}
+	h.configure(aggregates, percentiles)
+
+	cm.metrics[contextKey] = h
+	checkMetricsAddSampleTelemetry.Inc(h.isStateful())
+}
+
 // Expire enables metric data for given context keys to be removed.
 //
 // Metrics that do not keep state between flushes, will be removed immediately.
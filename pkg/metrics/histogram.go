@@ -66,14 +66,19 @@ func (h *histogramPercentilesConfig) percentiles() []int {
 			log.Errorf("histogram_percentiles must be between 0 and 1: skipping %f", i)
 			continue
 		}
-		// in some cases the '*100' will lower the number resulting in
-		// an int lower by 1 from what is expected (ex: 0.29 would
-		// become 28). As a workaround we add 0.5 before casting.
-		res = append(res, int(i*100+0.5))
+		res = append(res, percentileFromRatio(i))
 	}
 	return res
 }
 
+// percentileFromRatio converts a percentile expressed in the 0-1 range to the 1-100 range
+func percentileFromRatio(ratio float64) int {
+	// in some cases the '*100' will lower the number resulting in
+	// an int lower by 1 from what is expected (ex: 0.29 would
+	// become 28). As a workaround we add 0.5 before casting.
+	return int(ratio*100 + 0.5)
+}
+
 // NewHistogram returns a newly initialized histogram
 func NewHistogram(interval int64) *Histogram {
 	// we initialize default value on the first histogram creation
@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metrics
+
+import (
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// HistogramConfig holds the histogram aggregation overrides configured on a check instance.
+// Nil aggregates or percentiles fall back to the global `histogram_aggregates` and
+// `histogram_percentiles` settings.
+type HistogramConfig struct {
+	Aggregates     []string
+	Percentiles    []int // each in the 1-100 range
+	AsDistribution bool  // send the histogram as a distribution instead of local aggregates
+	// Metrics holds per-metric overrides, taking precedence over the check-level ones
+	Metrics map[string]HistogramMetricConfig
+}
+
+// HistogramMetricConfig holds the histogram aggregation overrides of a single metric.
+// Nil values fall back to the check-level ones.
+type HistogramMetricConfig struct {
+	Aggregates     []string
+	Percentiles    []int
+	AsDistribution *bool
+}
+
+// NewHistogramConfig builds the HistogramConfig of a check instance from its common options.
+// It returns nil when the instance doesn't override any histogram setting.
+func NewHistogramConfig(instance integration.CommonInstanceConfig) *HistogramConfig {
+	if instance.HistogramAggregates == nil && instance.HistogramPercentiles == nil &&
+		!instance.HistogramAsDistribution && len(instance.HistogramMetrics) == 0 {
+		return nil
+	}
+
+	c := &HistogramConfig{
+		Aggregates:     instance.HistogramAggregates,
+		Percentiles:    PercentilesFromRatios(instance.HistogramPercentiles),
+		AsDistribution: instance.HistogramAsDistribution,
+	}
+
+	if len(instance.HistogramMetrics) > 0 {
+		c.Metrics = make(map[string]HistogramMetricConfig, len(instance.HistogramMetrics))
+		for name, m := range instance.HistogramMetrics {
+			c.Metrics[name] = HistogramMetricConfig{
+				Aggregates:     m.Aggregates,
+				Percentiles:    PercentilesFromRatios(m.Percentiles),
+				AsDistribution: m.AsDistribution,
+			}
+		}
+	}
+
+	return c
+}
+
+// PercentilesFromRatios converts percentiles expressed in the 0-1 range to the
+// sorted 1-100 range, skipping the invalid ones. A nil input returns nil.
+func PercentilesFromRatios(ratios []float64) []int {
+	if ratios == nil {
+		return nil
+	}
+	res := []int{}
+	for _, r := range ratios {
+		if r < 0 || r > 1 {
+			log.Errorf("histogram_percentiles must be between 0 and 1: skipping %f", r)
+			continue
+		}
+		res = append(res, percentileFromRatio(r))
+	}
+	sort.Ints(res)
+	return res
+}
+
+// forMetric returns the aggregates and percentiles to use for the given metric,
+// nil values meaning the global defaults should be used.
+func (c *HistogramConfig) forMetric(name string) ([]string, []int) {
+	if c == nil {
+		return nil, nil
+	}
+	aggregates, percentiles := c.Aggregates, c.Percentiles
+	if m, ok := c.Metrics[name]; ok {
+		if m.Aggregates != nil {
+			aggregates = m.Aggregates
+		}
+		if m.Percentiles != nil {
+			percentiles = m.Percentiles
+		}
+	}
+	return aggregates, percentiles
+}
+
+// IsDistribution returns whether the samples of the given histogram metric should be
+// aggregated into a distribution instead of local aggregates
+func (c *HistogramConfig) IsDistribution(name string) bool {
+	if c == nil {
+		return false
+	}
+	if m, ok := c.Metrics[name]; ok && m.AsDistribution != nil {
+		return *m.AsDistribution
+	}
+	return c.AsDistribution
+}
@@ -0,0 +1,117 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/ckey"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+)
+
+func TestHistogramConfigForMetric(t *testing.T) {
+	asDistribution, notAsDistribution := true, false
+	c := &HistogramConfig{
+		Aggregates:     []string{"max", "min"},
+		Percentiles:    []int{50, 99},
+		AsDistribution: true,
+		Metrics: map[string]HistogramMetricConfig{
+			"foo": {Percentiles: []int{29}},
+			"bar": {AsDistribution: &notAsDistribution},
+		},
+	}
+
+	aggregates, percentiles := c.forMetric("foo")
+	assert.Equal(t, []string{"max", "min"}, aggregates)
+	assert.Equal(t, []int{29}, percentiles)
+	assert.True(t, c.IsDistribution("foo"))
+
+	aggregates, percentiles = c.forMetric("baz")
+	assert.Equal(t, []string{"max", "min"}, aggregates)
+	assert.Equal(t, []int{50, 99}, percentiles)
+	assert.True(t, c.IsDistribution("baz"))
+
+	// the per-metric setting takes precedence, even to disable distributions
+	assert.False(t, c.IsDistribution("bar"))
+	c.AsDistribution = false
+	c.Metrics["foo"] = HistogramMetricConfig{AsDistribution: &asDistribution}
+	assert.True(t, c.IsDistribution("foo"))
+	assert.False(t, c.IsDistribution("baz"))
+
+	var nilConfig *HistogramConfig
+	assert.False(t, nilConfig.IsDistribution("foo"))
+}
+
+func TestPercentilesFromRatios(t *testing.T) {
+	assert.Nil(t, PercentilesFromRatios(nil))
+	assert.Equal(t, []int{29, 50, 99}, PercentilesFromRatios([]float64{0.99, 0.5, 2, 0.29}))
+}
+
+func TestCheckMetricsHistogramConfig(t *testing.T) {
+	cm := NewCheckMetrics(true, 0)
+	cm.SetHistogramConfig(&HistogramConfig{
+		Metrics: map[string]HistogramMetricConfig{
+			"foo": {Aggregates: []string{"sum"}},
+		},
+	})
+
+	cm.AddSample(ckey.ContextKey(1), &MetricSample{Name: "foo", Value: 1, Mtype: HistogramType}, 1, 1)
+	cm.AddSample(ckey.ContextKey(1), &MetricSample{Name: "foo", Value: 2, Mtype: HistogramType}, 1, 1)
+	cm.AddSample(ckey.ContextKey(2), &MetricSample{Name: "bar", Value: 1, Mtype: HistogramType}, 1, 1)
+
+	configured := cm.metrics[ckey.ContextKey(1)].(*Histogram)
+	assert.Equal(t, []string{"sum"}, configured.aggregates)
+	assert.Equal(t, defaultPercentiles, configured.percentiles)
+
+	series, _ := cm.Flush(10)
+	var suffixes []string
+	for _, s := range series {
+		if s.ContextKey == ckey.ContextKey(1) {
+			suffixes = append(suffixes, s.NameSuffix)
+		}
+	}
+	// percentiles are not overridden, the default 95th percentile is kept
+	assert.ElementsMatch(t, []string{".sum", ".95percentile"}, suffixes)
+
+	unconfigured := cm.metrics[ckey.ContextKey(2)].(*Histogram)
+	assert.Equal(t, defaultAggregates, unconfigured.aggregates)
+}
+
+func TestNewHistogramConfig(t *testing.T) {
+	assert.Nil(t, NewHistogramConfig(integration.CommonInstanceConfig{}))
+
+	var instance integration.CommonInstanceConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+histogram_aggregates: [max, min]
+histogram_percentiles: [0.99, 0.5, 2]
+histogram_as_distribution: true
+histogram_metrics:
+  foo:
+    percentiles: [0.29]
+  bar:
+    as_distribution: false
+`), &instance))
+
+	c := NewHistogramConfig(instance)
+	require.NotNil(t, c)
+	assert.Equal(t, []string{"max", "min"}, c.Aggregates)
+	assert.Equal(t, []int{50, 99}, c.Percentiles)
+	assert.True(t, c.AsDistribution)
+
+	require.Len(t, c.Metrics, 2)
+	assert.Nil(t, c.Metrics["foo"].Aggregates)
+	assert.Equal(t, []int{29}, c.Metrics["foo"].Percentiles)
+	assert.Nil(t, c.Metrics["foo"].AsDistribution)
+	require.NotNil(t, c.Metrics["bar"].AsDistribution)
+	assert.False(t, *c.Metrics["bar"].AsDistribution)
+
+	assert.True(t, c.IsDistribution("foo"))
+	assert.False(t, c.IsDistribution("bar"))
+}
@@ -6,6 +6,7 @@
 package config
 
 import (
+	"strconv"
 	"strings"
 	"time"
 
@@ -72,6 +73,18 @@ type Config struct {
 	// Supported libraries: OpenSSL
 	EnableHTTPSMonitoring bool
 
+	// EnableKafkaMonitoring specifies whether the tracer should monitor Kafka traffic
+	EnableKafkaMonitoring bool
+
+	// EnablePostgresMonitoring specifies whether the tracer should monitor PostgreSQL traffic
+	EnablePostgresMonitoring bool
+
+	// KafkaPorts are the server ports of the TCP connections inspected for Kafka traffic
+	KafkaPorts []uint16
+
+	// PostgresPorts are the server ports of the TCP connections inspected for PostgreSQL traffic
+	PostgresPorts []uint16
+
 	// UDPConnTimeout determines the length of traffic inactivity between two
 	// (IP, port)-pairs before declaring a UDP connection as inactive. This is
 	// set to /proc/sys/net/netfilter/nf_conntrack_udp_timeout on Linux by
@@ -107,6 +120,14 @@ type Config struct {
 	// get flushed on every client request (default 30s check interval)
 	MaxHTTPStatsBuffered int
 
+	// MaxKafkaStatsBuffered represents the maximum number of Kafka stats we'll buffer in memory. These stats
+	// get flushed on every client request (default 30s check interval)
+	MaxKafkaStatsBuffered int
+
+	// MaxPostgresStatsBuffered represents the maximum number of PostgreSQL stats we'll buffer in memory. These stats
+	// get flushed on every client request (default 30s check interval)
+	MaxPostgresStatsBuffered int
+
 	// MaxConnectionsStateBuffered represents the maximum number of state objects that we'll store in memory. These state objects store
 	// the stats for a connection so we can accurately determine traffic change between client requests.
 	MaxConnectionsStateBuffered int
@@ -159,8 +180,19 @@ type Config struct {
 	// RecordedQueryTypes enables specific DNS query types to be recorded
 	RecordedQueryTypes []string
 
+	// DNSDomainIncludes is a list of glob patterns (e.g. "*.example.com"), only the DNS queries of the
+	// domains matching one of them are recorded in the DNS stats. All the domains are recorded when empty.
+	DNSDomainIncludes []string
+
+	// DNSDomainExcludes is a list of glob patterns of the domains whose DNS queries aren't recorded in the DNS stats
+	DNSDomainExcludes []string
+
 	// HTTP replace rules
 	HTTPReplaceRules []*ReplaceRule
+
+	// HTTPNormalizePathSegments specifies whether the numeric, UUID and long hexadecimal segments
+	// of HTTP paths should be replaced with a placeholder, after applying the replace rules
+	HTTPNormalizePathSegments bool
 }
 
 func join(pieces ...string) string {
@@ -208,6 +240,15 @@ func New() *Config {
 		EnableHTTPSMonitoring: cfg.GetBool(join(netNS, "enable_https_monitoring")),
 		MaxHTTPStatsBuffered:  100000,
 
+		HTTPNormalizePathSegments: cfg.GetBool(join(netNS, "http_normalize_path_segments")),
+
+		EnableKafkaMonitoring:    cfg.GetBool(join(netNS, "enable_kafka_monitoring")),
+		EnablePostgresMonitoring: cfg.GetBool(join(netNS, "enable_postgres_monitoring")),
+		KafkaPorts:               parsePorts(cfg, join(netNS, "kafka_ports")),
+		PostgresPorts:            parsePorts(cfg, join(netNS, "postgres_ports")),
+		MaxKafkaStatsBuffered:    100000,
+		MaxPostgresStatsBuffered: 100000,
+
 		EnableConntrack:              cfg.GetBool(join(spNS, "enable_conntrack")),
 		ConntrackMaxStateSize:        cfg.GetInt(join(spNS, "conntrack_max_state_size")),
 		ConntrackRateLimit:           cfg.GetInt(join(spNS, "conntrack_rate_limit")),
@@ -221,6 +262,8 @@ func New() *Config {
 		DriverBufferSize:     cfg.GetInt(join(spNS, "windows.driver_buffer_size")),
 
 		RecordedQueryTypes: cfg.GetStringSlice(join(netNS, "dns_recorded_query_types")),
+		DNSDomainIncludes:  cfg.GetStringSlice(join(netNS, "dns_domain_includes")),
+		DNSDomainExcludes:  cfg.GetStringSlice(join(netNS, "dns_domain_excludes")),
 	}
 
 	httpRRKey := join(netNS, "http_replace_rules")
@@ -264,3 +307,17 @@ func New() *Config {
 
 	return c
 }
+
+// parsePorts returns the valid TCP ports of a list setting, ignoring the others
+func parsePorts(cfg ddconfig.Config, key string) []uint16 {
+	var ports []uint16
+	for _, rawPort := range cfg.GetStringSlice(key) {
+		port, err := strconv.ParseUint(rawPort, 10, 16)
+		if err != nil || port == 0 {
+			log.Warnf("ignoring invalid port %q of %q", rawPort, key)
+			continue
+		}
+		ports = append(ports, uint16(port))
+	}
+	return ports
+}
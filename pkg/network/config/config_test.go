@@ -299,3 +299,120 @@ func TestHTTPReplaceRules(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTPNormalizePathSegments(t *testing.T) {
+	t.Run("via YAML", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		// default config
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.False(t, cfg.HTTPNormalizePathSegments)
+
+		newConfig()
+		_, err = sysconfig.New("./testdata/TestDDAgentConfigYamlAndSystemProbeConfig-HTTPNormalizePathSegments.yaml")
+		require.NoError(t, err)
+		cfg = New()
+
+		assert.True(t, cfg.HTTPNormalizePathSegments)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_HTTP_NORMALIZE_PATH_SEGMENTS", "true")
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_HTTP_NORMALIZE_PATH_SEGMENTS")
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.True(t, cfg.HTTPNormalizePathSegments)
+	})
+}
+
+func TestKafkaPostgresMonitoring(t *testing.T) {
+	t.Run("via YAML", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		// default config
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.False(t, cfg.EnableKafkaMonitoring)
+		assert.False(t, cfg.EnablePostgresMonitoring)
+		assert.Equal(t, []uint16{9092}, cfg.KafkaPorts)
+		assert.Equal(t, []uint16{5432}, cfg.PostgresPorts)
+
+		newConfig()
+		_, err = sysconfig.New("./testdata/TestDDAgentConfigYamlAndSystemProbeConfig-KafkaPostgresMonitoring.yaml")
+		require.NoError(t, err)
+		cfg = New()
+
+		assert.True(t, cfg.EnableKafkaMonitoring)
+		assert.True(t, cfg.EnablePostgresMonitoring)
+		assert.Equal(t, []uint16{9092, 19092}, cfg.KafkaPorts)
+		assert.Equal(t, []uint16{5432}, cfg.PostgresPorts)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_ENABLE_KAFKA_MONITORING", "true")
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_ENABLE_KAFKA_MONITORING")
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_ENABLE_POSTGRES_MONITORING", "true")
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_ENABLE_POSTGRES_MONITORING")
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_POSTGRES_PORTS", "5432 6432 invalid")
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_POSTGRES_PORTS")
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.True(t, cfg.EnableKafkaMonitoring)
+		assert.True(t, cfg.EnablePostgresMonitoring)
+		assert.Equal(t, []uint16{5432, 6432}, cfg.PostgresPorts)
+	})
+}
+
+func TestDNSDomainFilters(t *testing.T) {
+	t.Run("via YAML", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		// default config
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.Empty(t, cfg.DNSDomainIncludes)
+		assert.Empty(t, cfg.DNSDomainExcludes)
+
+		newConfig()
+		_, err = sysconfig.New("./testdata/TestDDAgentConfigYamlAndSystemProbeConfig-DNSDomainFilters.yaml")
+		require.NoError(t, err)
+		cfg = New()
+
+		assert.Equal(t, []string{"*.example.com"}, cfg.DNSDomainIncludes)
+		assert.Equal(t, []string{"*.internal.example.com", "metadata.google.internal"}, cfg.DNSDomainExcludes)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		newConfig()
+		defer restoreGlobalConfig()
+
+		os.Setenv("DD_SYSTEM_PROBE_NETWORK_DNS_DOMAIN_EXCLUDES", "*.internal.example.com metadata.google.internal")
+		defer os.Unsetenv("DD_SYSTEM_PROBE_NETWORK_DNS_DOMAIN_EXCLUDES")
+		_, err := sysconfig.New("")
+		require.NoError(t, err)
+		cfg := New()
+
+		assert.Empty(t, cfg.DNSDomainIncludes)
+		assert.Equal(t, []string{"*.internal.example.com", "metadata.google.internal"}, cfg.DNSDomainExcludes)
+	})
+}
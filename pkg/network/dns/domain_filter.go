@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows || linux_bpf
+// +build windows linux_bpf
+
+package dns
+
+import (
+	"path"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// domainFilter selects the domains whose queries are recorded in the DNS stats, from lists of
+// glob patterns. A `*` matches any sequence of characters, dots included, so `*.example.com`
+// matches `www.example.com` and `api.eu.example.com` but not `example.com`.
+type domainFilter struct {
+	includes []string
+	excludes []string
+
+	// buffer used to lower-case the domains
+	buf []byte
+}
+
+func newDomainFilter(includes, excludes []string) *domainFilter {
+	f := &domainFilter{
+		includes: compileDomainPatterns(includes),
+		excludes: compileDomainPatterns(excludes),
+	}
+	if len(f.includes) > 0 {
+		log.Infof("Recording DNS stats of the domains matching: %v", f.includes)
+	}
+	if len(f.excludes) > 0 {
+		log.Infof("Excluding DNS stats of the domains matching: %v", f.excludes)
+	}
+	return f
+}
+
+func compileDomainPatterns(patterns []string) []string {
+	var compiled []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(pattern)), ".")
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			log.Warnf("Invalid DNS domain pattern %q, skipping: %s", pattern, err)
+			continue
+		}
+		compiled = append(compiled, pattern)
+	}
+	return compiled
+}
+
+// isEmpty returns whether the filter lets all the domains through
+func (f *domainFilter) isEmpty() bool {
+	return len(f.includes) == 0 && len(f.excludes) == 0
+}
+
+// allowed returns whether the queries of a domain are recorded in the DNS stats.
+// The domain is matched case-insensitively, without its trailing dot.
+func (f *domainFilter) allowed(domain []byte) bool {
+	if f.isEmpty() {
+		return true
+	}
+
+	f.buf = append(f.buf[:0], domain...)
+	inplaceASCIILower(f.buf)
+	name := strings.TrimSuffix(string(f.buf), ".")
+
+	if len(f.includes) > 0 && !matchAny(f.includes, name) {
+		return false
+	}
+	return !matchAny(f.excludes, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		// the patterns are validated when compiled
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows || linux_bpf
+// +build windows linux_bpf
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		includes []string
+		excludes []string
+		allowed  []string
+		filtered []string
+	}{
+		{
+			name:    "no patterns",
+			allowed: []string{"example.com", "www.example.com", ""},
+		},
+		{
+			name:     "excludes",
+			excludes: []string{"*.svc.cluster.local", "metadata.google.internal"},
+			allowed:  []string{"example.com", "svc.cluster.local", "metadata.google.internal.example.com"},
+			filtered: []string{"kube-dns.kube-system.svc.cluster.local", "METADATA.Google.Internal", "metadata.google.internal."},
+		},
+		{
+			name:     "includes",
+			includes: []string{"*.example.com", "example.org"},
+			allowed:  []string{"www.example.com", "api.eu.example.com", "Example.org."},
+			filtered: []string{"example.com", "www.example.org", "example.net"},
+		},
+		{
+			name:     "includes and excludes",
+			includes: []string{"*.example.com"},
+			excludes: []string{"internal.example.com", "*.internal.example.com"},
+			allowed:  []string{"www.example.com"},
+			filtered: []string{"internal.example.com", "db.internal.example.com", "example.net"},
+		},
+		{
+			name:     "invalid and empty patterns",
+			excludes: []string{"[", "  ", "*.LOCAL."},
+			allowed:  []string{"example.com"},
+			filtered: []string{"host.local"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDomainFilter(tt.includes, tt.excludes)
+			for _, domain := range tt.allowed {
+				assert.True(t, f.allowed([]byte(domain)), domain)
+			}
+			for _, domain := range tt.filtered {
+				assert.False(t, f.allowed([]byte(domain)), domain)
+			}
+		})
+	}
+}
+
+func TestDomainFilterKeepsDomain(t *testing.T) {
+	f := newDomainFilter(nil, []string{"*.local"})
+	domain := []byte("Host.LOCAL")
+	assert.False(t, f.allowed(domain))
+	assert.Equal(t, "Host.LOCAL", string(domain))
+}
@@ -26,6 +26,7 @@ const maxIPBufferSize = 200
 var (
 	errTruncated      = errors.New("the packet is truncated")
 	errSkippedPayload = errors.New("the packet does not contain relevant DNS response")
+	errFilteredDomain = errors.New("the packet contains a DNS query for a domain excluded from the stats")
 
 	// recordedRecordTypes defines a map of DNS types that we'll capture by default.
 	// add additional types here to change the default.
@@ -69,6 +70,7 @@ type dnsParser struct {
 	collectDNSStats    bool
 	collectDNSDomains  bool
 	recordedQueryTypes map[layers.DNSType]struct{}
+	domainFilter       *domainFilter
 }
 
 func newDNSParser(layerType gopacket.LayerType, cfg *config.Config) *dnsParser {
@@ -105,6 +107,7 @@ func newDNSParser(layerType gopacket.LayerType, cfg *config.Config) *dnsParser {
 		collectDNSStats:    cfg.CollectDNSStats,
 		collectDNSDomains:  cfg.CollectDNSDomains,
 		recordedQueryTypes: queryTypes,
+		domainFilter:       newDomainFilter(cfg.DNSDomainIncludes, cfg.DNSDomainExcludes),
 	}
 }
 
@@ -191,6 +194,12 @@ func (p *dnsParser) parseAnswerInto(
 
 	// Only consider responses
 	if !dns.QR {
+		// The stats of the responses are computed from the queries, skipping the queries of the
+		// filtered domains is enough to exclude them from the stats, while still resolving their IPs
+		if !p.domainFilter.allowed(question.Name) {
+			return errFilteredDomain
+		}
+
 		pktInfo.pktType = query
 		pktInfo.queryType = QueryType(question.Type)
 		if p.collectDNSDomains {
@@ -202,12 +211,12 @@ func (p *dnsParser) parseAnswerInto(
 	}
 
 	pktInfo.rCode = uint8(dns.ResponseCode)
+	pktInfo.queryType = QueryType(question.Type)
 	if dns.ResponseCode != 0 {
 		pktInfo.pktType = failedResponse
 		return nil
 	}
 
-	pktInfo.queryType = QueryType(question.Type)
 	alias := p.extractCNAME(question.Name, dns.Answers)
 	p.extractIPsInto(alias, dns.Answers, t)
 	inplaceASCIILower(question.Name)
@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows || linux_bpf
+// +build windows linux_bpf
+
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilteredDomains(t *testing.T) {
+	cfg := &config.Config{
+		CollectDNSStats:   true,
+		CollectDNSDomains: true,
+		DNSDomainExcludes: []string{"*.cluster.local"},
+	}
+	p := newDNSParser(layers.LayerTypeEthernet, cfg)
+
+	// the queries of the excluded domains are skipped
+	pktInfo := dnsPacketInfo{}
+	err := p.ParseInto(dnsPacket(t, "redis.default.svc.cluster.local", false, layers.DNSResponseCodeNoErr), new(translation), &pktInfo)
+	assert.Equal(t, errFilteredDomain, err)
+
+	// but their responses are still used to resolve IPs
+	tr := &translation{ips: make(map[util.Address]time.Time)}
+	err = p.ParseInto(dnsPacket(t, "redis.default.svc.cluster.local", true, layers.DNSResponseCodeNoErr), tr, &pktInfo)
+	require.NoError(t, err)
+	assert.Equal(t, "redis.default.svc.cluster.local", ToString(tr.dns))
+	assert.Contains(t, tr.ips, util.AddressFromString("10.0.0.10"))
+
+	pktInfo = dnsPacketInfo{}
+	err = p.ParseInto(dnsPacket(t, "www.example.com", false, layers.DNSResponseCodeNoErr), new(translation), &pktInfo)
+	require.NoError(t, err)
+	assert.Equal(t, query, pktInfo.pktType)
+	assert.Equal(t, "www.example.com", ToString(pktInfo.question))
+}
+
+func TestParseFailedResponse(t *testing.T) {
+	cfg := &config.Config{CollectDNSStats: true, RecordedQueryTypes: []string{"A", "AAAA"}}
+	p := newDNSParser(layers.LayerTypeEthernet, cfg)
+
+	pktInfo := dnsPacketInfo{}
+	err := p.ParseInto(dnsPacket(t, "missing.example.com", true, layers.DNSResponseCodeNXDomain), new(translation), &pktInfo)
+	require.NoError(t, err)
+	assert.Equal(t, failedResponse, pktInfo.pktType)
+	assert.Equal(t, uint8(layers.DNSResponseCodeNXDomain), pktInfo.rCode)
+	assert.Equal(t, TypeA, pktInfo.queryType)
+}
+
+// dnsPacket returns an ethernet frame holding a DNS query of the A record of a domain, or its response
+func dnsPacket(t *testing.T, domain string, response bool, rcode layers.DNSResponseCode) []byte {
+	client, server := net.IPv4(10, 0, 0, 1), net.IPv4(8, 8, 8, 8)
+	clientPort, serverPort := layers.UDPPort(40000), layers.UDPPort(53)
+
+	dns := &layers.DNS{
+		ID:        42,
+		QR:        response,
+		RD:        true,
+		QDCount:   1,
+		Questions: []layers.DNSQuestion{{Name: []byte(domain), Type: layers.DNSTypeA, Class: layers.DNSClassIN}},
+	}
+	ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: client, DstIP: server}
+	udp := &layers.UDP{SrcPort: clientPort, DstPort: serverPort}
+	if response {
+		ip.SrcIP, ip.DstIP = server, client
+		udp.SrcPort, udp.DstPort = serverPort, clientPort
+		dns.ResponseCode = rcode
+		if rcode == layers.DNSResponseCodeNoErr {
+			dns.ANCount = 1
+			dns.Answers = []layers.DNSResourceRecord{{
+				Name:  []byte(domain),
+				Type:  layers.DNSTypeA,
+				Class: layers.DNSClassIN,
+				TTL:   60,
+				IP:    net.IPv4(10, 0, 0, 10),
+			}}
+		}
+	}
+	require.NoError(t, udp.SetNetworkLayerForChecksum(ip))
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	eth := &layers.Ethernet{SrcMAC: make([]byte, 6), DstMAC: make([]byte, 6), EthernetType: layers.EthernetTypeIPv4}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, udp, dns))
+	return buf.Bytes()
+}
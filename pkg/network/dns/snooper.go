@@ -38,6 +38,9 @@ type socketFilterSnooper struct {
 	successes int64
 	errors    int64
 
+	// queries of the domains excluded from the DNS stats
+	filteredQueries int64
+
 	source          packetSource
 	parser          *dnsParser
 	cache           *reverseDNSCache
@@ -135,6 +138,7 @@ func (s *socketFilterSnooper) GetStats() map[string]int64 {
 	stats["queries"] = atomic.LoadInt64(&s.queries)
 	stats["successes"] = atomic.LoadInt64(&s.successes)
 	stats["errors"] = atomic.LoadInt64(&s.errors)
+	stats["filtered_queries"] = atomic.LoadInt64(&s.filteredQueries)
 	if s.statKeeper != nil {
 		numStats, droppedStats := s.statKeeper.GetNumStats()
 		stats["num_stats"] = int64(numStats)
@@ -172,6 +176,8 @@ func (s *socketFilterSnooper) processPacket(data []byte, ts time.Time) error {
 	if err := s.parser.ParseInto(data, t, &pktInfo); err != nil {
 		switch err {
 		case errSkippedPayload: // no need to count or log cases where the packet is valid but has no relevant content
+		case errFilteredDomain:
+			atomic.AddInt64(&s.filteredQueries, 1)
 		case errTruncated:
 			atomic.AddInt64(&s.truncatedPkts, 1)
 		default:
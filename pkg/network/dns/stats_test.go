@@ -107,6 +107,45 @@ func TestExpiredStateRemoval(t *testing.T) {
 	assert.Equal(t, uint32(1), stats[key][d][TypeA].Timeouts)
 }
 
+func TestCountByRcode(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	key := getSampleDNSKey()
+	missing, flaky := ToHostname("missing.com"), ToHostname("flaky.com")
+
+	const (
+		rcodeServFail = 2
+		rcodeNXDomain = 3
+	)
+	responses := []struct {
+		domain Hostname
+		qtype  QueryType
+		rcode  uint8
+	}{
+		{missing, TypeA, rcodeNXDomain},
+		{missing, TypeA, rcodeNXDomain},
+		{missing, TypeAAAA, rcodeNXDomain},
+		{flaky, TypeA, rcodeServFail},
+		{flaky, TypeA, 0},
+	}
+
+	now := time.Now()
+	for i, r := range responses {
+		id := uint16(i)
+		respType := successfulResponse
+		if r.rcode != 0 {
+			respType = failedResponse
+		}
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: id, pktType: query, key: key, question: r.domain, queryType: r.qtype}, now)
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: id, pktType: respType, key: key, rCode: r.rcode, queryType: r.qtype}, now)
+	}
+
+	stats := sk.GetAndResetAllStats()
+	require.Contains(t, stats, key)
+	assert.Equal(t, map[uint32]uint32{rcodeNXDomain: 2}, stats[key][missing][TypeA].CountByRcode)
+	assert.Equal(t, map[uint32]uint32{rcodeNXDomain: 1}, stats[key][missing][TypeAAAA].CountByRcode)
+	assert.Equal(t, map[uint32]uint32{rcodeServFail: 1, 0: 1}, stats[key][flaky][TypeA].CountByRcode)
+}
+
 func BenchmarkStats(b *testing.B) {
 	key := getSampleDNSKey()
 
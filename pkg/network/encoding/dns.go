@@ -167,7 +167,12 @@ func formatDNSStatsByDomain(stats map[dns.Hostname]map[dns.QueryType]dns.Stats,
 
 			} else {
 				var ms model.DNSStats
-				ms.DnsCountByRcode = stat.CountByRcode
+				// the counts of the other query types are added to the map, which
+				// must not be shared with the stats of this query type
+				ms.DnsCountByRcode = make(map[uint32]uint32, len(stat.CountByRcode))
+				for rcode, count := range stat.CountByRcode {
+					ms.DnsCountByRcode[rcode] = count
+				}
 				ms.DnsFailureLatencySum = stat.FailureLatencySum
 				ms.DnsSuccessLatencySum = stat.SuccessLatencySum
 				ms.DnsTimeouts = stat.Timeouts
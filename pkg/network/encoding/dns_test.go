@@ -170,3 +170,93 @@ func TestDNSPIDCollision(t *testing.T) {
 	assert.NotNil(t, out1.DnsStatsByDomain)
 	assert.Nil(t, out2.DnsStatsByDomain)
 }
+
+func TestFormatDNSStatsByRcode(t *testing.T) {
+	const (
+		rcodeServFail = 2
+		rcodeNXDomain = 3
+	)
+
+	key := dns.Key{
+		ClientIP:   util.AddressFromString("10.1.1.1"),
+		ServerIP:   util.AddressFromString("8.8.8.8"),
+		ClientPort: uint16(1000),
+		Protocol:   syscall.IPPROTO_UDP,
+	}
+	payload := &network.Connections{
+		BufferedData: network.BufferedData{
+			Conns: []network.ConnectionStats{
+				{
+					Source:    util.AddressFromString("10.1.1.1"),
+					Dest:      util.AddressFromString("8.8.8.8"),
+					SPort:     1000,
+					DPort:     53,
+					Type:      network.UDP,
+					Family:    network.AFINET,
+					Direction: network.OUTGOING,
+				},
+			},
+		},
+		DNSStats: dns.StatsByKeyByNameByType{
+			key: map[dns.Hostname]map[dns.QueryType]dns.Stats{
+				dns.ToHostname("missing.com"): {
+					dns.TypeA: {
+						FailureLatencySum: 10,
+						CountByRcode:      map[uint32]uint32{rcodeNXDomain: 2},
+					},
+					dns.TypeAAAA: {
+						FailureLatencySum: 5,
+						CountByRcode:      map[uint32]uint32{rcodeNXDomain: 1, rcodeServFail: 1},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("by domain", func(t *testing.T) {
+		config.Datadog.Set("system_probe_config.collect_dns_domains", true)
+		config.Datadog.Set("network_config.enable_dns_by_querytype", false)
+
+		formatter := newDNSFormatter(payload, make(ipCache))
+		out := new(model.Connection)
+		formatter.FormatConnectionDNS(payload.Conns[0], out)
+
+		expected := map[int32]*model.DNSStats{
+			0: {
+				DnsFailureLatencySum: 15,
+				DnsCountByRcode:      map[uint32]uint32{rcodeNXDomain: 3, rcodeServFail: 1},
+			},
+		}
+		assert.Equal(t, expected, out.DnsStatsByDomain)
+
+		// the stats by query type are left untouched
+		byType := payload.DNSStats[key][dns.ToHostname("missing.com")]
+		assert.Equal(t, map[uint32]uint32{rcodeNXDomain: 2}, byType[dns.TypeA].CountByRcode)
+		assert.Equal(t, map[uint32]uint32{rcodeNXDomain: 1, rcodeServFail: 1}, byType[dns.TypeAAAA].CountByRcode)
+	})
+
+	t.Run("by domain and query type", func(t *testing.T) {
+		config.Datadog.Set("system_probe_config.collect_dns_domains", true)
+		config.Datadog.Set("network_config.enable_dns_by_querytype", true)
+
+		formatter := newDNSFormatter(payload, make(ipCache))
+		out := new(model.Connection)
+		formatter.FormatConnectionDNS(payload.Conns[0], out)
+
+		expected := map[int32]*model.DNSStatsByQueryType{
+			0: {
+				DnsStatsByQueryType: map[int32]*model.DNSStats{
+					int32(dns.TypeA): {
+						DnsFailureLatencySum: 10,
+						DnsCountByRcode:      map[uint32]uint32{rcodeNXDomain: 2},
+					},
+					int32(dns.TypeAAAA): {
+						DnsFailureLatencySum: 5,
+						DnsCountByRcode:      map[uint32]uint32{rcodeNXDomain: 1, rcodeServFail: 1},
+					},
+				},
+			},
+		}
+		assert.Equal(t, expected, out.DnsStatsByDomainByQueryType)
+	})
+}
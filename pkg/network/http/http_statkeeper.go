@@ -23,6 +23,9 @@ type httpStatKeeper struct {
 	// replace rules for HTTP path
 	replaceRules []*config.ReplaceRule
 
+	// whether numeric, UUID and hexadecimal path segments are replaced
+	normalizeSegments bool
+
 	// http path buffer
 	buffer []byte
 
@@ -33,13 +36,14 @@ type httpStatKeeper struct {
 
 func newHTTPStatkeeper(c *config.Config, telemetry *telemetry) *httpStatKeeper {
 	return &httpStatKeeper{
-		stats:        make(map[Key]RequestStats),
-		incomplete:   make(map[Key]httpTX),
-		maxEntries:   c.MaxHTTPStatsBuffered,
-		replaceRules: c.HTTPReplaceRules,
-		buffer:       make([]byte, HTTPBufferSize),
-		interned:     make(map[string]string),
-		telemetry:    telemetry,
+		stats:             make(map[Key]RequestStats),
+		incomplete:        make(map[Key]httpTX),
+		maxEntries:        c.MaxHTTPStatsBuffered,
+		replaceRules:      c.HTTPReplaceRules,
+		normalizeSegments: c.HTTPNormalizePathSegments,
+		buffer:            make([]byte, HTTPBufferSize),
+		interned:          make(map[string]string),
+		telemetry:         telemetry,
 	}
 }
 
@@ -145,6 +149,7 @@ func (h *httpStatKeeper) newKey(tx httpTX, path string) Key {
 }
 
 func (h *httpStatKeeper) processHTTPPath(path []byte) (pathStr string, rejected bool) {
+	rewritten := false
 	for _, r := range h.replaceRules {
 		if r.Re.Match(path) {
 			if r.Repl == "" {
@@ -153,9 +158,20 @@ func (h *httpStatKeeper) processHTTPPath(path []byte) (pathStr string, rejected
 			}
 
 			path = r.Re.ReplaceAll(path, []byte(r.Repl))
+			rewritten = true
 		}
 	}
 
+	if h.normalizeSegments {
+		var normalized bool
+		path, normalized = normalizePathSegments(path)
+		rewritten = rewritten || normalized
+	}
+
+	if rewritten {
+		atomic.AddInt64(&h.telemetry.rewritten, 1)
+	}
+
 	return h.intern(path), false
 }
 
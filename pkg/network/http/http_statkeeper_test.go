@@ -201,4 +201,31 @@ func TestPathProcessing(t *testing.T) {
 		}
 	})
 
+	t.Run("normalized segments", func(t *testing.T) {
+		rules := []*config.ReplaceRule{
+			{
+				Re:   regexp.MustCompile("/users/[a-z]+"),
+				Repl: "/users/*",
+			},
+		}
+
+		sk := setupStatKeeper(rules)
+		sk.normalizeSegments = true
+		transactions := []httpTX{
+			generateIPv4HTTPTransaction(sourceIP, destIP, sourcePort, destPort, "/users/ana/orders/123", statusCode, latency),
+			generateIPv4HTTPTransaction(sourceIP, destIP, sourcePort, destPort, "/users/bob/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8", statusCode, latency),
+			generateIPv4HTTPTransaction(sourceIP, destIP, sourcePort, destPort, "/health", statusCode, latency),
+		}
+		sk.Process(transactions)
+		stats := sk.GetAndResetAllStats()
+
+		require.Len(t, stats, 2)
+		paths := make(map[string]int)
+		for key, metrics := range stats {
+			paths[key.Path] = metrics[statusCode/100-1].Count
+		}
+		assert.Equal(t, map[string]int{"/users/*/orders/*": 2, "/health": 1}, paths)
+		assert.Equal(t, int64(2), sk.telemetry.rewritten)
+	})
+
 }
@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+const (
+	// pathSegmentPlaceholder replaces the path segments identified as high-cardinality values.
+	// Unlike `?`, `*` can't be mistaken for the start of a query string.
+	pathSegmentPlaceholder = '*'
+
+	// minHexSegmentLength is the minimum length of a hexadecimal segment (hashes, object ids...)
+	// to be considered as an identifier. It avoids replacing short words like "cafe" or "add".
+	minHexSegmentLength = 16
+)
+
+// normalizePathSegments replaces in place the segments of the path made of a number,
+// an UUID or a long hexadecimal string with a placeholder, so that requests to the
+// same endpoint share the same stats key.
+// Example: /users/123/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8 becomes /users/*/orders/*
+// It returns the normalized path and whether it was modified.
+func normalizePathSegments(path []byte) ([]byte, bool) {
+	var (
+		rewritten bool
+		n         int
+	)
+
+	for i := 0; i < len(path); {
+		if path[i] == '/' {
+			path[n] = '/'
+			n++
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(path) && path[end] != '/' {
+			end++
+		}
+
+		if isIdentifierSegment(path[i:end]) {
+			path[n] = pathSegmentPlaceholder
+			n++
+			rewritten = true
+		} else {
+			n += copy(path[n:], path[i:end])
+		}
+		i = end
+	}
+
+	return path[:n], rewritten
+}
+
+// isIdentifierSegment returns whether a path segment is a number, an UUID or a long hexadecimal string
+func isIdentifierSegment(segment []byte) bool {
+	return isNumeric(segment) || isUUID(segment) || isLongHex(segment)
+}
+
+func isNumeric(segment []byte) bool {
+	if len(segment) == 0 {
+		return false
+	}
+
+	for _, c := range segment {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUID returns whether the segment has the canonical 8-4-4-4-12 UUID format
+func isUUID(segment []byte) bool {
+	if len(segment) != 36 {
+		return false
+	}
+
+	for i, c := range segment {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !isHexDigit(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isLongHex returns whether the segment is an hexadecimal string of at least minHexSegmentLength
+// characters, containing at least one digit
+func isLongHex(segment []byte) bool {
+	if len(segment) < minHexSegmentLength {
+		return false
+	}
+
+	hasDigit := false
+	for _, c := range segment {
+		if !isHexDigit(c) {
+			return false
+		}
+		if c >= '0' && c <= '9' {
+			hasDigit = true
+		}
+	}
+	return hasDigit
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
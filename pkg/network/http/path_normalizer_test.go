@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePathSegments(t *testing.T) {
+	tests := []struct {
+		path      string
+		expected  string
+		rewritten bool
+	}{
+		{path: "/", expected: "/"},
+		{path: "/users", expected: "/users"},
+		{path: "/users/123", expected: "/users/*", rewritten: true},
+		{path: "/users/123/", expected: "/users/*/", rewritten: true},
+		{path: "/users/123/orders/456", expected: "/users/*/orders/*", rewritten: true},
+		{path: "/v2/users", expected: "/v2/users"},
+		{path: "/items/6ba7b810-9dad-11d1-80b4-00c04fd430c8", expected: "/items/*", rewritten: true},
+		{path: "/items/6BA7B810-9DAD-11D1-80B4-00C04FD430C8/details", expected: "/items/*/details", rewritten: true},
+		{path: "/items/6ba7b810-9dad-11d1-80b4-00c04fd430cz", expected: "/items/6ba7b810-9dad-11d1-80b4-00c04fd430cz"},
+		{path: "/commits/0123456789abcdef0123", expected: "/commits/*", rewritten: true},
+		{path: "/cafe", expected: "/cafe"},
+		{path: "/deadbeefdeadbeefdeadbeef", expected: "/deadbeefdeadbeefdeadbeef"},
+		{path: "//42", expected: "//*", rewritten: true},
+		{path: "*", expected: "*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			path, rewritten := normalizePathSegments([]byte(tt.path))
+			assert.Equal(t, tt.expected, string(path))
+			assert.Equal(t, tt.rewritten, rewritten)
+		})
+	}
+}
+
+func BenchmarkNormalizePathSegments(b *testing.B) {
+	path := []byte("/users/123/orders/6ba7b810-9dad-11d1-80b4-00c04fd430c8/items")
+	buffer := make([]byte, len(path))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := copy(buffer, path)
+		normalizePathSegments(buffer[:n])
+	}
+}
@@ -25,6 +25,7 @@ type telemetry struct {
 	misses                                      int64 `stats:"atomic"` // this happens when we can't cope with the rate of events
 	dropped                                     int64 `stats:"atomic"` // this happens when httpStatKeeper reaches capacity
 	rejected                                    int64 `stats:"atomic"` // this happens when an user-defined reject-filter matches a request
+	rewritten                                   int64 `stats:"atomic"` // this happens when a replace rule or the path segment normalization modifies a path
 	malformed                                   int64 `stats:"atomic"` // this happens when the request doesn't have the expected format
 	aggregations                                int64 `stats:"atomic"`
 
@@ -79,6 +80,7 @@ func (t *telemetry) reset() telemetry {
 	delta.misses = atomic.SwapInt64(&t.misses, 0)
 	delta.dropped = atomic.SwapInt64(&t.dropped, 0)
 	delta.rejected = atomic.SwapInt64(&t.rejected, 0)
+	delta.rewritten = atomic.SwapInt64(&t.rewritten, 0)
 	delta.malformed = atomic.SwapInt64(&t.malformed, 0)
 	delta.aggregations = atomic.SwapInt64(&t.aggregations, 0)
 	delta.elapsed = now - then
@@ -92,11 +94,12 @@ func (t *telemetry) report() map[string]interface{} {
 	misses := stats["misses"].(int64)
 	dropped := stats["dropped"].(int64)
 	rejected := stats["rejected"].(int64)
+	rewritten := stats["rewritten"].(int64)
 	aggregations := stats["aggregations"].(int64)
 	totalRequests := stats["hits1_xx"].(int64) + stats["hits2_xx"].(int64) + stats["hits3_xx"].(int64) + stats["hits4_xx"].(int64) + stats["hits5_xx"].(int64)
 
 	log.Debugf(
-		"http stats summary: requests_processed=%d(%.2f/s) requests_missed=%d(%.2f/s) requests_dropped=%d(%.2f/s) requests_rejected=%d(%.2f/s) requests_malformed=%d(%.2f/s) aggregations=%d",
+		"http stats summary: requests_processed=%d(%.2f/s) requests_missed=%d(%.2f/s) requests_dropped=%d(%.2f/s) requests_rejected=%d(%.2f/s) requests_rewritten=%d(%.2f/s) requests_malformed=%d(%.2f/s) aggregations=%d",
 		totalRequests,
 		float64(totalRequests)/float64(t.elapsed),
 		misses,
@@ -105,6 +108,8 @@ func (t *telemetry) report() map[string]interface{} {
 		float64(dropped)/float64(t.elapsed),
 		rejected,
 		float64(rejected)/float64(t.elapsed),
+		rewritten,
+		float64(rewritten)/float64(t.elapsed),
 		t.malformed,
 		float64(t.malformed)/float64(t.elapsed),
 		aggregations,
@@ -0,0 +1,479 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	// maxMessageSize is the maximum size of a message considered valid, the default
+	// socket.request.max.bytes setting of the brokers
+	maxMessageSize = 100 * 1024 * 1024
+
+	// maxAPIVersion is the highest version considered valid for a request type
+	maxAPIVersion = 20
+
+	// maxClientIDLength is the maximum length of a client ID considered valid
+	maxClientIDLength = 1024
+
+	// maxTopicLength is the maximum length of a topic name allowed by the brokers
+	maxTopicLength = 249
+
+	// maxTopicsPerRequest is the maximum number of topics recorded for a request
+	maxTopicsPerRequest = 10
+
+	// maxPendingRequests is the maximum number of requests waiting for a response on a connection
+	maxPendingRequests = 1000
+
+	// requestHeaderLength is the length of the fixed part of a request: size, api key,
+	// api version, correlation id and client id length
+	requestHeaderLength = 14
+
+	// responseHeaderLength is the length of the header of a response: size and correlation id
+	responseHeaderLength = 8
+
+	// last non-flexible versions of the Produce and Fetch requests, flexible versions use
+	// compact encodings which aren't supported for topic extraction
+	maxProduceVersion = 8
+	maxFetchVersion   = 11
+)
+
+var (
+	errTruncated       = errors.New("the kafka message is truncated")
+	errMalformed       = errors.New("the kafka message is malformed")
+	errTooManyRequests = errors.New("too many kafka requests waiting for a response")
+)
+
+// request holds the header of a Kafka request, plus the topics of Produce and Fetch requests
+type request struct {
+	apiKey        APIKey
+	apiVersion    int16
+	correlationID int32
+	topics        []string
+
+	// expectResponse is false for Produce requests with acks=0, as the broker doesn't reply
+	expectResponse bool
+}
+
+// Transaction represents a Kafka request on a topic and its response
+type Transaction struct {
+	Key             Key
+	APIVersion      int16
+	RequestStarted  time.Time
+	ResponseStarted time.Time
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (tx Transaction) RequestLatency() float64 {
+	return float64(tx.ResponseStarted.Sub(tx.RequestStarted).Nanoseconds())
+}
+
+type pendingRequest struct {
+	request
+	started time.Time
+}
+
+// Parser follows the requests and responses exchanged on a Kafka connection to
+// build transactions. It expects the TCP payloads of each direction in order.
+type Parser struct {
+	key     Key
+	pending map[int32]pendingRequest
+
+	client stream
+	server stream
+}
+
+// NewParser returns a parser for the connection identified by the key, its source
+// being the client
+func NewParser(key Key) *Parser {
+	key.Topic = ""
+	key.APIKey = 0
+	return &Parser{
+		key:     key,
+		pending: make(map[int32]pendingRequest),
+	}
+}
+
+// IsRequest returns whether the payload starts with a valid Kafka request
+func IsRequest(payload []byte) bool {
+	_, _, err := parseRequest(payload)
+	return err == nil
+}
+
+// HandleRequests parses the requests sent by the client
+func (p *Parser) HandleRequests(payload []byte, ts time.Time) error {
+	return p.client.messages(payload, func(b []byte) (int, error) {
+		req, size, err := parseRequest(b)
+		if err != nil {
+			return 0, err
+		}
+
+		if !req.expectResponse {
+			return size, nil
+		}
+		if len(p.pending) >= maxPendingRequests {
+			return size, errTooManyRequests
+		}
+
+		p.pending[req.correlationID] = pendingRequest{request: req, started: ts}
+		return size, nil
+	})
+}
+
+// HandleResponses parses the responses sent by the server and returns the transactions
+// they complete, one for each topic of the request
+func (p *Parser) HandleResponses(payload []byte, ts time.Time) ([]Transaction, error) {
+	var transactions []Transaction
+	err := p.server.messages(payload, func(b []byte) (int, error) {
+		correlationID, size, err := parseResponseHeader(b)
+		if err != nil {
+			return 0, err
+		}
+
+		req, ok := p.pending[correlationID]
+		if !ok {
+			return size, nil
+		}
+		delete(p.pending, correlationID)
+
+		tx := Transaction{
+			Key:             p.key,
+			APIVersion:      req.apiVersion,
+			RequestStarted:  req.started,
+			ResponseStarted: ts,
+		}
+		tx.Key.APIKey = req.apiKey
+		if len(req.topics) == 0 {
+			transactions = append(transactions, tx)
+		}
+		for _, topic := range req.topics {
+			tx.Key.Topic = topic
+			transactions = append(transactions, tx)
+		}
+		return size, nil
+	})
+	return transactions, err
+}
+
+// Resync discards the message being read in one direction, after a gap in the TCP stream
+func (p *Parser) Resync(fromClient bool) {
+	if fromClient {
+		p.client.reset()
+	} else {
+		p.server.reset()
+	}
+}
+
+// SkipUncaptured accounts for the last n bytes of a segment that weren't captured, once
+// the captured payload was handled. The parser resynchronizes on the next message if they
+// hold the beginning of a message.
+func (p *Parser) SkipUncaptured(fromClient bool, n int) {
+	if fromClient {
+		p.client.skip(n)
+	} else {
+		p.server.skip(n)
+	}
+}
+
+// stream tracks the boundaries of the messages sent in one direction of a connection,
+// as a message can span several TCP segments and a segment can hold several messages
+type stream struct {
+	// remaining is the number of bytes of the current message not seen yet
+	remaining int
+
+	// header holds the beginning of a message whose header is split across segments
+	header []byte
+}
+
+// messages calls parse with the payload starting at each message beginning in the payload.
+// parse returns the total size of the message, which can exceed the payload.
+func (s *stream) messages(payload []byte, parse func([]byte) (int, error)) error {
+	if len(s.header) > 0 {
+		// complete the header of the message started in the previous segment
+		buffered := len(s.header)
+		b := append(s.header, payload...)
+
+		size, err := parse(b)
+		if err == errTruncated {
+			s.header = b
+			return nil
+		}
+		s.header = nil
+		if err != nil {
+			return err
+		}
+		// the size of the message includes the bytes of the previous segment
+		s.remaining = size - buffered
+	}
+
+	for len(payload) > 0 {
+		if s.remaining > 0 {
+			n := s.remaining
+			if n > len(payload) {
+				n = len(payload)
+			}
+			s.remaining -= n
+			payload = payload[n:]
+			continue
+		}
+
+		size, err := parse(payload)
+		if err == errTruncated {
+			s.header = append([]byte(nil), payload...)
+			return nil
+		}
+		if size > len(payload) {
+			s.remaining = size - len(payload)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		payload = payload[size:]
+	}
+	return nil
+}
+
+// reset discards the message being read
+func (s *stream) reset() {
+	s.remaining = 0
+	s.header = nil
+}
+
+// skip accounts for n bytes of the stream that weren't seen, resetting the stream
+// unless they all belong to the message being read
+func (s *stream) skip(n int) {
+	if len(s.header) > 0 || n > s.remaining {
+		s.reset()
+		return
+	}
+	s.remaining -= n
+}
+
+// parseRequest parses the request at the beginning of b, returning its total size
+func parseRequest(b []byte) (request, int, error) {
+	if len(b) < requestHeaderLength {
+		return request{}, 0, errTruncated
+	}
+
+	size := int32(binary.BigEndian.Uint32(b))
+	if size < requestHeaderLength-4 || size > maxMessageSize {
+		return request{}, 0, errMalformed
+	}
+
+	req := request{
+		apiKey:         APIKey(binary.BigEndian.Uint16(b[4:])),
+		apiVersion:     int16(binary.BigEndian.Uint16(b[6:])),
+		correlationID:  int32(binary.BigEndian.Uint32(b[8:])),
+		expectResponse: true,
+	}
+	if req.apiKey < 0 || req.apiKey > maxAPIKey || req.apiVersion < 0 || req.apiVersion > maxAPIVersion || req.correlationID < 0 {
+		return request{}, 0, errMalformed
+	}
+
+	// the client ID is a nullable string
+	d := decoder{b: b[requestHeaderLength-2 : min(len(b), int(size)+4)]}
+	clientIDLength := d.int16()
+	if clientIDLength < -1 || clientIDLength > maxClientIDLength || int32(clientIDLength) > size-(requestHeaderLength-4) {
+		return request{}, 0, errMalformed
+	}
+	if clientIDLength > 0 {
+		clientID := d.bytes(int(clientIDLength))
+		if !isPrintable(clientID) {
+			return request{}, 0, errMalformed
+		}
+	}
+
+	switch {
+	case req.apiKey == APIKeyProduce && req.apiVersion <= maxProduceVersion:
+		req.topics, req.expectResponse = produceTopics(&d, req.apiVersion)
+	case req.apiKey == APIKeyFetch && req.apiVersion <= maxFetchVersion:
+		req.topics = fetchTopics(&d, req.apiVersion)
+	}
+
+	return req, int(size) + 4, nil
+}
+
+// parseResponseHeader parses the header of the response at the beginning of b,
+// returning its correlation ID and total size
+func parseResponseHeader(b []byte) (int32, int, error) {
+	if len(b) < responseHeaderLength {
+		return 0, 0, errTruncated
+	}
+
+	size := int32(binary.BigEndian.Uint32(b))
+	correlationID := int32(binary.BigEndian.Uint32(b[4:]))
+	if size < responseHeaderLength-4 || size > maxMessageSize || correlationID < 0 {
+		return 0, 0, errMalformed
+	}
+	return correlationID, int(size) + 4, nil
+}
+
+// produceTopics returns the topics of a Produce request, and whether the broker replies to it
+func produceTopics(d *decoder, version int16) ([]string, bool) {
+	if version >= 3 {
+		// transactional ID
+		d.string()
+	}
+	acks := d.int16()
+	if d.truncated {
+		return nil, true
+	}
+	// timeout
+	d.skip(4)
+
+	topics := readTopics(d, func(d *decoder) {
+		partitions := d.int32()
+		for i := int32(0); i < partitions && !d.truncated; i++ {
+			// partition index
+			d.skip(4)
+			// records
+			if records := d.int32(); records > 0 {
+				d.skip(int(records))
+			}
+		}
+	})
+	return topics, acks != 0
+}
+
+// fetchTopics returns the topics of a Fetch request
+func fetchTopics(d *decoder, version int16) []string {
+	// replica ID, max wait and min bytes
+	d.skip(12)
+	if version >= 3 {
+		// max bytes
+		d.skip(4)
+	}
+	if version >= 4 {
+		// isolation level
+		d.skip(1)
+	}
+	if version >= 7 {
+		// session ID and epoch
+		d.skip(8)
+	}
+
+	// partition index, fetch offset and partition max bytes
+	partitionSize := 16
+	if version >= 5 {
+		// log start offset
+		partitionSize += 8
+	}
+	if version >= 9 {
+		// current leader epoch
+		partitionSize += 4
+	}
+
+	return readTopics(d, func(d *decoder) {
+		if partitions := d.int32(); partitions > 0 {
+			d.skip(int(partitions) * partitionSize)
+		}
+	})
+}
+
+// readTopics reads an array of topics, skipping the partitions data of each topic. It
+// returns the topics read before reaching the end of the payload.
+func readTopics(d *decoder, skipPartitions func(*decoder)) []string {
+	count := d.int32()
+	if d.truncated || count <= 0 {
+		return nil
+	}
+
+	var topics []string
+	for i := int32(0); i < count && len(topics) < maxTopicsPerRequest; i++ {
+		topic := d.string()
+		if d.truncated || !isValidTopic(topic) {
+			break
+		}
+		topics = append(topics, topic)
+
+		skipPartitions(d)
+		if d.truncated {
+			break
+		}
+	}
+	return topics
+}
+
+// isValidTopic returns whether the name is a legal topic name
+func isValidTopic(topic string) bool {
+	if len(topic) == 0 || len(topic) > maxTopicLength {
+		return false
+	}
+
+	for i := 0; i < len(topic); i++ {
+		c := topic[i]
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '.' && c != '_' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// decoder reads the big-endian encoded values of a message, it
+// stops reading once the end of the buffer is reached
+type decoder struct {
+	b         []byte
+	truncated bool
+}
+
+func (d *decoder) int16() int16 {
+	b := d.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *decoder) int32() int32 {
+	b := d.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+// string reads a nullable string
+func (d *decoder) string() string {
+	length := d.int16()
+	if length <= 0 {
+		return ""
+	}
+	return string(d.bytes(int(length)))
+}
+
+func (d *decoder) skip(n int) {
+	d.bytes(n)
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.truncated || n > len(d.b) {
+		d.truncated = true
+		return nil
+	}
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
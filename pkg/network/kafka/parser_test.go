@@ -0,0 +1,288 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kafka
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRequest(t *testing.T) {
+	assert.True(t, IsRequest(produceRequest(3, 1, 1, "orders")))
+	assert.True(t, IsRequest(fetchRequest(4, 1, "orders")))
+	assert.True(t, IsRequest(apiVersionsRequest(1)))
+
+	assert.False(t, IsRequest([]byte("GET /index.html HTTP/1.1\r\nHost: localhost\r\n\r\n")))
+	assert.False(t, IsRequest([]byte("Q\x00\x00\x00\x0dSELECT 1;\x00")))
+	assert.False(t, IsRequest([]byte{0, 0, 0, 10, 0}))
+
+	// unknown request type
+	req := apiVersionsRequest(1)
+	binary.BigEndian.PutUint16(req[4:], 1000)
+	assert.False(t, IsRequest(req))
+
+	// client ID longer than the message
+	req = apiVersionsRequest(1)
+	binary.BigEndian.PutUint16(req[12:], 200)
+	assert.False(t, IsRequest(req))
+}
+
+func TestParserProduce(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleRequests(produceRequest(3, 42, 1, "orders", "payments"), now))
+	transactions, err := p.HandleResponses(response(42, 20), now.Add(5*time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+
+	assert.Equal(t, "orders", transactions[0].Key.Topic)
+	assert.Equal(t, "payments", transactions[1].Key.Topic)
+	for _, tx := range transactions {
+		assert.Equal(t, APIKeyProduce, tx.Key.APIKey)
+		assert.Equal(t, int16(3), tx.APIVersion)
+		assert.Equal(t, float64(5*time.Millisecond), tx.RequestLatency())
+		assert.Equal(t, uint16(40000), tx.Key.SrcPort)
+		assert.Equal(t, uint16(9092), tx.Key.DstPort)
+	}
+	assert.Empty(t, p.pending)
+}
+
+func TestParserProduceWithoutAcks(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleRequests(produceRequest(3, 42, 0, "orders"), now))
+	assert.Empty(t, p.pending)
+
+	transactions, err := p.HandleResponses(response(42, 20), now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+}
+
+func TestParserFetch(t *testing.T) {
+	for _, version := range []int16{0, 4, 7, 11} {
+		p := NewParser(testKey())
+		now := time.Now()
+
+		require.NoError(t, p.HandleRequests(fetchRequest(version, 7, "orders"), now))
+		transactions, err := p.HandleResponses(response(7, 100), now.Add(time.Millisecond))
+		require.NoError(t, err)
+		require.Len(t, transactions, 1, "version %d", version)
+
+		assert.Equal(t, APIKeyFetch, transactions[0].Key.APIKey)
+		assert.Equal(t, "orders", transactions[0].Key.Topic)
+	}
+}
+
+func TestParserOtherRequests(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleRequests(apiVersionsRequest(1), now))
+	transactions, err := p.HandleResponses(response(1, 50), now.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	assert.Equal(t, APIKeyAPIVersions, transactions[0].Key.APIKey)
+	assert.Empty(t, transactions[0].Key.Topic)
+}
+
+func TestParserSegmentedMessages(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	// a request split across several segments, including its header, followed by a second
+	// request. The topics are read from the segments holding the beginning of the request.
+	req := append(produceRequest(3, 1, 1, "orders"), fetchRequest(4, 2, "payments")...)
+	require.NoError(t, p.HandleRequests(req[:10], now))
+	require.NoError(t, p.HandleRequests(req[10:50], now))
+	require.NoError(t, p.HandleRequests(req[50:], now))
+	require.Len(t, p.pending, 2)
+
+	// both responses in a single segment, the second one being truncated
+	resp := append(response(1, 10), response(2, 3000)...)
+	transactions, err := p.HandleResponses(resp[:100], now.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, "orders", transactions[0].Key.Topic)
+	assert.Equal(t, "payments", transactions[1].Key.Topic)
+
+	// the rest of the second response is skipped
+	transactions, err = p.HandleResponses(resp[100:], now.Add(time.Millisecond))
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+	assert.Zero(t, p.server.remaining)
+}
+
+func TestParserResync(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	// the end of the first request is lost
+	first := produceRequest(3, 1, 1, "orders")
+	require.NoError(t, p.HandleRequests(first[:20], now))
+	p.Resync(true)
+
+	require.NoError(t, p.HandleRequests(fetchRequest(4, 2, "payments"), now))
+	transactions, err := p.HandleResponses(append(response(1, 10), response(2, 10)...), now)
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+}
+
+func TestParserSkipUncaptured(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	// only the beginning of a large request is captured, the rest of the segment is skipped
+	large := produceRequest(3, 1, 1, "orders")
+	large = append(large, make([]byte, 1000)...)
+	binary.BigEndian.PutUint32(large, uint32(len(large)-4))
+	require.NoError(t, p.HandleRequests(large[:100], now))
+	p.SkipUncaptured(true, 600)
+	require.NoError(t, p.HandleRequests(large[700:], now))
+	require.NoError(t, p.HandleRequests(fetchRequest(4, 2, "payments"), now))
+
+	// only the first bytes of the header of the second response are captured
+	resp := append(response(1, 10), response(2, 10)...)
+	transactions, err := p.HandleResponses(resp[:20], now)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "orders", transactions[0].Key.Topic)
+	p.SkipUncaptured(false, len(resp)-20)
+	assert.Zero(t, p.server.remaining)
+	assert.Empty(t, p.server.header)
+
+	transactions, err = p.HandleResponses(response(3, 10), now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+	assert.Len(t, p.pending, 1)
+}
+
+func TestParserMalformedMessage(t *testing.T) {
+	p := NewParser(testKey())
+	assert.Error(t, p.HandleRequests([]byte("GET / HTTP/1.1\r\n\r\n"), time.Now()))
+	assert.Empty(t, p.pending)
+}
+
+func testKey() Key {
+	return NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 40000, 9092, "", 0)
+}
+
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) int8(v int8) {
+	e.b = append(e.b, byte(v))
+}
+
+func (e *encoder) int16(v int16) {
+	e.b = append(e.b, byte(v>>8), byte(v))
+}
+
+func (e *encoder) int32(v int32) {
+	e.int16(int16(v >> 16))
+	e.int16(int16(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.int32(int32(v >> 32))
+	e.int32(int32(v))
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.b = append(e.b, s...)
+}
+
+// message prefixes the encoded data with its size
+func (e *encoder) message() []byte {
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(e.b)))
+	return append(size, e.b...)
+}
+
+func requestHeader(apiKey APIKey, version int16, correlationID int32) *encoder {
+	e := &encoder{}
+	e.int16(int16(apiKey))
+	e.int16(version)
+	e.int32(correlationID)
+	e.string("test-client")
+	return e
+}
+
+func produceRequest(version int16, correlationID int32, acks int16, topics ...string) []byte {
+	e := requestHeader(APIKeyProduce, version, correlationID)
+	if version >= 3 {
+		// null transactional ID
+		e.int16(-1)
+	}
+	e.int16(acks)
+	e.int32(30000)
+	e.int32(int32(len(topics)))
+	for _, topic := range topics {
+		e.string(topic)
+		e.int32(1)
+		e.int32(0)
+		records := []byte("some records")
+		e.int32(int32(len(records)))
+		e.b = append(e.b, records...)
+	}
+	return e.message()
+}
+
+func fetchRequest(version int16, correlationID int32, topics ...string) []byte {
+	e := requestHeader(APIKeyFetch, version, correlationID)
+	e.int32(-1)
+	e.int32(500)
+	e.int32(1)
+	if version >= 3 {
+		e.int32(52428800)
+	}
+	if version >= 4 {
+		e.int8(0)
+	}
+	if version >= 7 {
+		e.int32(0)
+		e.int32(-1)
+	}
+	e.int32(int32(len(topics)))
+	for _, topic := range topics {
+		e.string(topic)
+		e.int32(1)
+		e.int32(0)
+		if version >= 9 {
+			e.int32(-1)
+		}
+		e.int64(0)
+		if version >= 5 {
+			e.int64(-1)
+		}
+		e.int32(1048576)
+	}
+	if version >= 7 {
+		// forgotten topics
+		e.int32(0)
+	}
+	return e.message()
+}
+
+func apiVersionsRequest(correlationID int32) []byte {
+	return requestHeader(APIKeyAPIVersions, 0, correlationID).message()
+}
+
+func response(correlationID int32, bodyLength int) []byte {
+	e := &encoder{}
+	e.int32(correlationID)
+	e.b = append(e.b, make([]byte, bodyLength)...)
+	return e.message()
+}
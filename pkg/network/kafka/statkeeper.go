@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kafka
+
+import (
+	"sync/atomic"
+)
+
+// StatKeeper aggregates the Kafka transactions by connection, topic and request type
+type StatKeeper struct {
+	stats      map[Key]RequestStats
+	maxEntries int
+	telemetry  *Telemetry
+}
+
+// NewStatKeeper returns a new StatKeeper buffering at most maxEntries stats
+func NewStatKeeper(maxEntries int, telemetry *Telemetry) *StatKeeper {
+	return &StatKeeper{
+		stats:      make(map[Key]RequestStats),
+		maxEntries: maxEntries,
+		telemetry:  telemetry,
+	}
+}
+
+// Process adds transactions to the stats
+func (s *StatKeeper) Process(transactions []Transaction) {
+	for _, tx := range transactions {
+		s.add(tx)
+	}
+
+	atomic.StoreInt64(&s.telemetry.aggregations, int64(len(s.stats)))
+}
+
+// GetAndResetAllStats returns the stats aggregated so far and resets them
+func (s *StatKeeper) GetAndResetAllStats() map[Key]RequestStats {
+	ret := s.stats // No deep copy needed since `s.stats` gets reset
+	s.stats = make(map[Key]RequestStats)
+	return ret
+}
+
+func (s *StatKeeper) add(tx Transaction) {
+	atomic.AddInt64(&s.telemetry.requests, 1)
+
+	stats, ok := s.stats[tx.Key]
+	if !ok && len(s.stats) >= s.maxEntries {
+		atomic.AddInt64(&s.telemetry.dropped, 1)
+		return
+	}
+
+	stats.AddRequest(tx.RequestLatency())
+	s.stats[tx.Key] = stats
+}
@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kafka
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/sketches-go/ddsketch"
+)
+
+// RelativeAccuracy defines the acceptable error in quantile values calculated by DDSketch.
+const RelativeAccuracy = 0.01
+
+// APIKey is the type used to represent the Kafka request types
+type APIKey int16
+
+const (
+	// APIKeyProduce represents the Produce request type
+	APIKeyProduce APIKey = 0
+	// APIKeyFetch represents the Fetch request type
+	APIKeyFetch APIKey = 1
+	// APIKeyListOffsets represents the ListOffsets request type
+	APIKeyListOffsets APIKey = 2
+	// APIKeyMetadata represents the Metadata request type
+	APIKeyMetadata APIKey = 3
+	// APIKeyOffsetCommit represents the OffsetCommit request type
+	APIKeyOffsetCommit APIKey = 8
+	// APIKeyOffsetFetch represents the OffsetFetch request type
+	APIKeyOffsetFetch APIKey = 9
+	// APIKeyFindCoordinator represents the FindCoordinator request type
+	APIKeyFindCoordinator APIKey = 10
+	// APIKeyJoinGroup represents the JoinGroup request type
+	APIKeyJoinGroup APIKey = 11
+	// APIKeyHeartbeat represents the Heartbeat request type
+	APIKeyHeartbeat APIKey = 12
+	// APIKeyLeaveGroup represents the LeaveGroup request type
+	APIKeyLeaveGroup APIKey = 13
+	// APIKeySyncGroup represents the SyncGroup request type
+	APIKeySyncGroup APIKey = 14
+	// APIKeyAPIVersions represents the ApiVersions request type
+	APIKeyAPIVersions APIKey = 18
+
+	// maxAPIKey is the highest request type defined by the Kafka protocol
+	maxAPIKey APIKey = 67
+)
+
+// String returns a string representing the request type
+func (k APIKey) String() string {
+	switch k {
+	case APIKeyProduce:
+		return "Produce"
+	case APIKeyFetch:
+		return "Fetch"
+	case APIKeyListOffsets:
+		return "ListOffsets"
+	case APIKeyMetadata:
+		return "Metadata"
+	case APIKeyOffsetCommit:
+		return "OffsetCommit"
+	case APIKeyOffsetFetch:
+		return "OffsetFetch"
+	case APIKeyFindCoordinator:
+		return "FindCoordinator"
+	case APIKeyJoinGroup:
+		return "JoinGroup"
+	case APIKeyHeartbeat:
+		return "Heartbeat"
+	case APIKeyLeaveGroup:
+		return "LeaveGroup"
+	case APIKeySyncGroup:
+		return "SyncGroup"
+	case APIKeyAPIVersions:
+		return "ApiVersions"
+	default:
+		return "Other"
+	}
+}
+
+// Key is an identifier for a group of Kafka requests
+type Key struct {
+	SrcIPHigh uint64
+	SrcIPLow  uint64
+	SrcPort   uint16
+
+	DstIPHigh uint64
+	DstIPLow  uint64
+	DstPort   uint16
+
+	// Topic is only set for Produce and Fetch requests
+	Topic  string
+	APIKey APIKey
+}
+
+// NewKey generates a new Key
+func NewKey(saddr, daddr util.Address, sport, dport uint16, topic string, apiKey APIKey) Key {
+	saddrl, saddrh := util.ToLowHigh(saddr)
+	daddrl, daddrh := util.ToLowHigh(daddr)
+	return Key{
+		SrcIPHigh: saddrh,
+		SrcIPLow:  saddrl,
+		SrcPort:   sport,
+		DstIPHigh: daddrh,
+		DstIPLow:  daddrl,
+		DstPort:   dport,
+		Topic:     topic,
+		APIKey:    apiKey,
+	}
+}
+
+// RequestStats stores stats for Kafka requests of a given type to a particular topic
+type RequestStats struct {
+	// Count is the number of requests, which can be higher than the count of latencies
+	// as the DDSketch may discard values outside of its range
+	Count     int
+	Latencies *ddsketch.DDSketch
+
+	// FirstLatencySample holds the latency (in nanoseconds) of the first request, the
+	// DDSketch is only created when a second request is added
+	FirstLatencySample float64
+}
+
+// AddRequest adds the latency (in nanoseconds) of a request to the stats
+func (r *RequestStats) AddRequest(latency float64) {
+	r.Count++
+	if r.Count == 1 {
+		// We postpone the creation of histograms when we have only one latency sample
+		r.FirstLatencySample = latency
+		return
+	}
+
+	if r.Latencies == nil {
+		if err := r.initSketch(); err != nil {
+			return
+		}
+
+		// Add the deferred latency sample
+		if err := r.Latencies.Add(r.FirstLatencySample); err != nil {
+			log.Debugf("could not add kafka request latency to ddsketch: %v", err)
+		}
+	}
+
+	if err := r.Latencies.Add(latency); err != nil {
+		log.Debugf("could not add kafka request latency to ddsketch: %v", err)
+	}
+}
+
+// CombineWith merges the data in 2 RequestStats objects
+// newStats is kept as it is, while the method receiver gets mutated
+func (r *RequestStats) CombineWith(newStats RequestStats) {
+	if newStats.Count == 0 {
+		return
+	}
+
+	if newStats.Count == 1 {
+		r.AddRequest(newStats.FirstLatencySample)
+		return
+	}
+
+	if r.Latencies == nil {
+		if err := r.initSketch(); err != nil {
+			return
+		}
+
+		if r.Count == 1 {
+			if err := r.Latencies.Add(r.FirstLatencySample); err != nil {
+				log.Debugf("could not add kafka request latency to ddsketch: %v", err)
+			}
+		}
+	}
+
+	r.Count += newStats.Count
+	if err := r.Latencies.MergeWith(newStats.Latencies); err != nil {
+		log.Debugf("error merging kafka requests: %v", err)
+	}
+}
+
+func (r *RequestStats) initSketch() (err error) {
+	r.Latencies, err = ddsketch.NewDefaultDDSketch(RelativeAccuracy)
+	if err != nil {
+		log.Debugf("error recording kafka request latency: could not create new ddsketch: %v", err)
+	}
+	return
+}
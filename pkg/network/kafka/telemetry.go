@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package kafka
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/stats"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Telemetry holds the counters of the Kafka monitoring
+type Telemetry struct {
+	then    int64
+	elapsed int64
+
+	requests     int64 `stats:"atomic"`
+	dropped      int64 `stats:"atomic"` // this happens when the StatKeeper reaches capacity
+	malformed    int64 `stats:"atomic"` // this happens when a message doesn't have the expected format
+	aggregations int64 `stats:"atomic"`
+
+	reporter stats.Reporter
+}
+
+// NewTelemetry returns a new Telemetry
+func NewTelemetry() (*Telemetry, error) {
+	t := &Telemetry{
+		then: time.Now().Unix(),
+	}
+
+	var err error
+	t.reporter, err = stats.NewReporter(t)
+	if err != nil {
+		return nil, fmt.Errorf("error creating stats reporter: %w", err)
+	}
+
+	return t, nil
+}
+
+// AddMalformed counts messages that couldn't be parsed
+func (t *Telemetry) AddMalformed(n int64) {
+	atomic.AddInt64(&t.malformed, n)
+}
+
+// Reset returns the counters accumulated since the last reset and resets them
+func (t *Telemetry) Reset() *Telemetry {
+	now := time.Now().Unix()
+	then := atomic.SwapInt64(&t.then, now)
+
+	delta, _ := NewTelemetry()
+	delta.requests = atomic.SwapInt64(&t.requests, 0)
+	delta.dropped = atomic.SwapInt64(&t.dropped, 0)
+	delta.malformed = atomic.SwapInt64(&t.malformed, 0)
+	delta.aggregations = atomic.SwapInt64(&t.aggregations, 0)
+	delta.elapsed = now - then
+
+	return delta
+}
+
+// Report logs a summary of the counters and returns them
+func (t *Telemetry) Report() map[string]interface{} {
+	stats := t.reporter.Report()
+
+	requests := stats["requests"].(int64)
+	dropped := stats["dropped"].(int64)
+	malformed := stats["malformed"].(int64)
+	elapsed := float64(t.elapsed)
+	if elapsed == 0 {
+		elapsed = 1
+	}
+
+	log.Debugf(
+		"kafka stats summary: requests_processed=%d(%.2f/s) requests_dropped=%d(%.2f/s) messages_malformed=%d(%.2f/s) aggregations=%d",
+		requests,
+		float64(requests)/elapsed,
+		dropped,
+		float64(dropped)/elapsed,
+		malformed,
+		float64(malformed)/elapsed,
+		stats["aggregations"].(int64),
+	)
+
+	return stats
+}
@@ -0,0 +1,519 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package postgres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+const (
+	// maxMessageSize is the maximum size of a message considered valid
+	maxMessageSize = 1 << 30
+
+	// maxStartupMessageSize is the maximum size of a startup message considered valid
+	maxStartupMessageSize = 10000
+
+	// maxPendingQueries is the maximum number of queries waiting for a response on a connection
+	maxPendingQueries = 1000
+
+	// maxStatements is the maximum number of prepared statements and portals tracked on a connection
+	maxStatements = 1000
+
+	// headerLength is the length of the header of the messages: type and length
+	headerLength = 5
+
+	// startup message codes, sent in place of the protocol version
+	protocolVersion3    = 196608
+	sslRequestCode      = 80877103
+	gssEncRequestCode   = 80877104
+	cancelRequestCode   = 80877102
+	startupHeaderLength = 8
+)
+
+var (
+	errTruncated      = errors.New("the postgres message is truncated")
+	errMalformed      = errors.New("the postgres message is malformed")
+	errTooManyQueries = errors.New("too many postgres queries waiting for a response")
+)
+
+// frontendMessageTypes are the types of the messages sent by clients
+var frontendMessageTypes = [256]bool{
+	'B': true, // Bind
+	'C': true, // Close
+	'D': true, // Describe
+	'E': true, // Execute
+	'F': true, // FunctionCall
+	'H': true, // Flush
+	'P': true, // Parse
+	'Q': true, // Query
+	'S': true, // Sync
+	'X': true, // Terminate
+	'c': true, // CopyDone
+	'd': true, // CopyData
+	'f': true, // CopyFail
+	'p': true, // PasswordMessage, SASLInitialResponse, SASLResponse
+}
+
+// Transaction represents a PostgreSQL query and its completion
+type Transaction struct {
+	Key            Key
+	Failed         bool
+	QueryStarted   time.Time
+	QueryCompleted time.Time
+}
+
+// QueryLatency returns the latency of the query in nanoseconds
+func (tx Transaction) QueryLatency() float64 {
+	return float64(tx.QueryCompleted.Sub(tx.QueryStarted).Nanoseconds())
+}
+
+// pendingQuery is a query sent by the client, or a Sync message of the extended query protocol
+type pendingQuery struct {
+	command Command
+	started time.Time
+	failed  bool
+
+	// simple is true for the queries of the simple query protocol, completed by ReadyForQuery
+	simple bool
+	// sync is true for the Sync messages of the extended query protocol
+	sync bool
+}
+
+// Parser follows the messages exchanged on a PostgreSQL connection to build transactions.
+// It expects the TCP payloads of each direction in order.
+type Parser struct {
+	key     Key
+	pending []pendingQuery
+
+	// commands of the prepared statements and of the portals, by name
+	statements map[string]Command
+	portals    map[string]Command
+
+	// encryptionRequested is set when the client asks for a TLS or GSSAPI encrypted session,
+	// encrypted is set if the server accepts, in which case the messages can't be parsed anymore
+	encryptionRequested bool
+	encrypted           bool
+
+	client stream
+	server stream
+}
+
+// NewParser returns a parser for the connection identified by the key, its source
+// being the client
+func NewParser(key Key) *Parser {
+	key.Command = CommandUnknown
+	return &Parser{
+		key:        key,
+		statements: make(map[string]Command),
+		portals:    make(map[string]Command),
+	}
+}
+
+// IsFrontendMessage returns whether the payload starts with a valid message sent by a client
+func IsFrontendMessage(payload []byte) bool {
+	if len(payload) > 0 && payload[0] == 0 {
+		_, _, err := parseStartupMessage(payload)
+		return err == nil
+	}
+
+	msgType, size, err := parseHeader(payload)
+	if err != nil || !frontendMessageTypes[msgType] {
+		return false
+	}
+
+	// only consider the messages starting a query to be meaningful enough, the end
+	// of the query may not be in the payload
+	body := payload[headerLength:min(len(payload), size)]
+	truncated := size > len(payload)
+	switch msgType {
+	case 'Q':
+		return isQuery(body, truncated)
+	case 'P':
+		// skip the statement name
+		name, ok := readCString(body)
+		return ok && isQuery(body[len(name)+1:], truncated)
+	}
+	return false
+}
+
+// HandleFrontendMessages parses the messages sent by the client
+func (p *Parser) HandleFrontendMessages(payload []byte, ts time.Time) error {
+	if p.encrypted {
+		return nil
+	}
+
+	return p.client.messages(payload, func(b []byte) (int, error) {
+		if b[0] == 0 {
+			code, size, err := parseStartupMessage(b)
+			if err == nil && (code == sslRequestCode || code == gssEncRequestCode) {
+				p.encryptionRequested = true
+			}
+			return size, err
+		}
+
+		msgType, size, err := parseHeader(b)
+		if err != nil {
+			return 0, err
+		}
+		if !frontendMessageTypes[msgType] {
+			return 0, errMalformed
+		}
+
+		body := b[headerLength:min(len(b), size)]
+		switch msgType {
+		case 'Q':
+			query, _ := readCString(body)
+			err = p.push(pendingQuery{command: commandOf(query), started: ts, simple: true})
+		case 'P':
+			name, ok := readCString(body)
+			if ok {
+				query, _ := readCString(body[len(name)+1:])
+				p.setCommand(p.statements, string(name), commandOf(query))
+			}
+		case 'B':
+			portal, ok := readCString(body)
+			if ok {
+				statement, _ := readCString(body[len(portal)+1:])
+				p.setCommand(p.portals, string(portal), p.statements[string(statement)])
+			}
+		case 'E':
+			portal, _ := readCString(body)
+			err = p.push(pendingQuery{command: p.portals[string(portal)], started: ts})
+		case 'S':
+			err = p.push(pendingQuery{sync: true})
+		}
+		return size, err
+	})
+}
+
+// HandleBackendMessages parses the messages sent by the server and returns the
+// transactions of the queries they complete
+func (p *Parser) HandleBackendMessages(payload []byte, ts time.Time) ([]Transaction, error) {
+	if p.encrypted || len(payload) == 0 {
+		return nil, nil
+	}
+
+	// the server answers to the encryption requests with a single byte
+	if p.encryptionRequested {
+		p.encryptionRequested = false
+		switch payload[0] {
+		case 'S', 'G':
+			p.encrypted = true
+			return nil, nil
+		case 'N':
+			payload = payload[1:]
+		}
+	}
+
+	var transactions []Transaction
+	err := p.server.messages(payload, func(b []byte) (int, error) {
+		msgType, size, err := parseHeader(b)
+		if err != nil {
+			return 0, err
+		}
+
+		switch msgType {
+		case 'C', 'I', 's':
+			// CommandComplete, EmptyQueryResponse and PortalSuspended complete the executions
+			// of the extended query protocol, simple queries are completed by ReadyForQuery
+			if len(p.pending) > 0 && !p.pending[0].simple && !p.pending[0].sync {
+				transactions = append(transactions, p.complete(p.pending[0], ts))
+				p.pending = p.pending[1:]
+			}
+		case 'E':
+			if len(p.pending) == 0 || p.pending[0].sync {
+				break
+			}
+			if p.pending[0].simple {
+				p.pending[0].failed = true
+				break
+			}
+			query := p.pending[0]
+			query.failed = true
+			transactions = append(transactions, p.complete(query, ts))
+			p.pending = p.pending[1:]
+		case 'Z':
+			// ReadyForQuery is sent after a simple query or a Sync message. Executions pending
+			// before the Sync message were skipped by the server after an error.
+			for len(p.pending) > 0 {
+				query := p.pending[0]
+				p.pending = p.pending[1:]
+				if query.sync {
+					break
+				}
+				if query.simple {
+					transactions = append(transactions, p.complete(query, ts))
+					break
+				}
+			}
+		}
+		return size, nil
+	})
+	return transactions, err
+}
+
+// Resync discards the message being read in one direction, after a gap in the TCP stream
+func (p *Parser) Resync(fromClient bool) {
+	if fromClient {
+		p.client.reset()
+	} else {
+		p.server.reset()
+	}
+}
+
+// SkipUncaptured accounts for the last n bytes of a segment that weren't captured, once
+// the captured payload was handled. The parser resynchronizes on the next message if they
+// hold the beginning of a message.
+func (p *Parser) SkipUncaptured(fromClient bool, n int) {
+	if fromClient {
+		p.client.skip(n)
+	} else {
+		p.server.skip(n)
+	}
+}
+
+func (p *Parser) push(query pendingQuery) error {
+	if len(p.pending) >= maxPendingQueries {
+		return errTooManyQueries
+	}
+	p.pending = append(p.pending, query)
+	return nil
+}
+
+func (p *Parser) complete(query pendingQuery, ts time.Time) Transaction {
+	tx := Transaction{
+		Key:            p.key,
+		Failed:         query.failed,
+		QueryStarted:   query.started,
+		QueryCompleted: ts,
+	}
+	tx.Key.Command = query.command
+	return tx
+}
+
+func (p *Parser) setCommand(commands map[string]Command, name string, command Command) {
+	if _, ok := commands[name]; !ok && len(commands) >= maxStatements {
+		return
+	}
+	commands[name] = command
+}
+
+// stream tracks the boundaries of the messages sent in one direction of a connection,
+// as a message can span several TCP segments and a segment can hold several messages
+type stream struct {
+	// remaining is the number of bytes of the current message not seen yet
+	remaining int
+
+	// header holds the beginning of a message whose header is split across segments
+	header []byte
+}
+
+// messages calls parse with the payload starting at each message beginning in the payload.
+// parse returns the total size of the message, which can exceed the payload.
+func (s *stream) messages(payload []byte, parse func([]byte) (int, error)) error {
+	if len(s.header) > 0 {
+		// complete the header of the message started in the previous segment
+		buffered := len(s.header)
+		b := append(s.header, payload...)
+
+		size, err := parse(b)
+		if err == errTruncated {
+			s.header = b
+			return nil
+		}
+		s.header = nil
+		if err != nil {
+			return err
+		}
+		// the size of the message includes the bytes of the previous segment
+		s.remaining = size - buffered
+	}
+
+	for len(payload) > 0 {
+		if s.remaining > 0 {
+			n := s.remaining
+			if n > len(payload) {
+				n = len(payload)
+			}
+			s.remaining -= n
+			payload = payload[n:]
+			continue
+		}
+
+		size, err := parse(payload)
+		if err == errTruncated {
+			s.header = append([]byte(nil), payload...)
+			return nil
+		}
+		if size > len(payload) {
+			s.remaining = size - len(payload)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		payload = payload[size:]
+	}
+	return nil
+}
+
+// reset discards the message being read
+func (s *stream) reset() {
+	s.remaining = 0
+	s.header = nil
+}
+
+// skip accounts for n bytes of the stream that weren't seen, resetting the stream
+// unless they all belong to the message being read
+func (s *stream) skip(n int) {
+	if len(s.header) > 0 || n > s.remaining {
+		s.reset()
+		return
+	}
+	s.remaining -= n
+}
+
+// parseHeader parses the header of the typed message at the beginning of b,
+// returning its type and total size
+func parseHeader(b []byte) (byte, int, error) {
+	if len(b) < headerLength {
+		return 0, 0, errTruncated
+	}
+
+	length := int32(binary.BigEndian.Uint32(b[1:]))
+	if length < headerLength-1 || length > maxMessageSize {
+		return 0, 0, errMalformed
+	}
+	return b[0], int(length) + 1, nil
+}
+
+// parseStartupMessage parses the untyped message at the beginning of b, which can be a
+// StartupMessage, a SSLRequest, a GSSENCRequest or a CancelRequest, returning its code and size
+func parseStartupMessage(b []byte) (int32, int, error) {
+	if len(b) < startupHeaderLength {
+		return 0, 0, errTruncated
+	}
+
+	length := int32(binary.BigEndian.Uint32(b))
+	code := int32(binary.BigEndian.Uint32(b[4:]))
+	if length < startupHeaderLength || length > maxStartupMessageSize {
+		return 0, 0, errMalformed
+	}
+
+	switch code {
+	case protocolVersion3, sslRequestCode, gssEncRequestCode, cancelRequestCode:
+		return code, int(length), nil
+	default:
+		return 0, 0, errMalformed
+	}
+}
+
+// readCString returns the null-terminated string at the beginning of b,
+// and whether the terminator was found
+func readCString(b []byte) ([]byte, bool) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return b, false
+	}
+	return b[:i], true
+}
+
+// isQuery returns whether b starts with a null-terminated SQL query, or with the
+// beginning of one if b is truncated
+func isQuery(b []byte, truncated bool) bool {
+	query, ok := readCString(b)
+	if (!ok && !truncated) || len(query) == 0 {
+		return false
+	}
+
+	for _, c := range query {
+		if c < 0x20 && c != '\t' && c != '\n' && c != '\r' {
+			return false
+		}
+	}
+	return commandOf(query) != CommandUnknown
+}
+
+// commandOf returns the type of the command of a query, from its first keyword
+func commandOf(query []byte) Command {
+	query = skipSpacesAndComments(query)
+
+	end := 0
+	for end < len(query) && isLetter(query[end]) {
+		end++
+	}
+	if end == 0 {
+		return CommandUnknown
+	}
+
+	switch string(bytes.ToUpper(query[:end])) {
+	case "SELECT", "WITH", "VALUES", "TABLE", "SHOW", "EXPLAIN":
+		return CommandSelect
+	case "INSERT":
+		return CommandInsert
+	case "UPDATE":
+		return CommandUpdate
+	case "DELETE":
+		return CommandDelete
+	case "BEGIN", "START":
+		return CommandBegin
+	case "COMMIT", "END":
+		return CommandCommit
+	case "ROLLBACK", "ABORT":
+		return CommandRollback
+	case "CREATE":
+		return CommandCreate
+	case "ALTER":
+		return CommandAlter
+	case "DROP":
+		return CommandDrop
+	case "TRUNCATE":
+		return CommandTruncate
+	case "COPY":
+		return CommandCopy
+	default:
+		return CommandOther
+	}
+}
+
+// skipSpacesAndComments skips the leading spaces, comments and opening parenthesis of a query
+func skipSpacesAndComments(query []byte) []byte {
+	for len(query) > 0 {
+		switch {
+		case query[0] == ' ' || query[0] == '\t' || query[0] == '\n' || query[0] == '\r' || query[0] == '(':
+			query = query[1:]
+		case bytes.HasPrefix(query, []byte("--")):
+			end := bytes.IndexByte(query, '\n')
+			if end < 0 {
+				return nil
+			}
+			query = query[end+1:]
+		case bytes.HasPrefix(query, []byte("/*")):
+			end := bytes.Index(query, []byte("*/"))
+			if end < 0 {
+				return nil
+			}
+			query = query[end+2:]
+		default:
+			return query
+		}
+	}
+	return query
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
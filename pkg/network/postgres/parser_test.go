@@ -0,0 +1,322 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package postgres
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFrontendMessage(t *testing.T) {
+	assert.True(t, IsFrontendMessage(startupMessage(protocolVersion3, "user", "postgres", "database", "app")))
+	assert.True(t, IsFrontendMessage(startupMessage(sslRequestCode)))
+	assert.True(t, IsFrontendMessage(query("SELECT * FROM users")))
+	assert.True(t, IsFrontendMessage(parse("stmt1", "  -- comment\nINSERT INTO users VALUES ($1)")))
+	// only the beginning of the query is in the payload
+	assert.True(t, IsFrontendMessage(query("SELECT * FROM users WHERE name = 'some long name'")[:20]))
+
+	assert.False(t, IsFrontendMessage(startupMessage(12345)))
+	assert.False(t, IsFrontendMessage(sync()))
+	assert.False(t, IsFrontendMessage(query("\x01\x02\x03")))
+	assert.False(t, IsFrontendMessage([]byte("GET /index.html HTTP/1.1\r\nHost: localhost\r\n\r\n")))
+	assert.False(t, IsFrontendMessage([]byte("Q\x00\x00")))
+	// the query of a complete message isn't terminated
+	assert.False(t, IsFrontendMessage([]byte("Q\x00\x00\x00\x0cSELECT 1")))
+}
+
+func TestCommandOf(t *testing.T) {
+	for query, command := range map[string]Command{
+		"SELECT 1":                                CommandSelect,
+		"select * from users":                     CommandSelect,
+		"WITH t AS (SELECT 1) SELECT * FROM t":    CommandSelect,
+		"(SELECT 1) UNION (SELECT 2)":             CommandSelect,
+		"/* app:web */ UPDATE users SET a = 1":    CommandUpdate,
+		"-- comment\n\tINSERT INTO users DEFAULT": CommandInsert,
+		"DELETE FROM users":                       CommandDelete,
+		"BEGIN":                                   CommandBegin,
+		"start transaction":                       CommandBegin,
+		"COMMIT":                                  CommandCommit,
+		"ROLLBACK":                                CommandRollback,
+		"CREATE TABLE t (a int)":                  CommandCreate,
+		"ALTER TABLE t ADD b int":                 CommandAlter,
+		"DROP TABLE t":                            CommandDrop,
+		"TRUNCATE t":                              CommandTruncate,
+		"COPY t FROM STDIN":                       CommandCopy,
+		"VACUUM":                                  CommandOther,
+		"":                                        CommandUnknown,
+		"/* unterminated":                         CommandUnknown,
+	} {
+		assert.Equal(t, command, commandOf([]byte(query)), query)
+	}
+}
+
+func TestParserSimpleQuery(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleFrontendMessages(query("SELECT * FROM users"), now))
+	transactions, err := p.HandleBackendMessages(concat(
+		backendMessage('T', 20),
+		backendMessage('D', 10),
+		commandComplete("SELECT 1"),
+		readyForQuery(),
+	), now.Add(3*time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	tx := transactions[0]
+	assert.Equal(t, CommandSelect, tx.Key.Command)
+	assert.False(t, tx.Failed)
+	assert.Equal(t, float64(3*time.Millisecond), tx.QueryLatency())
+	assert.Equal(t, uint16(40000), tx.Key.SrcPort)
+	assert.Equal(t, uint16(5432), tx.Key.DstPort)
+	assert.Empty(t, p.pending)
+}
+
+func TestParserSimpleQueryError(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleFrontendMessages(query("INSERT INTO users VALUES (1)"), now))
+	transactions, err := p.HandleBackendMessages(concat(backendMessage('E', 40), readyForQuery()), now)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	assert.Equal(t, CommandInsert, transactions[0].Key.Command)
+	assert.True(t, transactions[0].Failed)
+}
+
+func TestParserExtendedQuery(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	// the statement is prepared once and executed twice
+	require.NoError(t, p.HandleFrontendMessages(concat(
+		parse("stmt1", "UPDATE users SET name = $1 WHERE id = $2"),
+		bind("", "stmt1"),
+		execute(""),
+		bind("portal1", "stmt1"),
+		execute("portal1"),
+		sync(),
+	), now))
+	require.Len(t, p.pending, 3)
+
+	transactions, err := p.HandleBackendMessages(concat(
+		backendMessage('1', 0),
+		backendMessage('2', 0),
+		commandComplete("UPDATE 1"),
+		backendMessage('2', 0),
+		commandComplete("UPDATE 1"),
+		readyForQuery(),
+	), now.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+
+	for _, tx := range transactions {
+		assert.Equal(t, CommandUpdate, tx.Key.Command)
+		assert.False(t, tx.Failed)
+	}
+	assert.Empty(t, p.pending)
+}
+
+func TestParserExtendedQueryError(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleFrontendMessages(concat(
+		parse("", "DELETE FROM users"),
+		bind("", ""),
+		execute(""),
+		parse("", "SELECT 1"),
+		bind("", ""),
+		execute(""),
+		sync(),
+	), now))
+
+	// the server skips the messages following an error until the Sync message
+	transactions, err := p.HandleBackendMessages(concat(
+		backendMessage('1', 0),
+		backendMessage('2', 0),
+		backendMessage('E', 40),
+		readyForQuery(),
+	), now)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	assert.Equal(t, CommandDelete, transactions[0].Key.Command)
+	assert.True(t, transactions[0].Failed)
+	assert.Empty(t, p.pending)
+}
+
+func TestParserSegmentedMessages(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	q := query("SELECT * FROM users WHERE name = 'some long name'")
+	require.NoError(t, p.HandleFrontendMessages(q[:3], now))
+	require.NoError(t, p.HandleFrontendMessages(q[3:20], now))
+	require.NoError(t, p.HandleFrontendMessages(concat(q[20:], query("COMMIT")), now))
+	require.Len(t, p.pending, 2)
+
+	resp := concat(backendMessage('D', 500), commandComplete("SELECT 1"), readyForQuery(), commandComplete("COMMIT"), readyForQuery())
+	transactions, err := p.HandleBackendMessages(resp[:100], now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	transactions, err = p.HandleBackendMessages(resp[100:], now)
+	require.NoError(t, err)
+	require.Len(t, transactions, 2)
+	assert.Equal(t, CommandSelect, transactions[0].Key.Command)
+	assert.Equal(t, CommandCommit, transactions[1].Key.Command)
+}
+
+func TestParserSkipUncaptured(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	// only the beginning of a large query is captured, the rest of the segment is skipped
+	q := query("INSERT INTO users VALUES ('" + strings.Repeat("a", 1000) + "')")
+	require.NoError(t, p.HandleFrontendMessages(q[:100], now))
+	p.SkipUncaptured(true, 500)
+	require.NoError(t, p.HandleFrontendMessages(q[600:], now))
+	require.Len(t, p.pending, 1)
+
+	// the rows returned don't fit in the captured part of the segment
+	resp := concat(backendMessage('D', 2000), commandComplete("INSERT 0 1"), readyForQuery())
+	transactions, err := p.HandleBackendMessages(resp[:100], now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+	p.SkipUncaptured(false, 1500)
+
+	transactions, err = p.HandleBackendMessages(resp[1600:], now)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, CommandInsert, transactions[0].Key.Command)
+}
+
+func TestParserStartup(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	// the server declines the SSL request
+	require.NoError(t, p.HandleFrontendMessages(startupMessage(sslRequestCode), now))
+	transactions, err := p.HandleBackendMessages([]byte("N"), now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	require.NoError(t, p.HandleFrontendMessages(startupMessage(protocolVersion3, "user", "postgres"), now))
+	transactions, err = p.HandleBackendMessages(concat(backendMessage('R', 4), backendMessage('S', 20), readyForQuery()), now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	require.NoError(t, p.HandleFrontendMessages(query("SELECT 1"), now))
+	transactions, err = p.HandleBackendMessages(concat(commandComplete("SELECT 1"), readyForQuery()), now)
+	require.NoError(t, err)
+	assert.Len(t, transactions, 1)
+}
+
+func TestParserEncryptedSession(t *testing.T) {
+	p := NewParser(testKey())
+	now := time.Now()
+
+	require.NoError(t, p.HandleFrontendMessages(startupMessage(sslRequestCode), now))
+	transactions, err := p.HandleBackendMessages([]byte("S"), now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+
+	// the TLS handshake and the encrypted messages are ignored
+	require.NoError(t, p.HandleFrontendMessages([]byte{0x16, 0x03, 0x01, 0x02, 0x00}, now))
+	transactions, err = p.HandleBackendMessages([]byte{0x16, 0x03, 0x03, 0x00, 0x7a}, now)
+	require.NoError(t, err)
+	assert.Empty(t, transactions)
+}
+
+func TestParserMalformedMessage(t *testing.T) {
+	p := NewParser(testKey())
+	assert.Error(t, p.HandleFrontendMessages([]byte("GET / HTTP/1.1\r\n\r\n"), time.Now()))
+	assert.Empty(t, p.pending)
+}
+
+func testKey() Key {
+	return NewKey(util.AddressFromString("1.1.1.1"), util.AddressFromString("2.2.2.2"), 40000, 5432, CommandUnknown)
+}
+
+func concat(messages ...[]byte) []byte {
+	var b []byte
+	for _, m := range messages {
+		b = append(b, m...)
+	}
+	return b
+}
+
+func message(msgType byte, body []byte) []byte {
+	b := make([]byte, headerLength, headerLength+len(body))
+	b[0] = msgType
+	binary.BigEndian.PutUint32(b[1:], uint32(len(body)+4))
+	return append(b, body...)
+}
+
+func cstrings(values ...string) []byte {
+	var b []byte
+	for _, v := range values {
+		b = append(b, v...)
+		b = append(b, 0)
+	}
+	return b
+}
+
+func startupMessage(code int32, parameters ...string) []byte {
+	body := cstrings(parameters...)
+	if code == protocolVersion3 {
+		body = append(body, 0)
+	}
+
+	b := make([]byte, startupHeaderLength, startupHeaderLength+len(body))
+	binary.BigEndian.PutUint32(b, uint32(startupHeaderLength+len(body)))
+	binary.BigEndian.PutUint32(b[4:], uint32(code))
+	return append(b, body...)
+}
+
+func query(q string) []byte {
+	return message('Q', cstrings(q))
+}
+
+func parse(statement, q string) []byte {
+	// no parameter types
+	return message('P', append(cstrings(statement, q), 0, 0))
+}
+
+func bind(portal, statement string) []byte {
+	// no parameter formats, no parameters and no result formats
+	return message('B', append(cstrings(portal, statement), 0, 0, 0, 0, 0, 0))
+}
+
+func execute(portal string) []byte {
+	// no row limit
+	return message('E', append(cstrings(portal), 0, 0, 0, 0))
+}
+
+func sync() []byte {
+	return message('S', nil)
+}
+
+func backendMessage(msgType byte, length int) []byte {
+	return message(msgType, make([]byte, length))
+}
+
+func commandComplete(tag string) []byte {
+	return message('C', cstrings(tag))
+}
+
+func readyForQuery() []byte {
+	return message('Z', []byte{'I'})
+}
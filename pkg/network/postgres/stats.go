@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package postgres
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/sketches-go/ddsketch"
+)
+
+// RelativeAccuracy defines the acceptable error in quantile values calculated by DDSketch.
+const RelativeAccuracy = 0.01
+
+// Command is the type used to represent the type of the SQL commands
+type Command int
+
+const (
+	// CommandUnknown represents a command which couldn't be identified
+	CommandUnknown Command = iota
+	// CommandSelect represents the SELECT command
+	CommandSelect
+	// CommandInsert represents the INSERT command
+	CommandInsert
+	// CommandUpdate represents the UPDATE command
+	CommandUpdate
+	// CommandDelete represents the DELETE command
+	CommandDelete
+	// CommandBegin represents the BEGIN and START TRANSACTION commands
+	CommandBegin
+	// CommandCommit represents the COMMIT and END commands
+	CommandCommit
+	// CommandRollback represents the ROLLBACK and ABORT commands
+	CommandRollback
+	// CommandCreate represents the CREATE commands
+	CommandCreate
+	// CommandAlter represents the ALTER commands
+	CommandAlter
+	// CommandDrop represents the DROP commands
+	CommandDrop
+	// CommandTruncate represents the TRUNCATE command
+	CommandTruncate
+	// CommandCopy represents the COPY command
+	CommandCopy
+	// CommandOther represents the other commands
+	CommandOther
+)
+
+// String returns a string representing the command
+func (c Command) String() string {
+	switch c {
+	case CommandSelect:
+		return "SELECT"
+	case CommandInsert:
+		return "INSERT"
+	case CommandUpdate:
+		return "UPDATE"
+	case CommandDelete:
+		return "DELETE"
+	case CommandBegin:
+		return "BEGIN"
+	case CommandCommit:
+		return "COMMIT"
+	case CommandRollback:
+		return "ROLLBACK"
+	case CommandCreate:
+		return "CREATE"
+	case CommandAlter:
+		return "ALTER"
+	case CommandDrop:
+		return "DROP"
+	case CommandTruncate:
+		return "TRUNCATE"
+	case CommandCopy:
+		return "COPY"
+	case CommandOther:
+		return "OTHER"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Key is an identifier for a group of PostgreSQL queries
+type Key struct {
+	SrcIPHigh uint64
+	SrcIPLow  uint64
+	SrcPort   uint16
+
+	DstIPHigh uint64
+	DstIPLow  uint64
+	DstPort   uint16
+
+	Command Command
+}
+
+// NewKey generates a new Key
+func NewKey(saddr, daddr util.Address, sport, dport uint16, command Command) Key {
+	saddrl, saddrh := util.ToLowHigh(saddr)
+	daddrl, daddrh := util.ToLowHigh(daddr)
+	return Key{
+		SrcIPHigh: saddrh,
+		SrcIPLow:  saddrl,
+		SrcPort:   sport,
+		DstIPHigh: daddrh,
+		DstIPLow:  daddrl,
+		DstPort:   dport,
+		Command:   command,
+	}
+}
+
+// RequestStats stores stats for PostgreSQL queries of a given command type
+type RequestStats struct {
+	// Count is the number of queries, which can be higher than the count of latencies
+	// as the DDSketch may discard values outside of its range
+	Count int
+	// ErrorCount is the number of queries which failed
+	ErrorCount int
+	Latencies  *ddsketch.DDSketch
+
+	// FirstLatencySample holds the latency (in nanoseconds) of the first query, the
+	// DDSketch is only created when a second query is added
+	FirstLatencySample float64
+}
+
+// AddRequest adds the latency (in nanoseconds) and the outcome of a query to the stats
+func (r *RequestStats) AddRequest(latency float64, failed bool) {
+	if failed {
+		r.ErrorCount++
+	}
+	r.addLatency(latency)
+}
+
+// CombineWith merges the data in 2 RequestStats objects
+// newStats is kept as it is, while the method receiver gets mutated
+func (r *RequestStats) CombineWith(newStats RequestStats) {
+	if newStats.Count == 0 {
+		return
+	}
+
+	r.ErrorCount += newStats.ErrorCount
+	if newStats.Count == 1 {
+		r.addLatency(newStats.FirstLatencySample)
+		return
+	}
+
+	if r.Latencies == nil {
+		if err := r.initSketch(); err != nil {
+			return
+		}
+
+		if r.Count == 1 {
+			if err := r.Latencies.Add(r.FirstLatencySample); err != nil {
+				log.Debugf("could not add postgres query latency to ddsketch: %v", err)
+			}
+		}
+	}
+
+	r.Count += newStats.Count
+	if err := r.Latencies.MergeWith(newStats.Latencies); err != nil {
+		log.Debugf("error merging postgres queries: %v", err)
+	}
+}
+
+func (r *RequestStats) addLatency(latency float64) {
+	r.Count++
+	if r.Count == 1 {
+		// We postpone the creation of histograms when we have only one latency sample
+		r.FirstLatencySample = latency
+		return
+	}
+
+	if r.Latencies == nil {
+		if err := r.initSketch(); err != nil {
+			return
+		}
+
+		// Add the deferred latency sample
+		if err := r.Latencies.Add(r.FirstLatencySample); err != nil {
+			log.Debugf("could not add postgres query latency to ddsketch: %v", err)
+		}
+	}
+
+	if err := r.Latencies.Add(latency); err != nil {
+		log.Debugf("could not add postgres query latency to ddsketch: %v", err)
+	}
+}
+
+func (r *RequestStats) initSketch() (err error) {
+	r.Latencies, err = ddsketch.NewDefaultDDSketch(RelativeAccuracy)
+	if err != nil {
+		log.Debugf("error recording postgres query latency: could not create new ddsketch: %v", err)
+	}
+	return
+}
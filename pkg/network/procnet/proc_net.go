@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+// Package procnet parses the socket tables of /proc/net/{tcp,tcp6,udp,udp6}
+package procnet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/native"
+)
+
+const (
+	// TCPListen is the state of the listening TCP sockets
+	TCPListen int64 = 10
+
+	// TCPClose is also used to indicate a UDP connection where the other end hasn't been established
+	TCPClose int64 = 7
+)
+
+// Socket is an entry of a /proc/net/ socket table
+type Socket struct {
+	// LocalAddress is the hexadecimal "address:port" representation of the local end of the socket,
+	// it is only valid until the callback passed to ReadWithState returns
+	LocalAddress []byte
+	Port         uint16
+	Inode        uint64
+}
+
+// ReadWithState reads a /proc/net/ file and calls fn for each socket in the given state
+func ReadWithState(path string, state int64, fn func(Socket)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	reader := bufio.NewReader(f)
+
+	// Skip header line
+	_, _ = reader.ReadBytes('\n')
+
+	for {
+		b, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		iter := &fieldIterator{data: b}
+		iter.nextField() // entry number
+
+		rawLocal := iter.nextField() // local_address
+
+		iter.nextField() // remote_address
+
+		rawState := iter.nextField() // st
+
+		st, err := strconv.ParseInt(string(rawState), 16, 0)
+		if err != nil {
+			log.Errorf("error parsing tcp state [%s] as hex: %s", rawState, err)
+			continue
+		}
+
+		if st != state {
+			continue
+		}
+
+		idx := bytes.IndexByte(rawLocal, ':')
+		if idx == -1 {
+			continue
+		}
+
+		port, err := strconv.ParseUint(string(rawLocal[idx+1:]), 16, 16)
+		if err != nil {
+			log.Errorf("error parsing port [%s] as hex: %s", rawLocal[idx+1:], err)
+			continue
+		}
+
+		iter.nextField() // tx_queue:rx_queue
+		iter.nextField() // tr:tm->when
+		iter.nextField() // retrnsmt
+		iter.nextField() // uid
+		iter.nextField() // timeout
+
+		// the inode is left to 0 when it can't be parsed, callers only interested in the port don't need it
+		inode, _ := strconv.ParseUint(string(iter.nextField()), 10, 64)
+
+		fn(Socket{
+			LocalAddress: rawLocal,
+			Port:         uint16(port),
+			Inode:        inode,
+		})
+	}
+
+	return nil
+}
+
+// ParseAddress parses the hexadecimal "address:port" representation of a socket address.
+// The address is printed as a sequence of 32 bits words in host byte order.
+func ParseAddress(raw []byte) (net.IP, uint16, error) {
+	idx := bytes.IndexByte(raw, ':')
+	if idx == -1 {
+		return nil, 0, fmt.Errorf("no port in address [%s]", raw)
+	}
+
+	port, err := strconv.ParseUint(string(raw[idx+1:]), 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error parsing port [%s] as hex: %w", raw[idx+1:], err)
+	}
+
+	ip := make(net.IP, hex.DecodedLen(idx))
+	if _, err := hex.Decode(ip, raw[:idx]); err != nil {
+		return nil, 0, fmt.Errorf("error parsing address [%s] as hex: %w", raw[:idx], err)
+	}
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil, 0, fmt.Errorf("invalid address length [%s]", raw[:idx])
+	}
+	for i := 0; i < len(ip); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], native.Endian.Uint32(ip[i:]))
+	}
+
+	return ip, uint16(port), nil
+}
+
+type fieldIterator struct {
+	data []byte
+}
+
+func (iter *fieldIterator) nextField() []byte {
+	// Skip any leading whitespace
+	for i, b := range iter.data {
+		if b != ' ' {
+			iter.data = iter.data[i:]
+			break
+		}
+	}
+
+	// Read field up until the first whitespace char
+	var result []byte
+	for i, b := range iter.data {
+		if b == ' ' {
+			result = iter.data[:i]
+			iter.data = iter.data[i:]
+			break
+		}
+	}
+
+	return result
+}
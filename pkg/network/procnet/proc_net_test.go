@@ -0,0 +1,71 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package procnet
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadWithState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tcp")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0200007F:B600 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 61632 1 ffff88003cc20780 100 0 0 10 0
+   1: 00000000:A160 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 16753 1 ffff880034e46d00 100 0 0 10 0
+   8: 0F02000A:0016 0202000A:C121 01 00000000:00000000 02:00091FA3 00000000     0        0 20179 3 ffff88003cc20000 20 4 1 10 -1
+`), 0644))
+
+	type socket struct {
+		address string
+		port    uint16
+		inode   uint64
+	}
+	var sockets []socket
+	err := ReadWithState(path, TCPListen, func(s Socket) {
+		sockets = append(sockets, socket{address: string(s.LocalAddress), port: s.Port, inode: s.Inode})
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []socket{
+		{address: "0200007F:B600", port: 46592, inode: 61632},
+		{address: "00000000:A160", port: 41312, inode: 16753},
+	}, sockets)
+}
+
+func TestParseAddress(t *testing.T) {
+	for _, tc := range []struct {
+		raw     string
+		address string
+		port    uint16
+		ok      bool
+	}{
+		{raw: "00000000:0050", address: "0.0.0.0", port: 80, ok: true},
+		{raw: "0100007F:18EB", address: "127.0.0.1", port: 6379, ok: true},
+		{raw: "0A00A8C0:1F90", address: "192.168.0.10", port: 8080, ok: true},
+		{raw: "00000000000000000000000000000000:1538", address: "::", port: 5432, ok: true},
+		{raw: "00000000000000000000000001000000:0035", address: "::1", port: 53, ok: true},
+		{raw: "0000000000000000FFFF00000100007F:0016", address: "127.0.0.1", port: 22, ok: true},
+		{raw: "00000000", ok: false},
+		{raw: "0000:0050", ok: false},
+		{raw: "ZZ000000:0050", ok: false},
+		{raw: "00000000:FFFFF", ok: false},
+	} {
+		ip, port, err := ParseAddress([]byte(tc.raw))
+		if !tc.ok {
+			assert.Error(t, err, tc.raw)
+			continue
+		}
+		require.NoError(t, err, tc.raw)
+		assert.Equal(t, tc.address, ip.String(), tc.raw)
+		assert.Equal(t, tc.port, port, tc.raw)
+	}
+}
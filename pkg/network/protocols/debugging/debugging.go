@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package debugging
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/network/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/postgres"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/sketches-go/ddsketch"
+)
+
+// Address represents represents a IP:Port
+type Address struct {
+	IP   string
+	Port uint16
+}
+
+// KafkaSummary represents a (debug-friendly) aggregated view of Kafka requests
+// matching a (client, server, topic, api key) tuple
+type KafkaSummary struct {
+	Client             Address
+	Server             Address
+	Topic              string
+	APIKey             string
+	Count              int
+	FirstLatencySample float64
+	LatencyP50         float64
+}
+
+// PostgresSummary represents a (debug-friendly) aggregated view of PostgreSQL queries
+// matching a (client, server, command) tuple
+type PostgresSummary struct {
+	Client             Address
+	Server             Address
+	Command            string
+	Count              int
+	ErrorCount         int
+	FirstLatencySample float64
+	LatencyP50         float64
+}
+
+// Kafka returns a debug-friendly representation of map[kafka.Key]kafka.RequestStats
+func Kafka(stats map[kafka.Key]kafka.RequestStats) []KafkaSummary {
+	all := make([]KafkaSummary, 0, len(stats))
+	for k, v := range stats {
+		all = append(all, KafkaSummary{
+			Client:             formatAddress(k.SrcIPLow, k.SrcIPHigh, k.SrcPort),
+			Server:             formatAddress(k.DstIPLow, k.DstIPHigh, k.DstPort),
+			Topic:              k.Topic,
+			APIKey:             k.APIKey.String(),
+			Count:              v.Count,
+			FirstLatencySample: v.FirstLatencySample,
+			LatencyP50:         getSketchQuantile(v.Latencies, 0.5),
+		})
+	}
+	return all
+}
+
+// Postgres returns a debug-friendly representation of map[postgres.Key]postgres.RequestStats
+func Postgres(stats map[postgres.Key]postgres.RequestStats) []PostgresSummary {
+	all := make([]PostgresSummary, 0, len(stats))
+	for k, v := range stats {
+		all = append(all, PostgresSummary{
+			Client:             formatAddress(k.SrcIPLow, k.SrcIPHigh, k.SrcPort),
+			Server:             formatAddress(k.DstIPLow, k.DstIPHigh, k.DstPort),
+			Command:            k.Command.String(),
+			Count:              v.Count,
+			ErrorCount:         v.ErrorCount,
+			FirstLatencySample: v.FirstLatencySample,
+			LatencyP50:         getSketchQuantile(v.Latencies, 0.5),
+		})
+	}
+	return all
+}
+
+func formatAddress(low, high uint64, port uint16) Address {
+	// The keys don't hold the socket family, the address is assumed
+	// to be IPv6 only if higher order bits are set
+	var addr util.Address
+	if high > 0 || (low>>32) > 0 {
+		addr = util.V6Address(low, high)
+	} else {
+		addr = util.V4Address(uint32(low))
+	}
+
+	return Address{
+		IP:   addr.String(),
+		Port: port,
+	}
+}
+
+func getSketchQuantile(sketch *ddsketch.DDSketch, quantile float64) float64 {
+	if sketch == nil {
+		return 0.0
+	}
+
+	val, _ := sketch.GetValueAtQuantile(quantile)
+	return val
+}
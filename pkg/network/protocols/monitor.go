@@ -0,0 +1,440 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package protocols
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/postgres"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+const (
+	// maxClassificationAttempts is the number of payloads inspected before giving up
+	// on identifying the protocol of a connection
+	maxClassificationAttempts = 3
+
+	// maxTrackedConnections is the maximum number of connections tracked at the same time
+	maxTrackedConnections = 65536
+
+	// connectionTimeout is the duration after which an idle connection stops being tracked
+	connectionTimeout = 2 * time.Minute
+)
+
+// packetSource reads raw packet data
+type packetSource interface {
+	// VisitPackets reads all new raw packets that are available, invoking the given callback for each packet.
+	// If no packet is available, VisitPacket returns immediately.
+	// The data buffer is reused between invocations of VisitPacket and thus should not be pointed to.
+	// If the cancel channel is closed, VisitPackets will stop reading.
+	VisitPackets(cancel <-chan struct{}, visitor func(data []byte, timestamp time.Time) error) error
+
+	// PacketType returns the type of packet this source reads
+	PacketType() gopacket.LayerType
+
+	// Close closes the packet source
+	Close()
+}
+
+// connTuple identifies a connection, its endpoints being ordered so that both
+// directions of the connection share the same tuple
+type connTuple struct {
+	aIPHigh, aIPLow uint64
+	bIPHigh, bIPLow uint64
+	aPort, bPort    uint16
+}
+
+type connection struct {
+	protocol Protocol
+	attempts int
+
+	// clientIsA is true when the client is the first endpoint of the tuple
+	clientIsA bool
+
+	// next expected sequence numbers, from the client and from the server
+	nextSeq  [2]uint32
+	seqKnown [2]bool
+
+	kafka    *kafka.Parser
+	postgres *postgres.Parser
+	lastSeen time.Time
+}
+
+// Monitor reads the TCP packets of a packet source to identify the protocol of
+// the connections, and aggregates the Kafka requests and PostgreSQL queries made
+// on the identified connections
+type Monitor struct {
+	source packetSource
+
+	decoder *gopacket.DecodingLayerParser
+	layers  []gopacket.LayerType
+	ipv4    *layers.IPv4
+	ipv6    *layers.IPv6
+	tcp     *layers.TCP
+
+	kafkaEnabled    bool
+	postgresEnabled bool
+
+	kafkaStatKeeper    *kafka.StatKeeper
+	kafkaTelemetry     *kafka.Telemetry
+	postgresStatKeeper *postgres.StatKeeper
+	postgresTelemetry  *postgres.Telemetry
+
+	// telemetry
+	decodingErrors int64
+	untracked      int64
+
+	// mux protects the connections and the stat keepers
+	mux         sync.Mutex
+	connections map[connTuple]*connection
+
+	exit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newMonitor returns a new Monitor reading the packets of the given source
+func newMonitor(c *config.Config, source packetSource) (*Monitor, error) {
+	kafkaTelemetry, err := kafka.NewTelemetry()
+	if err != nil {
+		return nil, err
+	}
+	postgresTelemetry, err := postgres.NewTelemetry()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Monitor{
+		source:             source,
+		ipv4:               &layers.IPv4{},
+		ipv6:               &layers.IPv6{},
+		tcp:                &layers.TCP{},
+		kafkaEnabled:       c.EnableKafkaMonitoring,
+		postgresEnabled:    c.EnablePostgresMonitoring,
+		kafkaStatKeeper:    kafka.NewStatKeeper(c.MaxKafkaStatsBuffered, kafkaTelemetry),
+		kafkaTelemetry:     kafkaTelemetry,
+		postgresStatKeeper: postgres.NewStatKeeper(c.MaxPostgresStatsBuffered, postgresTelemetry),
+		postgresTelemetry:  postgresTelemetry,
+		connections:        make(map[connTuple]*connection),
+		exit:               make(chan struct{}),
+	}
+
+	m.decoder = gopacket.NewDecodingLayerParser(source.PacketType(), &layers.Ethernet{}, m.ipv4, m.ipv6, m.tcp)
+	m.decoder.IgnoreUnsupported = true
+
+	return m, nil
+}
+
+// Start starts consuming packets
+func (m *Monitor) Start() {
+	if m == nil {
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.pollPackets()
+	}()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(connectionTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				m.expireConnections(now)
+			case <-m.exit:
+				return
+			}
+		}
+	}()
+}
+
+// GetKafkaStats returns the stats of the Kafka requests aggregated since the last call,
+// in the following format: [source, dest tuple, topic, api key] -> RequestStats object
+func (m *Monitor) GetKafkaStats() map[kafka.Key]kafka.RequestStats {
+	if m == nil || !m.kafkaEnabled {
+		return nil
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.kafkaTelemetry.Reset().Report()
+	return m.kafkaStatKeeper.GetAndResetAllStats()
+}
+
+// GetPostgresStats returns the stats of the PostgreSQL queries aggregated since the last call,
+// in the following format: [source, dest tuple, command] -> RequestStats object
+func (m *Monitor) GetPostgresStats() map[postgres.Key]postgres.RequestStats {
+	if m == nil || !m.postgresEnabled {
+		return nil
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.postgresTelemetry.Reset().Report()
+	return m.postgresStatKeeper.GetAndResetAllStats()
+}
+
+// GetStats returns stats for use with telemetry
+func (m *Monitor) GetStats() map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+
+	m.mux.Lock()
+	connections := len(m.connections)
+	m.mux.Unlock()
+
+	return map[string]interface{}{
+		"tracked_connections":   connections,
+		"untracked_connections": atomic.LoadInt64(&m.untracked),
+		"decoding_errors":       atomic.LoadInt64(&m.decodingErrors),
+	}
+}
+
+// Stop stops consuming packets and closes the packet source
+func (m *Monitor) Stop() {
+	if m == nil {
+		return
+	}
+
+	close(m.exit)
+	m.wg.Wait()
+	m.source.Close()
+}
+
+func (m *Monitor) pollPackets() {
+	for {
+		err := m.source.VisitPackets(m.exit, m.processPacket)
+		if err != nil {
+			log.Warnf("error reading packet: %s", err)
+		}
+
+		// Properly synchronizes termination process
+		select {
+		case <-m.exit:
+			return
+		default:
+		}
+
+		// Sleep briefly and try again
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// processPacket classifies the connection of a TCP packet and hands its payload to the parser of
+// the connection protocol. The underlying packet data can't be referenced after this method call.
+func (m *Monitor) processPacket(data []byte, ts time.Time) error {
+	if err := m.decoder.DecodeLayers(data, &m.layers); err != nil {
+		atomic.AddInt64(&m.decodingErrors, 1)
+		return nil
+	}
+
+	var src, dst util.Address
+	var ipPayloadLength int
+	var isTCP bool
+	for _, layer := range m.layers {
+		switch layer {
+		case layers.LayerTypeIPv4:
+			src, dst = util.AddressFromNetIP(m.ipv4.SrcIP), util.AddressFromNetIP(m.ipv4.DstIP)
+			ipPayloadLength = int(m.ipv4.Length) - int(m.ipv4.IHL)*4
+		case layers.LayerTypeIPv6:
+			src, dst = util.AddressFromNetIP(m.ipv6.SrcIP), util.AddressFromNetIP(m.ipv6.DstIP)
+			ipPayloadLength = int(m.ipv6.Length)
+		case layers.LayerTypeTCP:
+			isTCP = true
+		}
+	}
+	if !isTCP || src == nil {
+		return nil
+	}
+
+	// only the beginning of the segment may have been captured, its length is
+	// given by the IP header
+	segmentLength := ipPayloadLength - int(m.tcp.DataOffset)*4
+	if segmentLength < len(m.tcp.Payload) {
+		segmentLength = len(m.tcp.Payload)
+	}
+
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	tuple, fromA := newConnTuple(src, dst, uint16(m.tcp.SrcPort), uint16(m.tcp.DstPort))
+	m.handlePayload(tuple, fromA, src, dst, segmentLength, ts)
+	if m.tcp.FIN || m.tcp.RST {
+		delete(m.connections, tuple)
+	}
+	return nil
+}
+
+func (m *Monitor) handlePayload(tuple connTuple, fromA bool, src, dst util.Address, segmentLength int, ts time.Time) {
+	payload := m.tcp.Payload
+	if len(payload) == 0 {
+		return
+	}
+
+	conn, ok := m.connections[tuple]
+	if !ok {
+		if len(m.connections) >= maxTrackedConnections {
+			atomic.AddInt64(&m.untracked, 1)
+			return
+		}
+		conn = &connection{}
+		m.connections[tuple] = conn
+	}
+	conn.lastSeen = ts
+
+	if conn.protocol == ProtocolUnknown {
+		if conn.attempts >= maxClassificationAttempts {
+			return
+		}
+		conn.attempts++
+		if !m.classify(conn, payload, fromA, src, dst) {
+			return
+		}
+	}
+
+	fromClient := fromA == conn.clientIsA
+	if !conn.inSequence(fromClient, m.tcp.Seq, segmentLength) {
+		return
+	}
+
+	m.parse(conn, fromClient, payload, ts)
+	if uncaptured := segmentLength - len(payload); uncaptured > 0 {
+		conn.skipUncaptured(fromClient, uncaptured)
+	}
+}
+
+// parse hands a payload to the parser of the connection protocol, and aggregates the
+// transactions it completes
+func (m *Monitor) parse(conn *connection, fromClient bool, payload []byte, ts time.Time) {
+	switch conn.protocol {
+	case ProtocolKafka:
+		if fromClient {
+			if err := conn.kafka.HandleRequests(payload, ts); err != nil {
+				m.kafkaTelemetry.AddMalformed(1)
+			}
+			return
+		}
+
+		transactions, err := conn.kafka.HandleResponses(payload, ts)
+		if err != nil {
+			m.kafkaTelemetry.AddMalformed(1)
+		}
+		m.kafkaStatKeeper.Process(transactions)
+	case ProtocolPostgres:
+		if fromClient {
+			if err := conn.postgres.HandleFrontendMessages(payload, ts); err != nil {
+				m.postgresTelemetry.AddMalformed(1)
+			}
+			return
+		}
+
+		transactions, err := conn.postgres.HandleBackendMessages(payload, ts)
+		if err != nil {
+			m.postgresTelemetry.AddMalformed(1)
+		}
+		m.postgresStatKeeper.Process(transactions)
+	}
+}
+
+// classify identifies the protocol of a connection from a payload, its source being
+// the client if the payload matches one of the monitored protocols
+func (m *Monitor) classify(conn *connection, payload []byte, fromA bool, src, dst util.Address) bool {
+	protocol := Classify(payload)
+	sport, dport := uint16(m.tcp.SrcPort), uint16(m.tcp.DstPort)
+
+	switch {
+	case protocol == ProtocolKafka && m.kafkaEnabled:
+		conn.kafka = kafka.NewParser(kafka.NewKey(src, dst, sport, dport, "", 0))
+	case protocol == ProtocolPostgres && m.postgresEnabled:
+		conn.postgres = postgres.NewParser(postgres.NewKey(src, dst, sport, dport, postgres.CommandUnknown))
+	default:
+		return false
+	}
+
+	conn.protocol = protocol
+	conn.clientIsA = fromA
+	return true
+}
+
+// inSequence returns whether a segment follows the previous one in its direction, skipping
+// retransmissions and resynchronizing the parser on the next message when segments are missing
+func (c *connection) inSequence(fromClient bool, seq uint32, length int) bool {
+	dir := 1
+	if fromClient {
+		dir = 0
+	}
+
+	if c.seqKnown[dir] && seq != c.nextSeq[dir] {
+		if int32(seq-c.nextSeq[dir]) < 0 {
+			// retransmission of data already seen
+			return false
+		}
+
+		switch c.protocol {
+		case ProtocolKafka:
+			c.kafka.Resync(fromClient)
+		case ProtocolPostgres:
+			c.postgres.Resync(fromClient)
+		}
+	}
+
+	c.nextSeq[dir] = seq + uint32(length)
+	c.seqKnown[dir] = true
+	return true
+}
+
+// skipUncaptured resynchronizes the parser of the connection after the part of a
+// segment that wasn't captured
+func (c *connection) skipUncaptured(fromClient bool, n int) {
+	switch c.protocol {
+	case ProtocolKafka:
+		c.kafka.SkipUncaptured(fromClient, n)
+	case ProtocolPostgres:
+		c.postgres.SkipUncaptured(fromClient, n)
+	}
+}
+
+func (m *Monitor) expireConnections(now time.Time) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for tuple, conn := range m.connections {
+		if now.Sub(conn.lastSeen) > connectionTimeout {
+			delete(m.connections, tuple)
+		}
+	}
+}
+
+// newConnTuple returns the tuple of the connection of a packet, and whether the
+// source of the packet is the first endpoint of the tuple
+func newConnTuple(src, dst util.Address, sport, dport uint16) (connTuple, bool) {
+	srcLow, srcHigh := util.ToLowHigh(src)
+	dstLow, dstHigh := util.ToLowHigh(dst)
+
+	if srcHigh < dstHigh || (srcHigh == dstHigh && (srcLow < dstLow || (srcLow == dstLow && sport <= dport))) {
+		return connTuple{
+			aIPHigh: srcHigh, aIPLow: srcLow, aPort: sport,
+			bIPHigh: dstHigh, bIPLow: dstLow, bPort: dport,
+		}, true
+	}
+
+	return connTuple{
+		aIPHigh: dstHigh, aIPLow: dstLow, aPort: dport,
+		bIPHigh: srcHigh, bIPLow: srcLow, bPort: sport,
+	}, false
+}
@@ -0,0 +1,181 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+// +build linux_bpf
+
+package protocols
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	filterpkg "github.com/DataDog/datadog-agent/pkg/network/filter"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"golang.org/x/net/bpf"
+)
+
+const (
+	// maxHeadersLength is the length of the largest Ethernet, IP and TCP headers
+	maxHeadersLength = 14 + 60 + 60
+
+	// maxCapturedPayload is the number of payload bytes captured of each segment. It covers the
+	// headers of the messages, the topics of the Kafka requests and the command of the PostgreSQL
+	// queries, the parsers skip the rest of the segment.
+	maxCapturedPayload = 512
+
+	// snapLen is the number of bytes captured of each packet
+	snapLen = maxHeadersLength + maxCapturedPayload
+)
+
+// NewPacketMonitor returns a Monitor reading the TCP traffic of the host to or from the
+// ports of the enabled protocols
+func NewPacketMonitor(cfg *config.Config) (*Monitor, error) {
+	bpfFilter, err := generateBPFFilter(monitoredPorts(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("error creating bpf classic filter: %w", err)
+	}
+
+	// Create the RAW_SOCKET inside the root network namespace
+	var (
+		packetSrc *filterpkg.AFPacketSource
+		srcErr    error
+	)
+	err = util.WithRootNS(cfg.ProcRoot, func() error {
+		packetSrc, srcErr = filterpkg.NewPacketSource(nil, bpfFilter)
+		return srcErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := newMonitor(cfg, packetSrc)
+	if err != nil {
+		packetSrc.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// monitoredPorts returns the server ports of the enabled protocols, without duplicates
+func monitoredPorts(cfg *config.Config) []uint16 {
+	var candidates []uint16
+	if cfg.EnableKafkaMonitoring {
+		candidates = append(candidates, cfg.KafkaPorts...)
+	}
+	if cfg.EnablePostgresMonitoring {
+		candidates = append(candidates, cfg.PostgresPorts...)
+	}
+
+	seen := make(map[uint16]struct{}, len(candidates))
+	ports := make([]uint16, 0, len(candidates))
+	for _, port := range candidates {
+		if _, ok := seen[port]; ok {
+			continue
+		}
+		seen[port] = struct{}{}
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// generateBPFFilter returns a filter capturing the first snapLen bytes of the unfragmented
+// IPv4 and IPv6 TCP packets whose source or destination port is one of the given ports
+func generateBPFFilter(ports []uint16) ([]bpf.RawInstruction, error) {
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("no port to monitor")
+	}
+
+	var (
+		instructions []bpf.Instruction
+		// labels holds the position of the jump targets
+		labels = make(map[string]int)
+		// jumps holds the labels targeted by the conditional jumps, an empty label
+		// targeting the next instruction
+		jumps = make(map[int][2]string)
+	)
+	emit := func(instruction bpf.Instruction) {
+		instructions = append(instructions, instruction)
+	}
+	label := func(name string) {
+		labels[name] = len(instructions)
+	}
+	jumpIf := func(cond bpf.JumpTest, val uint32, ifTrue, ifFalse string) {
+		jumps[len(instructions)] = [2]string{ifTrue, ifFalse}
+		emit(bpf.JumpIf{Cond: cond, Val: val})
+	}
+	// matchPorts captures the packet if the port loaded in A is monitored
+	matchPorts := func(ifNone string) {
+		for i, port := range ports {
+			next := ""
+			if i == len(ports)-1 {
+				next = ifNone
+			}
+			jumpIf(bpf.JumpEqual, uint32(port), "capture", next)
+		}
+	}
+
+	// ldh [12] -- load Ethertype
+	emit(bpf.LoadAbsolute{Size: 2, Off: 12})
+	// jeq #0x86dd -- if IPv6, go next, else check IPv4
+	jumpIf(bpf.JumpEqual, 0x86dd, "", "ipv4")
+	// ldb [20] -- load IPv6 Next Header
+	emit(bpf.LoadAbsolute{Size: 1, Off: 20})
+	// jeq #0x6 -- IPv6 Next Header: if TCP, go next, else drop
+	jumpIf(bpf.JumpEqual, 0x6, "", "drop")
+	// ldh [54] -- load source port
+	emit(bpf.LoadAbsolute{Size: 2, Off: 54})
+	matchPorts("")
+	// ldh [56] -- load dest port
+	emit(bpf.LoadAbsolute{Size: 2, Off: 56})
+	matchPorts("drop")
+
+	label("ipv4")
+	// jeq #0x800 -- if IPv4, go next, else drop
+	jumpIf(bpf.JumpEqual, 0x800, "", "drop")
+	// ldb [23] -- load IPv4 Protocol
+	emit(bpf.LoadAbsolute{Size: 1, Off: 23})
+	// jeq #0x6 -- if TCP, go next, else drop
+	jumpIf(bpf.JumpEqual, 0x6, "", "drop")
+	// ldh [20] -- load Fragment Offset
+	emit(bpf.LoadAbsolute{Size: 2, Off: 20})
+	// jset #0x1fff -- use 0x1fff as mask for fragment offset, if != 0, drop
+	jumpIf(bpf.JumpBitsSet, 0x1fff, "drop", "")
+	// ldxb 4*([14]&0xf) -- x = IP header length
+	emit(bpf.LoadMemShift{Off: 14})
+	// ldh [x + 14] -- load source port
+	emit(bpf.LoadIndirect{Size: 2, Off: 14})
+	matchPorts("")
+	// ldh [x + 16] -- load dest port
+	emit(bpf.LoadIndirect{Size: 2, Off: 16})
+	matchPorts("drop")
+
+	label("capture")
+	// ret #snapLen -- capture the headers and the beginning of the payload
+	emit(bpf.RetConstant{Val: snapLen})
+	label("drop")
+	// ret #0 -- drop
+	emit(bpf.RetConstant{Val: 0})
+
+	for pos, targets := range jumps {
+		jump := instructions[pos].(bpf.JumpIf)
+		skips := [2]uint8{}
+		for i, target := range targets {
+			if target == "" {
+				continue
+			}
+			skip := labels[target] - pos - 1
+			if skip > math.MaxUint8 {
+				return nil, fmt.Errorf("too many ports to monitor: %d", len(ports))
+			}
+			skips[i] = uint8(skip)
+		}
+		jump.SkipTrue, jump.SkipFalse = skips[0], skips[1]
+		instructions[pos] = jump
+	}
+
+	return bpf.Assemble(instructions)
+}
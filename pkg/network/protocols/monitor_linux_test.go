@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+// +build linux_bpf
+
+package protocols
+
+import (
+	"net"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/bpf"
+)
+
+func TestMonitoredPorts(t *testing.T) {
+	cfg := &config.Config{
+		EnableKafkaMonitoring: true,
+		KafkaPorts:            []uint16{9092, 5432},
+		PostgresPorts:         []uint16{5432, 6432},
+	}
+	assert.Equal(t, []uint16{9092, 5432}, monitoredPorts(cfg))
+
+	cfg.EnablePostgresMonitoring = true
+	assert.Equal(t, []uint16{9092, 5432, 6432}, monitoredPorts(cfg))
+}
+
+func TestBPFFilter(t *testing.T) {
+	rawFilter, err := generateBPFFilter([]uint16{9092, 5432})
+	require.NoError(t, err)
+	filter, ok := bpf.Disassemble(rawFilter)
+	require.True(t, ok)
+	vm, err := bpf.NewVM(filter)
+	require.NoError(t, err)
+
+	largePayload := make([]byte, 4096)
+	for _, tc := range []struct {
+		name     string
+		packet   []byte
+		captured int
+	}{
+		{"ipv4 to monitored port", filterTestPacket(t, false, layers.IPProtocolTCP, 45678, 9092, largePayload), snapLen},
+		{"ipv4 from monitored port", filterTestPacket(t, false, layers.IPProtocolTCP, 5432, 45678, largePayload), snapLen},
+		{"ipv4 other port", filterTestPacket(t, false, layers.IPProtocolTCP, 45678, 80, largePayload), 0},
+		{"ipv4 udp", filterTestPacket(t, false, layers.IPProtocolUDP, 45678, 9092, largePayload), 0},
+		{"ipv6 to monitored port", filterTestPacket(t, true, layers.IPProtocolTCP, 45678, 5432, largePayload), snapLen},
+		{"ipv6 from monitored port", filterTestPacket(t, true, layers.IPProtocolTCP, 9092, 45678, nil), snapLen},
+		{"ipv6 other port", filterTestPacket(t, true, layers.IPProtocolTCP, 443, 45678, largePayload), 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			captured, err := vm.Run(tc.packet)
+			require.NoError(t, err)
+			assert.Equal(t, tc.captured, captured)
+		})
+	}
+
+	_, err = generateBPFFilter(nil)
+	assert.Error(t, err)
+
+	tooManyPorts := make([]uint16, 200)
+	for i := range tooManyPorts {
+		tooManyPorts[i] = uint16(10000 + i)
+	}
+	_, err = generateBPFFilter(tooManyPorts)
+	assert.Error(t, err)
+}
+
+func filterTestPacket(t *testing.T, ipv6 bool, protocol layers.IPProtocol, sport, dport uint16, payload []byte) []byte {
+	eth := &layers.Ethernet{SrcMAC: make([]byte, 6), DstMAC: make([]byte, 6)}
+	var ip gopacket.NetworkLayer
+	var ipLayer gopacket.SerializableLayer
+	if ipv6 {
+		eth.EthernetType = layers.EthernetTypeIPv6
+		ip6 := &layers.IPv6{Version: 6, HopLimit: 64, NextHeader: protocol, SrcIP: net.ParseIP("fd00::1"), DstIP: net.ParseIP("fd00::2")}
+		ip, ipLayer = ip6, ip6
+	} else {
+		eth.EthernetType = layers.EthernetTypeIPv4
+		ip4 := &layers.IPv4{Version: 4, TTL: 64, Protocol: protocol, SrcIP: net.IPv4(10, 0, 0, 1), DstIP: net.IPv4(10, 0, 0, 2)}
+		ip, ipLayer = ip4, ip4
+	}
+
+	var transport gopacket.SerializableLayer
+	if protocol == layers.IPProtocolTCP {
+		tcp := &layers.TCP{SrcPort: layers.TCPPort(sport), DstPort: layers.TCPPort(dport), ACK: true}
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ip))
+		transport = tcp
+	} else {
+		udp := &layers.UDP{SrcPort: layers.UDPPort(sport), DstPort: layers.UDPPort(dport)}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ip))
+		transport = udp
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ipLayer, transport, gopacket.Payload(payload)))
+	return buf.Bytes()
+}
@@ -0,0 +1,259 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package protocols
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/postgres"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaFromPcap(t *testing.T) {
+	m := newTestMonitor(t)
+	replay(t, m, "testdata/kafka.pcap")
+
+	client, server := util.AddressFromString("10.0.0.1"), util.AddressFromString("10.0.0.2")
+	key := func(topic string, apiKey kafka.APIKey) kafka.Key {
+		return kafka.NewKey(client, server, 45678, 9092, topic, apiKey)
+	}
+
+	stats := m.GetKafkaStats()
+	require.Len(t, stats, 3)
+
+	produceOrders := stats[key("orders", kafka.APIKeyProduce)]
+	assert.Equal(t, 2, produceOrders.Count)
+	assert.NotNil(t, produceOrders.Latencies)
+
+	producePayments := stats[key("payments", kafka.APIKeyProduce)]
+	assert.Equal(t, 1, producePayments.Count)
+	assert.Equal(t, float64(2*time.Millisecond), producePayments.FirstLatencySample)
+
+	// the retransmitted segment of the fetch request is ignored
+	fetchOrders := stats[key("orders", kafka.APIKeyFetch)]
+	assert.Equal(t, 1, fetchOrders.Count)
+	assert.Equal(t, float64(4*time.Millisecond), fetchOrders.FirstLatencySample)
+
+	// the connection is closed
+	assert.Empty(t, m.connections)
+	assert.Empty(t, m.GetKafkaStats())
+	assert.Empty(t, m.GetPostgresStats())
+}
+
+func TestPostgresFromPcap(t *testing.T) {
+	m := newTestMonitor(t)
+	replay(t, m, "testdata/postgres.pcap")
+
+	client, server := util.AddressFromString("10.0.0.1"), util.AddressFromString("10.0.0.3")
+	key := func(command postgres.Command) postgres.Key {
+		return postgres.NewKey(client, server, 45679, 5432, command)
+	}
+
+	stats := m.GetPostgresStats()
+	require.Len(t, stats, 3)
+
+	selects := stats[key(postgres.CommandSelect)]
+	assert.Equal(t, 1, selects.Count)
+	assert.Equal(t, 0, selects.ErrorCount)
+	assert.Equal(t, float64(3*time.Millisecond), selects.FirstLatencySample)
+
+	inserts := stats[key(postgres.CommandInsert)]
+	assert.Equal(t, 1, inserts.Count)
+	assert.Equal(t, 1, inserts.ErrorCount)
+
+	updates := stats[key(postgres.CommandUpdate)]
+	assert.Equal(t, 1, updates.Count)
+	assert.Equal(t, 0, updates.ErrorCount)
+	assert.Equal(t, float64(2*time.Millisecond), updates.FirstLatencySample)
+
+	assert.Empty(t, m.connections)
+	assert.Empty(t, m.GetKafkaStats())
+}
+
+func TestMonitorExpiresConnections(t *testing.T) {
+	m := newTestMonitor(t)
+
+	f, err := os.Open("testdata/postgres.pcap")
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := pcapgo.NewReader(f)
+	require.NoError(t, err)
+
+	// only replay the beginning of the session
+	data, ci, err := r.ReadPacketData()
+	require.NoError(t, err)
+	require.NoError(t, m.processPacket(data, ci.Timestamp))
+	require.Len(t, m.connections, 1)
+
+	m.expireConnections(ci.Timestamp.Add(connectionTimeout / 2))
+	assert.Len(t, m.connections, 1)
+
+	m.expireConnections(ci.Timestamp.Add(2 * connectionTimeout))
+	assert.Empty(t, m.connections)
+}
+
+func TestMonitorIgnoresUnknownProtocols(t *testing.T) {
+	m := newTestMonitor(t)
+	now := time.Now()
+
+	payload := []byte("GET /index.html HTTP/1.1\r\nHost: localhost\r\n\r\n")
+	for i := 0; i < maxClassificationAttempts+1; i++ {
+		require.NoError(t, m.processPacket(tcpPacket(t, uint32(i*len(payload)), payload), now))
+	}
+
+	require.Len(t, m.connections, 1)
+	for _, conn := range m.connections {
+		assert.Equal(t, ProtocolUnknown, conn.protocol)
+		assert.Equal(t, maxClassificationAttempts, conn.attempts)
+	}
+	assert.Empty(t, m.GetKafkaStats())
+	assert.Empty(t, m.GetPostgresStats())
+}
+
+func TestMonitorUncapturedPayload(t *testing.T) {
+	m := newTestMonitor(t)
+	now := time.Now()
+
+	// a query spanning two segments, only the first bytes of the first one being captured
+	largeQuery := postgresMessage('Q', "SELECT 1 -- "+strings.Repeat("x", 2000))
+	first, second := largeQuery[:1400], append(largeQuery[1400:], postgresMessage('Q', "UPDATE users SET name = 'a'")...)
+
+	clientSeq := uint32(1000)
+	require.NoError(t, m.processPacket(postgresPacket(t, true, clientSeq, first, 200), now))
+	clientSeq += uint32(len(first))
+	require.NoError(t, m.processPacket(postgresPacket(t, true, clientSeq, second, len(second)), now))
+
+	readyForQuery := []byte{'Z', 0, 0, 0, 5, 'I'}
+	var responses []byte
+	for _, tag := range []string{"SELECT 1", "UPDATE 1"} {
+		responses = append(responses, postgresMessage('C', tag)...)
+		responses = append(responses, readyForQuery...)
+	}
+	require.NoError(t, m.processPacket(postgresPacket(t, false, 5000, responses, len(responses)), now.Add(time.Millisecond)))
+
+	stats := m.GetPostgresStats()
+	require.Len(t, stats, 2)
+	for key, stat := range stats {
+		assert.Contains(t, []postgres.Command{postgres.CommandSelect, postgres.CommandUpdate}, key.Command)
+		assert.Equal(t, 1, stat.Count)
+	}
+}
+
+func TestNewConnTuple(t *testing.T) {
+	a, b := util.AddressFromString("10.0.0.1"), util.AddressFromString("10.0.0.2")
+
+	tuple, fromA := newConnTuple(a, b, 45678, 9092)
+	reverse, reverseFromA := newConnTuple(b, a, 9092, 45678)
+	assert.Equal(t, tuple, reverse)
+	assert.True(t, fromA)
+	assert.False(t, reverseFromA)
+
+	// same address, ordered by port
+	tuple, fromA = newConnTuple(a, a, 9092, 45678)
+	reverse, reverseFromA = newConnTuple(a, a, 45678, 9092)
+	assert.Equal(t, tuple, reverse)
+	assert.True(t, fromA)
+	assert.False(t, reverseFromA)
+}
+
+func newTestMonitor(t *testing.T) *Monitor {
+	cfg := &config.Config{
+		EnableKafkaMonitoring:    true,
+		EnablePostgresMonitoring: true,
+		MaxKafkaStatsBuffered:    1000,
+		MaxPostgresStatsBuffered: 1000,
+	}
+	m, err := newMonitor(cfg, nopSource{})
+	require.NoError(t, err)
+	return m
+}
+
+func replay(t *testing.T, m *Monitor, path string) {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	r, err := pcapgo.NewReader(f)
+	require.NoError(t, err)
+
+	for {
+		data, ci, err := r.ReadPacketData()
+		if err != nil {
+			break
+		}
+		require.NoError(t, m.processPacket(data, ci.Timestamp))
+	}
+}
+
+func tcpPacket(t *testing.T, seq uint32, payload []byte) []byte {
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    net.IPv4(10, 0, 0, 1),
+		DstIP:    net.IPv4(10, 0, 0, 4),
+	}
+	tcp := &layers.TCP{SrcPort: 45680, DstPort: 80, Seq: seq, ACK: true, PSH: true}
+	require.NoError(t, tcp.SetNetworkLayerForChecksum(ip))
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	eth := &layers.Ethernet{SrcMAC: make([]byte, 6), DstMAC: make([]byte, 6), EthernetType: layers.EthernetTypeIPv4}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, tcp, gopacket.Payload(payload)))
+	return buf.Bytes()
+}
+
+// postgresMessage returns a PostgreSQL message whose body is a string
+func postgresMessage(msgType byte, body string) []byte {
+	b := []byte{msgType, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(b[1:], uint32(len(body)+5))
+	return append(append(b, body...), 0)
+}
+
+// postgresPacket returns a packet of a PostgreSQL connection, of which only the first
+// captured bytes of the payload are kept
+func postgresPacket(t *testing.T, fromClient bool, seq uint32, payload []byte, captured int) []byte {
+	ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolTCP, SrcIP: net.IPv4(10, 0, 0, 1), DstIP: net.IPv4(10, 0, 0, 3)}
+	tcp := &layers.TCP{SrcPort: 45681, DstPort: 5432, Seq: seq, ACK: true, PSH: true}
+	if !fromClient {
+		ip.SrcIP, ip.DstIP = ip.DstIP, ip.SrcIP
+		tcp.SrcPort, tcp.DstPort = tcp.DstPort, tcp.SrcPort
+	}
+	require.NoError(t, tcp.SetNetworkLayerForChecksum(ip))
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	eth := &layers.Ethernet{SrcMAC: make([]byte, 6), DstMAC: make([]byte, 6), EthernetType: layers.EthernetTypeIPv4}
+	require.NoError(t, gopacket.SerializeLayers(buf, opts, eth, ip, tcp, gopacket.Payload(payload)))
+
+	packet := buf.Bytes()
+	return packet[:len(packet)-len(payload)+captured]
+}
+
+// nopSource is a packet source without packets, the tests hand the packets to the monitor directly
+type nopSource struct{}
+
+func (nopSource) VisitPackets(_ <-chan struct{}, _ func([]byte, time.Time) error) error {
+	return nil
+}
+
+func (nopSource) PacketType() gopacket.LayerType {
+	return layers.LayerTypeEthernet
+}
+
+func (nopSource) Close() {}
@@ -0,0 +1,47 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package protocols
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/network/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/postgres"
+)
+
+// Protocol is the application protocol of a connection
+type Protocol uint8
+
+const (
+	// ProtocolUnknown represents a connection whose protocol isn't identified yet
+	ProtocolUnknown Protocol = iota
+	// ProtocolKafka represents a Kafka connection
+	ProtocolKafka
+	// ProtocolPostgres represents a PostgreSQL connection
+	ProtocolPostgres
+)
+
+// String returns a string representing the protocol
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolKafka:
+		return "kafka"
+	case ProtocolPostgres:
+		return "postgres"
+	default:
+		return "unknown"
+	}
+}
+
+// Classify returns the protocol of a connection from a payload sent by the client
+func Classify(payload []byte) Protocol {
+	switch {
+	case kafka.IsRequest(payload):
+		return ProtocolKafka
+	case postgres.IsFrontendMessage(payload):
+		return ProtocolPostgres
+	default:
+		return ProtocolUnknown
+	}
+}
@@ -0,0 +1,285 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build ignore
+// +build ignore
+
+// This program generates the pcap fixtures of the protocols package tests.
+// Run it from the testdata directory with: go run gen_pcap.go
+package main
+
+import (
+	"encoding/binary"
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+var start = time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+type packet struct {
+	fromClient bool
+	payload    []byte
+	fin        bool
+	// delay since the previous packet
+	delay time.Duration
+	// retransmit sends the payload again with the sequence number of the previous packet
+	retransmit bool
+	// lost skips the payload without writing it
+	lost bool
+}
+
+type conversation struct {
+	clientIP, serverIP     net.IP
+	clientPort, serverPort uint16
+	packets                []packet
+}
+
+func main() {
+	write("kafka.pcap", kafkaConversation())
+	write("postgres.pcap", postgresConversation())
+}
+
+func write(path string, conversations ...conversation) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	w := pcapgo.NewWriter(f)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, c := range conversations {
+		ts := start
+		seq := [2]uint32{1000, 5000}
+		var previousSeq [2]uint32
+		for _, p := range c.packets {
+			ts = ts.Add(p.delay)
+			dir := 1
+			if p.fromClient {
+				dir = 0
+			}
+
+			packetSeq := seq[dir]
+			if p.retransmit {
+				packetSeq = previousSeq[dir]
+			} else {
+				previousSeq[dir] = seq[dir]
+				seq[dir] += uint32(len(p.payload))
+			}
+			if p.lost {
+				continue
+			}
+
+			data := encode(c, p, packetSeq, seq[1-dir])
+			ci := gopacket.CaptureInfo{Timestamp: ts, CaptureLength: len(data), Length: len(data)}
+			if err := w.WritePacket(ci, data); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+}
+
+func encode(c conversation, p packet, seq, ack uint32) []byte {
+	srcIP, dstIP, srcPort, dstPort := c.clientIP, c.serverIP, c.clientPort, c.serverPort
+	if !p.fromClient {
+		srcIP, dstIP, srcPort, dstPort = dstIP, srcIP, dstPort, srcPort
+	}
+
+	eth := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0x02, 0, 0, 0, 0, 1},
+		DstMAC:       net.HardwareAddr{0x02, 0, 0, 0, 0, 2},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    srcIP,
+		DstIP:    dstIP,
+	}
+	tcp := &layers.TCP{
+		SrcPort: layers.TCPPort(srcPort),
+		DstPort: layers.TCPPort(dstPort),
+		Seq:     seq,
+		Ack:     ack,
+		ACK:     true,
+		PSH:     len(p.payload) > 0,
+		FIN:     p.fin,
+		Window:  65535,
+	}
+	if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+		log.Fatal(err)
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, eth, ip, tcp, gopacket.Payload(p.payload)); err != nil {
+		log.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// kafkaConversation produces to two topics, then fetches one of them with a request
+// split across two segments, including a retransmission and a lost segment
+func kafkaConversation() conversation {
+	produce := kafkaProduceRequest(1, "orders", "payments")
+	fetch := kafkaFetchRequest(2, "orders")
+	metadata := kafkaRequest(3, 3, 1, nil)
+	// the second segment of the fetch request starts after its topic
+	split := len(fetch) - 8
+
+	return conversation{
+		clientIP:   net.IPv4(10, 0, 0, 1),
+		serverIP:   net.IPv4(10, 0, 0, 2),
+		clientPort: 45678,
+		serverPort: 9092,
+		packets: []packet{
+			{fromClient: true, payload: produce},
+			{fromClient: false, payload: kafkaResponse(1, 40), delay: 2 * time.Millisecond},
+			{fromClient: true, payload: fetch[:split], delay: 10 * time.Millisecond},
+			{fromClient: true, payload: fetch[split:]},
+			{fromClient: true, payload: fetch[split:], retransmit: true},
+			{fromClient: false, payload: kafkaResponse(2, 200), delay: 4 * time.Millisecond},
+			// the response to this request is lost, the next one is parsed after a resync
+			{fromClient: true, payload: metadata, delay: 10 * time.Millisecond},
+			{fromClient: false, payload: kafkaResponse(3, 3000), lost: true, delay: time.Millisecond},
+			{fromClient: true, payload: kafkaProduceRequest(4, "orders"), delay: 10 * time.Millisecond},
+			{fromClient: false, payload: kafkaResponse(4, 40), delay: 2 * time.Millisecond},
+			{fromClient: true, fin: true},
+		},
+	}
+}
+
+// postgresConversation opens a session, runs a simple query, a failing simple
+// query and a statement of the extended query protocol
+func postgresConversation() conversation {
+	return conversation{
+		clientIP:   net.IPv4(10, 0, 0, 1),
+		serverIP:   net.IPv4(10, 0, 0, 3),
+		clientPort: 45679,
+		serverPort: 5432,
+		packets: []packet{
+			{fromClient: true, payload: postgresStartupMessage("user", "app", "database", "app")},
+			{fromClient: false, payload: concat(
+				postgresMessage('R', []byte{0, 0, 0, 0}),
+				postgresMessage('S', cstrings("server_version", "14.2")),
+				postgresMessage('Z', []byte{'I'}),
+			), delay: time.Millisecond},
+			{fromClient: true, payload: postgresMessage('Q', cstrings("SELECT id, name FROM users")), delay: 5 * time.Millisecond},
+			{fromClient: false, payload: concat(
+				postgresMessage('T', make([]byte, 40)),
+				postgresMessage('D', make([]byte, 20)),
+				postgresMessage('C', cstrings("SELECT 1")),
+				postgresMessage('Z', []byte{'I'}),
+			), delay: 3 * time.Millisecond},
+			{fromClient: true, payload: postgresMessage('Q', cstrings("INSERT INTO users VALUES (1, 'duplicate')")), delay: 5 * time.Millisecond},
+			{fromClient: false, payload: concat(
+				postgresMessage('E', cstrings("SERROR", "C23505", "Mduplicate key value")),
+				postgresMessage('Z', []byte{'I'}),
+			), delay: time.Millisecond},
+			{fromClient: true, payload: concat(
+				postgresMessage('P', append(cstrings("", "UPDATE users SET name = $1 WHERE id = $2"), 0, 0)),
+				postgresMessage('B', append(cstrings("", ""), 0, 0, 0, 0, 0, 0)),
+				postgresMessage('E', append(cstrings(""), 0, 0, 0, 0)),
+				postgresMessage('S', nil),
+			), delay: 5 * time.Millisecond},
+			{fromClient: false, payload: concat(
+				postgresMessage('1', nil),
+				postgresMessage('2', nil),
+				postgresMessage('C', cstrings("UPDATE 1")),
+				postgresMessage('Z', []byte{'I'}),
+			), delay: 2 * time.Millisecond},
+			{fromClient: true, payload: postgresMessage('X', nil), delay: 5 * time.Millisecond},
+			{fromClient: true, fin: true},
+		},
+	}
+}
+
+func concat(messages ...[]byte) []byte {
+	var b []byte
+	for _, m := range messages {
+		b = append(b, m...)
+	}
+	return b
+}
+
+func int16Bytes(v int16) []byte {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, uint16(v))
+	return b
+}
+
+func int32Bytes(v int32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(v))
+	return b
+}
+
+func int64Bytes(v int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(v))
+	return b
+}
+
+func kafkaString(s string) []byte {
+	return append(int16Bytes(int16(len(s))), s...)
+}
+
+func kafkaRequest(apiKey, version int16, correlationID int32, body []byte) []byte {
+	msg := concat(int16Bytes(apiKey), int16Bytes(version), int32Bytes(correlationID), kafkaString("pcap-client"), body)
+	return append(int32Bytes(int32(len(msg))), msg...)
+}
+
+func kafkaProduceRequest(correlationID int32, topics ...string) []byte {
+	// null transactional ID, acks and timeout
+	body := concat(int16Bytes(-1), int16Bytes(1), int32Bytes(30000), int32Bytes(int32(len(topics))))
+	for _, topic := range topics {
+		records := make([]byte, 64)
+		body = concat(body, kafkaString(topic), int32Bytes(1), int32Bytes(0), int32Bytes(int32(len(records))), records)
+	}
+	return kafkaRequest(0, 3, correlationID, body)
+}
+
+func kafkaFetchRequest(correlationID int32, topics ...string) []byte {
+	// replica ID, max wait, min bytes, max bytes and isolation level
+	body := concat(int32Bytes(-1), int32Bytes(500), int32Bytes(1), int32Bytes(52428800), []byte{0}, int32Bytes(int32(len(topics))))
+	for _, topic := range topics {
+		// partition, fetch offset and partition max bytes
+		body = concat(body, kafkaString(topic), int32Bytes(1), int32Bytes(0), int64Bytes(0), int32Bytes(1048576))
+	}
+	return kafkaRequest(1, 4, correlationID, body)
+}
+
+func kafkaResponse(correlationID int32, bodyLength int) []byte {
+	msg := append(int32Bytes(correlationID), make([]byte, bodyLength)...)
+	return append(int32Bytes(int32(len(msg))), msg...)
+}
+
+func cstrings(values ...string) []byte {
+	var b []byte
+	for _, v := range values {
+		b = append(b, v...)
+		b = append(b, 0)
+	}
+	return b
+}
+
+func postgresMessage(msgType byte, body []byte) []byte {
+	return concat([]byte{msgType}, int32Bytes(int32(len(body)+4)), body)
+}
+
+func postgresStartupMessage(parameters ...string) []byte {
+	body := append(cstrings(parameters...), 0)
+	return concat(int32Bytes(int32(len(body)+8)), int32Bytes(196608), body)
+}
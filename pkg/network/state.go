@@ -11,6 +11,8 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
+	"github.com/DataDog/datadog-agent/pkg/network/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/postgres"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -43,6 +45,8 @@ type State interface {
 		active []ConnectionStats,
 		dns dns.StatsByKeyByNameByType,
 		http map[http.Key]http.RequestStats,
+		kafka map[kafka.Key]kafka.RequestStats,
+		postgres map[postgres.Key]postgres.RequestStats,
 	) Delta
 
 	// GetTelemetryDelta returns the telemetry delta since last time the given client requested telemetry data.
@@ -78,17 +82,21 @@ type State interface {
 type Delta struct {
 	BufferedData
 	HTTP     map[http.Key]http.RequestStats
+	Kafka    map[kafka.Key]kafka.RequestStats
+	Postgres map[postgres.Key]postgres.RequestStats
 	DNSStats dns.StatsByKeyByNameByType
 }
 
 type telemetry struct {
-	closedConnDropped  int64
-	connDropped        int64
-	statsResets        int64
-	timeSyncCollisions int64
-	dnsStatsDropped    int64
-	httpStatsDropped   int64
-	dnsPidCollisions   int64
+	closedConnDropped    int64
+	connDropped          int64
+	statsResets          int64
+	timeSyncCollisions   int64
+	dnsStatsDropped      int64
+	httpStatsDropped     int64
+	kafkaStatsDropped    int64
+	postgresStatsDropped int64
+	dnsPidCollisions     int64
 }
 
 type stats struct {
@@ -110,9 +118,11 @@ type client struct {
 	closedConnections     []ConnectionStats
 	stats                 map[string]*stats
 	// maps by dns key the domain (string) to stats structure
-	dnsStats        dns.StatsByKeyByNameByType
-	httpStatsDelta  map[http.Key]http.RequestStats
-	lastTelemetries map[ConnTelemetryType]int64
+	dnsStats           dns.StatsByKeyByNameByType
+	httpStatsDelta     map[http.Key]http.RequestStats
+	kafkaStatsDelta    map[kafka.Key]kafka.RequestStats
+	postgresStatsDelta map[postgres.Key]postgres.RequestStats
+	lastTelemetries    map[ConnTelemetryType]int64
 }
 
 func (c *client) Reset(active map[string]*ConnectionStats) {
@@ -125,6 +135,8 @@ func (c *client) Reset(active map[string]*ConnectionStats) {
 	c.closedConnectionsKeys = make(map[string]int)
 	c.dnsStats = make(dns.StatsByKeyByNameByType)
 	c.httpStatsDelta = make(map[http.Key]http.RequestStats)
+	c.kafkaStatsDelta = make(map[kafka.Key]kafka.RequestStats)
+	c.postgresStatsDelta = make(map[postgres.Key]postgres.RequestStats)
 
 	// XXX: we should change the way we clean this map once
 	// https://github.com/golang/go/issues/20135 is solved
@@ -149,24 +161,28 @@ type networkState struct {
 	latestTimeEpoch uint64
 
 	// Network state configuration
-	clientExpiry   time.Duration
-	maxClosedConns int
-	maxClientStats int
-	maxDNSStats    int
-	maxHTTPStats   int
+	clientExpiry     time.Duration
+	maxClosedConns   int
+	maxClientStats   int
+	maxDNSStats      int
+	maxHTTPStats     int
+	maxKafkaStats    int
+	maxPostgresStats int
 }
 
 // NewState creates a new network state
-func NewState(clientExpiry time.Duration, maxClosedConns, maxClientStats int, maxDNSStats int, maxHTTPStats int) State {
+func NewState(clientExpiry time.Duration, maxClosedConns, maxClientStats int, maxDNSStats int, maxHTTPStats int, maxKafkaStats int, maxPostgresStats int) State {
 	return &networkState{
-		clients:        map[string]*client{},
-		telemetry:      telemetry{},
-		clientExpiry:   clientExpiry,
-		maxClosedConns: maxClosedConns,
-		maxClientStats: maxClientStats,
-		maxDNSStats:    maxDNSStats,
-		maxHTTPStats:   maxHTTPStats,
-		buf:            make([]byte, ConnectionByteKeyMaxLen),
+		clients:          map[string]*client{},
+		telemetry:        telemetry{},
+		clientExpiry:     clientExpiry,
+		maxClosedConns:   maxClosedConns,
+		maxClientStats:   maxClientStats,
+		maxDNSStats:      maxDNSStats,
+		maxHTTPStats:     maxHTTPStats,
+		maxKafkaStats:    maxKafkaStats,
+		maxPostgresStats: maxPostgresStats,
+		buf:              make([]byte, ConnectionByteKeyMaxLen),
 	}
 }
 
@@ -207,6 +223,8 @@ func (ns *networkState) GetDelta(
 	active []ConnectionStats,
 	dnsStats dns.StatsByKeyByNameByType,
 	httpStats map[http.Key]http.RequestStats,
+	kafkaStats map[kafka.Key]kafka.RequestStats,
+	postgresStats map[postgres.Key]postgres.RequestStats,
 ) Delta {
 	ns.Lock()
 	defer ns.Unlock()
@@ -230,6 +248,12 @@ func (ns *networkState) GetDelta(
 	if len(httpStats) > 0 {
 		ns.storeHTTPStats(httpStats)
 	}
+	if len(kafkaStats) > 0 {
+		ns.storeKafkaStats(kafkaStats)
+	}
+	if len(postgresStats) > 0 {
+		ns.storePostgresStats(postgresStats)
+	}
 
 	return Delta{
 		BufferedData: BufferedData{
@@ -237,6 +261,8 @@ func (ns *networkState) GetDelta(
 			buffer: clientBuffer,
 		},
 		HTTP:     client.httpStatsDelta,
+		Kafka:    client.kafkaStatsDelta,
+		Postgres: client.postgresStatsDelta,
 		DNSStats: client.dnsStats,
 	}
 }
@@ -431,6 +457,56 @@ func (ns *networkState) storeHTTPStats(allStats map[http.Key]http.RequestStats)
 	}
 }
 
+// storeKafkaStats stores latest Kafka stats for all clients
+func (ns *networkState) storeKafkaStats(allStats map[kafka.Key]kafka.RequestStats) {
+	if len(ns.clients) == 1 {
+		for _, client := range ns.clients {
+			if len(client.kafkaStatsDelta) == 0 {
+				client.kafkaStatsDelta = allStats
+				return
+			}
+		}
+	}
+
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.kafkaStatsDelta[key]
+			if !ok && len(client.kafkaStatsDelta) >= ns.maxKafkaStats {
+				ns.telemetry.kafkaStatsDropped++
+				continue
+			}
+
+			prevStats.CombineWith(stats)
+			client.kafkaStatsDelta[key] = prevStats
+		}
+	}
+}
+
+// storePostgresStats stores latest PostgreSQL stats for all clients
+func (ns *networkState) storePostgresStats(allStats map[postgres.Key]postgres.RequestStats) {
+	if len(ns.clients) == 1 {
+		for _, client := range ns.clients {
+			if len(client.postgresStatsDelta) == 0 {
+				client.postgresStatsDelta = allStats
+				return
+			}
+		}
+	}
+
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.postgresStatsDelta[key]
+			if !ok && len(client.postgresStatsDelta) >= ns.maxPostgresStats {
+				ns.telemetry.postgresStatsDropped++
+				continue
+			}
+
+			prevStats.CombineWith(stats)
+			client.postgresStatsDelta[key] = prevStats
+		}
+	}
+}
+
 func (ns *networkState) getClient(clientID string) *client {
 	if c, ok := ns.clients[clientID]; ok {
 		return c
@@ -443,6 +519,8 @@ func (ns *networkState) getClient(clientID string) *client {
 		closedConnectionsKeys: make(map[string]int),
 		dnsStats:              dns.StatsByKeyByNameByType{},
 		httpStatsDelta:        map[http.Key]http.RequestStats{},
+		kafkaStatsDelta:       map[kafka.Key]kafka.RequestStats{},
+		postgresStatsDelta:    map[postgres.Key]postgres.RequestStats{},
 		lastTelemetries:       make(map[ConnTelemetryType]int64),
 	}
 	ns.clients[clientID] = c
@@ -641,6 +719,8 @@ func (ns *networkState) RemoveConnections(keys []string) {
 		s += " [%d closed connections dropped]"
 		s += " [%d dns stats dropped]"
 		s += " [%d HTTP stats dropped]"
+		s += " [%d Kafka stats dropped]"
+		s += " [%d PostgreSQL stats dropped]"
 		s += " [%d DNS pid collisions]"
 		s += " [%d time sync collisions]"
 		log.Warnf(s,
@@ -649,6 +729,8 @@ func (ns *networkState) RemoveConnections(keys []string) {
 			ns.telemetry.closedConnDropped,
 			ns.telemetry.dnsStatsDropped,
 			ns.telemetry.httpStatsDropped,
+			ns.telemetry.kafkaStatsDropped,
+			ns.telemetry.postgresStatsDropped,
 			ns.telemetry.dnsPidCollisions,
 			ns.telemetry.timeSyncCollisions)
 	}
@@ -673,13 +755,15 @@ func (ns *networkState) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"clients": clientInfo,
 		"telemetry": map[string]int64{
-			"stats_resets":         ns.telemetry.statsResets,
-			"closed_conn_dropped":  ns.telemetry.closedConnDropped,
-			"conn_dropped":         ns.telemetry.connDropped,
-			"time_sync_collisions": ns.telemetry.timeSyncCollisions,
-			"dns_stats_dropped":    ns.telemetry.dnsStatsDropped,
-			"http_stats_dropped":   ns.telemetry.httpStatsDropped,
-			"dns_pid_collisions":   ns.telemetry.dnsPidCollisions,
+			"stats_resets":           ns.telemetry.statsResets,
+			"closed_conn_dropped":    ns.telemetry.closedConnDropped,
+			"conn_dropped":           ns.telemetry.connDropped,
+			"time_sync_collisions":   ns.telemetry.timeSyncCollisions,
+			"dns_stats_dropped":      ns.telemetry.dnsStatsDropped,
+			"http_stats_dropped":     ns.telemetry.httpStatsDropped,
+			"kafka_stats_dropped":    ns.telemetry.kafkaStatsDropped,
+			"postgres_stats_dropped": ns.telemetry.postgresStatsDropped,
+			"dns_pid_collisions":     ns.telemetry.dnsPidCollisions,
 		},
 		"current_time":       time.Now().Unix(),
 		"latest_bpf_time_ns": ns.latestTimeEpoch,
@@ -28,6 +28,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network/ebpf/probes"
 	"github.com/DataDog/datadog-agent/pkg/network/http"
 	"github.com/DataDog/datadog-agent/pkg/network/netlink"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
 	"github.com/DataDog/datadog-agent/pkg/network/stats"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/connection"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/connection/kprobe"
@@ -50,6 +51,9 @@ type Tracer struct {
 	httpMonitor *http.Monitor
 	ebpfTracer  connection.Tracer
 
+	// protocolMonitor classifies connections and aggregates Kafka and PostgreSQL requests
+	protocolMonitor *protocols.Monitor
+
 	// Telemetry
 	skippedConns int64 `stats:"atomic"`
 	// Will track the count of expired TCP connections
@@ -148,6 +152,8 @@ func NewTracer(config *config.Config) (*Tracer, error) {
 		config.MaxConnectionsStateBuffered,
 		config.MaxDNSStatsBuffered,
 		config.MaxHTTPStatsBuffered,
+		config.MaxKafkaStatsBuffered,
+		config.MaxPostgresStatsBuffered,
 	)
 
 	gwLookup := newGatewayLookup(config)
@@ -160,6 +166,7 @@ func NewTracer(config *config.Config) (*Tracer, error) {
 		state:                      state,
 		reverseDNS:                 newReverseDNS(config),
 		httpMonitor:                newHTTPMonitor(!pre410Kernel, config, ebpfTracer, constantEditors),
+		protocolMonitor:            newProtocolMonitor(config),
 		activeBuffer:               network.NewConnectionBuffer(512, 256),
 		conntracker:                conntracker,
 		sourceExcludes:             network.ParseConnectionFilters(config.ExcludedSourceConnections),
@@ -317,6 +324,7 @@ func (t *Tracer) Stop() {
 	t.reverseDNS.Close()
 	t.ebpfTracer.Stop()
 	t.httpMonitor.Stop()
+	t.protocolMonitor.Stop()
 	t.conntracker.Close()
 }
 
@@ -332,7 +340,15 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	}
 	active := t.activeBuffer.Connections()
 
-	delta := t.state.GetDelta(clientID, latestTime, active, t.reverseDNS.GetDNSStats(), t.httpMonitor.GetHTTPStats())
+	delta := t.state.GetDelta(
+		clientID,
+		latestTime,
+		active,
+		t.reverseDNS.GetDNSStats(),
+		t.httpMonitor.GetHTTPStats(),
+		t.protocolMonitor.GetKafkaStats(),
+		t.protocolMonitor.GetPostgresStats(),
+	)
 	t.activeBuffer.Reset()
 
 	t.retryConntrack(delta.Conns)
@@ -351,6 +367,8 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 		DNS:                         names,
 		DNSStats:                    delta.DNSStats,
 		HTTP:                        delta.HTTP,
+		Kafka:                       delta.Kafka,
+		Postgres:                    delta.Postgres,
 		ConnTelemetry:               ctm,
 		CompilationTelemetryByAsset: rctm,
 	}, nil
@@ -573,6 +591,7 @@ const (
 	gatewayLookupStats
 	httpStats
 	kprobesStats
+	protocolsStats
 	stateStats
 	tracerStats
 )
@@ -584,6 +603,7 @@ var allStats = []statsComp{
 	gatewayLookupStats,
 	httpStats,
 	kprobesStats,
+	protocolsStats,
 	stateStats,
 	tracerStats,
 }
@@ -614,6 +634,10 @@ func (t *Tracer) getStats(comps ...statsComp) (map[string]interface{}, error) {
 			ret["http"] = t.httpMonitor.GetStats()
 		case kprobesStats:
 			ret["kprobes"] = ddebpf.GetProbeStats()
+		case protocolsStats:
+			if t.protocolMonitor != nil {
+				ret["protocols"] = t.protocolMonitor.GetStats()
+			}
 		case stateStats:
 			ret["state"] = t.state.GetStats()["telemetry"]
 		case tracerStats:
@@ -762,3 +786,19 @@ func newHTTPMonitor(supported bool, c *config.Config, tracer connection.Tracer,
 	log.Info("http monitoring enabled")
 	return monitor
 }
+
+func newProtocolMonitor(c *config.Config) *protocols.Monitor {
+	if !c.EnableKafkaMonitoring && !c.EnablePostgresMonitoring {
+		return nil
+	}
+
+	monitor, err := protocols.NewPacketMonitor(c)
+	if err != nil {
+		log.Errorf("could not instantiate protocol monitor: %s", err)
+		return nil
+	}
+	monitor.Start()
+
+	log.Infof("protocol monitoring enabled (kafka: %t, postgres: %t)", c.EnableKafkaMonitoring, c.EnablePostgresMonitoring)
+	return monitor
+}
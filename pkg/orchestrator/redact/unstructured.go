@@ -0,0 +1,120 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package redact
+
+import (
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RemoveLastAppliedConfigurationAnnotationUnstructured redacts the
+// "kubectl.kubernetes.io/last-applied-configuration" annotation of a resource
+// retrieved through the dynamic client.
+func RemoveLastAppliedConfigurationAnnotationUnstructured(r *unstructured.Unstructured) {
+	// GetAnnotations returns a copy of the annotations.
+	annotations := r.GetAnnotations()
+	if _, found := annotations["kubectl.kubernetes.io/last-applied-configuration"]; !found {
+		return
+	}
+	RemoveLastAppliedConfigurationAnnotation(annotations)
+	r.SetAnnotations(annotations)
+}
+
+// ScrubUnstructured scrubs sensitive information in the command lines & env
+// vars of every container found in a resource retrieved through the dynamic
+// client, like the pod templates of custom workload resources.
+func ScrubUnstructured(r *unstructured.Unstructured, scrubber *DataScrubber) {
+	scrubUnstructuredValue(r.Object, scrubber)
+}
+
+func scrubUnstructuredValue(value interface{}, scrubber *DataScrubber) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if key == "containers" || key == "initContainers" {
+				if containers, ok := field.([]interface{}); ok {
+					for _, c := range containers {
+						if container, ok := c.(map[string]interface{}); ok {
+							scrubUnstructuredContainer(container, scrubber)
+						}
+					}
+					continue
+				}
+			}
+			scrubUnstructuredValue(field, scrubber)
+		}
+	case []interface{}:
+		for _, item := range v {
+			scrubUnstructuredValue(item, scrubber)
+		}
+	}
+}
+
+// scrubUnstructuredContainer applies ScrubContainer to the unstructured
+// representation of a container.
+func scrubUnstructuredContainer(container map[string]interface{}, scrubber *DataScrubber) {
+	// scrub env vars
+	if envs, ok := container["env"].([]interface{}); ok {
+		for _, e := range envs {
+			env, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := env["name"].(string)
+			if _, found := env["value"]; found && scrubber.ContainsSensitiveWord(name) {
+				env["value"] = redactedValue
+			}
+		}
+	}
+
+	// scrub args and commands
+	command, commandOk := unstructuredStrings(container["command"])
+	args, argsOk := unstructuredStrings(container["args"])
+	if !commandOk || !argsOk || len(command)+len(args) == 0 {
+		return
+	}
+
+	c := v1.Container{Command: command, Args: args}
+	ScrubContainer(&c, scrubber)
+
+	if len(command) > 0 {
+		container["command"] = stringsToUnstructured(c.Command)
+	}
+	if len(args) > 0 {
+		container["args"] = stringsToUnstructured(c.Args)
+	}
+}
+
+// unstructuredStrings converts an unstructured list of strings. It returns
+// false if the value is neither absent nor a list of strings.
+func unstructuredStrings(value interface{}) ([]string, bool) {
+	if value == nil {
+		return nil, true
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		strs = append(strs, s)
+	}
+	return strs, true
+}
+
+func stringsToUnstructured(strs []string) []interface{} {
+	list := make([]interface{}, 0, len(strs))
+	for _, s := range strs {
+		list = append(list, s)
+	}
+	return list
+}
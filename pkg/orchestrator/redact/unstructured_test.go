@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestRemoveLastAppliedConfigurationAnnotationUnstructured(t *testing.T) {
+	r := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"metadata": map[string]interface{}{
+			"name": "rollout",
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": `{"spec": {}}`,
+				"foo": "bar",
+			},
+		},
+	}}
+
+	RemoveLastAppliedConfigurationAnnotationUnstructured(r)
+	assert.Equal(t, map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": replacedValue,
+		"foo": "bar",
+	}, r.GetAnnotations())
+
+	noAnnotations := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "rollout"},
+	}}
+	RemoveLastAppliedConfigurationAnnotationUnstructured(noAnnotations)
+	assert.Nil(t, noAnnotations.GetAnnotations())
+}
+
+func TestScrubUnstructured(t *testing.T) {
+	r := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Rollout",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"initContainers": []interface{}{
+						map[string]interface{}{
+							"name":    "init",
+							"command": []interface{}{"migrate", "--password", "hunter2"},
+						},
+					},
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":    "app",
+							"command": []interface{}{"app"},
+							"args":    []interface{}{"--api_key=abcdef", "--port=8080"},
+							"env": []interface{}{
+								map[string]interface{}{"name": "DB_PASSWORD", "value": "hunter2"},
+								map[string]interface{}{"name": "DB_HOST", "value": "db"},
+								map[string]interface{}{
+									"name":      "API_KEY",
+									"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "keys"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	ScrubUnstructured(r, NewDefaultDataScrubber())
+
+	initContainers, _, _ := unstructured.NestedSlice(r.Object, "spec", "template", "spec", "initContainers")
+	assert.Equal(t, []interface{}{"migrate", "--password", redactedValue}, initContainers[0].(map[string]interface{})["command"])
+
+	containers, _, _ := unstructured.NestedSlice(r.Object, "spec", "template", "spec", "containers")
+	container := containers[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"--api_key=" + redactedValue, "--port=8080"}, container["args"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "DB_PASSWORD", "value": redactedValue},
+		map[string]interface{}{"name": "DB_HOST", "value": "db"},
+		map[string]interface{}{
+			"name":      "API_KEY",
+			"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "keys"}},
+		},
+	}, container["env"])
+}
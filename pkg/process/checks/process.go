@@ -59,6 +59,9 @@ type ProcessCheck struct {
 	// Create times by PID used in the network check
 	createTimes atomic.Value
 
+	// Processes with listening sockets exposed by the process-agent API
+	services atomic.Value
+
 	// SysprobeProcessModuleEnabled tells the process check wheither to use the RemoteSystemProbeUtil to gather privileged process stats
 	SysprobeProcessModuleEnabled bool
 
@@ -173,6 +176,7 @@ func (p *ProcessCheck) run(cfg *config.AgentConfig, groupID int32, collectRealTi
 		p.lastCPUTime = cpuTimes[0]
 		p.lastRun = time.Now()
 		p.storeCreateTimes()
+		p.storeServices(cfg, pidToCid)
 
 		if collectRealTime {
 			p.realtimeLastCPUTime = p.lastCPUTime
@@ -192,6 +196,7 @@ func (p *ProcessCheck) run(cfg *config.AgentConfig, groupID int32, collectRealTi
 	p.lastCPUTime = cpuTimes[0]
 	p.lastRun = time.Now()
 	p.storeCreateTimes()
+	p.storeServices(cfg, pidToCid)
 
 	result := &RunResult{
 		Standard: messages,
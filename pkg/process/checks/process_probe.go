@@ -30,7 +30,9 @@ func getProcessProbe() procutil.Probe {
 			}
 			log.Info("Using perf counters probe for process data collection")
 		}
-		processProbe = procutil.NewProcessProbe()
+		processProbe = procutil.NewProcessProbe(
+			procutil.WithListeningSockets(config.Datadog.GetBool("process_config.listening_sockets.enabled")),
+		)
 	})
 	return processProbe
 }
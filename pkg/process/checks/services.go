@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+// Service is a process listening on at least one socket
+type Service struct {
+	Pid              int32              `json:"pid"`
+	Name             string             `json:"name"`
+	Cmdline          []string           `json:"cmdline"`
+	ContainerID      string             `json:"container_id,omitempty"`
+	ListeningSockets []*ListeningSocket `json:"listening_sockets"`
+}
+
+// ListeningSocket is a socket on which a service accepts connections or datagrams
+type ListeningSocket struct {
+	Protocol string `json:"protocol"`
+	Address  string `json:"address"`
+	Port     uint16 `json:"port"`
+}
+
+// GetServices returns the processes with listening sockets collected by the last run of the check, sorted by PID.
+// It returns false if the check did not run yet.
+func (p *ProcessCheck) GetServices() ([]*Service, bool) {
+	result := p.services.Load()
+	if result == nil {
+		return nil, false
+	}
+	return result.([]*Service), true
+}
+
+// storeServices stores the processes of the last run with listening sockets
+func (p *ProcessCheck) storeServices(cfg *config.AgentConfig, pidToCid map[int]string) {
+	p.services.Store(servicesFromProcesses(cfg, p.lastProcs, pidToCid))
+}
+
+// servicesFromProcesses returns the processes with listening sockets. The blacklisted processes are skipped
+// and the command lines are scrubbed here as the processes aren't all formatted by the check.
+func servicesFromProcesses(cfg *config.AgentConfig, procs map[int32]*procutil.Process, pidToCid map[int]string) []*Service {
+	services := make([]*Service, 0)
+	for pid, proc := range procs {
+		if len(proc.ListeningSockets) == 0 {
+			continue
+		}
+		if config.IsBlacklisted(proc.Cmdline, cfg.Blacklist) {
+			continue
+		}
+
+		service := &Service{
+			Pid:              pid,
+			Name:             proc.Name,
+			Cmdline:          cfg.Scrubber.ScrubCmdline(proc.Cmdline),
+			ContainerID:      pidToCid[int(pid)],
+			ListeningSockets: make([]*ListeningSocket, 0, len(proc.ListeningSockets)),
+		}
+		for _, s := range proc.ListeningSockets {
+			service.ListeningSockets = append(service.ListeningSockets, &ListeningSocket{
+				Protocol: s.Protocol,
+				Address:  s.Address,
+				Port:     s.Port,
+			})
+		}
+		services = append(services, service)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Pid < services[j].Pid
+	})
+	return services
+}
@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checks
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+)
+
+func TestServicesFromProcesses(t *testing.T) {
+	cfg := config.NewDefaultAgentConfig()
+	procs := map[int32]*procutil.Process{
+		// processes without listening sockets are skipped
+		1: {Pid: 1, Name: "init", Cmdline: []string{"/sbin/init"}},
+		42: {
+			Pid:     42,
+			Name:    "redis-server",
+			Cmdline: []string{"redis-server", "*:6379"},
+			ListeningSockets: []*procutil.ListeningSocket{
+				{Protocol: "tcp", Address: "0.0.0.0", Port: 6379},
+			},
+		},
+		7: {
+			Pid:     7,
+			Name:    "postgres",
+			Cmdline: []string{"postgres", "-D", "/var/lib/postgresql/data"},
+			ListeningSockets: []*procutil.ListeningSocket{
+				{Protocol: "tcp", Address: "::", Port: 5432},
+				{Protocol: "tcp", Address: "0.0.0.0", Port: 5432},
+			},
+		},
+	}
+	pidToCid := map[int]string{42: "container-1"}
+
+	assert.Equal(t, []*Service{
+		{
+			Pid:     7,
+			Name:    "postgres",
+			Cmdline: []string{"postgres", "-D", "/var/lib/postgresql/data"},
+			ListeningSockets: []*ListeningSocket{
+				{Protocol: "tcp", Address: "::", Port: 5432},
+				{Protocol: "tcp", Address: "0.0.0.0", Port: 5432},
+			},
+		},
+		{
+			Pid:         42,
+			Name:        "redis-server",
+			Cmdline:     []string{"redis-server", "*:6379"},
+			ContainerID: "container-1",
+			ListeningSockets: []*ListeningSocket{
+				{Protocol: "tcp", Address: "0.0.0.0", Port: 6379},
+			},
+		},
+	}, servicesFromProcesses(cfg, procs, pidToCid))
+
+	assert.Empty(t, servicesFromProcesses(cfg, map[int32]*procutil.Process{}, nil))
+}
+
+func TestServicesFromProcessesScrubbed(t *testing.T) {
+	cfg := config.NewDefaultAgentConfig()
+	cfg.Blacklist = []*regexp.Regexp{regexp.MustCompile("vault")}
+	sockets := []*procutil.ListeningSocket{{Protocol: "tcp", Address: "0.0.0.0", Port: 3306}}
+	procs := map[int32]*procutil.Process{
+		// the command lines of the processes are not scrubbed by the check before its second run
+		10: {Pid: 10, Name: "mysqld", Cmdline: []string{"mysqld", "--password=secret"}, ListeningSockets: sockets},
+		// the blacklisted processes are skipped
+		11: {Pid: 11, Name: "vault", Cmdline: []string{"vault", "server"}, ListeningSockets: sockets},
+	}
+
+	services := servicesFromProcesses(cfg, procs, nil)
+	assert.Len(t, services, 1)
+	assert.Equal(t, []string{"mysqld", "--password=********"}, services[0].Cmdline)
+	// the processes of the check are left untouched
+	assert.Equal(t, []string{"mysqld", "--password=secret"}, procs[10].Cmdline)
+}
+
+func TestGetServices(t *testing.T) {
+	p := &ProcessCheck{}
+	_, ok := p.GetServices()
+	assert.False(t, ok)
+
+	p.lastProcs = map[int32]*procutil.Process{
+		1: {Pid: 1, Name: "init", Cmdline: []string{"/sbin/init"}},
+	}
+	p.storeServices(config.NewDefaultAgentConfig(), nil)
+	services, ok := p.GetServices()
+	assert.True(t, ok)
+	assert.Empty(t, services)
+}
@@ -280,6 +280,9 @@ func TestDefaultConfig(t *testing.T) {
 	assert.False(config.Datadog.GetBool("process_config.remote_tagger"))
 	assert.True(config.Datadog.GetBool("process_config.process_discovery.enabled"))
 	assert.Equal(4*time.Hour, config.Datadog.GetDuration("process_config.process_discovery.interval"))
+	assert.False(config.Datadog.GetBool("process_config.listening_sockets.enabled"))
+	assert.False(config.Datadog.GetBool("process_config.event_collection.enabled"))
+	assert.False(config.Datadog.GetBool("process_config.event_collection.datadog_events.enabled"))
 }
 
 func TestAgentConfigYamlAndSystemProbeConfig(t *testing.T) {
@@ -146,6 +146,21 @@ func (ds *DataScrubber) ScrubProcessCommand(p *procutil.Process) []string {
 	return p.Cmdline
 }
 
+// ScrubCmdline scrubs a command line without using the cache of the known processes,
+// it is safe to call from several goroutines
+func (ds *DataScrubber) ScrubCmdline(cmdline []string) []string {
+	if ds.StripAllArguments {
+		return ds.stripArguments(cmdline)
+	}
+
+	if !ds.Enabled {
+		return cmdline
+	}
+
+	scrubbed, _ := ds.ScrubCommand(cmdline)
+	return scrubbed
+}
+
 // IncrementCacheAge increments one cycle of cache memory age. If it reaches
 // cacheMaxCycles, the cache is restarted
 func (ds *DataScrubber) IncrementCacheAge() {
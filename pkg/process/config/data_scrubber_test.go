@@ -374,6 +374,18 @@ func TestScrubberStrippingAllArgument(t *testing.T) {
 	}
 }
 
+func TestScrubCmdline(t *testing.T) {
+	scrubber := setupDataScrubber(t)
+	assert.Equal(t, []string{"agent", "-password", "********"}, scrubber.ScrubCmdline([]string{"agent", "-password", "1234"}))
+	assert.Equal(t, []string{"agent", "-port", "8080"}, scrubber.ScrubCmdline([]string{"agent", "-port", "8080"}))
+
+	scrubber.Enabled = false
+	assert.Equal(t, []string{"agent", "-password", "1234"}, scrubber.ScrubCmdline([]string{"agent", "-password", "1234"}))
+
+	scrubber.StripAllArguments = true
+	assert.Equal(t, []string{"agent"}, scrubber.ScrubCmdline([]string{"agent", "-password", "1234"}))
+}
+
 func TestNoBlacklistedArgs(t *testing.T) {
 	cases := setupInsensitiveCmdlines()
 	scrubber := setupDataScrubber(t)
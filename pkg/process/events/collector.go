@@ -0,0 +1,339 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package events
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	processStatsd "github.com/DataDog/datadog-agent/pkg/process/statsd"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// maxBufferedEvents is the number of recent events kept for the clients of the local event stream
+	maxBufferedEvents = 1000
+	// maxTrackedProcesses bounds the number of processes whose exec information is kept until they exit
+	maxTrackedProcesses = 65536
+	// eventQueueSize is the number of events read from the source waiting to be handled
+	eventQueueSize = 4096
+)
+
+// For testing purpose
+var (
+	timeNow     = time.Now
+	readProcess = readProcessInfo
+)
+
+var (
+	defaultCollectorMu sync.RWMutex
+	defaultCollector   *Collector
+)
+
+// Default returns the collector started by Start, or nil if the event collection is not running
+func Default() *Collector {
+	defaultCollectorMu.RLock()
+	defer defaultCollectorMu.RUnlock()
+	return defaultCollector
+}
+
+// datadogEventSender submits Datadog events, it is implemented by the statsd client
+type datadogEventSender interface {
+	Event(e *statsd.Event) error
+}
+
+// processInfo holds what is known about a running process
+type processInfo struct {
+	ppid    uint32
+	cmdline []string
+	start   time.Time
+}
+
+// Collector collects the exec and exit events of the processes. The recent events are
+// buffered for the local event stream, and the abnormal exits can be submitted as Datadog events.
+type Collector struct {
+	source   eventSource
+	scrubber *config.DataScrubber
+	sender   datadogEventSender
+
+	// processes holds the processes forked or executed since the collection started, indexed by PID
+	processes map[uint32]*processInfo
+
+	// queue holds the events read from the source until they are handled, so that reading
+	// the exec information from procfs doesn't slow down the reads of the source
+	queue chan procEvent
+
+	mu     sync.RWMutex
+	seq    uint64
+	buffer []*Event
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start creates the default collector and starts collecting the events. The abnormal exits
+// are submitted as Datadog events through the statsd client if datadogEvents is true.
+func Start(scrubber *config.DataScrubber, datadogEvents bool) (*Collector, error) {
+	source, err := newEventSource()
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen to the process events: %w", err)
+	}
+
+	var sender datadogEventSender
+	if datadogEvents && processStatsd.Client != nil {
+		sender = processStatsd.Client
+	}
+	c := newCollector(source, scrubber, sender)
+	go c.run()
+
+	defaultCollectorMu.Lock()
+	defaultCollector = c
+	defaultCollectorMu.Unlock()
+
+	log.Info("Process events collection started")
+	return c, nil
+}
+
+func newCollector(source eventSource, scrubber *config.DataScrubber, sender datadogEventSender) *Collector {
+	return &Collector{
+		source:    source,
+		scrubber:  scrubber,
+		sender:    sender,
+		processes: make(map[uint32]*processInfo),
+		queue:     make(chan procEvent, eventQueueSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Stop stops the collection of the events
+func (c *Collector) Stop() {
+	close(c.stop)
+	<-c.done
+
+	defaultCollectorMu.Lock()
+	if defaultCollector == c {
+		defaultCollector = nil
+	}
+	defaultCollectorMu.Unlock()
+}
+
+// Events returns the buffered events with a sequence number greater than since, oldest first
+func (c *Collector) Events(since uint64) []*Event {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	events := make([]*Event, 0)
+	for _, e := range c.buffer {
+		if e.Seq > since {
+			events = append(events, e)
+		}
+	}
+	return events
+}
+
+func (c *Collector) run() {
+	defer close(c.done)
+	defer c.source.Close()
+
+	handled := make(chan struct{})
+	go c.handleEvents(handled)
+	defer func() {
+		close(c.queue)
+		<-handled
+	}()
+
+	dropped := 0
+	droppedLogLimit := util.NewLogLimit(1, time.Minute)
+	defer droppedLogLimit.Close()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+
+		procEvents, err := c.source.Read()
+		if err != nil {
+			if errors.Is(err, errReadTimeout) {
+				continue
+			}
+			log.Warnf("Unable to read the process events: %s", err)
+			continue
+		}
+
+		now := timeNow()
+		for _, pe := range procEvents {
+			pe.timestamp = now
+			select {
+			case c.queue <- pe:
+			default:
+				dropped++
+				if droppedLogLimit.ShouldLog() {
+					log.Warnf("The process events are read faster than they are handled, %d events dropped so far", dropped)
+				}
+			}
+		}
+	}
+}
+
+// handleEvents handles the queued events until the queue is closed, then closes done
+func (c *Collector) handleEvents(done chan struct{}) {
+	defer close(done)
+
+	for pe := range c.queue {
+		if e := c.handle(pe); e != nil {
+			c.publish(e)
+		}
+	}
+}
+
+// handle updates the known processes and returns the event to publish, if any
+func (c *Collector) handle(pe procEvent) *Event {
+	now := pe.timestamp
+
+	switch pe.kind {
+	case procEventFork:
+		// the creation of threads is ignored
+		if pe.pid != pe.tgid {
+			return nil
+		}
+		info := &processInfo{ppid: pe.parentTgid, start: now}
+		// the child runs the program of its parent until it executes a new one
+		if parent, ok := c.processes[pe.parentTgid]; ok {
+			info.cmdline = parent.cmdline
+		}
+		c.track(pe.tgid, info)
+		return nil
+
+	case procEventExec:
+		info, ok := c.processes[pe.tgid]
+		if !ok {
+			info = &processInfo{start: now}
+		}
+		ppid, cmdline, err := readProcess(pe.tgid)
+		if err != nil {
+			log.Debugf("Unable to read the information of the process %d: %s", pe.tgid, err)
+		} else {
+			info.ppid = ppid
+			info.cmdline = c.scrubber.ScrubCmdline(cmdline)
+		}
+		c.track(pe.tgid, info)
+
+		return &Event{
+			Type:      ExecEventType,
+			Timestamp: now,
+			Pid:       pe.tgid,
+			Ppid:      info.ppid,
+			Cmdline:   info.cmdline,
+		}
+
+	case procEventExit:
+		// the exit of threads is ignored
+		if pe.pid != pe.tgid {
+			return nil
+		}
+		e := &Event{
+			Type:      ExitEventType,
+			Timestamp: now,
+			Pid:       pe.tgid,
+			Ppid:      pe.parentTgid,
+		}
+		exitCode, signal := decodeExitStatus(pe.exitStatus)
+		e.ExitCode = &exitCode
+		e.ExitSignal = signal
+
+		if info, ok := c.processes[pe.tgid]; ok {
+			delete(c.processes, pe.tgid)
+			e.Ppid = info.ppid
+			e.Cmdline = info.cmdline
+			e.Duration = now.Sub(info.start)
+		}
+		return e
+	}
+
+	return nil
+}
+
+func (c *Collector) track(pid uint32, info *processInfo) {
+	if _, ok := c.processes[pid]; !ok && len(c.processes) >= maxTrackedProcesses {
+		return
+	}
+	c.processes[pid] = info
+}
+
+// publish buffers the event, and submits it as a Datadog event if it is an abnormal exit
+func (c *Collector) publish(e *Event) {
+	c.mu.Lock()
+	c.seq++
+	e.Seq = c.seq
+	if len(c.buffer) >= maxBufferedEvents {
+		copy(c.buffer, c.buffer[1:])
+		c.buffer = c.buffer[:len(c.buffer)-1]
+	}
+	c.buffer = append(c.buffer, e)
+	c.mu.Unlock()
+
+	if c.sender == nil || e.Type != ExitEventType || (*e.ExitCode == 0 && e.ExitSignal == 0) {
+		return
+	}
+	if err := c.sender.Event(datadogEvent(e)); err != nil {
+		log.Debugf("Unable to submit the exit event of the process %d: %s", e.Pid, err)
+	}
+}
+
+// datadogEvent converts the abnormal exit of a process to a Datadog event
+func datadogEvent(e *Event) *statsd.Event {
+	name := "unknown"
+	if len(e.Cmdline) > 0 {
+		// some processes rewrite their command line with spaces between the arguments
+		if fields := strings.Fields(e.Cmdline[0]); len(fields) > 0 {
+			name = filepath.Base(fields[0])
+		}
+	}
+
+	title := fmt.Sprintf("Process %s (%d) exited with code %d", name, e.Pid, *e.ExitCode)
+	if e.ExitSignal != 0 {
+		title = fmt.Sprintf("Process %s (%d) was killed by signal %d", name, e.Pid, e.ExitSignal)
+	}
+
+	text := strings.Join(e.Cmdline, " ")
+	if e.Duration > 0 {
+		text += fmt.Sprintf("\nRan for %s", e.Duration)
+	}
+
+	return &statsd.Event{
+		Title:          title,
+		Text:           text,
+		Timestamp:      e.Timestamp,
+		AlertType:      statsd.Warning,
+		SourceTypeName: "process",
+		Tags: []string{
+			"process_name:" + name,
+			"pid:" + strconv.Itoa(int(e.Pid)),
+			"exit_code:" + strconv.Itoa(int(*e.ExitCode)),
+			"exit_signal:" + strconv.Itoa(int(e.ExitSignal)),
+		},
+	}
+}
+
+// decodeExitStatus returns the exit code and the signal which terminated the process from its wait status
+func decodeExitStatus(status uint32) (exitCode uint32, signal uint32) {
+	if signal := status & 0x7f; signal != 0 {
+		return 0, signal
+	}
+	return (status >> 8) & 0xff, 0
+}
@@ -0,0 +1,198 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+)
+
+type fakeSource struct {
+	batches chan []procEvent
+}
+
+func (s *fakeSource) Read() ([]procEvent, error) {
+	select {
+	case events := <-s.batches:
+		return events, nil
+	case <-time.After(10 * time.Millisecond):
+		return nil, errReadTimeout
+	}
+}
+
+func (s *fakeSource) Close() error {
+	return nil
+}
+
+type fakeSender struct {
+	events []*statsd.Event
+}
+
+func (s *fakeSender) Event(e *statsd.Event) error {
+	s.events = append(s.events, e)
+	return nil
+}
+
+func setupCollector(t *testing.T, sender datadogEventSender) (*Collector, *time.Time) {
+	origTimeNow, origReadProcess := timeNow, readProcess
+	t.Cleanup(func() {
+		timeNow, readProcess = origTimeNow, origReadProcess
+	})
+
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	readProcess = func(pid uint32) (uint32, []string, error) {
+		switch pid {
+		case 100:
+			return 1, []string{"/usr/bin/mysql", "--password", "secret"}, nil
+		case 101:
+			return 100, []string{"/bin/sh", "-c", "backup.sh"}, nil
+		}
+		return 0, nil, fmt.Errorf("process %d not found", pid)
+	}
+
+	scrubber := config.NewDefaultDataScrubber()
+	return newCollector(&fakeSource{}, scrubber, sender), &now
+}
+
+func TestExecExit(t *testing.T) {
+	c, now := setupCollector(t, nil)
+
+	assert.Nil(t, c.handle(procEvent{timestamp: *now, kind: procEventFork, pid: 100, tgid: 100, parentPid: 1, parentTgid: 1}))
+
+	exec := c.handle(procEvent{timestamp: *now, kind: procEventExec, pid: 100, tgid: 100})
+	require.NotNil(t, exec)
+	assert.Equal(t, ExecEventType, exec.Type)
+	assert.Equal(t, uint32(100), exec.Pid)
+	assert.Equal(t, uint32(1), exec.Ppid)
+	assert.Equal(t, []string{"/usr/bin/mysql", "--password", "********"}, exec.Cmdline)
+
+	*now = now.Add(3 * time.Second)
+	exit := c.handle(procEvent{timestamp: *now, kind: procEventExit, pid: 100, tgid: 100, exitStatus: 2 << 8})
+	require.NotNil(t, exit)
+	assert.Equal(t, ExitEventType, exit.Type)
+	assert.Equal(t, uint32(1), exit.Ppid)
+	assert.Equal(t, exec.Cmdline, exit.Cmdline)
+	assert.Equal(t, uint32(2), *exit.ExitCode)
+	assert.Equal(t, uint32(0), exit.ExitSignal)
+	assert.Equal(t, 3*time.Second, exit.Duration)
+	assert.Empty(t, c.processes)
+}
+
+func TestForkWithoutExec(t *testing.T) {
+	c, now := setupCollector(t, nil)
+
+	c.handle(procEvent{timestamp: *now, kind: procEventExec, pid: 100, tgid: 100})
+	// the child runs the program of its parent
+	assert.Nil(t, c.handle(procEvent{timestamp: *now, kind: procEventFork, pid: 200, tgid: 200, parentPid: 100, parentTgid: 100}))
+	// the threads are ignored
+	assert.Nil(t, c.handle(procEvent{timestamp: *now, kind: procEventFork, pid: 201, tgid: 200, parentPid: 200, parentTgid: 200}))
+	assert.Nil(t, c.handle(procEvent{timestamp: *now, kind: procEventExit, pid: 201, tgid: 200}))
+
+	*now = now.Add(time.Second)
+	// killed by SIGKILL
+	exit := c.handle(procEvent{timestamp: *now, kind: procEventExit, pid: 200, tgid: 200, exitStatus: 9})
+	require.NotNil(t, exit)
+	assert.Equal(t, uint32(100), exit.Ppid)
+	assert.Equal(t, []string{"/usr/bin/mysql", "--password", "********"}, exit.Cmdline)
+	assert.Equal(t, uint32(0), *exit.ExitCode)
+	assert.Equal(t, uint32(9), exit.ExitSignal)
+	assert.Equal(t, time.Second, exit.Duration)
+}
+
+func TestExitUnknownProcess(t *testing.T) {
+	c, now := setupCollector(t, nil)
+
+	exit := c.handle(procEvent{timestamp: *now, kind: procEventExit, pid: 300, tgid: 300, parentTgid: 1})
+	require.NotNil(t, exit)
+	assert.Equal(t, uint32(1), exit.Ppid)
+	assert.Empty(t, exit.Cmdline)
+	assert.Equal(t, uint32(0), *exit.ExitCode)
+	assert.Zero(t, exit.Duration)
+}
+
+func TestEvents(t *testing.T) {
+	c, _ := setupCollector(t, nil)
+
+	for i := 0; i < maxBufferedEvents+10; i++ {
+		c.publish(&Event{Type: ExecEventType, Pid: uint32(i)})
+	}
+
+	events := c.Events(0)
+	require.Len(t, events, maxBufferedEvents)
+	// the oldest events are dropped
+	assert.Equal(t, uint64(11), events[0].Seq)
+	assert.Equal(t, uint32(10), events[0].Pid)
+
+	events = c.Events(uint64(maxBufferedEvents + 8))
+	require.Len(t, events, 2)
+	assert.Equal(t, uint64(maxBufferedEvents+9), events[0].Seq)
+	assert.Empty(t, c.Events(uint64(maxBufferedEvents+10)))
+}
+
+func TestDatadogEvents(t *testing.T) {
+	sender := &fakeSender{}
+	c, now := setupCollector(t, sender)
+
+	c.publish(c.handle(procEvent{timestamp: *now, kind: procEventExec, pid: 101, tgid: 101}))
+	c.publish(c.handle(procEvent{timestamp: *now, kind: procEventExec, pid: 100, tgid: 100}))
+	// the normal exits are not submitted
+	c.publish(c.handle(procEvent{timestamp: *now, kind: procEventExit, pid: 101, tgid: 101}))
+	c.publish(c.handle(procEvent{timestamp: *now, kind: procEventExit, pid: 100, tgid: 100, exitStatus: 1 << 8}))
+
+	require.Len(t, sender.events, 1)
+	e := sender.events[0]
+	assert.Equal(t, "Process mysql (100) exited with code 1", e.Title)
+	assert.Equal(t, "/usr/bin/mysql --password ********", e.Text)
+	assert.ElementsMatch(t, []string{"process_name:mysql", "pid:100", "exit_code:1", "exit_signal:0"}, e.Tags)
+	assert.Len(t, c.Events(0), 4)
+}
+
+func TestRun(t *testing.T) {
+	c, _ := setupCollector(t, nil)
+	source := &fakeSource{batches: make(chan []procEvent)}
+	c.source = source
+
+	go c.run()
+	source.batches <- []procEvent{
+		{kind: procEventExec, pid: 100, tgid: 100},
+		{kind: procEventExit, pid: 100, tgid: 100},
+	}
+	assert.Eventually(t, func() bool { return len(c.Events(0)) == 2 }, time.Second, 10*time.Millisecond)
+	c.Stop()
+}
+
+func TestRunDoesNotWaitForProcfs(t *testing.T) {
+	c, _ := setupCollector(t, nil)
+	source := &fakeSource{batches: make(chan []procEvent)}
+	c.source = source
+
+	unblock := make(chan struct{})
+	readProcess = func(pid uint32) (uint32, []string, error) {
+		<-unblock
+		return 1, []string{"/bin/true"}, nil
+	}
+
+	go c.run()
+	source.batches <- []procEvent{{kind: procEventExec, pid: 100, tgid: 100}}
+	// the source is read again while the exec information of the first batch is being read
+	select {
+	case source.batches <- []procEvent{{kind: procEventExit, pid: 100, tgid: 100}}:
+	case <-time.After(time.Second):
+		t.Fatal("the source isn't read while procfs is read")
+	}
+
+	close(unblock)
+	assert.Eventually(t, func() bool { return len(c.Events(0)) == 2 }, time.Second, 10*time.Millisecond)
+	c.Stop()
+}
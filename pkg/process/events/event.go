@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package events collects the exec and exit events of the processes as they happen,
+// so that the short-lived processes and the crashes missed by the periodic process
+// collection are reported.
+package events
+
+import (
+	"errors"
+	"time"
+)
+
+// errReadTimeout is returned by the event sources when no event was received before the read timeout
+var errReadTimeout = errors.New("read timeout")
+
+// EventType is the type of a process lifecycle event
+type EventType string
+
+const (
+	// ExecEventType is the type of the events sent when a process executes a new program
+	ExecEventType EventType = "exec"
+	// ExitEventType is the type of the events sent when a process exits
+	ExitEventType EventType = "exit"
+)
+
+// Event is a process lifecycle event
+type Event struct {
+	// Seq is the sequence number of the event, increasing with each collected event
+	Seq       uint64    `json:"seq"`
+	Type      EventType `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Pid       uint32    `json:"pid"`
+	Ppid      uint32    `json:"ppid"`
+	// Cmdline is scrubbed, it is empty for the exit events of the processes started before the collection
+	Cmdline []string `json:"cmdline,omitempty"`
+
+	// ExitCode and ExitSignal are only set for the exit events, the signal being 0 if the process exited normally
+	ExitCode   *uint32 `json:"exit_code,omitempty"`
+	ExitSignal uint32  `json:"exit_signal,omitempty"`
+	// Duration is the lifetime of the exited process, 0 if it started before the collection
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// procEventType is the type of an event of the kernel
+type procEventType int
+
+const (
+	procEventFork procEventType = iota
+	procEventExec
+	procEventExit
+)
+
+// procEvent is an event of the kernel, the PIDs are the kernel ones: pid is the
+// thread ID and tgid the process ID
+type procEvent struct {
+	kind procEventType
+
+	pid, tgid             uint32
+	parentPid, parentTgid uint32
+	// exitStatus is the wait status of an exited process
+	exitStatus uint32
+
+	// timestamp is the time at which the event was read from the source
+	timestamp time.Time
+}
+
+// eventSource reads the events of the kernel
+type eventSource interface {
+	// Read blocks until events are received, or the read timeout of the source expires
+	Read() ([]procEvent, error)
+	Close() error
+}
@@ -0,0 +1,226 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/native"
+)
+
+// constants of the proc connector, see include/uapi/linux/connector.h and cn_proc.h
+const (
+	cnIdxProc = 0x1
+	cnValProc = 0x1
+
+	procCnMcastListen = 0x1
+	procCnMcastIgnore = 0x2
+
+	cnProcEventFork = 0x00000001
+	cnProcEventExec = 0x00000002
+	cnProcEventExit = 0x80000000
+
+	// cnMsgSize is the size of struct cn_msg, preceding the proc event
+	cnMsgSize = 20
+	// procEventHeaderSize is the size of the what, cpu and timestamp fields of struct proc_event
+	procEventHeaderSize = 16
+
+	readTimeout = 1 // second
+)
+
+// netlinkSource reads the process events from the netlink proc connector
+type netlinkSource struct {
+	fd  int
+	buf []byte
+}
+
+func newEventSource() (eventSource, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_CONNECTOR)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the netlink socket: %w", err)
+	}
+
+	s := &netlinkSource{fd: fd, buf: make([]byte, 16*1024)}
+	if err := s.init(); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *netlinkSource) init() error {
+	if err := unix.Bind(s.fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: cnIdxProc}); err != nil {
+		return fmt.Errorf("unable to bind the netlink socket: %w", err)
+	}
+
+	// the read timeout lets the collector check whether it is stopped
+	tv := unix.Timeval{Sec: readTimeout}
+	if err := unix.SetsockoptTimeval(s.fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		return fmt.Errorf("unable to set the read timeout of the netlink socket: %w", err)
+	}
+
+	if err := s.sendOp(procCnMcastListen); err != nil {
+		return fmt.Errorf("unable to subscribe to the process events: %w", err)
+	}
+	return nil
+}
+
+// sendOp sends a multicast operation to the proc connector
+func (s *netlinkSource) sendOp(op uint32) error {
+	return unix.Sendto(s.fd, newOpMessage(op), 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK})
+}
+
+// Read reads the next batch of process events
+func (s *netlinkSource) Read() ([]procEvent, error) {
+	n, _, err := unix.Recvfrom(s.fd, s.buf, 0)
+	if err != nil {
+		if err == unix.EAGAIN || err == unix.EWOULDBLOCK || err == unix.EINTR {
+			return nil, errReadTimeout
+		}
+		return nil, err
+	}
+
+	msgs, err := syscall.ParseNetlinkMessage(s.buf[:n])
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the netlink message: %w", err)
+	}
+
+	var events []procEvent
+	for _, msg := range msgs {
+		if e, ok := parseProcEvent(msg.Data); ok {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// Close unsubscribes from the process events and closes the socket
+func (s *netlinkSource) Close() error {
+	_ = s.sendOp(procCnMcastIgnore)
+	return unix.Close(s.fd)
+}
+
+// newOpMessage builds the netlink message sending an operation to the proc connector
+func newOpMessage(op uint32) []byte {
+	size := unix.SizeofNlMsghdr + cnMsgSize + 4
+	msg := make([]byte, size)
+
+	// struct nlmsghdr
+	native.Endian.PutUint32(msg[0:], uint32(size))
+	native.Endian.PutUint16(msg[4:], unix.NLMSG_DONE)
+	native.Endian.PutUint32(msg[12:], uint32(unix.Getpid()))
+
+	// struct cn_msg
+	cn := msg[unix.SizeofNlMsghdr:]
+	native.Endian.PutUint32(cn[0:], cnIdxProc)
+	native.Endian.PutUint32(cn[4:], cnValProc)
+	native.Endian.PutUint16(cn[16:], 4)
+
+	native.Endian.PutUint32(cn[cnMsgSize:], op)
+	return msg
+}
+
+// parseProcEvent parses the struct cn_msg holding a process event, other events are ignored
+func parseProcEvent(data []byte) (procEvent, bool) {
+	if len(data) < cnMsgSize+procEventHeaderSize {
+		return procEvent{}, false
+	}
+	if native.Endian.Uint32(data[0:]) != cnIdxProc || native.Endian.Uint32(data[4:]) != cnValProc {
+		return procEvent{}, false
+	}
+
+	ev := data[cnMsgSize:]
+	what := native.Endian.Uint32(ev[0:])
+	body := ev[procEventHeaderSize:]
+
+	switch what {
+	case cnProcEventFork:
+		if len(body) < 16 {
+			return procEvent{}, false
+		}
+		return procEvent{
+			kind:       procEventFork,
+			parentPid:  native.Endian.Uint32(body[0:]),
+			parentTgid: native.Endian.Uint32(body[4:]),
+			pid:        native.Endian.Uint32(body[8:]),
+			tgid:       native.Endian.Uint32(body[12:]),
+		}, true
+
+	case cnProcEventExec:
+		if len(body) < 8 {
+			return procEvent{}, false
+		}
+		return procEvent{
+			kind: procEventExec,
+			pid:  native.Endian.Uint32(body[0:]),
+			tgid: native.Endian.Uint32(body[4:]),
+		}, true
+
+	case cnProcEventExit:
+		if len(body) < 16 {
+			return procEvent{}, false
+		}
+		e := procEvent{
+			kind:       procEventExit,
+			pid:        native.Endian.Uint32(body[0:]),
+			tgid:       native.Endian.Uint32(body[4:]),
+			exitStatus: native.Endian.Uint32(body[8:]),
+		}
+		// the parent is only reported since linux 4.18
+		if len(body) >= 24 {
+			e.parentPid = native.Endian.Uint32(body[16:])
+			e.parentTgid = native.Endian.Uint32(body[20:])
+		}
+		return e, true
+	}
+
+	return procEvent{}, false
+}
+
+// readProcessInfo reads the parent PID and the command line of a process from procfs
+func readProcessInfo(pid uint32) (uint32, []string, error) {
+	pidStr := strconv.Itoa(int(pid))
+
+	cmdline, err := ioutil.ReadFile(util.HostProc(pidStr, "cmdline"))
+	if err != nil {
+		return 0, nil, err
+	}
+	stat, err := ioutil.ReadFile(util.HostProc(pidStr, "stat"))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// the command name may contain spaces and parentheses, the fields follow the last parenthesis
+	idx := bytes.LastIndexByte(stat, ')')
+	if idx == -1 {
+		return 0, nil, fmt.Errorf("unexpected stat content for the process %d", pid)
+	}
+	fields := strings.Fields(string(stat[idx+1:]))
+	if len(fields) < 2 {
+		return 0, nil, fmt.Errorf("unexpected stat content for the process %d", pid)
+	}
+	ppid, err := strconv.ParseUint(fields[1], 10, 32)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to parse the parent PID of the process %d: %w", pid, err)
+	}
+
+	var args []string
+	for _, arg := range bytes.Split(bytes.TrimRight(cmdline, "\x00"), []byte{0}) {
+		args = append(args, string(arg))
+	}
+	return uint32(ppid), args, nil
+}
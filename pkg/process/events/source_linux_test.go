@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/util/native"
+)
+
+// newProcEventMessage builds a netlink message holding a proc event with the given fields
+func newProcEventMessage(what uint32, fields ...uint32) []byte {
+	size := unix.SizeofNlMsghdr + cnMsgSize + procEventHeaderSize + 4*len(fields)
+	msg := make([]byte, size)
+	native.Endian.PutUint32(msg[0:], uint32(size))
+	native.Endian.PutUint16(msg[4:], unix.NLMSG_DONE)
+
+	cn := msg[unix.SizeofNlMsghdr:]
+	native.Endian.PutUint32(cn[0:], cnIdxProc)
+	native.Endian.PutUint32(cn[4:], cnValProc)
+	native.Endian.PutUint16(cn[16:], uint16(procEventHeaderSize+4*len(fields)))
+
+	ev := cn[cnMsgSize:]
+	native.Endian.PutUint32(ev[0:], what)
+	for i, f := range fields {
+		native.Endian.PutUint32(ev[procEventHeaderSize+4*i:], f)
+	}
+	return msg
+}
+
+func TestParseProcEvent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		msg      []byte
+		expected procEvent
+		ok       bool
+	}{
+		"fork": {
+			msg:      newProcEventMessage(cnProcEventFork, 10, 10, 20, 20),
+			expected: procEvent{kind: procEventFork, parentPid: 10, parentTgid: 10, pid: 20, tgid: 20},
+			ok:       true,
+		},
+		"exec": {
+			msg:      newProcEventMessage(cnProcEventExec, 21, 20),
+			expected: procEvent{kind: procEventExec, pid: 21, tgid: 20},
+			ok:       true,
+		},
+		"exit": {
+			msg:      newProcEventMessage(cnProcEventExit, 20, 20, 1<<8, 17, 10, 10),
+			expected: procEvent{kind: procEventExit, pid: 20, tgid: 20, exitStatus: 1 << 8, parentPid: 10, parentTgid: 10},
+			ok:       true,
+		},
+		"exit without parent": {
+			msg:      newProcEventMessage(cnProcEventExit, 20, 20, 9, 17),
+			expected: procEvent{kind: procEventExit, pid: 20, tgid: 20, exitStatus: 9},
+			ok:       true,
+		},
+		"uid change": {
+			msg: newProcEventMessage(0x4, 20, 20, 0, 0),
+		},
+		"truncated": {
+			msg: newProcEventMessage(cnProcEventFork, 10, 10),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			msgs, err := syscall.ParseNetlinkMessage(tc.msg)
+			require.NoError(t, err)
+			require.Len(t, msgs, 1)
+
+			e, ok := parseProcEvent(msgs[0].Data)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, e)
+		})
+	}
+}
+
+func TestReadProcessInfo(t *testing.T) {
+	procDir := t.TempDir()
+	pidDir := filepath.Join(procDir, "42")
+	require.NoError(t, os.Mkdir(pidDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "cmdline"), []byte("python3\x00-m\x00http.server\x00"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(pidDir, "stat"), []byte("42 (my (app)) S 7 42 42 0 -1"), 0644))
+	t.Setenv("HOST_PROC", procDir)
+
+	ppid, cmdline, err := readProcessInfo(42)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), ppid)
+	assert.Equal(t, []string{"python3", "-m", "http.server"}, cmdline)
+
+	_, _, err = readProcessInfo(43)
+	assert.Error(t, err)
+}
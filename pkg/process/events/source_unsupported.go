@@ -0,0 +1,23 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !linux
+// +build !linux
+
+package events
+
+import (
+	"errors"
+)
+
+var errNotSupported = errors.New("the process events collection is only supported on linux")
+
+func newEventSource() (eventSource, error) {
+	return nil, errNotSupported
+}
+
+func readProcessInfo(_ uint32) (uint32, []string, error) {
+	return 0, nil, errNotSupported
+}
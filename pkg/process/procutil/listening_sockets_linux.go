@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package procutil
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/network/procnet"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const socketLinkPrefix = "socket:["
+
+// socketTable holds the listening sockets of a network namespace indexed by inode
+type socketTable map[uint64]*ListeningSocket
+
+// socketFiles lists the files of /proc/[pid]/net holding the sockets of the network namespace of the process
+var socketFiles = []struct {
+	name     string
+	protocol string
+	state    int64
+}{
+	{"tcp", "tcp", procnet.TCPListen},
+	{"tcp6", "tcp", procnet.TCPListen},
+	{"udp", "udp", procnet.TCPClose},
+	{"udp6", "udp", procnet.TCPClose},
+}
+
+// getListeningSockets returns the listening sockets opened by a process. The socket tables of the
+// network namespaces are cached in socketsByNetNS so that they are only read once per collection.
+func (p *probe) getListeningSockets(pidPath string, socketsByNetNS map[string]socketTable) []*ListeningSocket {
+	inodes := p.getSocketInodes(pidPath)
+	if len(inodes) == 0 {
+		return nil
+	}
+
+	netNS := p.getLinkWithAuthCheck(pidPath, filepath.Join("ns", "net"))
+	if netNS == "" {
+		return nil
+	}
+
+	table, ok := socketsByNetNS[netNS]
+	if !ok {
+		table = readSocketTable(filepath.Join(pidPath, "net"))
+		socketsByNetNS[netNS] = table
+	}
+
+	var sockets []*ListeningSocket
+	seen := make(map[uint64]struct{}, len(inodes))
+	for _, inode := range inodes {
+		socket, ok := table[inode]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[inode]; ok {
+			continue
+		}
+		seen[inode] = struct{}{}
+		sockets = append(sockets, socket)
+	}
+
+	sort.Slice(sockets, func(i, j int) bool {
+		if sockets[i].Protocol != sockets[j].Protocol {
+			return sockets[i].Protocol < sockets[j].Protocol
+		}
+		if sockets[i].Port != sockets[j].Port {
+			return sockets[i].Port < sockets[j].Port
+		}
+		return sockets[i].Address < sockets[j].Address
+	})
+	return sockets
+}
+
+// getSocketInodes returns the inodes of the sockets opened by a process from the links of /proc/[pid]/fd
+func (p *probe) getSocketInodes(pidPath string) []uint64 {
+	path := filepath.Join(pidPath, "fd")
+	if err := p.ensurePathReadable(path); err != nil {
+		return nil
+	}
+
+	d, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+
+	names, err := d.Readdirnames(-1)
+	if err != nil {
+		return nil
+	}
+
+	var inodes []uint64
+	for _, name := range names {
+		link, err := os.Readlink(filepath.Join(path, name))
+		if err != nil || !strings.HasPrefix(link, socketLinkPrefix) || !strings.HasSuffix(link, "]") {
+			continue
+		}
+		inode, err := strconv.ParseUint(link[len(socketLinkPrefix):len(link)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		inodes = append(inodes, inode)
+	}
+	return inodes
+}
+
+// readSocketTable reads the listening sockets of the /proc/[pid]/net/{tcp,tcp6,udp,udp6} files
+func readSocketTable(netPath string) socketTable {
+	table := make(socketTable)
+	for _, f := range socketFiles {
+		if err := readProcNetSockets(filepath.Join(netPath, f.name), f.protocol, f.state, table); err != nil {
+			log.Debugf("Unable to read sockets from %s: %s", filepath.Join(netPath, f.name), err)
+		}
+	}
+	return table
+}
+
+// readProcNetSockets adds the sockets of a /proc/net/ file in the given state to the table
+func readProcNetSockets(path string, protocol string, state int64, table socketTable) error {
+	return procnet.ReadWithState(path, state, func(s procnet.Socket) {
+		if s.Port == 0 || s.Inode == 0 {
+			return
+		}
+		ip, _, err := procnet.ParseAddress(s.LocalAddress)
+		if err != nil {
+			return
+		}
+		table[s.Inode] = &ListeningSocket{
+			Protocol: protocol,
+			Address:  ip.String(),
+			Port:     s.Port,
+		}
+	})
+}
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package procutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const procNetHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+func TestGetListeningSockets(t *testing.T) {
+	procRoot := t.TempDir()
+
+	// pid 100 listens on TCP ports 80 and 5432 and on UDP port 5353, and has an established connection
+	writeProcPID(t, procRoot, "100", map[string]string{
+		"0": "/dev/null",
+		"3": "socket:[1001]",
+		"4": "socket:[1002]",
+		"5": "socket:[1004]",
+		"6": "socket:[1005]",
+		// duplicated file descriptor
+		"7": "socket:[1001]",
+	})
+	writeProcNet(t, procRoot, "100", map[string]string{
+		"tcp": procNetHeader +
+			"   0: 00000000:1538 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+			"   1: 0100007F:1538 0100007F:D431 01 00000000:00000000 00:00000000 00000000   999        0 1002 1 0000000000000000 20 4 30 10 -1\n" +
+			"   2: 0100007F:18EB 00000000:0000 0A 00000000:00000000 00:00000000 00000000   999        0 1003 1 0000000000000000 100 0 0 10 0\n",
+		"tcp6": procNetHeader +
+			"   0: 00000000000000000000000000000000:0050 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 100 0 0 10 0\n",
+		"udp": procNetHeader +
+			"   0: 00000000:14E9 00000000:0000 07 00000000:00000000 00:00000000 00000000   999        0 1005 2 0000000000000000 0\n",
+	})
+
+	// pid 200 shares the network namespace of pid 100, its socket table is read from the cache
+	writeProcPID(t, procRoot, "200", map[string]string{
+		"3": "socket:[1003]",
+	})
+
+	// pid 300 has no socket
+	writeProcPID(t, procRoot, "300", map[string]string{
+		"0": "/dev/null",
+	})
+
+	probe := getProbeWithPermission(WithListeningSockets(true))
+	defer probe.Close()
+
+	socketsByNetNS := make(map[string]socketTable)
+	assert.Equal(t, []*ListeningSocket{
+		{Protocol: "tcp", Address: "::", Port: 80},
+		{Protocol: "tcp", Address: "0.0.0.0", Port: 5432},
+		{Protocol: "udp", Address: "0.0.0.0", Port: 5353},
+	}, probe.getListeningSockets(filepath.Join(procRoot, "100"), socketsByNetNS))
+	assert.Len(t, socketsByNetNS, 1)
+
+	assert.Equal(t, []*ListeningSocket{
+		{Protocol: "tcp", Address: "127.0.0.1", Port: 6379},
+	}, probe.getListeningSockets(filepath.Join(procRoot, "200"), socketsByNetNS))
+
+	assert.Empty(t, probe.getListeningSockets(filepath.Join(procRoot, "300"), socketsByNetNS))
+}
+
+// writeProcPID creates the fd links and the network namespace link of a process
+func writeProcPID(t *testing.T, procRoot, pid string, fds map[string]string) {
+	fdPath := filepath.Join(procRoot, pid, "fd")
+	require.NoError(t, os.MkdirAll(fdPath, 0755))
+	for fd, target := range fds {
+		require.NoError(t, os.Symlink(target, filepath.Join(fdPath, fd)))
+	}
+
+	nsPath := filepath.Join(procRoot, pid, "ns")
+	require.NoError(t, os.MkdirAll(nsPath, 0755))
+	require.NoError(t, os.Symlink("net:[4026531992]", filepath.Join(nsPath, "net")))
+}
+
+func writeProcNet(t *testing.T, procRoot, pid string, files map[string]string) {
+	netPath := filepath.Join(procRoot, pid, "net")
+	require.NoError(t, os.MkdirAll(netPath, 0755))
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(netPath, name), []byte(content), 0644))
+	}
+}
@@ -26,3 +26,8 @@ func WithPermission(enabled bool) Option {
 func WithBootTimeRefreshInterval(bootTimeRefreshInterval time.Duration) Option {
 	return func(p Probe) {}
 }
+
+// WithListeningSockets configures if process collection should fetch the listening sockets of the processes
+func WithListeningSockets(enabled bool) Option {
+	return func(p Probe) {}
+}
@@ -89,6 +89,15 @@ func WithBootTimeRefreshInterval(bootTimeRefreshInterval time.Duration) Option {
 	}
 }
 
+// WithListeningSockets configures if process collection should fetch the listening sockets of the processes
+func WithListeningSockets(enabled bool) Option {
+	return func(p Probe) {
+		if linuxProbe, ok := p.(*probe); ok {
+			linuxProbe.withListeningSockets = enabled
+		}
+	}
+}
+
 // probe is a service that fetches process related info on current host
 type probe struct {
 	bootTime     uint64
@@ -102,6 +111,7 @@ type probe struct {
 	// configurations
 	withPermission          bool
 	returnZeroPermStats     bool
+	withListeningSockets    bool
 	bootTimeRefreshInterval time.Duration
 }
 
@@ -208,6 +218,8 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 	}
 
 	procsByPID := make(map[int32]*Process, len(pids))
+	// the socket tables are read once per network namespace
+	socketsByNetNS := make(map[string]socketTable)
 	for _, pid := range pids {
 		pathForPID := filepath.Join(p.procRootLoc, strconv.Itoa(int(pid)))
 		if !util.PathExists(pathForPID) {
@@ -267,6 +279,9 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 				WriteBytes: -1,
 			} // use -1 values to represent "no permission"
 		}
+		if p.withListeningSockets {
+			proc.ListeningSockets = p.getListeningSockets(pathForPID, socketsByNetNS) // /proc/[pid]/fd and /proc/[pid]/net, requires permission checks
+		}
 		procsByPID[pid] = proc
 	}
 
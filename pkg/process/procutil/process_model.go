@@ -23,9 +23,19 @@ type Process struct {
 	Uids     []int32
 	Gids     []int32
 
+	// ListeningSockets holds the sockets on which the process listens (Linux only)
+	ListeningSockets []*ListeningSocket
+
 	Stats *Stats
 }
 
+// ListeningSocket holds a TCP socket in the listening state or an unconnected UDP socket
+type ListeningSocket struct {
+	Protocol string // "tcp" or "udp"
+	Address  string
+	Port     uint16
+}
+
 // DeepCopy creates a deep copy of Process
 func (p *Process) DeepCopy() *Process {
 	copy := &Process{
@@ -49,6 +59,13 @@ func (p *Process) DeepCopy() *Process {
 	for i := range p.Gids {
 		copy.Gids[i] = p.Gids[i]
 	}
+	if p.ListeningSockets != nil {
+		copy.ListeningSockets = make([]*ListeningSocket, len(p.ListeningSockets))
+		for i := range p.ListeningSockets {
+			s := *p.ListeningSockets[i]
+			copy.ListeningSockets[i] = &s
+		}
+	}
 	if p.Stats != nil {
 		copy.Stats = p.Stats.DeepCopy()
 	}
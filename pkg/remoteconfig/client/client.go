@@ -110,25 +110,8 @@ func (c *Client) Update(update Update) error {
 	}
 	newConfigs := newConfigList()
 	for targetPath, targetMeta := range newTargets.Targets() {
-		configMeta, err := parseConfigMeta(targetPath, *targetMeta.Custom)
-		if err != nil {
-			return err
-		}
-		_, hasProduct := c.products[configMeta.path.Product]
-		if !hasProduct || !configMeta.scopedToClient(c.id) {
-			continue
-		}
 		configContents, found := newTargets.TargetFile(targetPath)
-		if !found {
-			return fmt.Errorf("missing config file: %s", targetPath)
-		}
-		config := config{
-			meta:     configMeta,
-			contents: configContents,
-			hash:     configHash(configMeta, configContents),
-		}
-		err = newConfigs.addConfig(config)
-		if err != nil {
+		if err := c.addConfig(newConfigs, targetPath, *targetMeta.Custom, configContents, found); err != nil {
 			return err
 		}
 	}
@@ -136,3 +119,45 @@ func (c *Client) Update(update Update) error {
 	c.currentTargets = newTargets
 	return nil
 }
+
+// UnverifiedFile is a target file along with its custom metadata, read from a
+// trusted local source instead of being verified against the TUF metadata
+type UnverifiedFile struct {
+	Custom []byte
+	Raw    []byte
+}
+
+// UpdateUnverified replaces the configurations of the client with target files
+// whose integrity is not verified. It must only be used with trusted sources.
+func (c *Client) UpdateUnverified(files map[string]UnverifiedFile) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	newConfigs := newConfigList()
+	for targetPath, file := range files {
+		if err := c.addConfig(newConfigs, targetPath, file.Custom, file.Raw, true); err != nil {
+			return err
+		}
+	}
+	c.currentConfigs = newConfigs
+	return nil
+}
+
+// addConfig adds a target file to configs if it belongs to the products of the client
+func (c *Client) addConfig(configs *configList, targetPath string, custom []byte, contents []byte, found bool) error {
+	configMeta, err := parseConfigMeta(targetPath, custom)
+	if err != nil {
+		return err
+	}
+	_, hasProduct := c.products[configMeta.path.Product]
+	if !hasProduct || !configMeta.scopedToClient(c.id) {
+		return nil
+	}
+	if !found {
+		return fmt.Errorf("missing config file: %s", targetPath)
+	}
+	return configs.addConfig(config{
+		meta:     configMeta,
+		contents: contents,
+		hash:     configHash(configMeta, contents),
+	})
+}
@@ -281,6 +281,11 @@ func (m *Module) Reload() error {
 				}, nil)
 			},
 		}).
+		WithCorrelationScopes(map[rules.Scope]rules.CorrelationScopeFunc{
+			"process":      model.ProcessScopeKeys,
+			"process_tree": model.ProcessTreeScopeKeys,
+			"container":    model.ContainerScopeKeys,
+		}).
 		WithLogger(&seclog.PatternLogger{})
 
 	model := &model.Model{}
@@ -292,6 +297,7 @@ func (m *Module) Reload() error {
 	opts.WithStateScopes(map[rules.Scope]rules.VariableProviderFactory{
 		"process": m.probe.GetResolvers().ProcessResolver.NewProcessVariables,
 	})
+	opts.WithCorrelationScopes(sprobe.SECLCorrelationScopes)
 
 	ruleSet := m.probe.NewRuleSet(&opts)
 	loadErr := rules.LoadPolicies(policiesDir, ruleSet)
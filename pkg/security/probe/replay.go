@@ -0,0 +1,659 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// DiscarderReport describes a discarder found for an event that didn't match any rule
+type DiscarderReport struct {
+	Field eval.Field  `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// EventEvaluationReport describes the result of the evaluation of a serialized event
+type EventEvaluationReport struct {
+	EventType    eval.EventType     `json:"event_type"`
+	Date         time.Time          `json:"date,omitempty"`
+	MatchedRules []eval.RuleID      `json:"matched_rules,omitempty"`
+	Discarders   []*DiscarderReport `json:"discarders,omitempty"`
+}
+
+// ReplayEvaluator evaluates serialized events against a rule set, without a running probe
+type ReplayEvaluator struct {
+	ruleSet *rules.RuleSet
+	current *EventEvaluationReport
+}
+
+// NewReplayEvaluator returns a new replay evaluator for the given rule set
+func NewReplayEvaluator(ruleSet *rules.RuleSet) *ReplayEvaluator {
+	evaluator := &ReplayEvaluator{ruleSet: ruleSet}
+	ruleSet.AddListener(evaluator)
+	return evaluator
+}
+
+// RuleMatch is called by the rule set when a rule matches the evaluated event
+func (r *ReplayEvaluator) RuleMatch(rule *rules.Rule, event eval.Event) {
+	if r.current != nil {
+		r.current.MatchedRules = append(r.current.MatchedRules, rule.ID)
+	}
+}
+
+// EventDiscarderFound is called by the rule set when a discarder is found for the evaluated event
+func (r *ReplayEvaluator) EventDiscarderFound(rs *rules.RuleSet, event eval.Event, field eval.Field, eventType eval.EventType) {
+	if r.current == nil {
+		return
+	}
+
+	value, err := event.GetFieldValue(field)
+	if err != nil {
+		return
+	}
+
+	// the probe doesn't push discarders for these values
+	for _, invalid := range InvalidDiscarders[field] {
+		if value == invalid {
+			return
+		}
+	}
+
+	r.current.Discarders = append(r.current.Discarders, &DiscarderReport{Field: field, Value: value})
+}
+
+// Evaluate evaluates a serialized event against the rule set
+func (r *ReplayEvaluator) Evaluate(s *EventSerializer) (*EventEvaluationReport, error) {
+	event, err := NewEventFromSerializer(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r.current = &EventEvaluationReport{
+		EventType: event.GetType(),
+		Date:      event.Timestamp,
+	}
+	defer func() {
+		r.current = nil
+	}()
+
+	r.ruleSet.Evaluate(event)
+
+	return r.current, nil
+}
+
+// NewEventFromSerializer rebuilds the event described by a serialized event. The values resolved
+// by the probe at runtime, like the paths or the user names, are read from the serialized event.
+func NewEventFromSerializer(s *EventSerializer) (*model.Event, error) {
+	eventType := model.ParseEvalEventType(s.EventContextSerializer.Name)
+	if eventType == model.UnknownEventType {
+		return nil, fmt.Errorf("unknown event type `%s`", s.EventContextSerializer.Name)
+	}
+
+	event := &model.Event{
+		Type:      uint64(eventType),
+		Timestamp: s.Date,
+	}
+
+	if s.ProcessContextSerializer != nil {
+		pc, err := newProcessContextFromSerializer(s.ProcessContextSerializer)
+		if err != nil {
+			return nil, err
+		}
+		event.ProcessContext = *pc
+	}
+
+	if s.ContainerContextSerializer != nil {
+		event.ContainerContext.ID = s.ContainerContextSerializer.ID
+	} else {
+		event.ContainerContext.ID = event.ProcessContext.ContainerID
+	}
+
+	if s.NetworkContextSerializer != nil {
+		if err := setNetworkContextFromSerializer(&event.NetworkContext, s.NetworkContextSerializer); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := setEventFromSerializer(event, eventType, s); err != nil {
+		return nil, fmt.Errorf("invalid `%s` event: %w", s.EventContextSerializer.Name, err)
+	}
+
+	return event, nil
+}
+
+func setEventFromSerializer(event *model.Event, eventType model.EventType, s *EventSerializer) error {
+	var (
+		file        model.FileEvent
+		destination *FileSerializer
+		flags       []string
+		err         error
+	)
+	if fs := s.FileEventSerializer; fs != nil {
+		file = newFileEventFromSerializer(&fs.FileSerializer)
+		destination = fs.Destination
+		flags = fs.Flags
+	}
+	if destination == nil {
+		destination = &FileSerializer{}
+	}
+	retval := retvalFromOutcome(s.EventContextSerializer.Outcome)
+
+	switch eventType {
+	case model.FileChmodEventType:
+		event.Chmod.File, event.Chmod.Retval = file, retval
+		event.Chmod.Mode = uint32Value(destination.Mode)
+	case model.FileChownEventType:
+		event.Chown.File, event.Chown.Retval = file, retval
+		event.Chown.UID, event.Chown.GID = destination.UID, destination.GID
+		event.Chown.User, event.Chown.Group = destination.User, destination.Group
+	case model.FileLinkEventType:
+		event.Link.Source, event.Link.Retval = file, retval
+		event.Link.Target = newFileEventFromSerializer(destination)
+	case model.FileOpenEventType:
+		event.Open.File, event.Open.Retval = file, retval
+		event.Open.Mode = uint32Value(destination.Mode)
+		openFlags, err := flagsValue(flags)
+		if err != nil {
+			return err
+		}
+		event.Open.Flags = uint32(openFlags)
+	case model.FileMkdirEventType:
+		event.Mkdir.File, event.Mkdir.Retval = file, retval
+		event.Mkdir.Mode = uint32Value(destination.Mode)
+	case model.FileRmdirEventType:
+		event.Rmdir.File, event.Rmdir.Retval = file, retval
+	case model.FileUnlinkEventType:
+		event.Unlink.File, event.Unlink.Retval = file, retval
+		unlinkFlags, err := flagsValue(flags)
+		if err != nil {
+			return err
+		}
+		event.Unlink.Flags = uint32(unlinkFlags)
+	case model.FileRenameEventType:
+		event.Rename.Old, event.Rename.Retval = file, retval
+		event.Rename.New = newFileEventFromSerializer(destination)
+	case model.FileSetXAttrEventType:
+		event.SetXAttr.File, event.SetXAttr.Retval = file, retval
+		event.SetXAttr.Namespace, event.SetXAttr.Name = destination.XAttrNamespace, destination.XAttrName
+	case model.FileRemoveXAttrEventType:
+		event.RemoveXAttr.File, event.RemoveXAttr.Retval = file, retval
+		event.RemoveXAttr.Namespace, event.RemoveXAttr.Name = destination.XAttrNamespace, destination.XAttrName
+	case model.FileUtimesEventType:
+		event.Utimes.File, event.Utimes.Retval = file, retval
+		event.Utimes.Atime, event.Utimes.Mtime = timeValue(destination.Atime), timeValue(destination.Mtime)
+	case model.ExecEventType:
+		event.Exec.Process = event.ProcessContext.Process
+	case model.SetuidEventType:
+		var setuid SetuidSerializer
+		if err := decodeCredentialsDestination(s, &setuid); err != nil {
+			return err
+		}
+		event.SetUID = model.SetuidEvent{
+			UID:    uint32(setuid.UID),
+			User:   setuid.User,
+			EUID:   uint32(setuid.EUID),
+			EUser:  setuid.EUser,
+			FSUID:  uint32(setuid.FSUID),
+			FSUser: setuid.FSUser,
+		}
+	case model.SetgidEventType:
+		var setgid SetgidSerializer
+		if err := decodeCredentialsDestination(s, &setgid); err != nil {
+			return err
+		}
+		event.SetGID = model.SetgidEvent{
+			GID:     uint32(setgid.GID),
+			Group:   setgid.Group,
+			EGID:    uint32(setgid.EGID),
+			EGroup:  setgid.EGroup,
+			FSGID:   uint32(setgid.FSGID),
+			FSGroup: setgid.FSGroup,
+		}
+	case model.CapsetEventType:
+		var capset CapsetSerializer
+		if err := decodeCredentialsDestination(s, &capset); err != nil {
+			return err
+		}
+		if event.Capset.CapEffective, err = capabilitiesValue(capset.CapEffective); err != nil {
+			return err
+		}
+		if event.Capset.CapPermitted, err = capabilitiesValue(capset.CapPermitted); err != nil {
+			return err
+		}
+	case model.SELinuxEventType:
+		event.SELinux.File = file
+		if selinux := s.SELinuxEventSerializer; selinux != nil {
+			switch {
+			case selinux.BoolChange != nil:
+				event.SELinux.EventKind = model.SELinuxBoolChangeEventKind
+				event.SELinux.BoolName = selinux.BoolChange.Name
+				event.SELinux.BoolChangeValue = selinux.BoolChange.State
+			case selinux.EnforceStatus != nil:
+				event.SELinux.EventKind = model.SELinuxStatusChangeEventKind
+				event.SELinux.EnforceStatus = selinux.EnforceStatus.Status
+			case selinux.BoolCommit != nil:
+				event.SELinux.EventKind = model.SELinuxBoolCommitEventKind
+				event.SELinux.BoolCommitValue = selinux.BoolCommit.State
+			}
+		}
+	case model.BPFEventType:
+		event.BPF.Retval = retval
+		if bpf := s.BPFEventSerializer; bpf != nil {
+			return setBPFEventFromSerializer(&event.BPF, bpf)
+		}
+	case model.MMapEventType:
+		event.MMap.File, event.MMap.Retval = file, retval
+		if mmap := s.MMapEventSerializer; mmap != nil {
+			if event.MMap.Protection, err = bitmaskValue(mmap.Protection); err != nil {
+				return err
+			}
+			if event.MMap.Flags, err = bitmaskValue(mmap.Flags); err != nil {
+				return err
+			}
+			event.MMap.Offset, event.MMap.Len = mmap.Offset, mmap.Len
+		}
+	case model.MProtectEventType:
+		event.MProtect.Retval = retval
+		if mprotect := s.MProtectEventSerializer; mprotect != nil {
+			if event.MProtect.VMProtection, err = bitmaskValue(mprotect.VMProtection); err != nil {
+				return err
+			}
+			if event.MProtect.ReqProtection, err = bitmaskValue(mprotect.ReqProtection); err != nil {
+				return err
+			}
+		}
+	case model.PTraceEventType:
+		event.PTrace.Retval = retval
+		if ptrace := s.PTraceEventSerializer; ptrace != nil {
+			request, err := constantValue(ptrace.Request)
+			if err != nil {
+				return err
+			}
+			event.PTrace.Request = uint32(request)
+			if ptrace.Tracee != nil {
+				tracee, err := newProcessContextFromSerializer(ptrace.Tracee)
+				if err != nil {
+					return err
+				}
+				event.PTrace.Tracee = *tracee
+			}
+		}
+	case model.LoadModuleEventType:
+		event.LoadModule.File, event.LoadModule.Retval = file, retval
+		if module := s.ModuleEventSerializer; module != nil {
+			event.LoadModule.Name = module.Name
+			if module.LoadedFromMemory != nil {
+				event.LoadModule.LoadedFromMemory = *module.LoadedFromMemory
+			}
+		}
+	case model.UnloadModuleEventType:
+		event.UnloadModule.Retval = retval
+		if module := s.ModuleEventSerializer; module != nil {
+			event.UnloadModule.Name = module.Name
+		}
+	case model.SignalEventType:
+		event.Signal.Retval = retval
+		if signal := s.SignalEventSerializer; signal != nil {
+			signalType, err := constantValue(signal.Type)
+			if err != nil {
+				return err
+			}
+			event.Signal.Type, event.Signal.PID = uint32(signalType), signal.PID
+			if signal.Target != nil {
+				target, err := newProcessContextFromSerializer(signal.Target)
+				if err != nil {
+					return err
+				}
+				event.Signal.Target = *target
+			}
+		}
+	case model.SpliceEventType:
+		event.Splice.File, event.Splice.Retval = file, retval
+		if splice := s.SpliceEventSerializer; splice != nil {
+			entryFlag, err := bitmaskValue(splice.PipeEntryFlag)
+			if err != nil {
+				return err
+			}
+			exitFlag, err := bitmaskValue(splice.PipeExitFlag)
+			if err != nil {
+				return err
+			}
+			event.Splice.PipeEntryFlag, event.Splice.PipeExitFlag = uint32(entryFlag), uint32(exitFlag)
+		}
+	case model.DNSEventType:
+		if dns := s.DNSEventSerializer; dns != nil {
+			event.DNS.ID = dns.ID
+			if q := dns.Question; q != nil {
+				qtype, err := constantValue(q.Type)
+				if err != nil {
+					return err
+				}
+				qclass, err := constantValue(q.Class)
+				if err != nil {
+					return err
+				}
+				event.DNS.Name, event.DNS.Size, event.DNS.Count = q.Name, q.Size, q.Count
+				event.DNS.Type, event.DNS.Class = uint16(qtype), uint16(qclass)
+			}
+		}
+	}
+
+	return nil
+}
+
+func setBPFEventFromSerializer(e *model.BPFEvent, s *BPFEventSerializer) error {
+	cmd, err := constantValue(s.Cmd)
+	if err != nil {
+		return err
+	}
+	e.Cmd = uint32(cmd)
+
+	if s.Map != nil {
+		mapType, err := constantValue(s.Map.MapType)
+		if err != nil {
+			return err
+		}
+		e.Map.Name, e.Map.Type = s.Map.Name, uint32(mapType)
+	}
+
+	if s.Program != nil {
+		progType, err := constantValue(s.Program.ProgramType)
+		if err != nil {
+			return err
+		}
+		attachType, err := constantValue(s.Program.AttachType)
+		if err != nil {
+			return err
+		}
+		e.Program.Name, e.Program.Tag = s.Program.Name, s.Program.Tag
+		e.Program.Type, e.Program.AttachType = uint32(progType), uint32(attachType)
+
+		for _, helper := range s.Program.Helpers {
+			value, err := constantValue(helper)
+			if err != nil {
+				return err
+			}
+			e.Program.Helpers = append(e.Program.Helpers, uint32(value))
+		}
+	}
+
+	return nil
+}
+
+func setNetworkContextFromSerializer(nc *model.NetworkContext, s *NetworkContextSerializer) error {
+	l3Protocol, err := constantValue(s.L3Protocol)
+	if err != nil {
+		return err
+	}
+	l4Protocol, err := constantValue(s.L4Protocol)
+	if err != nil {
+		return err
+	}
+	nc.L3Protocol, nc.L4Protocol, nc.Size = uint16(l3Protocol), uint16(l4Protocol), s.Size
+
+	if s.Device != nil {
+		nc.Device = model.NetworkDeviceContext{NetNS: s.Device.NetNS, IfIndex: s.Device.IfIndex, IfName: s.Device.IfName}
+	}
+	if nc.Source, err = newIPPortContextFromSerializer(s.Source); err != nil {
+		return err
+	}
+	if nc.Destination, err = newIPPortContextFromSerializer(s.Destination); err != nil {
+		return err
+	}
+	return nil
+}
+
+func newIPPortContextFromSerializer(s *IPPortSerializer) (model.IPPortContext, error) {
+	if s == nil {
+		return model.IPPortContext{}, nil
+	}
+
+	ip := net.ParseIP(s.IP)
+	if ip == nil {
+		return model.IPPortContext{}, fmt.Errorf("invalid IP address `%s`", s.IP)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	return model.IPPortContext{
+		IPNet: net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)},
+		Port:  s.Port,
+	}, nil
+}
+
+// decodeCredentialsDestination decodes the credentials set by a setuid, setgid or capset event
+func decodeCredentialsDestination(s *EventSerializer, destination interface{}) error {
+	if s.ProcessContextSerializer == nil || s.ProcessContextSerializer.ProcessSerializer == nil ||
+		s.ProcessContextSerializer.Credentials == nil || s.ProcessContextSerializer.Credentials.Destination == nil {
+		return nil
+	}
+
+	// the destination was decoded as a generic map
+	data, err := json.Marshal(s.ProcessContextSerializer.Credentials.Destination)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, destination)
+}
+
+func newProcessContextFromSerializer(s *ProcessContextSerializer) (*model.ProcessContext, error) {
+	pc := &model.ProcessContext{}
+	if s.ProcessSerializer == nil {
+		return pc, nil
+	}
+
+	process, err := newProcessFromSerializer(s.ProcessSerializer)
+	if err != nil {
+		return nil, err
+	}
+	pc.Process = *process
+
+	// the first ancestor is the parent, it is the only one serialized in older events
+	ancestors := s.Ancestors
+	if len(ancestors) == 0 && s.Parent != nil {
+		ancestors = []*ProcessSerializer{s.Parent}
+	}
+
+	prev := &pc.Ancestor
+	for _, ancestor := range ancestors {
+		process, err := newProcessFromSerializer(ancestor)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &model.ProcessCacheEntry{}
+		entry.Process = *process
+		*prev = entry
+		prev = &entry.Ancestor
+	}
+
+	return pc, nil
+}
+
+func newProcessFromSerializer(s *ProcessSerializer) (*model.Process, error) {
+	process := &model.Process{
+		Pid:           s.Pid,
+		PPid:          s.PPid,
+		Tid:           s.Tid,
+		Comm:          s.Comm,
+		TTYName:       s.TTY,
+		ForkTime:      timeValue(s.ForkTime),
+		ExecTime:      timeValue(s.ExecTime),
+		ExitTime:      timeValue(s.ExitTime),
+		Argv0:         s.Argv0,
+		Argv:          s.Args,
+		Args:          strings.Join(s.Args, " "),
+		ArgsTruncated: s.ArgsTruncated,
+		Envs:          s.Envs,
+		EnvsTruncated: s.EnvsTruncated,
+	}
+
+	if s.Executable != nil {
+		process.FileEvent = newFileEventFromSerializer(s.Executable)
+	}
+	if s.Container != nil {
+		process.ContainerID = s.Container.ID
+	}
+
+	if s.Credentials != nil && s.Credentials.CredentialsSerializer != nil {
+		creds := s.Credentials.CredentialsSerializer
+		process.Credentials = model.Credentials{
+			UID:     uint32(creds.UID),
+			User:    creds.User,
+			GID:     uint32(creds.GID),
+			Group:   creds.Group,
+			EUID:    uint32(creds.EUID),
+			EUser:   creds.EUser,
+			EGID:    uint32(creds.EGID),
+			EGroup:  creds.EGroup,
+			FSUID:   uint32(creds.FSUID),
+			FSUser:  creds.FSUser,
+			FSGID:   uint32(creds.FSGID),
+			FSGroup: creds.FSGroup,
+		}
+
+		var err error
+		if process.CapEffective, err = capabilitiesValue(creds.CapEffective); err != nil {
+			return nil, err
+		}
+		if process.CapPermitted, err = capabilitiesValue(creds.CapPermitted); err != nil {
+			return nil, err
+		}
+	} else {
+		// legacy user and group fields
+		process.UID, process.User = uint32(s.UID), s.User
+		process.GID, process.Group = uint32(s.GID), s.Group
+	}
+
+	return process, nil
+}
+
+func newFileEventFromSerializer(s *FileSerializer) model.FileEvent {
+	fe := model.FileEvent{
+		FileFields: model.FileFields{
+			UID:     uint32(s.UID),
+			User:    s.User,
+			GID:     uint32(s.GID),
+			Group:   s.Group,
+			Mode:    uint16(uint32Value(s.Mode)),
+			MountID: uint32Value(s.MountID),
+		},
+		Filesystem: s.Filesystem,
+	}
+	fe.SetPathnameStr(s.Path)
+	fe.SetBasenameStr(s.Name)
+
+	if s.Inode != nil {
+		fe.Inode = *s.Inode
+	}
+	if s.InUpperLayer != nil {
+		if *s.InUpperLayer {
+			fe.Flags |= model.UpperLayer
+		} else {
+			fe.Flags |= model.LowerLayer
+		}
+	}
+	if s.Mtime != nil {
+		fe.MTime = uint64(s.Mtime.UnixNano())
+	}
+	if s.Ctime != nil {
+		fe.CTime = uint64(s.Ctime.UnixNano())
+	}
+
+	return fe
+}
+
+// retvalFromOutcome returns a syscall return value matching the serialized outcome. The exact
+// error of a failed syscall isn't serialized, only whether the access was refused.
+func retvalFromOutcome(outcome string) int64 {
+	switch outcome {
+	case "Refused":
+		return -int64(syscall.EACCES)
+	case "Error":
+		return -int64(syscall.EINVAL)
+	default:
+		return 0
+	}
+}
+
+// constantValue returns the value of a SECL constant, or of a number
+func constantValue(name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	if value, err := strconv.Atoi(name); err == nil {
+		return value, nil
+	}
+	if constant, ok := model.SECLConstants[name].(*eval.IntEvaluator); ok {
+		return constant.Value, nil
+	}
+	return 0, fmt.Errorf("unknown constant `%s`", name)
+}
+
+// bitmaskValue returns the value of a bitmask serialized as a `A | B` string
+func bitmaskValue(bitmask string) (int, error) {
+	if bitmask == "" {
+		return 0, nil
+	}
+	return flagsValue(strings.Split(bitmask, " | "))
+}
+
+// flagsValue returns the value of a bitmask serialized as a list of constants
+func flagsValue(flags []string) (int, error) {
+	var value int
+	for _, flag := range flags {
+		v, err := constantValue(strings.TrimSpace(flag))
+		if err != nil {
+			return 0, err
+		}
+		value |= v
+	}
+	return value, nil
+}
+
+func capabilitiesValue(capabilities []string) (uint64, error) {
+	var value uint64
+	for _, capability := range capabilities {
+		v, ok := model.KernelCapabilityConstants[capability]
+		if !ok {
+			parsed, err := strconv.ParseUint(capability, 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("unknown capability `%s`", capability)
+			}
+			v = parsed
+		}
+		value |= v
+	}
+	return value, nil
+}
+
+func uint32Value(v *uint32) uint32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+func timeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package probe
+
+import (
+	"encoding/json"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	seclog "github.com/DataDog/datadog-agent/pkg/security/log"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const serializedOpenEvent = `{
+	"evt": {"name": "open", "category": "File Activity", "outcome": "Success"},
+	"file": {
+		"path": "/etc/shadow",
+		"name": "shadow",
+		"inode": 1234,
+		"mode": 33184,
+		"uid": 0,
+		"gid": 42,
+		"flags": ["O_RDWR", "O_CREAT"],
+		"destination": {"mode": 384}
+	},
+	"process": {
+		"pid": 4321,
+		"ppid": 1,
+		"uid": 1000,
+		"gid": 1000,
+		"user": "john",
+		"comm": "vipw",
+		"executable": {"path": "/usr/sbin/vipw", "name": "vipw", "uid": 0, "gid": 0},
+		"args": ["-s"],
+		"credentials": {"uid": 0, "user": "root", "gid": 0, "euid": 0, "egid": 0, "fsuid": 0, "fsgid": 0, "cap_effective": ["CAP_CHOWN", "CAP_SYS_ADMIN"], "cap_permitted": ["CAP_CHOWN"]},
+		"ancestors": [
+			{"pid": 1000, "uid": 0, "gid": 0, "comm": "bash", "executable": {"path": "/usr/bin/bash", "name": "bash", "uid": 0, "gid": 0}},
+			{"pid": 1, "uid": 0, "gid": 0, "comm": "systemd", "executable": {"path": "/usr/lib/systemd/systemd", "name": "systemd", "uid": 0, "gid": 0}}
+		]
+	},
+	"container": {"id": "0123456789abcdef"},
+	"date": "2022-06-01T12:00:00Z"
+}`
+
+func newReplayRuleSet(t *testing.T, exprs ...string) *rules.RuleSet {
+	enabled := map[eval.EventType]bool{"*": true}
+
+	var opts rules.Opts
+	opts.
+		WithConstants(model.SECLConstants).
+		WithSupportedDiscarders(SupportedDiscarders).
+		WithEventTypeEnabled(enabled).
+		WithLegacyFields(model.SECLLegacyFields).
+		WithLogger(&seclog.PatternLogger{})
+
+	m := &model.Model{}
+	rs := rules.NewRuleSet(m, m.NewEvent, &opts)
+	addRuleExpr(t, rs, exprs...)
+	return rs
+}
+
+func decodeSerializedEvent(t *testing.T, data string) *EventSerializer {
+	var s EventSerializer
+	require.NoError(t, json.Unmarshal([]byte(data), &s))
+	return &s
+}
+
+func TestNewEventFromSerializer(t *testing.T) {
+	event, err := NewEventFromSerializer(decodeSerializedEvent(t, serializedOpenEvent))
+	require.NoError(t, err)
+
+	assert.Equal(t, "open", event.GetType())
+	assert.Equal(t, "/etc/shadow", event.Open.File.PathnameStr)
+	assert.Equal(t, uint64(1234), event.Open.File.Inode)
+	assert.Equal(t, uint32(syscall.O_RDWR|syscall.O_CREAT), event.Open.Flags)
+	assert.Equal(t, uint32(0600), event.Open.Mode)
+	assert.Equal(t, "0123456789abcdef", event.ContainerContext.ID)
+
+	process := event.ProcessContext.Process
+	assert.Equal(t, uint32(4321), process.Pid)
+	assert.Equal(t, "/usr/sbin/vipw", process.FileEvent.PathnameStr)
+	assert.Equal(t, "-s", process.Args)
+	assert.Equal(t, "root", process.User)
+	assert.Equal(t, model.KernelCapabilityConstants["CAP_CHOWN"]|model.KernelCapabilityConstants["CAP_SYS_ADMIN"], process.CapEffective)
+
+	require.NotNil(t, event.ProcessContext.Ancestor)
+	assert.Equal(t, "bash", event.ProcessContext.Ancestor.FileEvent.BasenameStr)
+	require.NotNil(t, event.ProcessContext.Ancestor.Ancestor)
+	assert.Equal(t, uint32(1), event.ProcessContext.Ancestor.Ancestor.Pid)
+	assert.Nil(t, event.ProcessContext.Ancestor.Ancestor.Ancestor)
+
+	_, err = NewEventFromSerializer(decodeSerializedEvent(t, `{"evt": {"name": "unknown"}}`))
+	assert.Error(t, err)
+
+	_, err = NewEventFromSerializer(decodeSerializedEvent(t, `{"evt": {"name": "open"}, "file": {"path": "/tmp/a", "flags": ["O_UNKNOWN"]}}`))
+	assert.Error(t, err)
+}
+
+func TestReplayEvaluator(t *testing.T) {
+	rs := newReplayRuleSet(t,
+		`open.file.path == "/etc/shadow" && process.ancestors.file.name == "bash"`,
+		`open.file.path == "/etc/shadow" && open.flags & O_CREAT > 0 && container.id != ""`,
+		`open.file.path == "/etc/passwd"`,
+		`exec.file.path == "/usr/bin/nc" && exec.argv in ["-l"]`,
+	)
+	evaluator := NewReplayEvaluator(rs)
+
+	report, err := evaluator.Evaluate(decodeSerializedEvent(t, serializedOpenEvent))
+	require.NoError(t, err)
+	assert.Equal(t, "open", report.EventType)
+	assert.ElementsMatch(t, []eval.RuleID{"ID0", "ID1"}, report.MatchedRules)
+	assert.Empty(t, report.Discarders)
+
+	report, err = evaluator.Evaluate(decodeSerializedEvent(t, `{
+		"evt": {"name": "open", "outcome": "Success"},
+		"file": {"path": "/var/log/syslog", "name": "syslog", "uid": 0, "gid": 0, "flags": ["O_RDONLY"]},
+		"process": {"pid": 12, "uid": 0, "gid": 0, "executable": {"path": "/usr/sbin/rsyslogd", "name": "rsyslogd", "uid": 0, "gid": 0}}
+	}`))
+	require.NoError(t, err)
+	assert.Empty(t, report.MatchedRules)
+	assert.Contains(t, report.Discarders, &DiscarderReport{Field: "open.file.path", Value: "/var/log/syslog"})
+
+	report, err = evaluator.Evaluate(decodeSerializedEvent(t, `{
+		"evt": {"name": "exec", "outcome": "Success"},
+		"process": {"pid": 13, "uid": 0, "gid": 0, "args": ["-l", "-p", "4444"], "executable": {"path": "/usr/bin/nc", "name": "nc", "uid": 0, "gid": 0}}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, []eval.RuleID{"ID3"}, report.MatchedRules)
+}
+
+func TestConstantValue(t *testing.T) {
+	value, err := bitmaskValue("PROT_READ | PROT_WRITE")
+	require.NoError(t, err)
+	assert.Equal(t, syscall.PROT_READ|syscall.PROT_WRITE, value)
+
+	// the unknown bits are serialized as a number
+	value, err = flagsValue([]string{"O_RDONLY", "1073741824"})
+	require.NoError(t, err)
+	assert.Equal(t, 1<<30, value)
+
+	assert.Equal(t, -int64(syscall.EACCES), retvalFromOutcome("Refused"))
+	assert.Equal(t, int64(0), retvalFromOutcome("Success"))
+}
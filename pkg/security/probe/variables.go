@@ -10,6 +10,8 @@ package probe
 
 import (
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
 )
 
 var (
@@ -19,4 +21,14 @@ var (
 			return int((*Event)(ctx.Object).ProcessContext.Process.Pid)
 		}, nil),
 	}
+
+	// SECLCorrelationScopes set of scopes of the sequence and threshold rules
+	SECLCorrelationScopes = map[rules.Scope]rules.CorrelationScopeFunc{
+		"process":      model.ProcessScopeKeys,
+		"process_tree": model.ProcessTreeScopeKeys,
+		"container": func(ctx *eval.Context) []string {
+			ev := (*Event)(ctx.Object)
+			return []string{ev.ResolveContainerID(&ev.ContainerContext)}
+		},
+	}
 )
@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package model
+
+import (
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// ProcessScopeKeys returns the scope key of the process of an event
+func ProcessScopeKeys(ctx *eval.Context) []string {
+	return []string{strconv.Itoa(int((*Event)(ctx.Object).ProcessContext.Process.Pid))}
+}
+
+// ProcessTreeScopeKeys returns the scope keys of the process of an event followed by the ones of its ancestors
+func ProcessTreeScopeKeys(ctx *eval.Context) []string {
+	processContext := &(*Event)(ctx.Object).ProcessContext
+
+	keys := []string{strconv.Itoa(int(processContext.Process.Pid))}
+	for ancestor := processContext.Ancestor; ancestor != nil; ancestor = ancestor.Ancestor {
+		keys = append(keys, strconv.Itoa(int(ancestor.Process.Pid)))
+	}
+	return keys
+}
+
+// ContainerScopeKeys returns the scope key of the container of an event
+func ContainerScopeKeys(ctx *eval.Context) []string {
+	return []string{(*Event)(ctx.Object).ContainerContext.ID}
+}
@@ -0,0 +1,277 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+// maxCorrelationStates is the maximum number of scopes for which the state of a correlation rule is kept
+const maxCorrelationStates = 4096
+
+// CorrelationScopeFunc returns the keys of the scopes of an event. The first key identifies the scope of
+// the event itself, the following ones identify the enclosing scopes, like the ancestors of a process.
+type CorrelationScopeFunc func(ctx *eval.Context) []string
+
+// SequenceDefinition describes the 'sequence' section of a rule. The rule matches when its expression
+// matches after the expressions listed in 'after', in order, within the same scope.
+type SequenceDefinition struct {
+	After  []string      `yaml:"after"`
+	Within time.Duration `yaml:"within"`
+	Scope  Scope         `yaml:"scope"`
+}
+
+// ThresholdDefinition describes the 'threshold' section of a rule. The rule matches when its expression
+// matched more than 'count' times within the same scope during 'window'.
+type ThresholdDefinition struct {
+	Count  int           `yaml:"count"`
+	Window time.Duration `yaml:"window"`
+	Scope  Scope         `yaml:"scope"`
+}
+
+func checkCorrelationScope(scope Scope, opts *Opts) (CorrelationScopeFunc, error) {
+	if scope == "" {
+		return nil, nil
+	}
+
+	scopeFnc, found := opts.CorrelationScopes[scope]
+	if !found {
+		return nil, fmt.Errorf("invalid scope '%s'", scope)
+	}
+	return scopeFnc, nil
+}
+
+// Check returns an error if the sequence definition is invalid
+func (s *SequenceDefinition) Check(opts *Opts) error {
+	if len(s.After) == 0 {
+		return errors.New("no expression defined in 'after'")
+	}
+
+	for _, expression := range s.After {
+		if expression == "" {
+			return errors.New("empty expression in 'after'")
+		}
+	}
+
+	if s.Within <= 0 {
+		return errors.New("'within' must be a positive duration")
+	}
+
+	_, err := checkCorrelationScope(s.Scope, opts)
+	return err
+}
+
+// Check returns an error if the threshold definition is invalid
+func (t *ThresholdDefinition) Check(opts *Opts) error {
+	if t.Count <= 0 {
+		return errors.New("'count' must be a positive number")
+	}
+
+	if t.Window <= 0 {
+		return errors.New("'window' must be a positive duration")
+	}
+
+	_, err := checkCorrelationScope(t.Scope, opts)
+	return err
+}
+
+// correlationStates holds the state of a correlation rule per scope. The least recently used
+// scopes are evicted when more than size scopes are tracked.
+type correlationStates struct {
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type correlationEntry struct {
+	key   string
+	value interface{}
+}
+
+func newCorrelationStates(size int) *correlationStates {
+	return &correlationStates{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (cs *correlationStates) get(key string) (interface{}, bool) {
+	element, found := cs.entries[key]
+	if !found {
+		return nil, false
+	}
+	cs.lru.MoveToFront(element)
+	return element.Value.(*correlationEntry).value, true
+}
+
+func (cs *correlationStates) set(key string, value interface{}) {
+	if element, found := cs.entries[key]; found {
+		element.Value.(*correlationEntry).value = value
+		cs.lru.MoveToFront(element)
+		return
+	}
+
+	if cs.lru.Len() >= cs.size {
+		if oldest := cs.lru.Back(); oldest != nil {
+			cs.remove(oldest)
+		}
+	}
+	cs.entries[key] = cs.lru.PushFront(&correlationEntry{key: key, value: value})
+}
+
+func (cs *correlationStates) delete(key string) {
+	if element, found := cs.entries[key]; found {
+		cs.remove(element)
+	}
+}
+
+func (cs *correlationStates) remove(element *list.Element) {
+	cs.lru.Remove(element)
+	delete(cs.entries, element.Value.(*correlationEntry).key)
+}
+
+func (cs *correlationStates) len() int {
+	return cs.lru.Len()
+}
+
+// scopeKeys returns the keys of the scopes of the event, a rule without scope uses a single global scope
+func scopeKeys(scopeFnc CorrelationScopeFunc, ctx *eval.Context) []string {
+	if scopeFnc == nil {
+		return []string{""}
+	}
+	return scopeFnc(ctx)
+}
+
+// sequenceProgress holds the progress of a sequence in a scope
+type sequenceProgress struct {
+	next  int
+	start time.Time
+}
+
+// sequence tracks the progress of a sequence rule
+type sequence struct {
+	// steps is the number of expressions preceding the rule expression
+	steps  int
+	within time.Duration
+	scope  CorrelationScopeFunc
+	states *correlationStates
+}
+
+func newSequence(def *SequenceDefinition, opts *Opts) *sequence {
+	scopeFnc, _ := checkCorrelationScope(def.Scope, opts)
+	return &sequence{
+		steps:  len(def.After),
+		within: def.Within,
+		scope:  scopeFnc,
+		states: newCorrelationStates(maxCorrelationStates),
+	}
+}
+
+// matchStep records that the expression of the given step matched an event of the given scopes, the
+// last step being the rule expression. It returns true when the sequence is complete.
+func (s *sequence) matchStep(step int, keys []string, now time.Time) bool {
+	if len(keys) == 0 {
+		return false
+	}
+
+	if step == 0 {
+		s.states.set(keys[0], &sequenceProgress{next: 1, start: now})
+		return false
+	}
+
+	for _, key := range keys {
+		value, found := s.states.get(key)
+		if !found {
+			continue
+		}
+
+		progress := value.(*sequenceProgress)
+		if now.Sub(progress.start) > s.within {
+			s.states.delete(key)
+			continue
+		}
+
+		if progress.next != step {
+			continue
+		}
+
+		if step == s.steps {
+			s.states.delete(key)
+			return true
+		}
+		progress.next++
+
+		return false
+	}
+
+	return false
+}
+
+// threshold counts the matches of a threshold rule
+type threshold struct {
+	count  int
+	window time.Duration
+	scope  CorrelationScopeFunc
+	states *correlationStates
+}
+
+func newThreshold(def *ThresholdDefinition, opts *Opts) *threshold {
+	scopeFnc, _ := checkCorrelationScope(def.Scope, opts)
+	return &threshold{
+		count:  def.Count,
+		window: def.Window,
+		scope:  scopeFnc,
+		states: newCorrelationStates(maxCorrelationStates),
+	}
+}
+
+// match records a match in the scope of the event. It returns true when the rule matched more
+// than count times during the window, the count of the scope is then reset.
+func (t *threshold) match(keys []string, now time.Time) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	key := keys[0]
+
+	var matches []time.Time
+	if value, found := t.states.get(key); found {
+		matches = value.([]time.Time)
+	}
+
+	// drop the matches that left the window
+	i := 0
+	for i < len(matches) && now.Sub(matches[i]) > t.window {
+		i++
+	}
+	matches = append(matches[i:], now)
+
+	if len(matches) > t.count {
+		t.states.delete(key)
+		return true
+	}
+	t.states.set(key, matches)
+
+	return false
+}
+
+// correlate returns whether a rule whose expression matched the event should be notified. The
+// steps of a sequence and the threshold rules below their count are not.
+func (r *Rule) correlate(ctx *eval.Context) bool {
+	switch {
+	case r.sequence != nil:
+		return r.sequence.matchStep(r.step, scopeKeys(r.sequence.scope, ctx), ctx.Now())
+	case r.threshold != nil:
+		return r.threshold.match(scopeKeys(r.threshold.scope, ctx), ctx.Now())
+	}
+	return true
+}
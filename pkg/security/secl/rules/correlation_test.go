@@ -0,0 +1,300 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+type testMatchHandler struct {
+	matches []RuleID
+}
+
+func (h *testMatchHandler) RuleMatch(rule *Rule, event eval.Event) {
+	h.matches = append(h.matches, rule.ID)
+}
+
+func (h *testMatchHandler) EventDiscarderFound(rs *RuleSet, event eval.Event, field string, eventType eval.EventType) {
+}
+
+func newCorrelationRuleSet(t *testing.T, ruleDefs ...*RuleDefinition) (*RuleSet, *testMatchHandler) {
+	enabled := map[eval.EventType]bool{"*": true}
+
+	var opts Opts
+	opts.
+		WithConstants(testConstants).
+		WithSupportedDiscarders(testSupportedDiscarders).
+		WithEventTypeEnabled(enabled).
+		WithCorrelationScopes(map[Scope]CorrelationScopeFunc{
+			"process": func(ctx *eval.Context) []string {
+				return []string{(*testEvent)(ctx.Object).process.name}
+			},
+		})
+
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, &opts)
+	if err := rs.AddRules(ruleDefs); err.ErrorOrNil() != nil {
+		t.Fatal(err)
+	}
+
+	handler := &testMatchHandler{}
+	rs.AddListener(handler)
+
+	return rs, handler
+}
+
+func newOpenTestEvent(processName, filename string) *testEvent {
+	event := &testEvent{kind: "open"}
+	event.process.name = processName
+	event.open.filename = filename
+	return event
+}
+
+func newMkdirTestEvent(processName, filename string) *testEvent {
+	event := &testEvent{kind: "mkdir"}
+	event.process.name = processName
+	event.mkdir.filename = filename
+	return event
+}
+
+func TestSequenceRule(t *testing.T) {
+	rs, handler := newCorrelationRuleSet(t, &RuleDefinition{
+		ID:         "download_then_mkdir",
+		Expression: `mkdir.filename == "/tmp/dir"`,
+		Sequence: &SequenceDefinition{
+			After: []string{
+				`open.filename == "/tmp/payload"`,
+				`open.filename == "/tmp/config"`,
+			},
+			Within: time.Minute,
+			Scope:  "process",
+		},
+	})
+
+	if len(rs.GetRules()) != 1 {
+		t.Fatalf("the steps of the sequence shouldn't be listed as rules: %v", rs.ListRuleIDs())
+	}
+	if bucket := rs.GetBucket("open"); bucket == nil || len(bucket.GetRules()) != 2 {
+		t.Fatal("the steps of the sequence should be in the bucket of their event type")
+	}
+
+	// the events out of order don't match
+	rs.Evaluate(newMkdirTestEvent("curl", "/tmp/dir"))
+	rs.Evaluate(newOpenTestEvent("curl", "/tmp/config"))
+	rs.Evaluate(newOpenTestEvent("curl", "/tmp/payload"))
+	rs.Evaluate(newMkdirTestEvent("curl", "/tmp/dir"))
+	if len(handler.matches) != 0 {
+		t.Fatalf("unexpected matches: %v", handler.matches)
+	}
+
+	// the events of another scope don't make the sequence progress
+	rs.Evaluate(newOpenTestEvent("bash", "/tmp/config"))
+	rs.Evaluate(newMkdirTestEvent("bash", "/tmp/dir"))
+	if len(handler.matches) != 0 {
+		t.Fatalf("unexpected matches: %v", handler.matches)
+	}
+
+	rs.Evaluate(newOpenTestEvent("curl", "/tmp/config"))
+	if !rs.Evaluate(newMkdirTestEvent("curl", "/tmp/dir")) {
+		t.Fatal("the sequence should match")
+	}
+	if len(handler.matches) != 1 || handler.matches[0] != "download_then_mkdir" {
+		t.Fatalf("unexpected matches: %v", handler.matches)
+	}
+
+	// the sequence restarts once matched
+	if rs.Evaluate(newMkdirTestEvent("curl", "/tmp/dir")) {
+		t.Fatal("the sequence shouldn't match again")
+	}
+}
+
+func TestSequenceWithin(t *testing.T) {
+	s := &sequence{steps: 1, within: 10 * time.Second, states: newCorrelationStates(maxCorrelationStates)}
+	now := time.Now()
+
+	s.matchStep(0, []string{"child"}, now)
+	if s.matchStep(1, []string{"child"}, now.Add(11*time.Second)) {
+		t.Fatal("the sequence shouldn't match after the delay")
+	}
+	if s.states.len() != 0 {
+		t.Fatal("the expired progress should be dropped")
+	}
+
+	// the sequence continues in the enclosing scopes
+	s.matchStep(0, []string{"parent", "init"}, now)
+	if !s.matchStep(1, []string{"child", "parent", "init"}, now.Add(5*time.Second)) {
+		t.Fatal("the sequence should match in the enclosing scope")
+	}
+}
+
+func TestThresholdRule(t *testing.T) {
+	rs, handler := newCorrelationRuleSet(t, &RuleDefinition{
+		ID:         "many_opens",
+		Expression: `open.filename == "/etc/shadow"`,
+		Threshold: &ThresholdDefinition{
+			Count:  2,
+			Window: time.Minute,
+			Scope:  "process",
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		rs.Evaluate(newOpenTestEvent("cat", "/etc/shadow"))
+		rs.Evaluate(newOpenTestEvent("vi", "/etc/shadow"))
+	}
+	if len(handler.matches) != 0 {
+		t.Fatalf("unexpected matches: %v", handler.matches)
+	}
+
+	if !rs.Evaluate(newOpenTestEvent("cat", "/etc/shadow")) {
+		t.Fatal("the threshold should be reached")
+	}
+	if rs.Evaluate(newOpenTestEvent("cat", "/etc/shadow")) {
+		t.Fatal("the count should be reset once the threshold is reached")
+	}
+}
+
+func TestThresholdWindow(t *testing.T) {
+	th := &threshold{count: 1, window: 10 * time.Second, states: newCorrelationStates(maxCorrelationStates)}
+	now := time.Now()
+
+	th.match([]string{"container"}, now)
+	if th.match([]string{"container"}, now.Add(11*time.Second)) {
+		t.Fatal("the matches out of the window shouldn't be counted")
+	}
+	if !th.match([]string{"container"}, now.Add(12*time.Second)) {
+		t.Fatal("the threshold should be reached")
+	}
+}
+
+func TestCorrelationStates(t *testing.T) {
+	states := newCorrelationStates(2)
+	states.set("a", 1)
+	states.set("b", 2)
+	states.get("a")
+	states.set("c", 3)
+
+	if states.len() != 2 {
+		t.Fatalf("expected 2 states, got %d", states.len())
+	}
+	if _, found := states.get("b"); found {
+		t.Fatal("the least recently used state should be evicted")
+	}
+	if value, found := states.get("a"); !found || value.(int) != 1 {
+		t.Fatal("the recently used state should be kept")
+	}
+}
+
+func TestCorrelationRuleInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleDef *RuleDefinition
+		err     string
+	}{
+		{
+			name: "both-sequence-and-threshold",
+			ruleDef: &RuleDefinition{
+				Sequence:  &SequenceDefinition{After: []string{`open.filename == "/tmp/a"`}, Within: time.Second},
+				Threshold: &ThresholdDefinition{Count: 1, Window: time.Second},
+			},
+			err: "only one of 'sequence' and 'threshold'",
+		},
+		{
+			name: "no-step",
+			ruleDef: &RuleDefinition{
+				Sequence: &SequenceDefinition{Within: time.Second},
+			},
+			err: "no expression defined in 'after'",
+		},
+		{
+			name: "no-within",
+			ruleDef: &RuleDefinition{
+				Sequence: &SequenceDefinition{After: []string{`open.filename == "/tmp/a"`}},
+			},
+			err: "'within' must be a positive duration",
+		},
+		{
+			name: "invalid-step",
+			ruleDef: &RuleDefinition{
+				Sequence: &SequenceDefinition{After: []string{`open.filename ==`}, Within: time.Second},
+			},
+			err: "syntax error",
+		},
+		{
+			name: "invalid-scope",
+			ruleDef: &RuleDefinition{
+				Threshold: &ThresholdDefinition{Count: 1, Window: time.Second, Scope: "container"},
+			},
+			err: "invalid scope 'container'",
+		},
+		{
+			name: "no-count",
+			ruleDef: &RuleDefinition{
+				Threshold: &ThresholdDefinition{Window: time.Second},
+			},
+			err: "'count' must be a positive number",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rs, _ := newCorrelationRuleSet(t)
+
+			test.ruleDef.ID = "invalid_rule"
+			test.ruleDef.Expression = `mkdir.filename == "/tmp/dir"`
+
+			_, err := rs.AddRule(test.ruleDef)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("expected error containing `%s`, got `%v`", test.err, err)
+			}
+			if len(rs.GetEventTypes()) != 0 {
+				t.Fatal("the invalid rule shouldn't be added")
+			}
+		})
+	}
+}
+
+func TestLoadCorrelationPolicy(t *testing.T) {
+	policy, err := LoadPolicy(strings.NewReader(`
+rules:
+  - id: download_then_mkdir
+    expression: mkdir.filename == "/tmp/dir"
+    sequence:
+      after:
+        - open.filename == "/tmp/payload"
+      within: 30s
+      scope: process
+  - id: many_opens
+    expression: open.filename == "/etc/shadow"
+    threshold:
+      count: 10
+      window: 1m
+      scope: process
+`), "correlation.policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if policy.Rules[0].Sequence == nil || policy.Rules[0].Sequence.Within != 30*time.Second {
+		t.Fatalf("unexpected sequence: %+v", policy.Rules[0].Sequence)
+	}
+	if policy.Rules[1].Threshold == nil || policy.Rules[1].Threshold.Window != time.Minute || policy.Rules[1].Threshold.Count != 10 {
+		t.Fatalf("unexpected threshold: %+v", policy.Rules[1].Threshold)
+	}
+
+	_, rules, mErr := policy.GetValidMacroAndRules()
+	if mErr.ErrorOrNil() != nil {
+		t.Fatal(mErr)
+	}
+
+	rs, _ := newCorrelationRuleSet(t, rules...)
+	if len(rs.GetRules()) != 2 {
+		t.Fatalf("unexpected rules: %v", rs.ListRuleIDs())
+	}
+}
@@ -24,6 +24,7 @@ type Opts struct {
 	ReservedRuleIDs     []RuleID
 	EventTypeEnabled    map[eval.EventType]bool
 	StateScopes         map[Scope]VariableProviderFactory
+	CorrelationScopes   map[Scope]CorrelationScopeFunc
 	Logger              Logger
 }
 
@@ -86,3 +87,9 @@ func (o *Opts) WithStateScopes(stateScopes map[Scope]VariableProviderFactory) *O
 	o.StateScopes = stateScopes
 	return o
 }
+
+// WithCorrelationScopes set the scopes of the sequence and threshold rules
+func (o *Opts) WithCorrelationScopes(scopes map[Scope]CorrelationScopeFunc) *Opts {
+	o.CorrelationScopes = scopes
+	return o
+}
@@ -64,14 +64,16 @@ type RuleID = string
 
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID          RuleID             `yaml:"id"`
-	Version     string             `yaml:"version"`
-	Expression  string             `yaml:"expression"`
-	Description string             `yaml:"description"`
-	Tags        map[string]string  `yaml:"tags"`
-	Disabled    bool               `yaml:"disabled"`
-	Combine     CombinePolicy      `yaml:"combine"`
-	Actions     []ActionDefinition `yaml:"actions"`
+	ID          RuleID               `yaml:"id"`
+	Version     string               `yaml:"version"`
+	Expression  string               `yaml:"expression"`
+	Description string               `yaml:"description"`
+	Tags        map[string]string    `yaml:"tags"`
+	Disabled    bool                 `yaml:"disabled"`
+	Combine     CombinePolicy        `yaml:"combine"`
+	Actions     []ActionDefinition   `yaml:"actions"`
+	Sequence    *SequenceDefinition  `yaml:"sequence"`
+	Threshold   *ThresholdDefinition `yaml:"threshold"`
 	Policy      *Policy
 }
 
@@ -91,6 +93,12 @@ func (rd *RuleDefinition) MergeWith(rd2 *RuleDefinition) error {
 	switch rd2.Combine {
 	case OverridePolicy:
 		rd.Expression = rd2.Expression
+		if rd2.Sequence != nil {
+			rd.Sequence = rd2.Sequence
+		}
+		if rd2.Threshold != nil {
+			rd.Threshold = rd2.Threshold
+		}
 	default:
 		if !rd2.Disabled {
 			return &ErrRuleLoad{Definition: rd2, Err: ErrInternalIDConflict}
@@ -138,6 +146,11 @@ type SetDefinition struct {
 type Rule struct {
 	*eval.Rule
 	Definition *RuleDefinition
+
+	// sequence is set for the rules of a sequence, step being the index of the rule in the sequence
+	sequence  *sequence
+	step      int
+	threshold *threshold
 }
 
 // RuleSetListener describes the methods implemented by an object used to be
@@ -283,15 +296,87 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrDefinitionIDConflict}
 	}
 
+	if ruleDef.Sequence != nil && ruleDef.Threshold != nil {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: errors.New("only one of 'sequence' and 'threshold' can be defined")}
+	}
+
 	var tags []string
 	for k, v := range ruleDef.Tags {
 		tags = append(tags, k+":"+v)
 	}
 
+	rule, err := rs.newRule(ruleDef, ruleDef.ID, ruleDef.Expression, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rules matching the events preceding the rule expression in the sequence
+	var steps []*Rule
+
+	if ruleDef.Sequence != nil {
+		if err := ruleDef.Sequence.Check(rs.opts); err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("invalid sequence: %w", err)}
+		}
+
+		rule.sequence = newSequence(ruleDef.Sequence, rs.opts)
+		rule.step = len(ruleDef.Sequence.After)
+
+		for i, expression := range ruleDef.Sequence.After {
+			step, err := rs.newRule(ruleDef, fmt.Sprintf("%s.after[%d]", ruleDef.ID, i), expression, tags)
+			if err != nil {
+				return nil, err
+			}
+			step.sequence = rule.sequence
+			step.step = i
+
+			steps = append(steps, step)
+		}
+	}
+
+	if ruleDef.Threshold != nil {
+		if err := ruleDef.Threshold.Check(rs.opts); err != nil {
+			return nil, &ErrRuleLoad{Definition: ruleDef, Err: fmt.Errorf("invalid threshold: %w", err)}
+		}
+
+		rule.threshold = newThreshold(ruleDef.Threshold, rs.opts)
+	}
+
+	if err := rs.addRuleToBuckets(rule); err != nil {
+		return nil, err
+	}
+
+	// the steps are evaluated after the rule and in reverse order so that a single event can't
+	// make a sequence progress by more than one step
+	for i := len(steps) - 1; i >= 0; i-- {
+		if err := rs.addRuleToBuckets(steps[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	rs.rules[ruleDef.ID] = rule
+
+	// Generate evaluator for fields that are used in variables
+	for _, action := range rule.Definition.Actions {
+		if action.Set != nil && action.Set.Field != "" {
+			if _, found := rs.fieldEvaluators[action.Set.Field]; !found {
+				evaluator, err := rs.model.GetEvaluator(action.Set.Field, "")
+				if err != nil {
+					return nil, err
+				}
+				rs.fieldEvaluators[action.Set.Field] = evaluator
+			}
+		}
+	}
+
+	return rule.Rule, nil
+}
+
+// newRule parses the expression and generates the evaluator of a rule
+func (rs *RuleSet) newRule(ruleDef *RuleDefinition, id RuleID, expression string, tags []string) (*Rule, error) {
 	rule := &Rule{
 		Rule: &eval.Rule{
-			ID:         ruleDef.ID,
-			Expression: ruleDef.Expression,
+			ID:         id,
+			Expression: expression,
 			Tags:       tags,
 		},
 		Definition: ruleDef,
@@ -317,6 +402,11 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		}
 	}
 
+	return rule, nil
+}
+
+// addRuleToBuckets adds the rule to the buckets of its event types
+func (rs *RuleSet) addRuleToBuckets(rule *Rule) error {
 	for _, event := range rule.GetEvaluator().EventTypes {
 		bucket, exists := rs.eventRuleBuckets[event]
 		if !exists {
@@ -325,29 +415,14 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		}
 
 		if err := bucket.AddRule(rule); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	// Merge the fields of the new rule with the existing list of fields of the ruleset
 	rs.AddFields(rule.GetEvaluator().GetFields())
 
-	rs.rules[ruleDef.ID] = rule
-
-	// Generate evaluator for fields that are used in variables
-	for _, action := range rule.Definition.Actions {
-		if action.Set != nil && action.Set.Field != "" {
-			if _, found := rs.fieldEvaluators[action.Set.Field]; !found {
-				evaluator, err := rs.model.GetEvaluator(action.Set.Field, "")
-				if err != nil {
-					return nil, err
-				}
-				rs.fieldEvaluators[action.Set.Field] = evaluator
-			}
-		}
-	}
-
-	return rule.Rule, nil
+	return nil
 }
 
 // NotifyRuleMatch notifies all the ruleset listeners that an event matched a rule
@@ -508,6 +583,10 @@ func (rs *RuleSet) Evaluate(event eval.Event) bool {
 
 	for _, rule := range bucket.rules {
 		if rule.GetEvaluator().Eval(ctx) {
+			if !rule.correlate(ctx) {
+				continue
+			}
+
 			rs.logger.Tracef("Rule `%s` matches with event `%s`\n", rule.ID, event)
 
 			rs.NotifyRuleMatch(rule, event)
@@ -341,6 +341,13 @@ func expvarStats(stats map[string]interface{}) (map[string]interface{}, error) {
 	json.Unmarshal(autoConfigStatsJSON, &autoConfigStats) //nolint:errcheck
 	stats["autoConfigStats"] = autoConfigStats
 
+	if schedulerStatsVar := expvar.Get("scheduler"); schedulerStatsVar != nil {
+		schedulerStatsJSON := []byte(schedulerStatsVar.String())
+		schedulerStats := make(map[string]interface{})
+		json.Unmarshal(schedulerStatsJSON, &schedulerStats) //nolint:errcheck
+		stats["schedulerStats"] = schedulerStats
+	}
+
 	checkSchedulerStatsJSON := []byte(expvar.Get("CheckScheduler").String())
 	checkSchedulerStats := make(map[string]interface{})
 	json.Unmarshal(checkSchedulerStatsJSON, &checkSchedulerStats) //nolint:errcheck
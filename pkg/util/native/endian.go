@@ -3,29 +3,25 @@
 // This product includes software developed at Datadog (https://www.datadoghq.com/).
 // Copyright 2016-present Datadog, Inc.
 
-//go:build linux_bpf
-// +build linux_bpf
-
-package tracer
+// Package native exposes properties of the host the agent runs on
+package native
 
 import (
 	"encoding/binary"
 	"unsafe"
 )
 
-var (
-	nativeEndian binary.ByteOrder
-)
+// Endian is the byte order of the host, either binary.LittleEndian or binary.BigEndian
+var Endian binary.ByteOrder = hostEndian()
 
 // In lack of binary.NativeEndian ...
-func init() {
+func hostEndian() binary.ByteOrder {
 	var i int32 = 0x01020304
 	u := unsafe.Pointer(&i)
 	pb := (*byte)(u)
 	b := *pb
 	if b == 0x04 {
-		nativeEndian = binary.LittleEndian
-	} else {
-		nativeEndian = binary.BigEndian
+		return binary.LittleEndian
 	}
+	return binary.BigEndian
 }
@@ -197,6 +197,17 @@ DATADOG_AGENT_RTLOADER_API char *run_check(rtloader_t *, rtloader_pyobject_t *ch
 */
 DATADOG_AGENT_RTLOADER_API void cancel_check(rtloader_t *, rtloader_pyobject_t *check);
 
+/*! \fn void interrupt_check(rtloader_t *, rtloader_pyobject_t *check)
+    \brief Interrupts a running check instance by raising a `CheckInterruptedError` in
+    the thread running it. The exception is raised the next time the check runs python
+    code, a call blocked outside of the interpreter being interrupted once it returns.
+    It is a noop if the check isn't running.
+    \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
+    \param check A rtloader_pyobject_t * pointer to the check instance we wish to interrupt.
+    \sa rtloader_pyobject_t, rtloader_t
+*/
+DATADOG_AGENT_RTLOADER_API void interrupt_check(rtloader_t *, rtloader_pyobject_t *check);
+
 /*! \fn char **get_checks_warnings(rtloader_t *, rtloader_pyobject_t *check)
     \brief Get all warnings, if any, for a check instance.
     \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
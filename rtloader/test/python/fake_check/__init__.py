@@ -1,6 +1,10 @@
+import time
+
 from datadog_checks.base.checks import AgentCheck
 
 was_canceled = False
+block_run = False
+running = False
 
 # Fake check for testing purposes
 class FakeCheck(AgentCheck):
@@ -9,6 +13,19 @@ class FakeCheck(AgentCheck):
         assert not was_canceled
         was_canceled = True
 
+    def run(self):
+        global running
+        if not block_run:
+            return super(FakeCheck, self).run()
+
+        # run until interrupted
+        running = True
+        try:
+            while True:
+                time.sleep(0.01)
+        finally:
+            running = False
+
     def get_warnings(self):
         return ["warning 1", "warning 2", "warning 3"]
 
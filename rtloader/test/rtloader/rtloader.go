@@ -17,6 +17,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 	"unsafe"
 
 	common "github.com/DataDog/datadog-agent/rtloader/test/common"
@@ -255,6 +256,75 @@ func cancelFakeCheck() error {
 	return fetchError()
 }
 
+func runInterruptedFakeCheck() error {
+	var module *C.rtloader_pyobject_t
+	var class *C.rtloader_pyobject_t
+	var check *C.rtloader_pyobject_t
+
+	runtime.LockOSThread()
+	state := C.ensure_gil(rtloader)
+
+	classStr := (*C.char)(helpers.TrackedCString("fake_check"))
+	defer C._free(unsafe.Pointer(classStr))
+	C.get_class(rtloader, classStr, &module, &class)
+
+	emptyStr := (*C.char)(helpers.TrackedCString(""))
+	defer C._free(unsafe.Pointer(emptyStr))
+	checkIDStr := (*C.char)(helpers.TrackedCString("checkID"))
+	defer C._free(unsafe.Pointer(checkIDStr))
+	configStr := (*C.char)(helpers.TrackedCString("{\"fake_check\": \"/\"}"))
+	defer C._free(unsafe.Pointer(configStr))
+	classStr = (*C.char)(helpers.TrackedCString("fake_check"))
+	defer C._free(unsafe.Pointer(classStr))
+
+	C.get_check(rtloader, class, emptyStr, configStr, checkIDStr, classStr, &check)
+
+	C.release_gil(rtloader, state)
+	runtime.UnlockOSThread()
+
+	// The check runs in another thread until it is interrupted
+	runErr := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		state := C.ensure_gil(rtloader)
+
+		checkResultStr := C.run_check(rtloader, check)
+		C._free(unsafe.Pointer(checkResultStr))
+		err := fetchError()
+
+		C.release_gil(rtloader, state)
+		runtime.UnlockOSThread()
+
+		runErr <- err
+	}()
+
+	code := fmt.Sprintf(`
+import fake_check
+with open(r'%s', 'w') as f:
+	f.write(str(fake_check.running))`, tmpfile.Name())
+	for running := false; !running; time.Sleep(10 * time.Millisecond) {
+		output, err := runString(code)
+		if err != nil {
+			return err
+		}
+		running = output == "True"
+	}
+
+	runtime.LockOSThread()
+	state = C.ensure_gil(rtloader)
+
+	C.interrupt_check(rtloader, check)
+	err := fetchError()
+
+	C.release_gil(rtloader, state)
+	runtime.UnlockOSThread()
+
+	if err != nil {
+		return err
+	}
+	return <-runErr
+}
+
 func runFakeGetWarnings() ([]string, error) {
 	var module *C.rtloader_pyobject_t
 	var class *C.rtloader_pyobject_t
@@ -258,3 +258,25 @@ with open(r'%s', 'w') as f:
 	// Check for leaks
 	helpers.AssertMemoryUsage(t)
 }
+
+func TestInterruptCheck(t *testing.T) {
+	// Reset memory counters
+	helpers.ResetMemoryStats()
+
+	code := `import fake_check
+fake_check.block_run = True`
+
+	if _, err := runString(code); err != nil {
+		t.Fatalf("`TestInterruptCheck` error setting block_run: %v", err)
+	}
+	defer runString(`import fake_check
+fake_check.block_run = False`)
+
+	err := runInterruptedFakeCheck()
+	if err == nil || !strings.Contains(err.Error(), "CheckInterruptedError") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Check for leaks
+	helpers.AssertMemoryUsage(t)
+}
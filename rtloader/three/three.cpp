@@ -37,7 +37,9 @@ Three::Three(const char *python_home, const char *python_exe, cb_memory_tracker_
     , _pythonHome(NULL)
     , _pythonExe(NULL)
     , _baseClass(NULL)
+    , _checkInterruptedError(NULL)
     , _pythonPaths()
+    , _runningChecks()
 {
     initPythonHome(python_home);
 
@@ -53,6 +55,7 @@ Three::~Three()
     // refer to the header file or the doxygen documentation.
     PyEval_RestoreThread(_threadState);
     Py_XDECREF(_baseClass);
+    Py_XDECREF(_checkInterruptedError);
 }
 
 void Three::initPythonHome(const char *pythonHome)
@@ -144,6 +147,13 @@ bool Three::init()
         goto done;
     }
 
+    // create the exception raised in the interrupted checks
+    _checkInterruptedError = PyErr_NewException(const_cast<char *>("datadog_agent.CheckInterruptedError"), NULL, NULL);
+    if (_checkInterruptedError == NULL) {
+        setError("error creating the check interruption exception: " + _fetchPythonError());
+        goto done;
+    }
+
     // import the base class
     _baseClass = _importFrom("datadog_checks.checks", "AgentCheck");
     if (_baseClass == NULL) {
@@ -442,7 +452,17 @@ char *Three::runCheck(RtLoaderPyObject *check)
     char run[] = "run";
     PyObject *result = NULL;
 
+    // keep track of the thread running the check so that it can be interrupted
+    unsigned long thread_id = PyThread_get_thread_ident();
+    _runningChecks[py_check] = thread_id;
+
     result = PyObject_CallMethod(py_check, run, NULL);
+
+    // discard an interruption requested too late to be raised during the run,
+    // so that it isn't raised in the next python code run by this thread
+    _runningChecks.erase(py_check);
+    PyThreadState_SetAsyncExc(thread_id, NULL);
+
     if (result == NULL || !PyUnicode_Check(result)) {
         setError("error invoking 'run' method: " + _fetchPythonError());
         goto done;
@@ -479,6 +499,27 @@ void Three::cancelCheck(RtLoaderPyObject *check)
     Py_XDECREF(result);
 }
 
+void Three::interruptCheck(RtLoaderPyObject *check)
+{
+    if (check == NULL) {
+        return;
+    }
+
+    PyObject *py_check = reinterpret_cast<PyObject *>(check);
+
+    // a check which isn't running has nothing to interrupt
+    RunningChecks::iterator it = _runningChecks.find(py_check);
+    if (it == _runningChecks.end()) {
+        return;
+    }
+
+    // the exception is raised in the thread running the check the next time it runs
+    // python code: a call blocked outside of the interpreter is interrupted once it returns
+    if (PyThreadState_SetAsyncExc(it->second, _checkInterruptedError) != 1) {
+        setError("error interrupting check: thread not found");
+    }
+}
+
 char **Three::getCheckWarnings(RtLoaderPyObject *check)
 {
     if (check == NULL) {
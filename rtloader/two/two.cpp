@@ -39,7 +39,9 @@ Two::Two(const char *python_home, const char *python_exe, cb_memory_tracker_t me
     , _pythonHome(NULL)
     , _pythonExe(NULL)
     , _baseClass(NULL)
+    , _checkInterruptedError(NULL)
     , _pythonPaths()
+    , _runningChecks()
 {
     initPythonHome(python_home);
 
@@ -55,6 +57,7 @@ Two::~Two()
     // refer to the header file or the doxygen documentation.
     PyEval_RestoreThread(_threadState);
     Py_XDECREF(_baseClass);
+    Py_XDECREF(_checkInterruptedError);
 }
 
 void Two::initPythonHome(const char *pythonHome)
@@ -139,6 +142,13 @@ bool Two::init()
     Py2_init_kubeutil();
     Py2_init_containers();
 
+    // create the exception raised in the interrupted checks
+    _checkInterruptedError = PyErr_NewException(const_cast<char *>("datadog_agent.CheckInterruptedError"), NULL, NULL);
+    if (_checkInterruptedError == NULL) {
+        setError("error creating the check interruption exception: " + _fetchPythonError());
+        goto done;
+    }
+
     // import the base class
     _baseClass = _importFrom("datadog_checks.checks", "AgentCheck");
     if (_baseClass == NULL) {
@@ -435,7 +445,17 @@ char *Two::runCheck(RtLoaderPyObject *check)
     char run[] = "run";
     PyObject *result = NULL;
 
+    // keep track of the thread running the check so that it can be interrupted
+    long thread_id = PyThread_get_thread_ident();
+    _runningChecks[py_check] = thread_id;
+
     result = PyObject_CallMethod(py_check, run, NULL);
+
+    // discard an interruption requested too late to be raised during the run,
+    // so that it isn't raised in the next python code run by this thread
+    _runningChecks.erase(py_check);
+    PyThreadState_SetAsyncExc(thread_id, NULL);
+
     if (result == NULL) {
         setError("error invoking 'run' method: " + _fetchPythonError());
         goto done;
@@ -475,6 +495,27 @@ void Two::cancelCheck(RtLoaderPyObject *check)
     Py_XDECREF(result);
 }
 
+void Two::interruptCheck(RtLoaderPyObject *check)
+{
+    if (check == NULL) {
+        return;
+    }
+
+    PyObject *py_check = reinterpret_cast<PyObject *>(check);
+
+    // a check which isn't running has nothing to interrupt
+    RunningChecks::iterator it = _runningChecks.find(py_check);
+    if (it == _runningChecks.end()) {
+        return;
+    }
+
+    // the exception is raised in the thread running the check the next time it runs
+    // python code: a call blocked outside of the interpreter is interrupted once it returns
+    if (PyThreadState_SetAsyncExc(it->second, _checkInterruptedError) != 1) {
+        setError("error interrupting check: thread not found");
+    }
+}
+
 char **Two::getCheckWarnings(RtLoaderPyObject *check)
 {
     if (check == NULL) {
@@ -68,6 +68,7 @@ public:
 
     char *runCheck(RtLoaderPyObject *check);
     void cancelCheck(RtLoaderPyObject *check);
+    void interruptCheck(RtLoaderPyObject *check);
     char **getCheckWarnings(RtLoaderPyObject *check);
     void decref(RtLoaderPyObject *obj);
     void incref(RtLoaderPyObject *obj);
@@ -182,8 +183,16 @@ private:
     char *_pythonHome; /*!< string with the PYTHONHOME for the underlying interpreter */
     char *_pythonExe; /*!< string with the path to the executable of the underlying interpreter */
     PyObject *_baseClass; /*!< PyObject * pointer to the base Agent check class */
+    PyObject *_checkInterruptedError; /*!< PyObject * pointer to the exception raised in the interrupted checks */
     PyPaths _pythonPaths; /*!< string vector containing paths in the PYTHONPATH */
     PyThreadState *_threadState; /*!< PyThreadState * pointer to the saved Python interpreter thread state */
+
+    /*! RunningChecks type prototype
+      \typedef RunningChecks maps the running checks to the identifier of the thread running them.
+    */
+    typedef std::map<PyObject *, long> RunningChecks;
+
+    RunningChecks _runningChecks; /*!< running checks, only accessed with the GIL held */
 };
 
 #endif
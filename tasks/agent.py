@@ -56,6 +56,8 @@ AGENT_CORECHECKS = [
     "memory",
     "ntp",
     "oom_kill",
+    "openmetrics_core",
+    "process_core",
     "systemd",
     "tcp_queue_length",
     "uptime",